@@ -5,16 +5,107 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Coke3a/HotelManagement/internal/adapter/auth/paseto"
 	"github.com/Coke3a/HotelManagement/internal/adapter/config"
+	"github.com/Coke3a/HotelManagement/internal/adapter/document/pdf"
 	"github.com/Coke3a/HotelManagement/internal/adapter/handler/http"
+	"github.com/Coke3a/HotelManagement/internal/adapter/payment/fake"
+	"github.com/Coke3a/HotelManagement/internal/adapter/scheduler"
+	"github.com/Coke3a/HotelManagement/internal/adapter/spreadsheet"
 	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
 	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres/repository"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
 	"github.com/Coke3a/HotelManagement/internal/core/service"
 )
 
+// newPaymentGateway creates the card payment gateway selected by PAYMENT_GATEWAY_PROVIDER.
+// Real providers such as Omise or Stripe are added as adapters under internal/adapter/payment and selected here.
+func newPaymentGateway(config *config.PaymentGateway) (port.PaymentGateway, error) {
+	switch config.Provider {
+	case "", "fake":
+		return fake.New(config)
+	default:
+		return nil, fmt.Errorf("unsupported payment gateway provider %q", config.Provider)
+	}
+}
+
+// newHotel converts the hotel settings printed on invoices and receipts
+func newHotel(config *config.Hotel) (domain.Hotel, error) {
+	hotel := domain.Hotel{
+		Name:    config.Name,
+		Address: config.Address,
+		Phone:   config.Phone,
+		TaxID:   config.TaxID,
+	}
+	if config.ServiceChargeRate != "" {
+		rate, err := strconv.ParseFloat(config.ServiceChargeRate, 64)
+		if err != nil {
+			return domain.Hotel{}, err
+		}
+		hotel.ServiceChargeRate = rate
+	}
+	return hotel, nil
+}
+
+const (
+	defaultLoyaltyEarnRate     = 0.04
+	defaultLoyaltyPointValue   = 1
+	defaultLoyaltyExpiryMonths = 24
+	defaultLoyaltyTiers        = "Member:0,Silver:1000,Gold:5000,Platinum:15000"
+)
+
+// newLoyaltyProgram converts the loyalty program settings, using the defaults for those left unset.
+// Tiers are listed as name:points from the lowest, which starts at 0 points.
+func newLoyaltyProgram(config *config.Loyalty) (domain.LoyaltyProgram, error) {
+	program := domain.LoyaltyProgram{
+		EarnRate:     defaultLoyaltyEarnRate,
+		PointValue:   defaultLoyaltyPointValue,
+		ExpiryMonths: defaultLoyaltyExpiryMonths,
+	}
+	var err error
+	if config.EarnRate != "" {
+		program.EarnRate, err = strconv.ParseFloat(config.EarnRate, 64)
+		if err != nil || program.EarnRate < 0 {
+			return domain.LoyaltyProgram{}, fmt.Errorf("invalid loyalty earn rate %q", config.EarnRate)
+		}
+	}
+	if config.PointValue != "" {
+		program.PointValue, err = strconv.ParseFloat(config.PointValue, 64)
+		if err != nil || program.PointValue <= 0 {
+			return domain.LoyaltyProgram{}, fmt.Errorf("invalid loyalty point value %q", config.PointValue)
+		}
+	}
+	if config.ExpiryMonths != "" {
+		program.ExpiryMonths, err = strconv.Atoi(config.ExpiryMonths)
+		if err != nil || program.ExpiryMonths < 0 {
+			return domain.LoyaltyProgram{}, fmt.Errorf("invalid loyalty expiry months %q", config.ExpiryMonths)
+		}
+	}
+
+	tiers := config.Tiers
+	if tiers == "" {
+		tiers = defaultLoyaltyTiers
+	}
+	for _, tier := range strings.Split(tiers, ",") {
+		name, points, ok := strings.Cut(tier, ":")
+		minPoints, err := strconv.Atoi(strings.TrimSpace(points))
+		name = strings.TrimSpace(name)
+		if !ok || err != nil || name == "" {
+			return domain.LoyaltyProgram{}, fmt.Errorf("invalid loyalty tier %q", tier)
+		}
+		if n := len(program.Tiers); (n == 0 && minPoints != 0) || (n > 0 && minPoints <= program.Tiers[n-1].MinPoints) {
+			return domain.LoyaltyProgram{}, fmt.Errorf("loyalty tiers must start at 0 points and rise: %q", tiers)
+		}
+		program.Tiers = append(program.Tiers, domain.LoyaltyTier{Name: name, MinPoints: minPoints})
+	}
+
+	return program, nil
+}
 
 func main() {
 		// Load environment variables
@@ -61,6 +152,30 @@ func main() {
 			os.Exit(1)
 		}
 
+		// Init payment gateway
+		paymentGateway, err := newPaymentGateway(config.PaymentGateway)
+		if err != nil {
+			slog.Error("Error initializing payment gateway", "error", err)
+			os.Exit(1)
+		}
+
+		// Init invoice and receipt rendering
+		documentRenderer, err := pdf.New(config.Document)
+		if err != nil {
+			slog.Error("Error initializing document renderer", "error", err)
+			os.Exit(1)
+		}
+		hotel, err := newHotel(config.Hotel)
+		if err != nil {
+			slog.Error("Error loading hotel details", "error", err)
+			os.Exit(1)
+		}
+		loyaltyProgram, err := newLoyaltyProgram(config.Loyalty)
+		if err != nil {
+			slog.Error("Error loading loyalty program", "error", err)
+			os.Exit(1)
+		}
+
 		logRepository := repository.NewLogRepository(db)
 		logService := service.NewLogService(logRepository)
 		logHandler := http.NewLogHandler(logService)
@@ -71,17 +186,50 @@ func main() {
 	
 
 		customerRepository := repository.NewCustomerRepository(db)
-		customerService := service.NewCustomerService(customerRepository, logRepository)
-		customerHandler := http.NewCustomerHandler(customerService)
+
+		exchangeRateRepository := repository.NewExchangeRateRepository(db)
+		exchangeRateService := service.NewExchangeRateService(exchangeRateRepository, logRepository)
+		exchangeRateHandler := http.NewExchangeRateHandler(exchangeRateService)
+
+		nightAuditRepository := repository.NewNightAuditRepository(db)
+		dailyBookingSummaryRepository := repository.NewDailyBookingSummaryRepository(db)
+
+		cashierShiftRepository := repository.NewCashierShiftRepository(db)
+		cashierShiftService := service.NewCashierShiftService(cashierShiftRepository, nightAuditRepository, logRepository)
+		cashierShiftHandler := http.NewCashierShiftHandler(cashierShiftService)
 
 		paymentRepository := repository.NewPaymentRepository(db)
-		paymentService := service.NewPaymentService(paymentRepository, logRepository)
+		folioRepository := repository.NewFolioRepository(db)
+		bookingRepository := repository.NewBookingRepository(db)
+		bookingPayerRepository := repository.NewBookingPayerRepository(db)
+
+		customerService := service.NewCustomerService(customerRepository, bookingRepository, logRepository)
+		customerHandler := http.NewCustomerHandler(customerService)
+
+		folioService := service.NewFolioService(folioRepository, bookingRepository, paymentRepository, bookingPayerRepository, nightAuditRepository, dailyBookingSummaryRepository, logRepository)
+		folioHandler := http.NewFolioHandler(folioService)
+
+		loyaltyRepository := repository.NewLoyaltyRepository(db)
+		loyaltyService := service.NewLoyaltyService(loyaltyRepository, customerRepository, bookingRepository, paymentRepository, folioService, nightAuditRepository, dailyBookingSummaryRepository, loyaltyProgram, logRepository)
+		loyaltyHandler := http.NewLoyaltyHandler(loyaltyService)
+
+		paymentService := service.NewPaymentService(paymentRepository, exchangeRateRepository, cashierShiftRepository, nightAuditRepository, dailyBookingSummaryRepository, paymentGateway, loyaltyService, userRepository, logRepository)
 		paymentHandler := http.NewPaymentHandler(paymentService)
 
-		bookingRepository := repository.NewBookingRepository(db)
-		bookingService := service.NewBookingService(bookingRepository, paymentRepository, logRepository)
+		companyRepository := repository.NewCompanyRepository(db)
+		companyService := service.NewCompanyService(companyRepository, nightAuditRepository, dailyBookingSummaryRepository, logRepository)
+		companyHandler := http.NewCompanyHandler(companyService)
+
+		bookingService := service.NewBookingService(bookingRepository, paymentRepository, folioRepository, bookingPayerRepository, companyRepository, cashierShiftRepository, nightAuditRepository, dailyBookingSummaryRepository, loyaltyService, logRepository)
 		bookingHandler := http.NewBookingHandler(bookingService)
 
+		invoiceRepository := repository.NewInvoiceRepository(db)
+		invoiceService := service.NewInvoiceService(invoiceRepository, bookingRepository, folioRepository, paymentRepository, bookingPayerRepository, nightAuditRepository, logRepository)
+		invoiceHandler := http.NewInvoiceHandler(invoiceService)
+
+		documentService := service.NewDocumentService(bookingRepository, folioRepository, paymentRepository, invoiceRepository, bookingPayerRepository, customerRepository, companyRepository, nightAuditRepository, documentRenderer, hotel)
+		documentHandler := http.NewDocumentHandler(documentService)
+
 		rankRepository := repository.NewRankRepository(db)
 		rankService := service.NewRankService(rankRepository, logRepository)
 		rankHandler := http.NewRankHandler(rankService)
@@ -102,10 +250,34 @@ func main() {
 		customerTypeService := service.NewCustomerTypeService(customerTypeRepository, logRepository)
 		customerTypeHandler := http.NewCustomerTypeHandler(customerTypeService)
 
-		dailyBookingSummaryRepository := repository.NewDailyBookingSummaryRepository(db)
-		dailyBookingSummaryService := service.NewDailyBookingSummaryService(dailyBookingSummaryRepository, bookingRepository, logRepository)
+		dailyBookingSummaryService := service.NewDailyBookingSummaryService(dailyBookingSummaryRepository, paymentRepository, nightAuditRepository, logRepository)
 		dailyBookingSummaryHandler := http.NewDailyBookingSummaryHandler(dailyBookingSummaryService)
 
+		nightAuditService := service.NewNightAuditService(nightAuditRepository, bookingRepository, folioRepository, paymentRepository, dailyBookingSummaryService, logRepository)
+		nightAuditHandler := http.NewNightAuditHandler(nightAuditService)
+
+		reportRepository := repository.NewReportRepository(db)
+		reportService := service.NewReportService(reportRepository, nightAuditRepository)
+		reportHandler := http.NewReportHandler(reportService)
+
+		exportService := service.NewExportService(bookingRepository, paymentRepository, customerRepository, logRepository, dailyBookingSummaryRepository, reportService, spreadsheet.New())
+		exportHandler := http.NewExportHandler(exportService)
+
+		idempotencyRepository := repository.NewIdempotencyRepository(db)
+		idempotencyService := service.NewIdempotencyService(idempotencyRepository)
+
+		jobRunRepository := repository.NewJobRunRepository(db)
+		jobService := service.NewJobService(jobRunRepository, userRepository, dailyBookingSummaryService, reportService, loyaltyService, idempotencyService, logRepository)
+		jobHandler := http.NewJobHandler(jobService)
+
+		// Start scheduled jobs
+		jobScheduler, err := scheduler.New(config.Scheduler, jobService)
+		if err != nil {
+			slog.Error("Error initializing scheduler", "error", err)
+			os.Exit(1)
+		}
+		jobScheduler.Start(ctx)
+
 
 		authService := service.NewAuthService(userRepository, token)
 		authHandler := http.NewAuthHandler(authService)
@@ -124,6 +296,18 @@ func main() {
 			*customerTypeHandler,
 			*logHandler,
 			*dailyBookingSummaryHandler,
+			*exchangeRateHandler,
+			*folioHandler,
+			*documentHandler,
+			*invoiceHandler,
+			*companyHandler,
+			*cashierShiftHandler,
+			*nightAuditHandler,
+			*jobHandler,
+			*reportHandler,
+			*exportHandler,
+			*loyaltyHandler,
+			idempotencyService,
 			token,
 		)
 		if err != nil {
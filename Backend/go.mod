@@ -2,7 +2,12 @@ module github.com/Coke3a/HotelManagement
 
 go 1.22.3
 
-require github.com/Masterminds/squirrel v1.5.4
+require (
+	github.com/Masterminds/squirrel v1.5.4
+	github.com/go-playground/validator/v10 v10.20.0
+	github.com/google/uuid v1.4.0
+	golang.org/x/crypto v0.23.0
+)
 
 require (
 	aidanwoods.dev/go-result v0.1.0 // indirect
@@ -21,9 +26,7 @@ require (
 	github.com/go-openapi/swag v0.19.15 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
-	github.com/go-playground/validator/v10 v10.20.0 // indirect
 	github.com/goccy/go-json v0.10.2 // indirect
-	github.com/google/uuid v1.4.0 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
@@ -47,7 +50,6 @@ require (
 	go.opentelemetry.io/otel/trace v1.19.0 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	golang.org/x/arch v0.8.0 // indirect
-	golang.org/x/crypto v0.23.0 // indirect
 	golang.org/x/exp v0.0.0-20230522175609-2e198f4a06a1 // indirect
 	golang.org/x/net v0.25.0 // indirect
 	golang.org/x/sync v0.5.0 // indirect
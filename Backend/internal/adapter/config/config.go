@@ -9,10 +9,15 @@ import (
 // Container contains environment variables for the application, database, cache, token, and http server
 type (
 	Container struct {
-		App   *App
-		Token *Token
-		DB    *DB
-		HTTP  *HTTP
+		App            *App
+		Token          *Token
+		DB             *DB
+		HTTP           *HTTP
+		PaymentGateway *PaymentGateway
+		Hotel          *Hotel
+		Scheduler      *Scheduler
+		Loyalty        *Loyalty
+		Document       *Document
 	}
 	// App contains all the environment variables for the application
 	App struct {
@@ -40,6 +45,40 @@ type (
 		Port           string
 		AllowedOrigins string
 	}
+	// PaymentGateway contains all the environment variables for the card payment gateway
+	PaymentGateway struct {
+		Provider      string
+		WebhookSecret string
+	}
+	// Hotel contains all the environment variables for the hotel printed on invoices and receipts
+	Hotel struct {
+		Name              string
+		Address           string
+		Phone             string
+		TaxID             string
+		ServiceChargeRate string
+	}
+	// Scheduler contains all the environment variables for the in-process job scheduler
+	Scheduler struct {
+		DailySummaryTime       string
+		ForecastSnapshotTime   string
+		LoyaltyExpiryTime      string
+		IdempotencyCleanupTime string
+		RetryAttempts          string
+		RetryInterval          string
+	}
+	// Loyalty contains all the environment variables for the loyalty points program
+	Loyalty struct {
+		EarnRate     string
+		PointValue   string
+		ExpiryMonths string
+		Tiers        string
+	}
+	// Document contains all the environment variables for rendering invoices and receipts
+	Document struct {
+		Fonts     string
+		BoldFonts string
+	}
 )
 
 // New creates a new container instance
@@ -77,10 +116,49 @@ func New() (*Container, error) {
 		AllowedOrigins: os.Getenv("HTTP_ALLOWED_ORIGINS"),
 	}
 
+	paymentGateway := &PaymentGateway{
+		Provider:      os.Getenv("PAYMENT_GATEWAY_PROVIDER"),
+		WebhookSecret: os.Getenv("PAYMENT_GATEWAY_WEBHOOK_SECRET"),
+	}
+
+	hotel := &Hotel{
+		Name:              os.Getenv("HOTEL_NAME"),
+		Address:           os.Getenv("HOTEL_ADDRESS"),
+		Phone:             os.Getenv("HOTEL_PHONE"),
+		TaxID:             os.Getenv("HOTEL_TAX_ID"),
+		ServiceChargeRate: os.Getenv("HOTEL_SERVICE_CHARGE_RATE"),
+	}
+
+	scheduler := &Scheduler{
+		DailySummaryTime:       os.Getenv("SCHEDULER_DAILY_SUMMARY_TIME"),
+		ForecastSnapshotTime:   os.Getenv("SCHEDULER_FORECAST_SNAPSHOT_TIME"),
+		LoyaltyExpiryTime:      os.Getenv("SCHEDULER_LOYALTY_EXPIRY_TIME"),
+		IdempotencyCleanupTime: os.Getenv("SCHEDULER_IDEMPOTENCY_CLEANUP_TIME"),
+		RetryAttempts:          os.Getenv("SCHEDULER_RETRY_ATTEMPTS"),
+		RetryInterval:          os.Getenv("SCHEDULER_RETRY_INTERVAL"),
+	}
+
+	loyalty := &Loyalty{
+		EarnRate:     os.Getenv("LOYALTY_EARN_RATE"),
+		PointValue:   os.Getenv("LOYALTY_POINT_VALUE"),
+		ExpiryMonths: os.Getenv("LOYALTY_EXPIRY_MONTHS"),
+		Tiers:        os.Getenv("LOYALTY_TIERS"),
+	}
+
+	document := &Document{
+		Fonts:     os.Getenv("DOCUMENT_FONTS"),
+		BoldFonts: os.Getenv("DOCUMENT_BOLD_FONTS"),
+	}
+
 	return &Container{
 		app,
 		token,
 		db,
 		http,
+		paymentGateway,
+		hotel,
+		scheduler,
+		loyalty,
+		document,
 	}, nil
 }
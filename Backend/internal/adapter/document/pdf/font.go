@@ -0,0 +1,275 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// trueTypeFont is a TrueType font read far enough to embed it whole in a PDF and lay text out with it
+type trueTypeFont struct {
+	name       string // PostScript name
+	data       []byte
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	capHeight  int
+	fixedPitch bool
+	advances   []uint16 // Advance width of each glyph, in font units
+	glyphs     map[rune]uint16
+}
+
+var errUnsupportedFont = errors.New("only TrueType outline fonts (.ttf) are supported")
+
+// loadFont reads and parses the TrueType font file at path
+func loadFont(path string) (*trueTypeFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	font, err := parseTrueType(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if font.name == "" {
+		font.name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return font, nil
+}
+
+func parseTrueType(data []byte) (*trueTypeFont, error) {
+	if len(data) < 12 {
+		return nil, errUnsupportedFont
+	}
+	// CFF based OpenType ("OTTO") and collections ("ttcf") cannot be embedded as FontFile2
+	if version := binary.BigEndian.Uint32(data); version != 0x00010000 && version != 0x74727565 {
+		return nil, errUnsupportedFont
+	}
+
+	tables := map[string][]byte{}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		entry := 12 + i*16
+		if entry+16 > len(data) {
+			return nil, errUnsupportedFont
+		}
+		tag := string(data[entry : entry+4])
+		offset := int(binary.BigEndian.Uint32(data[entry+8:]))
+		length := int(binary.BigEndian.Uint32(data[entry+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("table %q is out of bounds", tag)
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "cmap", "glyf", "loca"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("missing %q table: %w", tag, errUnsupportedFont)
+		}
+	}
+
+	font := &trueTypeFont{data: data}
+
+	head := tables["head"]
+	if len(head) < 54 {
+		return nil, errors.New("head table is too short")
+	}
+	font.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	if font.unitsPerEm == 0 {
+		return nil, errors.New("units per em is zero")
+	}
+	for i := range font.bbox {
+		font.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+i*2:])))
+	}
+
+	hhea := tables["hhea"]
+	if len(hhea) < 36 {
+		return nil, errors.New("hhea table is too short")
+	}
+	font.ascent = int(int16(binary.BigEndian.Uint16(hhea[4:])))
+	font.descent = int(int16(binary.BigEndian.Uint16(hhea[6:])))
+	numberOfHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+
+	maxp := tables["maxp"]
+	if len(maxp) < 6 {
+		return nil, errors.New("maxp table is too short")
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+
+	hmtx := tables["hmtx"]
+	if numberOfHMetrics == 0 || len(hmtx) < numberOfHMetrics*4 {
+		return nil, errors.New("hmtx table is too short")
+	}
+	font.advances = make([]uint16, numGlyphs)
+	for i := range font.advances {
+		metric := i
+		if metric >= numberOfHMetrics {
+			metric = numberOfHMetrics - 1
+		}
+		font.advances[i] = binary.BigEndian.Uint16(hmtx[metric*4:])
+	}
+
+	font.capHeight = font.ascent
+	if os2 := tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		font.capHeight = int(int16(binary.BigEndian.Uint16(os2[88:])))
+	}
+	if post := tables["post"]; len(post) >= 16 {
+		font.fixedPitch = binary.BigEndian.Uint32(post[12:]) != 0
+	}
+
+	glyphs, err := parseCmap(tables["cmap"], numGlyphs)
+	if err != nil {
+		return nil, err
+	}
+	font.glyphs = glyphs
+	font.name = parsePostScriptName(tables["name"])
+
+	return font, nil
+}
+
+// parseCmap maps characters to glyphs from the Unicode subtable of the cmap, preferring the full
+// format 12 table over the Basic Multilingual Plane one in format 4
+func parseCmap(cmap []byte, numGlyphs int) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errors.New("cmap table is too short")
+	}
+
+	var format4, format12 []byte
+	numSubtables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numSubtables; i++ {
+		record := 4 + i*8
+		if record+8 > len(cmap) {
+			break
+		}
+		platformID := binary.BigEndian.Uint16(cmap[record:])
+		encodingID := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+		unicode := platformID == 0 || (platformID == 3 && (encodingID == 1 || encodingID == 10))
+		if !unicode {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			format4 = cmap[offset:]
+		case 12:
+			format12 = cmap[offset:]
+		}
+	}
+
+	glyphs := map[rune]uint16{}
+	add := func(r rune, glyph int) {
+		if glyph > 0 && glyph < numGlyphs {
+			glyphs[r] = uint16(glyph)
+		}
+	}
+
+	switch {
+	case len(format12) >= 16:
+		numGroups := int(binary.BigEndian.Uint32(format12[12:]))
+		if 16+numGroups*12 > len(format12) {
+			return nil, errors.New("cmap format 12 subtable is too short")
+		}
+		for i := 0; i < numGroups; i++ {
+			group := format12[16+i*12:]
+			start := rune(binary.BigEndian.Uint32(group))
+			end := rune(binary.BigEndian.Uint32(group[4:]))
+			startGlyph := int(binary.BigEndian.Uint32(group[8:]))
+			for r := start; r <= end && r <= 0x10ffff; r++ {
+				add(r, startGlyph+int(r-start))
+			}
+		}
+	case len(format4) >= 14:
+		segCount := int(binary.BigEndian.Uint16(format4[6:])) / 2
+		endCodes := 14
+		startCodes := endCodes + segCount*2 + 2
+		idDeltas := startCodes + segCount*2
+		idRangeOffsets := idDeltas + segCount*2
+		if idRangeOffsets+segCount*2 > len(format4) {
+			return nil, errors.New("cmap format 4 subtable is too short")
+		}
+		for i := 0; i < segCount; i++ {
+			end := int(binary.BigEndian.Uint16(format4[endCodes+i*2:]))
+			start := int(binary.BigEndian.Uint16(format4[startCodes+i*2:]))
+			delta := int(binary.BigEndian.Uint16(format4[idDeltas+i*2:]))
+			rangeOffset := int(binary.BigEndian.Uint16(format4[idRangeOffsets+i*2:]))
+			for c := start; c <= end && c != 0xffff; c++ {
+				if rangeOffset == 0 {
+					add(rune(c), (c+delta)&0xffff)
+					continue
+				}
+				at := idRangeOffsets + i*2 + rangeOffset + (c-start)*2
+				if at+2 > len(format4) {
+					continue
+				}
+				if glyph := int(binary.BigEndian.Uint16(format4[at:])); glyph != 0 {
+					add(rune(c), (glyph+delta)&0xffff)
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("no Unicode cmap subtable: %w", errUnsupportedFont)
+	}
+
+	return glyphs, nil
+}
+
+// parsePostScriptName returns name ID 6 of the name table, or "" when it has none
+func parsePostScriptName(name []byte) string {
+	if len(name) < 6 {
+		return ""
+	}
+	count := int(binary.BigEndian.Uint16(name[2:]))
+	storage := int(binary.BigEndian.Uint16(name[4:]))
+	for i := 0; i < count; i++ {
+		record := 6 + i*12
+		if record+12 > len(name) {
+			break
+		}
+		platformID := binary.BigEndian.Uint16(name[record:])
+		nameID := binary.BigEndian.Uint16(name[record+6:])
+		length := int(binary.BigEndian.Uint16(name[record+8:]))
+		offset := storage + int(binary.BigEndian.Uint16(name[record+10:]))
+		if nameID != 6 || offset+length > len(name) {
+			continue
+		}
+		value := name[offset : offset+length]
+		switch platformID {
+		case 1:
+			return string(value)
+		case 0, 3:
+			units := make([]uint16, len(value)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(value[j*2:])
+			}
+			return string(utf16.Decode(units))
+		}
+	}
+	return ""
+}
+
+// glyph returns the glyph of r and whether the font has one
+func (f *trueTypeFont) glyph(r rune) (uint16, bool) {
+	glyph, ok := f.glyphs[r]
+	return glyph, ok
+}
+
+// width returns the advance of a glyph in thousandths of the font size
+func (f *trueTypeFont) width(glyph uint16) int {
+	if int(glyph) >= len(f.advances) {
+		return 0
+	}
+	return int(f.advances[glyph]) * 1000 / f.unitsPerEm
+}
+
+// scale converts font units to thousandths of the font size
+func (f *trueTypeFont) scale(units int) int {
+	return units * 1000 / f.unitsPerEm
+}
@@ -0,0 +1,188 @@
+package pdf
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/config"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+/**
+ * Renderer implements port.DocumentRenderer interface
+ * and renders the text templates in templates/ into PDF files
+ */
+type Renderer struct {
+	templates *template.Template
+	regular   *fontSet
+	bold      *fontSet
+}
+
+var chargeTypeNames = map[domain.ChargeType]string{
+	domain.ChargeTypeRoom:       "Room",
+	domain.ChargeTypeMinibar:    "Minibar",
+	domain.ChargeTypeLaundry:    "Laundry",
+	domain.ChargeTypeRestaurant: "Restaurant",
+	domain.ChargeTypeExtraBed:   "Extra bed",
+	domain.ChargeTypeAdjustment: "Adjustment",
+	domain.ChargeTypeOther:      "Other",
+}
+
+var paymentMethodNames = map[domain.PaymentMethod]string{
+	domain.PaymentMethodNotSpecified:  "Payment",
+	domain.PaymentMethodCreditCard:    "Credit card",
+	domain.PaymentMethodDebitCard:     "Debit card",
+	domain.PaymentMethodCash:          "Cash",
+	domain.PaymentMethodBankTransfer:  "Bank transfer",
+	domain.PaymentMethodCityLedger:    "City ledger",
+	domain.PaymentMethodLoyaltyPoints: "Loyalty points",
+}
+
+var paymentTypeNames = map[domain.PaymentType]string{
+	domain.PaymentTypeDeposit: "deposit",
+	domain.PaymentTypeBalance: "balance",
+	domain.PaymentTypeTopUp:   "top-up",
+	domain.PaymentTypeRefund:  "refund",
+	domain.PaymentTypeVoid:    "void",
+}
+
+var funcs = template.FuncMap{
+	"money": money,
+	"neg":   func(amount float64) float64 { return -amount },
+	"date":  func(t time.Time) string { return t.Format("02/01/2006") },
+	"datePtr": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("02/01/2006")
+	},
+	"fit":          fit,
+	"isCreditNote": func(t domain.DocumentType) bool { return t == domain.DocumentTypeCreditNote },
+	"chargeDescription": func(charge domain.FolioCharge) string {
+		if charge.Description == "" {
+			return chargeTypeNames[charge.ChargeType]
+		}
+		return charge.Description
+	},
+	"paymentDescription": func(payment domain.Payment) string {
+		return fmt.Sprintf("%s %s", paymentMethodNames[payment.PaymentMethod], paymentTypeNames[payment.Type])
+	},
+}
+
+// New creates a new renderer instance with the fonts in config
+func New(config *config.Document) (port.DocumentRenderer, error) {
+	templates, err := template.New("").Funcs(funcs).ParseFS(templatesFS, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	regular, err := loadFontSet(config.Fonts)
+	if err != nil {
+		return nil, err
+	}
+	if len(regular.fonts) == 0 {
+		return nil, errors.New("no document fonts configured")
+	}
+	bold, err := loadFontSet(config.BoldFonts)
+	if err != nil {
+		return nil, err
+	}
+	if len(bold.fonts) == 0 {
+		bold = &fontSet{regular.fonts, true}
+	}
+
+	if !regular.has('\u0e01') {
+		slog.Warn("None of the document fonts has Thai characters; they will print as \"?\"", "fonts", config.Fonts)
+	}
+
+	return &Renderer{
+		templates,
+		regular,
+		bold,
+	}, nil
+}
+
+// loadFontSet loads the comma separated font files in paths, in order
+func loadFontSet(paths string) (*fontSet, error) {
+	set := &fontSet{}
+	for _, path := range strings.Split(paths, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		font, err := loadFont(path)
+		if err != nil {
+			return nil, err
+		}
+		set.fonts = append(set.fonts, font)
+	}
+	return set, nil
+}
+
+// has reports whether a font in the set has r
+func (s *fontSet) has(r rune) bool {
+	for _, font := range s.fonts {
+		if _, ok := font.glyph(r); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderBookingDocument renders a booking invoice or receipt
+func (r *Renderer) RenderBookingDocument(document *domain.BookingDocument) ([]byte, error) {
+	var text bytes.Buffer
+	if err := r.templates.ExecuteTemplate(&text, "booking_document.tmpl", document); err != nil {
+		return nil, err
+	}
+
+	return writePDF(parseLines(text.String()), r.regular, r.bold), nil
+}
+
+// money formats an amount with two decimals and thousands separators
+func money(amount float64) string {
+	s := fmt.Sprintf("%.2f", amount)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	whole, fraction := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+
+	return sign + b.String() + fraction
+}
+
+// fit pads or cuts text to width character cells, so columns stay aligned when text has Thai
+// vowel and tone marks, which sit on the character before them and take no cell of their own
+func fit(width int, text string) string {
+	var b strings.Builder
+	cells := 0
+	for _, r := range text {
+		if !unicode.Is(unicode.Mn, r) {
+			if cells == width {
+				break
+			}
+			cells++
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + strings.Repeat(" ", width-cells)
+}
@@ -0,0 +1,376 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// A4 page size and layout in points
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	marginLeft   = 40
+	marginTop    = 50
+	marginBottom = 50
+	lineHeight   = 12
+	fontSize     = 9
+	headingSize  = 13
+)
+
+// line is a single line of text produced by a document template.
+// Templates mark headings with a leading "!h " and bold lines with "!b ".
+type line struct {
+	text    string
+	bold    bool
+	heading bool
+}
+
+func parseLines(text string) []line {
+	var lines []line
+	for _, raw := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(raw, "!h "):
+			lines = append(lines, line{text: raw[3:], bold: true, heading: true})
+		case strings.HasPrefix(raw, "!b "):
+			lines = append(lines, line{text: raw[3:], bold: true})
+		default:
+			lines = append(lines, line{text: raw})
+		}
+	}
+	return lines
+}
+
+// fontSet is a chain of fonts tried in order for each character, so a font with Thai glyphs
+// can back a Latin one. Without bold fonts of its own, bold is drawn by also stroking the
+// outlines of the regular ones.
+type fontSet struct {
+	fonts    []*trueTypeFont
+	fakeBold bool
+}
+
+// glyphRun is a stretch of text set in a single font
+type glyphRun struct {
+	font   *trueTypeFont
+	glyphs []uint16
+	runes  []rune
+}
+
+// layout splits text into runs of the first font in the set that has each character.
+// Combining marks stay in the font of the character they sit on when it has them,
+// and characters no font has are printed as "?".
+func (s *fontSet) layout(text string) []glyphRun {
+	var runs []glyphRun
+	for _, r := range strings.ReplaceAll(text, "\t", "    ") {
+		if unicode.IsControl(r) {
+			continue
+		}
+
+		var font *trueTypeFont
+		var glyph uint16
+		if last := len(runs) - 1; last >= 0 && unicode.Is(unicode.Mn, r) {
+			if g, ok := runs[last].font.glyph(r); ok {
+				font, glyph = runs[last].font, g
+			}
+		}
+		for _, f := range s.fonts {
+			if font != nil {
+				break
+			}
+			if g, ok := f.glyph(r); ok {
+				font, glyph = f, g
+			}
+		}
+		if font == nil {
+			font, r = s.fonts[0], '?'
+			glyph, _ = font.glyph(r)
+		}
+
+		if last := len(runs) - 1; last >= 0 && runs[last].font == font {
+			runs[last].glyphs = append(runs[last].glyphs, glyph)
+			runs[last].runes = append(runs[last].runes, r)
+			continue
+		}
+		runs = append(runs, glyphRun{font: font, glyphs: []uint16{glyph}, runes: []rune{r}})
+	}
+	return runs
+}
+
+// runsWidth returns the advance of runs in thousandths of the font size
+func runsWidth(runs []glyphRun) int {
+	width := 0
+	for _, run := range runs {
+		for _, glyph := range run.glyphs {
+			width += run.font.width(glyph)
+		}
+	}
+	return width
+}
+
+// embeddedFont is a font used by the document, with the characters its used glyphs stand for
+type embeddedFont struct {
+	resource string
+	font     *trueTypeFont
+	unicode  map[uint16]rune
+}
+
+// pdfWriter collects the numbered objects of a document so they can refer to each other
+// before they are written out
+type pdfWriter struct {
+	objects [][]byte
+	fonts   map[*trueTypeFont]*embeddedFont
+	order   []*embeddedFont
+}
+
+// reserve allocates the number of an object written later with set or setStream
+func (w *pdfWriter) reserve() int {
+	w.objects = append(w.objects, nil)
+	return len(w.objects)
+}
+
+func (w *pdfWriter) set(id int, format string, args ...any) {
+	w.objects[id-1] = []byte(fmt.Sprintf(format, args...))
+}
+
+// setStream writes a stream object, compressed when it is large enough to be worth it
+func (w *pdfWriter) setStream(id int, dict string, data []byte) {
+	var buf bytes.Buffer
+	if len(data) > 512 {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(data)
+		zw.Close()
+		fmt.Fprintf(&buf, "<< %s /Filter /FlateDecode /Length %d >>\nstream\n", dict, compressed.Len())
+		buf.Write(compressed.Bytes())
+	} else {
+		fmt.Fprintf(&buf, "<< %s /Length %d >>\nstream\n", dict, len(data))
+		buf.Write(data)
+	}
+	buf.WriteString("\nendstream")
+	w.objects[id-1] = buf.Bytes()
+}
+
+// use records the glyphs of runs as used and returns the resource name of the font of each run
+func (w *pdfWriter) use(runs []glyphRun) []string {
+	resources := make([]string, len(runs))
+	for i, run := range runs {
+		embedded, ok := w.fonts[run.font]
+		if !ok {
+			embedded = &embeddedFont{
+				resource: fmt.Sprintf("F%d", len(w.order)+1),
+				font:     run.font,
+				unicode:  map[uint16]rune{},
+			}
+			w.fonts[run.font] = embedded
+			w.order = append(w.order, embedded)
+		}
+		for j, glyph := range run.glyphs {
+			if _, ok := embedded.unicode[glyph]; !ok {
+				embedded.unicode[glyph] = run.runes[j]
+			}
+		}
+		resources[i] = embedded.resource
+	}
+	return resources
+}
+
+// writePDF lays the lines out on as many A4 pages as needed. Fonts are embedded whole as
+// CID-keyed TrueType fonts with a ToUnicode map, so Thai prints and can be copied or searched.
+func writePDF(lines []line, regular, bold *fontSet) []byte {
+	var pages [][]line
+	var current []line
+	y := pageHeight - marginTop
+	for _, l := range lines {
+		height := lineHeight
+		if l.heading {
+			height = lineHeight * 2
+		}
+		if y-height < marginBottom && len(current) > 0 {
+			pages = append(pages, current)
+			current = nil
+			y = pageHeight - marginTop
+		}
+		current = append(current, l)
+		y -= height
+	}
+	pages = append(pages, current)
+
+	w := &pdfWriter{fonts: map[*trueTypeFont]*embeddedFont{}}
+	catalogID := w.reserve()
+	pagesID := w.reserve()
+	fontsID := w.reserve()
+
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		pageID := w.reserve()
+		contentID := w.reserve()
+		kids[i] = fmt.Sprintf("%d 0 R", pageID)
+
+		w.setStream(contentID, "", w.pageContent(page, i+1, len(pages), regular, bold))
+		w.set(pageID, "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font %d 0 R >> /Contents %d 0 R >>",
+			pagesID, pageWidth, pageHeight, fontsID, contentID)
+	}
+
+	w.set(catalogID, "<< /Type /Catalog /Pages %d 0 R >>", pagesID)
+	w.set(pagesID, "<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	resources := make([]string, len(w.order))
+	for i, embedded := range w.order {
+		resources[i] = fmt.Sprintf("/%s %d 0 R", embedded.resource, w.embedFont(embedded))
+	}
+	w.set(fontsID, "<< %s >>", strings.Join(resources, " "))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(w.objects))
+	for i, object := range w.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(object)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(offsets)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogID, xref)
+
+	return buf.Bytes()
+}
+
+func (w *pdfWriter) pageContent(lines []line, pageNumber, pageCount int, regular, bold *fontSet) []byte {
+	var buf bytes.Buffer
+	y := pageHeight - marginTop
+	for _, l := range lines {
+		fonts, size := regular, fontSize
+		if l.bold {
+			fonts = bold
+		}
+		if l.heading {
+			size = headingSize
+			y -= lineHeight
+		}
+		w.showText(&buf, fonts.layout(l.text), size, marginLeft, y, l.bold && fonts.fakeBold)
+		y -= lineHeight
+	}
+
+	footer := regular.layout(fmt.Sprintf("Page %d of %d", pageNumber, pageCount))
+	footerX := pageWidth - marginLeft - runsWidth(footer)*(fontSize-1)/1000
+	w.showText(&buf, footer, fontSize-1, footerX, marginBottom/2, false)
+
+	return buf.Bytes()
+}
+
+// showText writes runs as one text object starting at x, y. Each glyph is shown by its two byte
+// glyph ID, which the Identity-H encoding passes straight through to the font.
+func (w *pdfWriter) showText(buf *bytes.Buffer, runs []glyphRun, size, x, y int, stroke bool) {
+	if len(runs) == 0 {
+		return
+	}
+	resources := w.use(runs)
+
+	if stroke {
+		buf.WriteString("q 0.3 w BT 2 Tr ")
+	} else {
+		buf.WriteString("BT ")
+	}
+	fmt.Fprintf(buf, "1 0 0 1 %d %d Tm", x, y)
+	for i, run := range runs {
+		fmt.Fprintf(buf, " /%s %d Tf <", resources[i], size)
+		for _, glyph := range run.glyphs {
+			fmt.Fprintf(buf, "%04X", glyph)
+		}
+		buf.WriteString("> Tj")
+	}
+	if stroke {
+		buf.WriteString(" ET Q\n")
+	} else {
+		buf.WriteString(" ET\n")
+	}
+}
+
+// embedFont writes the objects of a Type0 font and returns its object number
+func (w *pdfWriter) embedFont(embedded *embeddedFont) int {
+	font := embedded.font
+	name := pdfName(font.name)
+	fontID := w.reserve()
+	cidFontID := w.reserve()
+	descriptorID := w.reserve()
+	fileID := w.reserve()
+	toUnicodeID := w.reserve()
+
+	glyphs := make([]int, 0, len(embedded.unicode))
+	for glyph := range embedded.unicode {
+		glyphs = append(glyphs, int(glyph))
+	}
+	sort.Ints(glyphs)
+
+	var widths strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, font.width(uint16(glyph)))
+	}
+
+	flags := 32 // Nonsymbolic
+	if font.fixedPitch {
+		flags |= 1
+	}
+
+	w.set(fontID, "<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cidFontID, toUnicodeID)
+	w.set(cidFontID, "<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>",
+		name, descriptorID, strings.TrimSpace(widths.String()))
+	w.set(descriptorID, "<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		name, flags, font.scale(font.bbox[0]), font.scale(font.bbox[1]), font.scale(font.bbox[2]), font.scale(font.bbox[3]),
+		font.scale(font.ascent), font.scale(font.descent), font.scale(font.capHeight), fileID)
+	w.setStream(fileID, fmt.Sprintf("/Length1 %d", len(font.data)), font.data)
+	w.setStream(toUnicodeID, "", toUnicodeCMap(embedded.unicode, glyphs))
+
+	return fontID
+}
+
+// toUnicodeCMap maps glyph IDs back to the characters they were laid out from
+func toUnicodeCMap(unicode map[uint16]rune, glyphs []int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// A bfchar block holds at most 100 mappings
+	for start := 0; start < len(glyphs); start += 100 {
+		end := min(start+100, len(glyphs))
+		fmt.Fprintf(&buf, "%d beginbfchar\n", end-start)
+		for _, glyph := range glyphs[start:end] {
+			fmt.Fprintf(&buf, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{unicode[uint16(glyph)]}) {
+				fmt.Fprintf(&buf, "%04X", unit)
+			}
+			buf.WriteString(">\n")
+		}
+		buf.WriteString("endbfchar\n")
+	}
+
+	buf.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return buf.Bytes()
+}
+
+// pdfName keeps the characters of a font name that are allowed in a PDF name as they are
+func pdfName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r > 0x20 && r < 0x7f && !strings.ContainsRune("/()<>[]{}%#", r) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "Font"
+	}
+	return b.String()
+}
@@ -82,21 +82,30 @@ func (bh *BookingHandler) CreateBooking(ctx *gin.Context) {
 	handleSuccess(ctx, rsp)
 }
 
+// createBookingAndPaymentRequest represents the request body for creating a booking with an optional deposit
+type createBookingAndPaymentRequest struct {
+	createBookingRequest
+	DepositAmount float64              `json:"deposit_amount" binding:"omitempty,gt=0" example:"500.00"`
+	DepositMethod domain.PaymentMethod `json:"deposit_method" example:"3"`
+}
+
 // CreateBookingAndPayment godoc
 //
 //	@Summary		Create a new booking with payment
-//	@Description	Create a new booking for a customer with payment details
+//	@Description	Create a new booking for a customer with an unpaid balance payment, optionally taking a cash or bank transfer deposit up front
 //	@Tags			Bookings
 //	@Accept			json
 //	@Produce		json
-//	@Param			createBookingRequest	body		createBookingRequest	true	"Create booking request"
+//	@Param			createBookingAndPaymentRequest	body		createBookingAndPaymentRequest	true	"Create booking request"
+//	@Param			Idempotency-Key	header		string				false	"Key that makes retries replay the first response"
 //	@Success		200					{object}	bookingResponse		"Booking created with payment"
 //	@Failure		400					{object}	errorResponse		"Validation error"
 //	@Failure		409					{object}	errorResponse		"Data conflict error"
+//	@Failure		422					{object}	errorResponse		"Idempotency key used with a different request"
 //	@Failure		500					{object}	errorResponse		"Internal server error"
 //	@Router			/bookings/payment [post]
 func (bh *BookingHandler) CreateBookingAndPayment(ctx *gin.Context) {
-	var req createBookingRequest
+	var req createBookingAndPaymentRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		validationError(ctx, err)
 		return
@@ -116,7 +125,15 @@ func (bh *BookingHandler) CreateBookingAndPayment(ctx *gin.Context) {
 		UpdatedAt:    &now,
 	}
 
-	createdBooking, err := bh.svc.CreateBookingAndPayment(ctx, &booking)
+	var deposit *domain.Payment
+	if req.DepositAmount > 0 {
+		deposit = &domain.Payment{
+			Amount:        req.DepositAmount,
+			PaymentMethod: req.DepositMethod,
+		}
+	}
+
+	createdBooking, err := bh.svc.CreateBookingAndPayment(ctx, &booking, deposit)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -357,6 +374,56 @@ func (bh *BookingHandler) GetBooking(ctx *gin.Context) {
 	handleSuccess(ctx, rsp)
 }
 
+// ListBookingStatusEvents godoc
+//
+//	@Summary		List the status history of a booking
+//	@Description	List every status a booking has been created with or moved to, oldest first
+//	@Tags			Bookings
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64							true	"Booking ID"
+//	@Success		200	{array}		bookingStatusEventResponse	"Booking status history displayed"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/bookings/{id}/status-events [get]
+//	@Security		BearerAuth
+func (bh *BookingHandler) ListBookingStatusEvents(ctx *gin.Context) {
+	var req getBookingRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	events, err := bh.svc.ListBookingStatusEvents(ctx, req.BookingID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := make([]bookingStatusEventResponse, 0, len(events))
+	for _, event := range events {
+		rsp = append(rsp, bookingStatusEventResponse{
+			ID:         event.ID,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Amount:     event.Amount,
+			ChangedAt:  event.ChangedAt,
+		})
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// bookingStatusEventResponse represents a booking status change response body
+type bookingStatusEventResponse struct {
+	ID         uint64                `json:"id" example:"1"`
+	FromStatus *domain.BookingStatus `json:"from_status" example:"2"`
+	ToStatus   domain.BookingStatus  `json:"to_status" example:"3"`
+	Amount     float64               `json:"amount" example:"3000.00"`
+	ChangedAt  time.Time             `json:"changed_at" example:"2024-08-10T11:00:00Z"`
+}
+
 // updateBookingRequest represents the request body for updating a booking
 type updateBookingRequest struct {
 	BookingID    uint64               `json:"id" binding:"required" example:"1"`
@@ -372,7 +439,7 @@ type updateBookingRequest struct {
 
 // UpdateBooking godoc
 //
-//	@Summary		Update a booking
+//	@Description	Update a booking's details by id; check-outs go through /booking/{id}/checkout
 //	@Description	Update a booking's details by id
 //	@Tags			Bookings
 //	@Accept			json
@@ -437,6 +504,7 @@ type deleteBookingRequest struct {
 //	@Success		200	{object}	response		"Booking deleted"
 //	@Failure		400	{object}	errorResponse	"Validation error"
 //	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		409	{object}	errorResponse	"Booking has payments, was completed or is in a closed day"
 //	@Failure		500	{object}	errorResponse	"Internal server error"
 //	@Router			/bookings/{id} [delete]
 //	@Security		BearerAuth
@@ -526,7 +594,8 @@ type bookingCustomerPaymentResponse struct {
 	RatePriceID       uint64               `json:"rate_price_id"`
 	RoomTypeID        uint64               `json:"room_type_id"`
 	RoomTypeName      string               `json:"room_type_name"`
-	PaymentID         *uint64              `json:"payment_id"`
+	PaidAmount        float64              `json:"paid_amount"`
+	Balance           float64              `json:"balance"`
 	PaymentStatus     *uint64              `json:"payment_status"`
 	PaymentUpdateDate *string              `json:"payment_update_date"`
 }
@@ -552,6 +621,8 @@ func newBookingCustomerPaymentResponse(bcp *domain.BookingCustomerPayment) (*boo
 		RatePriceID:       bcp.RatePriceID,
 		RoomTypeID:        bcp.RoomTypeID,
 		RoomTypeName:      bcp.RoomTypeName,
+		PaidAmount:        bcp.PaidAmount,
+		Balance:           bcp.Balance,
 		PaymentStatus:     bcp.PaymentStatus,
 	}
 
@@ -567,9 +638,6 @@ func newBookingCustomerPaymentResponse(bcp *domain.BookingCustomerPayment) (*boo
 	if bcp.BookingUpdatedAt != nil {
 		response.BookingUpdatedAt = bcp.BookingUpdatedAt.Format(time.RFC3339)
 	}
-	if bcp.PaymentID != nil {
-		response.PaymentID = bcp.PaymentID
-	}
 	if bcp.PaymentStatus != nil {
 		response.PaymentStatus = bcp.PaymentStatus
 	}
@@ -707,6 +775,39 @@ func (bh *BookingHandler) ListBookingCustomerPaymentsWithFilter(ctx *gin.Context
 		return
 	}
 
+	booking := bookingCustomerPaymentFilter(ctx)
+
+	bookings, totalCount, err := bh.svc.ListBookingCustomerPaymentsWithFilter(ctx, booking, skipUint, limitUint)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, booking := range bookings {
+		rsp, err := newBookingCustomerPaymentResponse(&booking)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		bookingsList = append(bookingsList, *rsp)
+	}
+
+	meta := map[string]interface{}{
+		"total": totalCount,
+		"limit": limitUint,
+		"skip":  skipUint,
+	}
+	
+	rsp := map[string]interface{}{
+		"booking_customer_payments": bookingsList,
+		"meta":     meta,
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// bookingCustomerPaymentFilter reads the booking list filters from the query, leaving out any that do not parse
+func bookingCustomerPaymentFilter(ctx *gin.Context) *domain.BookingCustomerPayment {
 	// Initialize booking with nil values
 	booking := &domain.BookingCustomerPayment{}
 	if bookingID := ctx.Query("booking_id"); bookingID != "" {
@@ -762,30 +863,57 @@ func (bh *BookingHandler) ListBookingCustomerPaymentsWithFilter(ctx *gin.Context
 		}
 	}
 
-	bookings, totalCount, err := bh.svc.ListBookingCustomerPaymentsWithFilter(ctx, booking, skipUint, limitUint)
-	if err != nil {
-		handleError(ctx, err)
+	return booking
+}
+
+
+// checkOutBookingRequest represents the request body for checking out a booking
+type checkOutBookingRequest struct {
+	Override  bool   `form:"override" example:"false"`
+	CompanyID uint64 `form:"company_id" example:"1"`
+}
+
+// CheckOutBooking godoc
+//
+//	@Summary		Check out a booking
+//	@Description	Check out a checked-in booking. Fails while the folio has a balance unless it is billed to a company or an admin overrides it.
+//	@Tags			Bookings
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		uint64			true	"Booking ID"
+//	@Param			override	query		bool			false	"Override an unsettled balance (admin only)"
+//	@Param			company_id	query		uint64			false	"Bill the balance of this company payer's share to the company's ledger"
+//	@Success		200			{object}	bookingResponse	"Booking checked out"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		403			{object}	errorResponse	"Forbidden error"
+//	@Failure		404			{object}	errorResponse	"Data not found error"
+//	@Failure		409			{object}	errorResponse	"Folio not settled or company credit limit exceeded"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/booking/{id}/checkout [put]
+//	@Security		BearerAuth
+func (bh *BookingHandler) CheckOutBooking(ctx *gin.Context) {
+	var uri getBookingRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
 		return
 	}
 
-	for _, booking := range bookings {
-		rsp, err := newBookingCustomerPaymentResponse(&booking)
-		if err != nil {
-			handleError(ctx, err)
-			return
-		}
-		bookingsList = append(bookingsList, *rsp)
+	var req checkOutBookingRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
 	}
 
-	meta := map[string]interface{}{
-		"total": totalCount,
-		"limit": limitUint,
-		"skip":  skipUint,
+	booking, err := bh.svc.CheckOutBooking(ctx, uri.BookingID, req.CompanyID, req.Override)
+	if err != nil {
+		handleError(ctx, err)
+		return
 	}
-	
-	rsp := map[string]interface{}{
-		"booking_customer_payments": bookingsList,
-		"meta":     meta,
+
+	rsp, err := newBookingResponse(booking)
+	if err != nil {
+		handleError(ctx, err)
+		return
 	}
 
 	handleSuccess(ctx, rsp)
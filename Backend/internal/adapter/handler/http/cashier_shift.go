@@ -0,0 +1,301 @@
+package http
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// CashierShiftHandler represents the HTTP handler for cashier shifts and cash drawer reconciliation
+type CashierShiftHandler struct {
+	svc port.CashierShiftService
+}
+
+// NewCashierShiftHandler creates a new CashierShiftHandler instance
+func NewCashierShiftHandler(svc port.CashierShiftService) *CashierShiftHandler {
+	return &CashierShiftHandler{
+		svc,
+	}
+}
+
+// openShiftRequest represents the request body for opening a cashier shift
+type openShiftRequest struct {
+	OpeningFloat float64 `json:"opening_float" binding:"min=0" example:"5000.00"`
+}
+
+// OpenShift godoc
+//
+//	@Summary		Open a cashier shift
+//	@Description	Open a shift for the current user with the cash float placed in the drawer
+//	@Tags			Cashier Shifts
+//	@Accept			json
+//	@Produce		json
+//	@Param			openShiftRequest	body		openShiftRequest		true	"Open shift request"
+//	@Success		200					{object}	cashierShiftResponse	"Shift opened"
+//	@Failure		400					{object}	errorResponse			"Validation error"
+//	@Failure		409					{object}	errorResponse			"A shift is already open"
+//	@Failure		500					{object}	errorResponse			"Internal server error"
+//	@Router			/shifts/open [post]
+//	@Security		BearerAuth
+func (csh *CashierShiftHandler) OpenShift(ctx *gin.Context) {
+	var req openShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	shift, err := csh.svc.OpenShift(ctx, req.OpeningFloat)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCashierShiftResponse(shift)
+
+	handleSuccess(ctx, rsp)
+}
+
+// GetCurrentShift godoc
+//
+//	@Summary		Get the current cashier shift
+//	@Description	Get the open shift of the current user
+//	@Tags			Cashier Shifts
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	cashierShiftResponse	"Shift displayed"
+//	@Failure		404	{object}	errorResponse			"No open shift"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/shifts/current [get]
+//	@Security		BearerAuth
+func (csh *CashierShiftHandler) GetCurrentShift(ctx *gin.Context) {
+	shift, err := csh.svc.GetCurrentShift(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCashierShiftResponse(shift)
+
+	handleSuccess(ctx, rsp)
+}
+
+// shiftCountRequest represents the amount counted for one payment method and currency at close
+type shiftCountRequest struct {
+	PaymentMethod domain.PaymentMethod `json:"payment_method" binding:"required,min=1" example:"3"`
+	Currency      string               `json:"currency" binding:"omitempty,len=3" example:"THB"`
+	Counted       float64              `json:"counted" binding:"min=0" example:"12500.00"`
+}
+
+// closeShiftRequest represents the request body for closing a cashier shift
+type closeShiftRequest struct {
+	Counts []shiftCountRequest `json:"counts" binding:"dive"`
+	Note   string              `json:"note" example:"Short 20 THB, change given twice"`
+}
+
+// CloseShift godoc
+//
+//	@Summary		Close the current cashier shift
+//	@Description	Close the current user's shift with the amounts counted per payment method and currency and record the variances.
+//	@Description	Amounts are in the currency counted, THB when none is given. Payment methods and currencies that are not counted are recorded as zero.
+//	@Tags			Cashier Shifts
+//	@Accept			json
+//	@Produce		json
+//	@Param			closeShiftRequest	body		closeShiftRequest	true	"Close shift request"
+//	@Success		200					{object}	shiftReportResponse	"Shift closed"
+//	@Failure		400					{object}	errorResponse		"Validation error"
+//	@Failure		404					{object}	errorResponse		"No open shift"
+//	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Router			/shifts/close [post]
+//	@Security		BearerAuth
+func (csh *CashierShiftHandler) CloseShift(ctx *gin.Context) {
+	var req closeShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	counted := make(map[domain.ShiftTender]float64)
+	for _, count := range req.Counts {
+		currency := strings.ToUpper(count.Currency)
+		if currency == "" {
+			currency = domain.BaseCurrency
+		}
+		counted[domain.ShiftTender{PaymentMethod: count.PaymentMethod, Currency: currency}] += count.Counted
+	}
+
+	report, err := csh.svc.CloseShift(ctx, counted, req.Note)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newShiftReportResponse(report)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listShiftsRequest represents the request body for listing cashier shifts
+type listShiftsRequest struct {
+	UserID uint64 `form:"user_id" binding:"min=0" example:"1"`
+	Skip   uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit  uint64 `form:"limit" binding:"required,min=1" example:"10"`
+}
+
+// ListShifts godoc
+//
+//	@Summary		List cashier shifts
+//	@Description	List cashier shifts, newest first. Only admins can see other users' shifts.
+//	@Tags			Cashier Shifts
+//	@Accept			json
+//	@Produce		json
+//	@Param			user_id	query		uint64			false	"User ID"
+//	@Param			skip	query		uint64			false	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Shifts displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/shifts [get]
+//	@Security		BearerAuth
+func (csh *CashierShiftHandler) ListShifts(ctx *gin.Context) {
+	var req listShiftsRequest
+	var shiftsList []cashierShiftResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	shifts, totalCount, err := csh.svc.ListShifts(ctx, req.UserID, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, shift := range shifts {
+		shiftsList = append(shiftsList, newCashierShiftResponse(&shift))
+	}
+
+	meta := newMeta(totalCount, req.Limit, req.Skip)
+	rsp := toMap(meta, shiftsList, "shifts")
+
+	handleSuccess(ctx, rsp)
+}
+
+// getShiftReportRequest represents the request body for getting a shift report
+type getShiftReportRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// GetShiftReport godoc
+//
+//	@Summary		Get a shift close-out report
+//	@Description	Get the expected and counted amounts per payment method of a shift
+//	@Tags			Cashier Shifts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64				true	"Shift ID"
+//	@Success		200	{object}	shiftReportResponse	"Report displayed"
+//	@Failure		400	{object}	errorResponse		"Validation error"
+//	@Failure		403	{object}	errorResponse		"Forbidden error"
+//	@Failure		404	{object}	errorResponse		"Data not found error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/shifts/{id}/report [get]
+//	@Security		BearerAuth
+func (csh *CashierShiftHandler) GetShiftReport(ctx *gin.Context) {
+	var req getShiftReportRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	report, err := csh.svc.GetShiftReport(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newShiftReportResponse(report)
+
+	handleSuccess(ctx, rsp)
+}
+
+// cashierShiftResponse represents a cashier shift response body
+type cashierShiftResponse struct {
+	ID           uint64             `json:"id" example:"1"`
+	UserID       uint64             `json:"user_id" example:"1"`
+	Status       domain.ShiftStatus `json:"status" example:"1"`
+	OpeningFloat float64            `json:"opening_float" example:"5000.00"`
+	OpenedAt     *time.Time         `json:"opened_at" example:"2024-08-01T07:00:00Z"`
+	ClosedAt     *time.Time         `json:"closed_at" example:"2024-08-01T15:00:00Z"`
+	ClosingNote  string             `json:"closing_note" example:"Short 20 THB, change given twice"`
+}
+
+// newCashierShiftResponse creates a new cashier shift response
+func newCashierShiftResponse(shift *domain.CashierShift) cashierShiftResponse {
+	return cashierShiftResponse{
+		ID:           shift.ID,
+		UserID:       shift.UserID,
+		Status:       shift.Status,
+		OpeningFloat: shift.OpeningFloat,
+		OpenedAt:     shift.OpenedAt,
+		ClosedAt:     shift.ClosedAt,
+		ClosingNote:  shift.ClosingNote,
+	}
+}
+
+// shiftCountResponse represents the expected and counted amount of one payment method in one currency
+type shiftCountResponse struct {
+	PaymentMethod domain.PaymentMethod `json:"payment_method" example:"3"`
+	Currency      string               `json:"currency" example:"THB"`
+	Expected      float64              `json:"expected" example:"12520.00"`
+	Counted       float64              `json:"counted" example:"12500.00"`
+	Variance      float64              `json:"variance" example:"-20.00"`
+}
+
+// shiftCurrencyTotalResponse represents the counts of a shift in one currency added up
+type shiftCurrencyTotalResponse struct {
+	Currency string  `json:"currency" example:"THB"`
+	Expected float64 `json:"expected" example:"30520.00"`
+	Counted  float64 `json:"counted" example:"30500.00"`
+	Variance float64 `json:"variance" example:"-20.00"`
+}
+
+// shiftReportResponse represents a shift close-out report response body
+type shiftReportResponse struct {
+	Shift  cashierShiftResponse         `json:"shift"`
+	Counts []shiftCountResponse         `json:"counts"`
+	Totals []shiftCurrencyTotalResponse `json:"totals"`
+}
+
+// newShiftReportResponse creates a new shift report response
+func newShiftReportResponse(report *domain.ShiftReport) shiftReportResponse {
+	counts := make([]shiftCountResponse, 0, len(report.Counts))
+	for _, count := range report.Counts {
+		counts = append(counts, shiftCountResponse{
+			PaymentMethod: count.PaymentMethod,
+			Currency:      count.Currency,
+			Expected:      count.Expected,
+			Counted:       count.Counted,
+			Variance:      count.Variance,
+		})
+	}
+
+	totals := make([]shiftCurrencyTotalResponse, 0, len(report.Totals))
+	for _, total := range report.Totals {
+		totals = append(totals, shiftCurrencyTotalResponse{
+			Currency: total.Currency,
+			Expected: total.Expected,
+			Counted:  total.Counted,
+			Variance: total.Variance,
+		})
+	}
+
+	return shiftReportResponse{
+		Shift:  newCashierShiftResponse(&report.Shift),
+		Counts: counts,
+		Totals: totals,
+	}
+}
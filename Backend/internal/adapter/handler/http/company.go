@@ -0,0 +1,499 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// CompanyHandler represents the HTTP handler for corporate accounts and their receivables
+type CompanyHandler struct {
+	svc port.CompanyService
+}
+
+// NewCompanyHandler creates a new CompanyHandler instance
+func NewCompanyHandler(svc port.CompanyService) *CompanyHandler {
+	return &CompanyHandler{
+		svc,
+	}
+}
+
+// createCompanyRequest represents the request body for creating a company account
+type createCompanyRequest struct {
+	Name            string  `json:"name" binding:"required" example:"Siam Trading Co., Ltd."`
+	TaxID           string  `json:"tax_id" example:"0105551234567"`
+	Address         string  `json:"address" example:"99 Sukhumvit Rd, Bangkok"`
+	Email           string  `json:"email" binding:"omitempty,email" example:"accounts@siamtrading.co.th"`
+	Phone           string  `json:"phone" example:"+66 2 123 4567"`
+	ContactName     string  `json:"contact_name" example:"Somchai P."`
+	CreditLimit     float64 `json:"credit_limit" binding:"min=0" example:"100000.00"`
+	PaymentTermDays int     `json:"payment_term_days" binding:"min=0" example:"30"`
+}
+
+// CreateCompany godoc
+//
+//	@Summary		Create a company account
+//	@Description	Create a corporate account that can be billed for bookings at checkout
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			createCompanyRequest	body		createCompanyRequest	true	"Create company request"
+//	@Success		200						{object}	companyResponse			"Company created"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		409						{object}	errorResponse			"Data conflict error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/companies [post]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) CreateCompany(ctx *gin.Context) {
+	var req createCompanyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	company := domain.Company{
+		Name:            req.Name,
+		TaxID:           req.TaxID,
+		Address:         req.Address,
+		Email:           req.Email,
+		Phone:           req.Phone,
+		ContactName:     req.ContactName,
+		CreditLimit:     req.CreditLimit,
+		PaymentTermDays: req.PaymentTermDays,
+		IsActive:        true,
+	}
+	if company.PaymentTermDays == 0 {
+		company.PaymentTermDays = 30
+	}
+
+	createdCompany, err := ch.svc.CreateCompany(ctx, &company)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCompanyResponse(createdCompany)
+
+	handleSuccess(ctx, rsp)
+}
+
+// getCompanyRequest represents the request body for getting a company account
+type getCompanyRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// GetCompany godoc
+//
+//	@Summary		Get a company account
+//	@Description	Get a company account by id
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Company ID"
+//	@Success		200	{object}	companyResponse	"Company displayed"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/companies/{id} [get]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) GetCompany(ctx *gin.Context) {
+	var req getCompanyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	company, err := ch.svc.GetCompany(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCompanyResponse(company)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listCompaniesRequest represents the request body for listing company accounts
+type listCompaniesRequest struct {
+	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=1" example:"10"`
+}
+
+// ListCompanies godoc
+//
+//	@Summary		List company accounts
+//	@Description	List company accounts with pagination, by name
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		uint64			false	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Companies displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/companies [get]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) ListCompanies(ctx *gin.Context) {
+	var req listCompaniesRequest
+	var companiesList []companyResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	companies, totalCount, err := ch.svc.ListCompanies(ctx, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, company := range companies {
+		companiesList = append(companiesList, newCompanyResponse(&company))
+	}
+
+	meta := newMeta(totalCount, req.Limit, req.Skip)
+	rsp := toMap(meta, companiesList, "companies")
+
+	handleSuccess(ctx, rsp)
+}
+
+// updateCompanyRequest represents the request body for updating a company account
+type updateCompanyRequest struct {
+	ID              uint64  `json:"id" binding:"required" example:"1"`
+	Name            string  `json:"name" binding:"required" example:"Siam Trading Co., Ltd."`
+	TaxID           string  `json:"tax_id" example:"0105551234567"`
+	Address         string  `json:"address" example:"99 Sukhumvit Rd, Bangkok"`
+	Email           string  `json:"email" binding:"omitempty,email" example:"accounts@siamtrading.co.th"`
+	Phone           string  `json:"phone" example:"+66 2 123 4567"`
+	ContactName     string  `json:"contact_name" example:"Somchai P."`
+	CreditLimit     float64 `json:"credit_limit" binding:"min=0" example:"100000.00"`
+	PaymentTermDays int     `json:"payment_term_days" binding:"min=0" example:"30"`
+	IsActive        bool    `json:"is_active" example:"true"`
+}
+
+// UpdateCompany godoc
+//
+//	@Summary		Update a company account
+//	@Description	Update a company account. Changing the credit limit requires an admin.
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			updateCompanyRequest	body		updateCompanyRequest	true	"Update company request"
+//	@Success		200						{object}	companyResponse			"Company updated"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		409						{object}	errorResponse			"Data conflict error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/companies [put]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) UpdateCompany(ctx *gin.Context) {
+	var req updateCompanyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	company := domain.Company{
+		ID:              req.ID,
+		Name:            req.Name,
+		TaxID:           req.TaxID,
+		Address:         req.Address,
+		Email:           req.Email,
+		Phone:           req.Phone,
+		ContactName:     req.ContactName,
+		CreditLimit:     req.CreditLimit,
+		PaymentTermDays: req.PaymentTermDays,
+		IsActive:        req.IsActive,
+	}
+
+	updatedCompany, err := ch.svc.UpdateCompany(ctx, &company)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCompanyResponse(updatedCompany)
+
+	handleSuccess(ctx, rsp)
+}
+
+// GetLedger godoc
+//
+//	@Summary		Get a company ledger
+//	@Description	Get the accounts receivable entries and outstanding balance of a company
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64					true	"Company ID"
+//	@Success		200	{object}	companyLedgerResponse	"Ledger displayed"
+//	@Failure		400	{object}	errorResponse			"Validation error"
+//	@Failure		404	{object}	errorResponse			"Data not found error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/companies/{id}/ledger [get]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) GetLedger(ctx *gin.Context) {
+	var req getCompanyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	ledger, err := ch.svc.GetLedger(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := companyLedgerResponse{
+		Company: newCompanyResponse(&ledger.Company),
+		Entries: []ledgerEntryResponse{},
+		Balance: ledger.Balance,
+	}
+	for _, entry := range ledger.Entries {
+		rsp.Entries = append(rsp.Entries, newLedgerEntryResponse(&entry))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// ListOpenInvoices godoc
+//
+//	@Summary		List a company's outstanding invoices
+//	@Description	List the company ledger invoices that are not fully paid, oldest first
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64					true	"Company ID"
+//	@Success		200	{array}		companyInvoiceResponse	"Invoices displayed"
+//	@Failure		400	{object}	errorResponse			"Validation error"
+//	@Failure		404	{object}	errorResponse			"Data not found error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/companies/{id}/invoices [get]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) ListOpenInvoices(ctx *gin.Context) {
+	var req getCompanyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	invoices, err := ch.svc.ListOpenInvoices(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []companyInvoiceResponse{}
+	for _, invoice := range invoices {
+		rsp = append(rsp, companyInvoiceResponse{
+			ledgerEntryResponse: newLedgerEntryResponse(&invoice.CompanyLedgerEntry),
+			Paid:                invoice.Paid,
+			Outstanding:         invoice.Outstanding,
+		})
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// recordCompanyPaymentRequest represents the request body for recording a company payment
+type recordCompanyPaymentRequest struct {
+	InvoiceEntryID uint64               `json:"invoice_entry_id" binding:"required,min=1" example:"12"`
+	Amount         float64              `json:"amount" binding:"required,gt=0" example:"5600.00"`
+	PaymentMethod  domain.PaymentMethod `json:"payment_method" binding:"required,min=1" example:"4"`
+	Reference      string               `json:"reference" example:"Transfer KBANK 2024-09-01"`
+}
+
+// RecordPayment godoc
+//
+//	@Summary		Record a company payment
+//	@Description	Record money received from a company against one of its outstanding invoices
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			id							path		uint64						true	"Company ID"
+//	@Param			recordCompanyPaymentRequest	body		recordCompanyPaymentRequest	true	"Record payment request"
+//	@Success		200							{object}	ledgerEntryResponse			"Payment recorded"
+//	@Failure		400							{object}	errorResponse				"Validation error"
+//	@Failure		404							{object}	errorResponse				"Data not found error"
+//	@Failure		500							{object}	errorResponse				"Internal server error"
+//	@Router			/companies/{id}/payments [post]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) RecordPayment(ctx *gin.Context) {
+	var uri getCompanyRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req recordCompanyPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	entry, err := ch.svc.RecordPayment(ctx, uri.ID, req.InvoiceEntryID, req.Amount, req.PaymentMethod, req.Reference)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newLedgerEntryResponse(entry)
+
+	handleSuccess(ctx, rsp)
+}
+
+// agingReportRequest represents the request body for the accounts receivable aging report
+type agingReportRequest struct {
+	AsOf string `form:"as_of" example:"2024-09-30"`
+}
+
+// GetAgingReport godoc
+//
+//	@Summary		Accounts receivable aging
+//	@Description	Outstanding company balances bucketed by invoice age (0-30, 31-60, 61-90 and over 90 days) as of a date, today by default
+//	@Tags			Companies
+//	@Accept			json
+//	@Produce		json
+//	@Param			as_of	query		string				false	"As-of date (YYYY-MM-DD)"
+//	@Success		200		{array}		agingRowResponse	"Aging report"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/companies/aging [get]
+//	@Security		BearerAuth
+func (ch *CompanyHandler) GetAgingReport(ctx *gin.Context) {
+	var req agingReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	asOf := time.Now()
+	if req.AsOf != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.AsOf, time.Local)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+		// Include everything posted on the as-of date
+		asOf = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	rows, err := ch.svc.GetAgingReport(ctx, asOf)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []agingRowResponse{}
+	for _, row := range rows {
+		rsp = append(rsp, agingRowResponse{
+			CompanyID:   row.CompanyID,
+			CompanyName: row.CompanyName,
+			Days0To30:   row.Days0To30,
+			Days31To60:  row.Days31To60,
+			Days61To90:  row.Days61To90,
+			Over90Days:  row.Over90Days,
+			Total:       row.Total,
+		})
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// companyResponse represents the response body for a company account
+type companyResponse struct {
+	ID              uint64     `json:"id" example:"1"`
+	Name            string     `json:"name" example:"Siam Trading Co., Ltd."`
+	TaxID           string     `json:"tax_id" example:"0105551234567"`
+	Address         string     `json:"address" example:"99 Sukhumvit Rd, Bangkok"`
+	Email           string     `json:"email" example:"accounts@siamtrading.co.th"`
+	Phone           string     `json:"phone" example:"+66 2 123 4567"`
+	ContactName     string     `json:"contact_name" example:"Somchai P."`
+	CreditLimit     float64    `json:"credit_limit" example:"100000.00"`
+	PaymentTermDays int        `json:"payment_term_days" example:"30"`
+	IsActive        bool       `json:"is_active" example:"true"`
+	CreatedAt       *time.Time `json:"created_at" example:"2024-08-01T15:04:05Z"`
+	UpdatedAt       *time.Time `json:"updated_at" example:"2024-08-01T15:04:05Z"`
+}
+
+// newCompanyResponse creates a new company response
+func newCompanyResponse(company *domain.Company) companyResponse {
+	return companyResponse{
+		ID:              company.ID,
+		Name:            company.Name,
+		TaxID:           company.TaxID,
+		Address:         company.Address,
+		Email:           company.Email,
+		Phone:           company.Phone,
+		ContactName:     company.ContactName,
+		CreditLimit:     company.CreditLimit,
+		PaymentTermDays: company.PaymentTermDays,
+		IsActive:        company.IsActive,
+		CreatedAt:       company.CreatedAt,
+		UpdatedAt:       company.UpdatedAt,
+	}
+}
+
+// ledgerEntryResponse represents the response body for a company ledger entry
+type ledgerEntryResponse struct {
+	ID            uint64                 `json:"id" example:"12"`
+	CompanyID     uint64                 `json:"company_id" example:"1"`
+	EntryType     domain.LedgerEntryType `json:"entry_type" example:"1"`
+	BookingID     *uint64                `json:"booking_id" example:"1"`
+	PaymentID     *uint64                `json:"payment_id" example:"7"`
+	AppliedToID   *uint64                `json:"applied_to_id" example:"12"`
+	Reference     string                 `json:"reference" example:"Booking #1"`
+	Amount        float64                `json:"amount" example:"5600.00"`
+	PaymentMethod domain.PaymentMethod   `json:"payment_method" example:"0"`
+	EntryDate     *time.Time             `json:"entry_date" example:"2024-08-01T15:04:05Z"`
+	DueDate       *time.Time             `json:"due_date" example:"2024-08-31T15:04:05Z"`
+	PostedBy      *uint64                `json:"posted_by" example:"1"`
+}
+
+// newLedgerEntryResponse creates a new ledger entry response
+func newLedgerEntryResponse(entry *domain.CompanyLedgerEntry) ledgerEntryResponse {
+	return ledgerEntryResponse{
+		ID:            entry.ID,
+		CompanyID:     entry.CompanyID,
+		EntryType:     entry.EntryType,
+		BookingID:     entry.BookingID,
+		PaymentID:     entry.PaymentID,
+		AppliedToID:   entry.AppliedToID,
+		Reference:     entry.Reference,
+		Amount:        entry.Amount,
+		PaymentMethod: entry.PaymentMethod,
+		EntryDate:     entry.EntryDate,
+		DueDate:       entry.DueDate,
+		PostedBy:      entry.PostedBy,
+	}
+}
+
+// companyLedgerResponse represents the response body for a company ledger
+type companyLedgerResponse struct {
+	Company companyResponse       `json:"company"`
+	Entries []ledgerEntryResponse `json:"entries"`
+	Balance float64               `json:"balance" example:"5600.00"`
+}
+
+// companyInvoiceResponse represents the response body for an outstanding company invoice
+type companyInvoiceResponse struct {
+	ledgerEntryResponse
+	Paid        float64 `json:"paid" example:"2000.00"`
+	Outstanding float64 `json:"outstanding" example:"3600.00"`
+}
+
+// agingRowResponse represents one company of the accounts receivable aging report
+type agingRowResponse struct {
+	CompanyID   uint64  `json:"company_id" example:"1"`
+	CompanyName string  `json:"company_name" example:"Siam Trading Co., Ltd."`
+	Days0To30   float64 `json:"days_0_30" example:"3600.00"`
+	Days31To60  float64 `json:"days_31_60" example:"0"`
+	Days61To90  float64 `json:"days_61_90" example:"0"`
+	Over90Days  float64 `json:"over_90_days" example:"0"`
+	Total       float64 `json:"total" example:"3600.00"`
+}
@@ -132,6 +132,238 @@ func (ch *CustomerHandler) ListCustomers(ctx *gin.Context) {
         return
     }
 
+	customer := customerFilter(ctx)
+
+	customers, totalCount, err := ch.svc.ListCustomersWithFilter(ctx, customer, skipUint, limitUint)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, customer := range customers {
+		customerResponse, err := newCustomerResponse(&customer)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		customersList = append(customersList, customerResponse)
+	}
+
+	meta := map[string]interface{}{
+		"total": totalCount,
+		"limit": limitUint,
+		"skip":  skipUint,
+	}
+	rsp := map[string]interface{}{
+		"customers": customersList,
+		"meta":     meta,
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// searchCustomersRequest represents the request body for searching customers
+type searchCustomersRequest struct {
+	Query string `form:"q" binding:"required" example:"somchai"`
+	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=1,max=100" example:"10"`
+}
+
+// customerMatchResponse represents a customer found by a search
+type customerMatchResponse struct {
+	customerResponse
+	Relevance float64 `json:"relevance" example:"0.9"`
+}
+
+// SearchCustomers godoc
+//
+//	@Summary		Search customers
+//	@Description	Search customers by name, phone, email or identity number, ignoring case and extra spaces.
+//	@Description	Names match by prefix, substring or close spelling in Thai or Latin script, and phones by
+//	@Description	their digits. The most relevant customers come first.
+//	@Tags			Customers
+//	@Accept			json
+//	@Produce		json
+//	@Param			q		query		string			true	"Search text"
+//	@Param			skip	query		uint64			false	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Customers displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/customers/search [get]
+//	@Security		BearerAuth
+func (ch *CustomerHandler) SearchCustomers(ctx *gin.Context) {
+	var req searchCustomersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	matches, totalCount, err := ch.svc.SearchCustomers(ctx, req.Query, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	customersList := make([]customerMatchResponse, 0, len(matches))
+	for _, match := range matches {
+		customerResponse, err := newCustomerResponse(&match.Customer)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		customersList = append(customersList, customerMatchResponse{customerResponse, match.Relevance})
+	}
+
+	meta := map[string]interface{}{
+		"total": totalCount,
+		"limit": req.Limit,
+		"skip":  req.Skip,
+	}
+	rsp := map[string]interface{}{
+		"customers": customersList,
+		"meta":      meta,
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// listCustomerDuplicatesRequest represents the request body for listing likely duplicate customers
+type listCustomerDuplicatesRequest struct {
+	MinScore float64 `form:"min_score" binding:"omitempty,gt=0,max=1" example:"0.5"`
+	Skip     uint64  `form:"skip" binding:"min=0" example:"0"`
+	Limit    uint64  `form:"limit" binding:"required,min=1,max=100" example:"10"`
+}
+
+// customerDuplicateResponse represents a pair of customers likely to be the same person
+type customerDuplicateResponse struct {
+	Customer       customerResponse `json:"customer"`
+	Duplicate      customerResponse `json:"duplicate"`
+	Score          float64          `json:"score" example:"0.9"`
+	SameIdentity   bool             `json:"same_identity" example:"false"`
+	SamePhone      bool             `json:"same_phone" example:"true"`
+	SameEmail      bool             `json:"same_email" example:"false"`
+	NameSimilarity float64          `json:"name_similarity" example:"0.75"`
+}
+
+// ListCustomerDuplicates godoc
+//
+//	@Summary		List likely duplicate customers
+//	@Description	List pairs of customers likely to be the same person, scored from 0 to 1 by a shared identity
+//	@Description	number, phone or email and by name similarity, highest first. The older customer of a pair comes
+//	@Description	first. Only admins can list them.
+//	@Tags			Customers
+//	@Accept			json
+//	@Produce		json
+//	@Param			min_score	query		number			false	"Lowest score listed (default 0.5)"
+//	@Param			skip		query		uint64			false	"Skip"
+//	@Param			limit		query		uint64			true	"Limit"
+//	@Success		200			{object}	meta			"Duplicates displayed"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		403			{object}	errorResponse	"Forbidden error"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/customers/duplicates [get]
+//	@Security		BearerAuth
+func (ch *CustomerHandler) ListCustomerDuplicates(ctx *gin.Context) {
+	var req listCustomerDuplicatesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+	if req.MinScore == 0 {
+		req.MinScore = domain.DefaultDuplicateScore
+	}
+
+	duplicates, totalCount, err := ch.svc.ListCustomerDuplicates(ctx, req.MinScore, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	duplicatesList := make([]customerDuplicateResponse, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		customer, err := newCustomerResponse(&duplicate.Customer)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		other, err := newCustomerResponse(&duplicate.Duplicate)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		duplicatesList = append(duplicatesList, customerDuplicateResponse{
+			Customer:       customer,
+			Duplicate:      other,
+			Score:          duplicate.Score,
+			SameIdentity:   duplicate.SameIdentity,
+			SamePhone:      duplicate.SamePhone,
+			SameEmail:      duplicate.SameEmail,
+			NameSimilarity: duplicate.NameSimilarity,
+		})
+	}
+
+	meta := map[string]interface{}{
+		"total": totalCount,
+		"limit": req.Limit,
+		"skip":  req.Skip,
+	}
+	rsp := map[string]interface{}{
+		"duplicates": duplicatesList,
+		"meta":       meta,
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// mergeCustomersRequest represents the request body for merging customers
+type mergeCustomersRequest struct {
+	SurvivorID  uint64 `json:"survivor_id" binding:"required,min=1" example:"1"`
+	DuplicateID uint64 `json:"duplicate_id" binding:"required,min=1,nefield=SurvivorID" example:"2"`
+}
+
+// MergeCustomers godoc
+//
+//	@Summary		Merge duplicate customers
+//	@Description	Merge a duplicate customer into the surviving one. The duplicate's bookings, with their payments,
+//	@Description	and its booking payers move to the survivor, the survivor's blank details are filled from the
+//	@Description	duplicate, and the duplicate is deleted. Only admins can merge customers.
+//	@Tags			Customers
+//	@Accept			json
+//	@Produce		json
+//	@Param			mergeCustomersRequest	body		mergeCustomersRequest	true	"Merge customers request"
+//	@Success		200						{object}	customerResponse		"Customers merged"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		409						{object}	errorResponse			"Data conflict error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/customers/merge [post]
+//	@Security		BearerAuth
+func (ch *CustomerHandler) MergeCustomers(ctx *gin.Context) {
+	var req mergeCustomersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	customer, err := ch.svc.MergeCustomers(ctx, req.SurvivorID, req.DuplicateID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp, err := newCustomerResponse(customer)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// customerFilter reads the customer list filters from the query, leaving out any that do not parse
+func customerFilter(ctx *gin.Context) *domain.Customer {
 	// Initialize customer with nil values
 	customer := &domain.Customer{}
 	if id := ctx.Query("id"); id != "" {
@@ -179,32 +411,7 @@ func (ch *CustomerHandler) ListCustomers(ctx *gin.Context) {
 		}
 	}
 
-	customers, totalCount, err := ch.svc.ListCustomersWithFilter(ctx, customer, skipUint, limitUint)
-	if err != nil {
-		handleError(ctx, err)
-		return
-	}
-
-	for _, customer := range customers {
-		customerResponse, err := newCustomerResponse(&customer)
-		if err != nil {
-			handleError(ctx, err)
-			return
-		}
-		customersList = append(customersList, customerResponse)
-	}
-
-	meta := map[string]interface{}{
-		"total": totalCount,
-		"limit": limitUint,
-		"skip":  skipUint,
-	}
-	rsp := map[string]interface{}{
-		"customers": customersList,
-		"meta":     meta,
-	}
-
-	handleSuccess(ctx, rsp)
+	return customer
 }
 
 // getCustomerRequest represents the request body for getting a customer
@@ -248,6 +455,43 @@ func (ch *CustomerHandler) GetCustomer(ctx *gin.Context) {
 	handleSuccess(ctx, rsp)
 }
 
+// GetCustomerHistory godoc
+//
+//	@Summary		Get a customer's stay history
+//	@Description	Get every booking of a customer, latest arrival first, with its room, nights and amount paid,
+//	@Description	and their lifetime stats: nights stayed, revenue, average rate, last stay and favorite room type
+//	@Tags			Customers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64					true	"Customer ID"
+//	@Success		200	{object}	customerHistoryResponse	"Customer history displayed"
+//	@Failure		400	{object}	errorResponse			"Validation error"
+//	@Failure		404	{object}	errorResponse			"Data not found error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/customers/{id}/history [get]
+//	@Security		BearerAuth
+func (ch *CustomerHandler) GetCustomerHistory(ctx *gin.Context) {
+	var req getCustomerRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	history, err := ch.svc.GetCustomerHistory(ctx, req.CustomerID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp, err := newCustomerHistoryResponse(history)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
 // updateCustomerRequest represents the request body for updating a customer
 type updateCustomerRequest struct {
 	ID               uint64 `json:"id" binding:"required" example:"1"`
@@ -392,4 +636,73 @@ func newCustomerResponse(customer *domain.Customer) (customerResponse, error) {
 		CreatedAt:        createdAt.Format(time.RFC3339),
 		UpdatedAt:        updatedAt.Format(time.RFC3339),
 	}, nil
-}
\ No newline at end of file
+}
+
+// customerHistoryBookingResponse represents the response body for a booking in a customer's history
+type customerHistoryBookingResponse struct {
+	bookingCustomerPaymentResponse
+	Nights int `json:"nights" example:"3"`
+}
+
+// customerRoomTypeResponse represents the response body for the room type a customer stayed in most
+type customerRoomTypeResponse struct {
+	ID     uint64 `json:"id" example:"2"`
+	Name   string `json:"name" example:"Deluxe"`
+	Nights int    `json:"nights" example:"9"`
+}
+
+// customerStatsResponse represents the response body for the lifetime stats of a customer
+type customerStatsResponse struct {
+	Bookings         int                       `json:"bookings" example:"6"`
+	Stays            int                       `json:"stays" example:"4"`
+	Cancellations    int                       `json:"cancellations" example:"1"`
+	NoShows          int                       `json:"no_shows" example:"1"`
+	TotalNights      int                       `json:"total_nights" example:"11"`
+	TotalRevenue     float64                   `json:"total_revenue" example:"24500.00"`
+	AverageRate      float64                   `json:"average_rate" example:"2227.27"`
+	LastStay         *time.Time                `json:"last_stay" example:"2024-08-01T14:00:00Z"`
+	FavoriteRoomType *customerRoomTypeResponse `json:"favorite_room_type"`
+}
+
+// customerHistoryResponse represents the response body for a customer's stay history
+type customerHistoryResponse struct {
+	Customer customerResponse                 `json:"customer"`
+	Bookings []customerHistoryBookingResponse `json:"bookings"`
+	Stats    customerStatsResponse            `json:"stats"`
+}
+
+// newCustomerHistoryResponse creates a new customer history response
+func newCustomerHistoryResponse(history *domain.CustomerHistory) (customerHistoryResponse, error) {
+	customer, err := newCustomerResponse(&history.Customer)
+	if err != nil {
+		return customerHistoryResponse{}, err
+	}
+
+	rsp := customerHistoryResponse{
+		Customer: customer,
+		Bookings: []customerHistoryBookingResponse{},
+		Stats: customerStatsResponse{
+			Bookings:      history.Stats.Bookings,
+			Stays:         history.Stats.Stays,
+			Cancellations: history.Stats.Cancellations,
+			NoShows:       history.Stats.NoShows,
+			TotalNights:   history.Stats.TotalNights,
+			TotalRevenue:  history.Stats.TotalRevenue,
+			AverageRate:   history.Stats.AverageRate,
+			LastStay:      history.Stats.LastStay,
+		},
+	}
+	if roomType := history.Stats.FavoriteRoomType; roomType != nil {
+		rsp.Stats.FavoriteRoomType = &customerRoomTypeResponse{roomType.ID, roomType.Name, roomType.Nights}
+	}
+
+	for i := range history.Bookings {
+		booking, err := newBookingCustomerPaymentResponse(&history.Bookings[i])
+		if err != nil {
+			return customerHistoryResponse{}, err
+		}
+		rsp.Bookings = append(rsp.Bookings, customerHistoryBookingResponse{*booking, history.Bookings[i].Nights()})
+	}
+
+	return rsp, nil
+}
@@ -18,6 +18,75 @@ func NewDailyBookingSummaryHandler(svc port.DailyBookingSummaryService) *DailyBo
     }
 }
 
+// dailyBookingSummaryResponse represents a daily booking summary response body
+type dailyBookingSummaryResponse struct {
+    SummaryDate       string                       `json:"summary_date" example:"2024-01-01"`
+    TotalAmount       float64                      `json:"total_amount" example:"1000"`
+    TotalRefunds      float64                      `json:"total_refunds" example:"0"`
+    Status            domain.SummaryStatus         `json:"status" example:"0"`
+    Checksum          string                       `json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+    CreatedBookings   []summaryBookingResponse     `json:"created_bookings"`
+    CompletedBookings []summaryBookingResponse     `json:"completed_bookings"`
+    CanceledBookings  []summaryBookingResponse     `json:"canceled_bookings"`
+    CreatedAt         time.Time                    `json:"created_at" example:"1970-01-01T00:00:00Z"`
+    UpdatedAt         time.Time                    `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// summaryBookingResponse is a booking listed in a daily summary, with the amount recorded when the summary was generated
+type summaryBookingResponse struct {
+    bookingCustomerPaymentResponse
+    SummaryAmount float64 `json:"summary_amount" example:"1000"`
+}
+
+// newDailyBookingSummaryResponse is a helper function to create a response body for handling daily booking summary data
+func newDailyBookingSummaryResponse(summary *domain.DailyBookingSummary) (*dailyBookingSummaryResponse, error) {
+    response := &dailyBookingSummaryResponse{
+        SummaryDate:  summary.SummaryDate.Format("2006-01-02"),
+        TotalAmount:  summary.TotalAmount,
+        TotalRefunds: summary.TotalRefunds,
+        Status:       summary.Status,
+        Checksum:     summary.Checksum,
+        CreatedAt:    summary.CreatedAt,
+        UpdatedAt:    summary.UpdatedAt,
+    }
+
+    var err error
+    if response.CreatedBookings, err = newSummaryBookingsResponse(summary.BookingsIn(domain.SummaryCategoryCreated)); err != nil {
+        return nil, err
+    }
+    if response.CompletedBookings, err = newSummaryBookingsResponse(summary.BookingsIn(domain.SummaryCategoryCompleted)); err != nil {
+        return nil, err
+    }
+    if response.CanceledBookings, err = newSummaryBookingsResponse(summary.BookingsIn(domain.SummaryCategoryCanceled)); err != nil {
+        return nil, err
+    }
+
+    return response, nil
+}
+
+func newSummaryBookingsResponse(items []domain.DailyBookingSummaryItem) ([]summaryBookingResponse, error) {
+    bookings := make([]summaryBookingResponse, 0, len(items))
+    for _, item := range items {
+        booking, err := newBookingCustomerPaymentResponse(item.Booking)
+        if err != nil {
+            return nil, err
+        }
+        bookings = append(bookings, summaryBookingResponse{*booking, item.Amount})
+    }
+    return bookings, nil
+}
+
+// respondWithSummary writes a daily booking summary as its response body
+func respondWithSummary(ctx *gin.Context, summary *domain.DailyBookingSummary) {
+    rsp, err := newDailyBookingSummaryResponse(summary)
+    if err != nil {
+        handleError(ctx, err)
+        return
+    }
+
+    ctx.JSON(200, rsp)
+}
+
 // GenerateDailySummary godoc
 // @Summary Generate daily booking summary
 // @Description Generate summary for specified date
@@ -25,7 +94,7 @@ func NewDailyBookingSummaryHandler(svc port.DailyBookingSummaryService) *DailyBo
 // @Accept json
 // @Produce json
 // @Param date query string true "Date (YYYY-MM-DD)"
-// @Success 200 {object} domain.DailyBookingSummary
+// @Success 200 {object} dailyBookingSummaryResponse
 // @Router /api/v1/daily-summary/generate [post]
 func (h *DailyBookingSummaryHandler) GenerateDailySummary(ctx *gin.Context) {
     dateStr := ctx.Query("date")
@@ -35,13 +104,19 @@ func (h *DailyBookingSummaryHandler) GenerateDailySummary(ctx *gin.Context) {
         return
     }
 
-    summary, err := h.svc.GenerateDailySummary(ctx, date)
+    userID, exists := ctx.Get("userID")
+    if !exists {
+        handleError(ctx, domain.ErrUnauthorized)
+        return
+    }
+
+    summary, err := h.svc.GenerateDailySummary(ctx, userID.(uint64), date)
     if err != nil {
         handleError(ctx, err)
         return
     }
 
-    ctx.JSON(200, summary)
+    respondWithSummary(ctx, summary)
 }
 
 // UpdateSummaryStatus godoc
@@ -52,7 +127,7 @@ func (h *DailyBookingSummaryHandler) GenerateDailySummary(ctx *gin.Context) {
 // @Produce json
 // @Param date query string true "Date (YYYY-MM-DD)"
 // @Param status query int true "Status (0: Unchecked, 1: Checked, 2: Confirmed)"
-// @Success 200 {object} domain.DailyBookingSummary
+// @Success 200 {object} dailyBookingSummaryResponse
 // @Router /api/v1/daily-summary/status [put]
 func (h *DailyBookingSummaryHandler) UpdateSummaryStatus(ctx *gin.Context) {
     dateStr := ctx.Query("date")
@@ -75,7 +150,34 @@ func (h *DailyBookingSummaryHandler) UpdateSummaryStatus(ctx *gin.Context) {
         return
     }
 
-    ctx.JSON(200, summary)
+    respondWithSummary(ctx, summary)
+}
+
+// ReopenSummary godoc
+// @Summary Reopen a confirmed daily booking summary
+// @Description Move a confirmed summary back to checked so the bookings and payments of its day can be changed again.
+// @Description Only admins can reopen a day. A day locked by the night audit is unlocked until its summary is confirmed again.
+// @Tags daily-booking-summary
+// @Accept json
+// @Produce json
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {object} dailyBookingSummaryResponse
+// @Router /api/v1/daily-summary/reopen [post]
+func (h *DailyBookingSummaryHandler) ReopenSummary(ctx *gin.Context) {
+    dateStr := ctx.Query("date")
+    date, err := time.Parse("2006-01-02", dateStr)
+    if err != nil {
+        validationError(ctx, err)
+        return
+    }
+
+    summary, err := h.svc.ReopenSummary(ctx, date)
+    if err != nil {
+        handleError(ctx, err)
+        return
+    }
+
+    respondWithSummary(ctx, summary)
 }
 
 // GetSummaryByDate godoc
@@ -85,7 +187,7 @@ func (h *DailyBookingSummaryHandler) UpdateSummaryStatus(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param date query string true "Date (YYYY-MM-DD)"
-// @Success 200 {object} domain.DailyBookingSummary
+// @Success 200 {object} dailyBookingSummaryResponse
 // @Router /api/v1/daily-summary [get]
 func (h *DailyBookingSummaryHandler) GetSummaryByDate(ctx *gin.Context) {
     dateStr := ctx.Query("date")
@@ -101,7 +203,7 @@ func (h *DailyBookingSummaryHandler) GetSummaryByDate(ctx *gin.Context) {
         return
     }
 
-    ctx.JSON(200, summary)
+    respondWithSummary(ctx, summary)
 }
 
 // ListSummaries godoc
@@ -112,7 +214,7 @@ func (h *DailyBookingSummaryHandler) GetSummaryByDate(ctx *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
-// @Success 200 {array} domain.DailyBookingSummary
+// @Success 200 {array} dailyBookingSummaryResponse
 // @Router /api/v1/daily-summary/list [get]
 func (h *DailyBookingSummaryHandler) ListSummaries(ctx *gin.Context) {
     page, _ := strconv.ParseUint(ctx.DefaultQuery("page", "1"), 10, 64)
@@ -125,8 +227,18 @@ func (h *DailyBookingSummaryHandler) ListSummaries(ctx *gin.Context) {
         return
     }
 
+    summariesList := make([]dailyBookingSummaryResponse, 0, len(summaries))
+    for i := range summaries {
+        rsp, err := newDailyBookingSummaryResponse(&summaries[i])
+        if err != nil {
+            handleError(ctx, err)
+            return
+        }
+        summariesList = append(summariesList, *rsp)
+    }
+
     ctx.JSON(200, gin.H{
-        "data":  summaries,
+        "data":  summariesList,
         "total": total,
         "page":  page,
         "limit": limit,
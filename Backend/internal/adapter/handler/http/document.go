@@ -0,0 +1,100 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// DocumentHandler represents the HTTP handler for printable booking documents
+type DocumentHandler struct {
+	svc port.DocumentService
+}
+
+// NewDocumentHandler creates a new DocumentHandler instance
+func NewDocumentHandler(svc port.DocumentService) *DocumentHandler {
+	return &DocumentHandler{
+		svc,
+	}
+}
+
+// getBookingDocumentRequest represents the request for downloading a booking invoice or receipt
+type getBookingDocumentRequest struct {
+	BookingID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+	Type      string `form:"type" binding:"omitempty,oneof=invoice receipt" example:"invoice"`
+	PayerID   uint64 `form:"payer_id" example:"1"`
+}
+
+// GetBookingInvoicePDF godoc
+//
+//	@Summary		Download a booking invoice
+//	@Description	Render the pro forma invoice (default) or receipt of a booking as a PDF, for the guest or one of the booking payers
+//	@Tags			Bookings
+//	@Produce		application/pdf
+//	@Param			id			path		uint64			true	"Booking ID"
+//	@Param			type		query		string			false	"Document type (invoice or receipt)"
+//	@Param			payer_id	query		uint64			false	"Booking payer ID, the guest when omitted"
+//	@Success		200		{file}		binary			"PDF document"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		404		{object}	errorResponse	"Data not found error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/booking/{id}/invoice.pdf [get]
+//	@Security		BearerAuth
+func (dh *DocumentHandler) GetBookingInvoicePDF(ctx *gin.Context) {
+	var req getBookingDocumentRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	documentType, filename := domain.DocumentTypeTaxInvoice, "invoice"
+	if req.Type == "receipt" {
+		documentType, filename = domain.DocumentTypeReceipt, "receipt"
+	}
+
+	pdf, err := dh.svc.GetBookingDocumentPDF(ctx, req.BookingID, req.PayerID, documentType)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%d.pdf\"", filename, req.BookingID))
+	ctx.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetInvoicePDF godoc
+//
+//	@Summary		Download an issued invoice
+//	@Description	Render an issued tax invoice or credit note as a PDF
+//	@Tags			Invoices
+//	@Produce		application/pdf
+//	@Param			id	path		uint64			true	"Invoice ID"
+//	@Success		200	{file}		binary			"PDF document"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/invoices/{id}/pdf [get]
+//	@Security		BearerAuth
+func (dh *DocumentHandler) GetInvoicePDF(ctx *gin.Context) {
+	var req getInvoiceRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	pdf, err := dh.svc.GetInvoicePDF(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"invoice-%d.pdf\"", req.ID))
+	ctx.Data(http.StatusOK, "application/pdf", pdf)
+}
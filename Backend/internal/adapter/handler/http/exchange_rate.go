@@ -0,0 +1,301 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeRateHandler represents the HTTP handler for exchange rate-related requests
+type ExchangeRateHandler struct {
+	svc port.ExchangeRateService
+}
+
+// NewExchangeRateHandler creates a new ExchangeRateHandler instance
+func NewExchangeRateHandler(svc port.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		svc,
+	}
+}
+
+// createExchangeRateRequest represents the request body for creating an exchange rate
+type createExchangeRateRequest struct {
+	Currency      string     `json:"currency" binding:"required,len=3" example:"USD"`
+	Rate          float64    `json:"rate" binding:"required,gt=0" example:"35.25"`
+	EffectiveDate *time.Time `json:"effective_date" example:"2024-08-01T00:00:00Z"`
+}
+
+// CreateExchangeRate godoc
+//
+//	@Summary		Create an exchange rate
+//	@Description	Create a new THB exchange rate for a currency, effective from the given date
+//	@Tags			ExchangeRates
+//	@Accept			json
+//	@Produce		json
+//	@Param			createExchangeRateRequest	body		createExchangeRateRequest	true	"Create exchange rate request"
+//	@Success		200							{object}	exchangeRateResponse		"Exchange rate created"
+//	@Failure		400							{object}	errorResponse				"Validation error"
+//	@Failure		409							{object}	errorResponse				"Data conflict error"
+//	@Failure		500							{object}	errorResponse				"Internal server error"
+//	@Router			/exchange-rates [post]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) CreateExchangeRate(ctx *gin.Context) {
+	var req createExchangeRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	exchangeRate := domain.ExchangeRate{
+		Currency:      req.Currency,
+		Rate:          req.Rate,
+		EffectiveDate: req.EffectiveDate,
+	}
+
+	createdExchangeRate, err := eh.svc.CreateExchangeRate(ctx, &exchangeRate)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExchangeRateResponse(createdExchangeRate)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listExchangeRatesRequest represents the request body for listing exchange rates
+type listExchangeRatesRequest struct {
+	Skip     uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit    uint64 `form:"limit" binding:"required,min=1" example:"10"`
+	Currency string `form:"currency" binding:"omitempty,len=3" example:"USD"`
+}
+
+// ListExchangeRates godoc
+//
+//	@Summary		List exchange rates
+//	@Description	List exchange rates with pagination, newest first
+//	@Tags			ExchangeRates
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip		query		uint64			false	"Skip"
+//	@Param			limit		query		uint64			true	"Limit"
+//	@Param			currency	query		string			false	"Currency"
+//	@Success		200			{object}	meta			"Exchange rates displayed"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/exchange-rates [get]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) ListExchangeRates(ctx *gin.Context) {
+	var req listExchangeRatesRequest
+	var exchangeRatesList []exchangeRateResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	exchangeRates, totalCount, err := eh.svc.ListExchangeRates(ctx, req.Currency, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, exchangeRate := range exchangeRates {
+		exchangeRatesList = append(exchangeRatesList, newExchangeRateResponse(&exchangeRate))
+	}
+
+	meta := newMeta(totalCount, req.Limit, req.Skip)
+	rsp := toMap(meta, exchangeRatesList, "exchange_rates")
+
+	handleSuccess(ctx, rsp)
+}
+
+// getEffectiveExchangeRateRequest represents the request body for getting the effective rate of a currency
+type getEffectiveExchangeRateRequest struct {
+	Currency string `form:"currency" binding:"required,len=3" example:"USD"`
+	Date     string `form:"date" example:"2024-08-01"`
+}
+
+// GetEffectiveExchangeRate godoc
+//
+//	@Summary		Get the effective exchange rate
+//	@Description	Get the exchange rate used for a currency on a date (defaults to today)
+//	@Tags			ExchangeRates
+//	@Accept			json
+//	@Produce		json
+//	@Param			currency	query		string					true	"Currency"
+//	@Param			date		query		string					false	"Date (YYYY-MM-DD)"
+//	@Success		200			{object}	exchangeRateResponse	"Exchange rate displayed"
+//	@Failure		400			{object}	errorResponse			"Validation error"
+//	@Failure		500			{object}	errorResponse			"Internal server error"
+//	@Router			/exchange-rates/effective [get]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) GetEffectiveExchangeRate(ctx *gin.Context) {
+	var req getEffectiveExchangeRateRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	date := time.Now()
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+		date = parsed
+	}
+
+	exchangeRate, err := eh.svc.GetEffectiveExchangeRate(ctx, req.Currency, date)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExchangeRateResponse(exchangeRate)
+
+	handleSuccess(ctx, rsp)
+}
+
+// getExchangeRateRequest represents the request body for getting an exchange rate
+type getExchangeRateRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// GetExchangeRate godoc
+//
+//	@Summary		Get an exchange rate
+//	@Description	Get an exchange rate by id
+//	@Tags			ExchangeRates
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64					true	"Exchange rate ID"
+//	@Success		200	{object}	exchangeRateResponse	"Exchange rate displayed"
+//	@Failure		400	{object}	errorResponse			"Validation error"
+//	@Failure		404	{object}	errorResponse			"Data not found error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/exchange-rates/{id} [get]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) GetExchangeRate(ctx *gin.Context) {
+	var req getExchangeRateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	exchangeRate, err := eh.svc.GetExchangeRate(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExchangeRateResponse(exchangeRate)
+
+	handleSuccess(ctx, rsp)
+}
+
+// updateExchangeRateRequest represents the request body for updating an exchange rate
+type updateExchangeRateRequest struct {
+	ID            uint64     `json:"id" binding:"required" example:"1"`
+	Rate          float64    `json:"rate" binding:"required,gt=0" example:"35.40"`
+	EffectiveDate *time.Time `json:"effective_date" example:"2024-08-01T00:00:00Z"`
+}
+
+// UpdateExchangeRate godoc
+//
+//	@Summary		Update an exchange rate
+//	@Description	Update the rate or effective date of an exchange rate
+//	@Tags			ExchangeRates
+//	@Accept			json
+//	@Produce		json
+//	@Param			updateExchangeRateRequest	body		updateExchangeRateRequest	true	"Update exchange rate request"
+//	@Success		200							{object}	exchangeRateResponse		"Exchange rate updated"
+//	@Failure		400							{object}	errorResponse				"Validation error"
+//	@Failure		404							{object}	errorResponse				"Data not found error"
+//	@Failure		409							{object}	errorResponse				"Data conflict error"
+//	@Failure		500							{object}	errorResponse				"Internal server error"
+//	@Router			/exchange-rates [put]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) UpdateExchangeRate(ctx *gin.Context) {
+	var req updateExchangeRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	exchangeRate := domain.ExchangeRate{
+		ID:            req.ID,
+		Rate:          req.Rate,
+		EffectiveDate: req.EffectiveDate,
+	}
+
+	updatedExchangeRate, err := eh.svc.UpdateExchangeRate(ctx, &exchangeRate)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExchangeRateResponse(updatedExchangeRate)
+
+	handleSuccess(ctx, rsp)
+}
+
+// deleteExchangeRateRequest represents the request body for deleting an exchange rate
+type deleteExchangeRateRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// DeleteExchangeRate godoc
+//
+//	@Summary		Delete an exchange rate
+//	@Description	Delete an exchange rate by id
+//	@Tags			ExchangeRates
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Exchange rate ID"
+//	@Success		200	{object}	response		"Exchange rate deleted"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/exchange-rates/{id} [delete]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) DeleteExchangeRate(ctx *gin.Context) {
+	var req deleteExchangeRateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := eh.svc.DeleteExchangeRate(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, "Exchange rate deleted successfully")
+}
+
+// exchangeRateResponse represents the response body for an exchange rate
+type exchangeRateResponse struct {
+	ID            uint64     `json:"id" example:"1"`
+	Currency      string     `json:"currency" example:"USD"`
+	Rate          float64    `json:"rate" example:"35.25"`
+	EffectiveDate *time.Time `json:"effective_date" example:"2024-08-01T00:00:00Z"`
+	CreatedAt     *time.Time `json:"created_at,omitempty" example:"2024-08-01T15:04:05Z"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty" example:"2024-08-01T15:04:05Z"`
+}
+
+// newExchangeRateResponse creates a new exchange rate response
+func newExchangeRateResponse(exchangeRate *domain.ExchangeRate) exchangeRateResponse {
+	return exchangeRateResponse{
+		ID:            exchangeRate.ID,
+		Currency:      exchangeRate.Currency,
+		Rate:          exchangeRate.Rate,
+		EffectiveDate: exchangeRate.EffectiveDate,
+		CreatedAt:     exchangeRate.CreatedAt,
+		UpdatedAt:     exchangeRate.UpdatedAt,
+	}
+}
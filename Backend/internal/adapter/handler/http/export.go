@@ -0,0 +1,220 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler represents the HTTP handler for spreadsheet exports
+type ExportHandler struct {
+	svc port.ExportService
+}
+
+// NewExportHandler creates a new ExportHandler instance
+func NewExportHandler(svc port.ExportService) *ExportHandler {
+	return &ExportHandler{
+		svc,
+	}
+}
+
+// exportRequest represents the request body for exporting a list
+type exportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx" example:"csv"`
+}
+
+// exportKPIReportRequest represents the request body for exporting a KPI report
+type exportKPIReportRequest struct {
+	getKPIReportRequest
+	exportRequest
+}
+
+// exportFormat returns the requested format, CSV by default
+func (req exportRequest) exportFormat() domain.ExportFormat {
+	if req.Format == "" {
+		return domain.ExportFormatCSV
+	}
+	return domain.ExportFormat(req.Format)
+}
+
+// attachmentWriter sends the file headers on the first write, so an export that fails before writing
+// anything can still be answered with an error
+type attachmentWriter struct {
+	ctx      *gin.Context
+	format   domain.ExportFormat
+	filename string
+	started  bool
+}
+
+func (aw *attachmentWriter) Write(p []byte) (int, error) {
+	if !aw.started {
+		aw.started = true
+		aw.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%s.%s\"", aw.filename, time.Now().Format("20060102"), aw.format))
+		aw.ctx.Header("Content-Type", aw.format.ContentType())
+		aw.ctx.Status(http.StatusOK)
+	}
+	return aw.ctx.Writer.Write(p)
+}
+
+// export binds the format, streams the file written by write and handles its error
+func export(ctx *gin.Context, filename string, write func(format domain.ExportFormat, aw *attachmentWriter) error) {
+	var req exportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	exportFile(ctx, req.exportFormat(), filename, write)
+}
+
+// exportFile streams the file written by write. Once the file has started an error can only cut it
+// short, so it is logged instead.
+func exportFile(ctx *gin.Context, format domain.ExportFormat, filename string, write func(format domain.ExportFormat, aw *attachmentWriter) error) {
+	aw := &attachmentWriter{ctx: ctx, format: format, filename: filename}
+	if err := write(format, aw); err != nil {
+		if !aw.started {
+			handleError(ctx, err)
+			return
+		}
+		slog.Error("Error writing export", "file", filename, "error", err)
+	}
+}
+
+// ExportBookingCustomerPayments godoc
+//
+//	@Summary		Export bookings with customer and payment
+//	@Description	Stream the bookings matching the filters of the booking list as a CSV or XLSX file
+//	@Tags			Bookings
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string			false	"File format"	Enums(csv, xlsx)
+//	@Success		200		{file}		file			"Bookings exported"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/booking/export [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) ExportBookingCustomerPayments(ctx *gin.Context) {
+	filter := bookingCustomerPaymentFilter(ctx)
+
+	export(ctx, "bookings", func(format domain.ExportFormat, aw *attachmentWriter) error {
+		return eh.svc.ExportBookingCustomerPayments(ctx, filter, format, aw)
+	})
+}
+
+// ExportPayments godoc
+//
+//	@Summary		Export payments
+//	@Description	Stream all payments as a CSV or XLSX file
+//	@Tags			Payments
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string			false	"File format"	Enums(csv, xlsx)
+//	@Success		200		{file}		file			"Payments exported"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/payments/export [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) ExportPayments(ctx *gin.Context) {
+	export(ctx, "payments", func(format domain.ExportFormat, aw *attachmentWriter) error {
+		return eh.svc.ExportPayments(ctx, format, aw)
+	})
+}
+
+// ExportCustomers godoc
+//
+//	@Summary		Export customers
+//	@Description	Stream the customers matching the filters of the customer list as a CSV or XLSX file
+//	@Tags			Customers
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string			false	"File format"	Enums(csv, xlsx)
+//	@Success		200		{file}		file			"Customers exported"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/customers/export [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) ExportCustomers(ctx *gin.Context) {
+	filter := customerFilter(ctx)
+
+	export(ctx, "customers", func(format domain.ExportFormat, aw *attachmentWriter) error {
+		return eh.svc.ExportCustomers(ctx, filter, format, aw)
+	})
+}
+
+// ExportLogs godoc
+//
+//	@Summary		Export logs
+//	@Description	Stream all logs as a CSV or XLSX file
+//	@Tags			Logs
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string			false	"File format"	Enums(csv, xlsx)
+//	@Success		200		{file}		file			"Logs exported"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/logs/export [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) ExportLogs(ctx *gin.Context) {
+	export(ctx, "logs", func(format domain.ExportFormat, aw *attachmentWriter) error {
+		return eh.svc.ExportLogs(ctx, format, aw)
+	})
+}
+
+// ExportDailySummaries godoc
+//
+//	@Summary		Export daily booking summaries
+//	@Description	Stream all daily booking summaries as a CSV or XLSX file
+//	@Tags			DailyBookingSummary
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string			false	"File format"	Enums(csv, xlsx)
+//	@Success		200		{file}		file			"Daily summaries exported"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/daily-summary/export [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) ExportDailySummaries(ctx *gin.Context) {
+	export(ctx, "daily-summaries", func(format domain.ExportFormat, aw *attachmentWriter) error {
+		return eh.svc.ExportDailySummaries(ctx, format, aw)
+	})
+}
+
+// ExportKPIReport godoc
+//
+//	@Summary		Export the occupancy, ADR and RevPAR report
+//	@Description	Stream the KPI report of a date range as a CSV or XLSX file. Only admins can export it.
+//	@Tags			Reports
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			from		query		string			true	"First night (YYYY-MM-DD)"
+//	@Param			to			query		string			true	"Last night (YYYY-MM-DD)"
+//	@Param			interval	query		string			false	"Period length"	Enums(day, week, month)
+//	@Param			format		query		string			false	"File format"	Enums(csv, xlsx)
+//	@Success		200			{file}		file			"KPI report exported"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		403			{object}	errorResponse	"Forbidden error"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/reports/kpi/export [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) ExportKPIReport(ctx *gin.Context) {
+	var req exportKPIReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	from, to, interval, err := req.parse()
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	exportFile(ctx, req.exportFormat(), "kpi-report", func(format domain.ExportFormat, aw *attachmentWriter) error {
+		return eh.svc.ExportKPIReport(ctx, from, to, interval, format, aw)
+	})
+}
@@ -0,0 +1,426 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// FolioHandler represents the HTTP handler for folio-related requests
+type FolioHandler struct {
+	svc port.FolioService
+}
+
+// NewFolioHandler creates a new FolioHandler instance
+func NewFolioHandler(svc port.FolioService) *FolioHandler {
+	return &FolioHandler{
+		svc,
+	}
+}
+
+// getFolioRequest represents the request body for getting a booking's folio
+type getFolioRequest struct {
+	BookingID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// GetFolio godoc
+//
+//	@Summary		Get a booking folio
+//	@Description	Get the itemized charges, payments, adjustments and live balance of a booking
+//	@Tags			Folios
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Booking ID"
+//	@Success		200	{object}	folioResponse	"Folio displayed"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/booking/{id}/folio [get]
+//	@Security		BearerAuth
+func (fh *FolioHandler) GetFolio(ctx *gin.Context) {
+	var req getFolioRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	folio, err := fh.svc.GetFolio(ctx, req.BookingID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newFolioResponse(folio)
+
+	handleSuccess(ctx, rsp)
+}
+
+// ListSplitFolios godoc
+//
+//	@Summary		List the split folios of a booking
+//	@Description	Get the guest's folio followed by the folio of each booking payer, each with its own balance
+//	@Tags			Folios
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Booking ID"
+//	@Success		200	{array}		folioResponse	"Folios displayed"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/booking/{id}/folios [get]
+//	@Security		BearerAuth
+func (fh *FolioHandler) ListSplitFolios(ctx *gin.Context) {
+	var req getFolioRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	folios, err := fh.svc.ListSplitFolios(ctx, req.BookingID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []folioResponse{}
+	for _, folio := range folios {
+		rsp = append(rsp, newFolioResponse(&folio))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// addPayerRequest represents the request body for adding a payer to a booking
+type addPayerRequest struct {
+	CustomerID  *uint64             `json:"customer_id" binding:"omitempty,min=1" example:"2"`
+	CompanyID   *uint64             `json:"company_id" binding:"omitempty,min=1" example:"1"`
+	ChargeTypes []domain.ChargeType `json:"charge_types" binding:"required,min=1,dive,min=1" example:"1"`
+}
+
+// AddPayer godoc
+//
+//	@Summary		Add a payer to a booking
+//	@Description	Bill the given charge types of a booking to another customer or to a company, e.g. room charges to the guest's company. Give either customer_id or company_id. Other charges stay with the guest.
+//	@Tags			Folios
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		uint64					true	"Booking ID"
+//	@Param			addPayerRequest	body		addPayerRequest			true	"Add payer request"
+//	@Success		200				{object}	bookingPayerResponse	"Payer added"
+//	@Failure		400				{object}	errorResponse			"Validation error"
+//	@Failure		404				{object}	errorResponse			"Data not found error"
+//	@Failure		409				{object}	errorResponse			"Charge type already routed to another payer"
+//	@Failure		500				{object}	errorResponse			"Internal server error"
+//	@Router			/booking/{id}/payers [post]
+//	@Security		BearerAuth
+func (fh *FolioHandler) AddPayer(ctx *gin.Context) {
+	var uri getFolioRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req addPayerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payer := domain.BookingPayer{
+		BookingID:   uri.BookingID,
+		CustomerID:  req.CustomerID,
+		CompanyID:   req.CompanyID,
+		ChargeTypes: req.ChargeTypes,
+	}
+
+	createdPayer, err := fh.svc.AddPayer(ctx, &payer)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newBookingPayerResponse(createdPayer)
+
+	handleSuccess(ctx, rsp)
+}
+
+// ListPayers godoc
+//
+//	@Summary		List the payers of a booking
+//	@Description	List the customers and companies billed for part of a booking and the charge types routed to each
+//	@Tags			Folios
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64					true	"Booking ID"
+//	@Success		200	{array}		bookingPayerResponse	"Payers displayed"
+//	@Failure		400	{object}	errorResponse			"Validation error"
+//	@Failure		404	{object}	errorResponse			"Data not found error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/booking/{id}/payers [get]
+//	@Security		BearerAuth
+func (fh *FolioHandler) ListPayers(ctx *gin.Context) {
+	var req getFolioRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payers, err := fh.svc.ListPayers(ctx, req.BookingID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []bookingPayerResponse{}
+	for _, payer := range payers {
+		rsp = append(rsp, newBookingPayerResponse(&payer))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// removePayerRequest represents the request body for removing a payer from a booking
+type removePayerRequest struct {
+	BookingID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+	PayerID   uint64 `uri:"payer_id" binding:"required,min=1" example:"1"`
+}
+
+// RemovePayer godoc
+//
+//	@Summary		Remove a payer from a booking
+//	@Description	Route the payer's charge types back to the guest. Payers with payments or invoices cannot be removed.
+//	@Tags			Folios
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		uint64			true	"Booking ID"
+//	@Param			payer_id	path		uint64			true	"Payer ID"
+//	@Success		200			{object}	response		"Payer removed"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		404			{object}	errorResponse	"Data not found error"
+//	@Failure		409			{object}	errorResponse	"Payer has payments or invoices"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/booking/{id}/payers/{payer_id} [delete]
+//	@Security		BearerAuth
+func (fh *FolioHandler) RemovePayer(ctx *gin.Context) {
+	var req removePayerRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := fh.svc.RemovePayer(ctx, req.BookingID, req.PayerID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, "Payer removed successfully")
+}
+
+// postChargeRequest represents the request body for posting a charge to a folio
+type postChargeRequest struct {
+	ChargeType  domain.ChargeType `json:"charge_type" binding:"required,min=1" example:"2"`
+	Description string            `json:"description" example:"Minibar - soft drinks"`
+	Quantity    int               `json:"quantity" binding:"required,min=1" example:"2"`
+	UnitPrice   float64           `json:"unit_price" binding:"required,gt=0" example:"60.00"`
+	TaxRate     float64           `json:"tax_rate" binding:"min=0,max=100" example:"7"`
+}
+
+// PostCharge godoc
+//
+//	@Summary		Post a charge
+//	@Description	Post an itemized charge (room, minibar, laundry, restaurant, extra bed, other) to a booking's folio
+//	@Tags			Folios
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		uint64				true	"Booking ID"
+//	@Param			postChargeRequest	body		postChargeRequest	true	"Post charge request"
+//	@Success		200					{object}	folioChargeResponse	"Charge posted"
+//	@Failure		400					{object}	errorResponse		"Validation error"
+//	@Failure		404					{object}	errorResponse		"Data not found error"
+//	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Router			/booking/{id}/folio/charges [post]
+//	@Security		BearerAuth
+func (fh *FolioHandler) PostCharge(ctx *gin.Context) {
+	var uri getFolioRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req postChargeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	charge := domain.FolioCharge{
+		BookingID:   uri.BookingID,
+		ChargeType:  req.ChargeType,
+		Description: req.Description,
+		Quantity:    req.Quantity,
+		UnitPrice:   req.UnitPrice,
+		TaxRate:     req.TaxRate,
+	}
+
+	postedCharge, err := fh.svc.PostCharge(ctx, &charge)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newFolioChargeResponse(postedCharge)
+
+	handleSuccess(ctx, rsp)
+}
+
+// postAdjustmentRequest represents the request body for crediting a folio
+type postAdjustmentRequest struct {
+	Amount      float64 `json:"amount" binding:"required,gt=0" example:"200.00"`
+	Description string  `json:"description" binding:"required" example:"Late check-out compensation"`
+}
+
+// PostAdjustment godoc
+//
+//	@Summary		Post an adjustment
+//	@Description	Credit a booking's folio with an adjustment that reduces the balance
+//	@Tags			Folios
+//	@Accept			json
+//	@Produce		json
+//	@Param			id						path		uint64					true	"Booking ID"
+//	@Param			postAdjustmentRequest	body		postAdjustmentRequest	true	"Post adjustment request"
+//	@Success		200						{object}	folioChargeResponse		"Adjustment posted"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/booking/{id}/folio/adjustments [post]
+//	@Security		BearerAuth
+func (fh *FolioHandler) PostAdjustment(ctx *gin.Context) {
+	var uri getFolioRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req postAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	postedCharge, err := fh.svc.PostAdjustment(ctx, uri.BookingID, req.Amount, req.Description)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newFolioChargeResponse(postedCharge)
+
+	handleSuccess(ctx, rsp)
+}
+
+// folioChargeResponse represents the response body for a folio charge
+type folioChargeResponse struct {
+	ID          uint64            `json:"id" example:"1"`
+	BookingID   uint64            `json:"booking_id" example:"1"`
+	ChargeType  domain.ChargeType `json:"charge_type" example:"2"`
+	Description string            `json:"description" example:"Minibar - soft drinks"`
+	Quantity    int               `json:"quantity" example:"2"`
+	UnitPrice   float64           `json:"unit_price" example:"60.00"`
+	TaxRate     float64           `json:"tax_rate" example:"7"`
+	TaxAmount   float64           `json:"tax_amount" example:"8.40"`
+	Amount      float64           `json:"amount" example:"120.00"`
+	Total       float64           `json:"total" example:"128.40"`
+	PostedBy    *uint64           `json:"posted_by" example:"1"`
+	PostedAt    *time.Time        `json:"posted_at" example:"2024-08-01T15:04:05Z"`
+}
+
+// newFolioChargeResponse creates a new folio charge response
+func newFolioChargeResponse(charge *domain.FolioCharge) folioChargeResponse {
+	return folioChargeResponse{
+		ID:          charge.ID,
+		BookingID:   charge.BookingID,
+		ChargeType:  charge.ChargeType,
+		Description: charge.Description,
+		Quantity:    charge.Quantity,
+		UnitPrice:   charge.UnitPrice,
+		TaxRate:     charge.TaxRate,
+		TaxAmount:   charge.TaxAmount,
+		Amount:      charge.Amount,
+		Total:       charge.Total(),
+		PostedBy:    charge.PostedBy,
+		PostedAt:    charge.PostedAt,
+	}
+}
+
+// folioResponse represents the response body for a booking folio
+type folioResponse struct {
+	BookingID        uint64                `json:"booking_id" example:"1"`
+	PayerID          *uint64               `json:"payer_id" example:"1"`
+	Charges          []folioChargeResponse `json:"charges"`
+	Adjustments      []folioChargeResponse `json:"adjustments"`
+	Payments         []paymentResponse     `json:"payments"`
+	TotalCharges     float64               `json:"total_charges" example:"3128.40"`
+	TotalAdjustments float64               `json:"total_adjustments" example:"200.00"`
+	TotalPayments    float64               `json:"total_payments" example:"2000.00"`
+	Balance          float64               `json:"balance" example:"928.40"`
+	PaymentStatus    domain.PaymentStatus  `json:"payment_status" example:"5"`
+}
+
+// newFolioResponse creates a new folio response, listing adjustments alongside payments as credits
+func newFolioResponse(folio *domain.Folio) folioResponse {
+	rsp := folioResponse{
+		BookingID:        folio.BookingID,
+		PayerID:          folio.PayerID,
+		Charges:          []folioChargeResponse{},
+		Adjustments:      []folioChargeResponse{},
+		Payments:         []paymentResponse{},
+		TotalCharges:     folio.TotalCharges,
+		TotalAdjustments: folio.TotalAdjustments,
+		TotalPayments:    folio.TotalPayments,
+		Balance:          folio.Balance,
+		PaymentStatus:    folio.PaymentStatus(),
+	}
+
+	for _, charge := range folio.Charges {
+		if charge.ChargeType == domain.ChargeTypeAdjustment {
+			rsp.Adjustments = append(rsp.Adjustments, newFolioChargeResponse(&charge))
+			continue
+		}
+		rsp.Charges = append(rsp.Charges, newFolioChargeResponse(&charge))
+	}
+
+	for _, payment := range folio.Payments {
+		paymentRsp, err := newPaymentResponse(&payment)
+		if err != nil {
+			continue
+		}
+		rsp.Payments = append(rsp.Payments, paymentRsp)
+	}
+
+	return rsp
+}
+
+// bookingPayerResponse represents the response body for a booking payer
+type bookingPayerResponse struct {
+	ID          uint64              `json:"id" example:"1"`
+	BookingID   uint64              `json:"booking_id" example:"1"`
+	CustomerID  *uint64             `json:"customer_id" example:"2"`
+	CompanyID   *uint64             `json:"company_id" example:"1"`
+	ChargeTypes []domain.ChargeType `json:"charge_types" example:"1"`
+	CreatedAt   *time.Time          `json:"created_at" example:"2024-08-01T15:04:05Z"`
+}
+
+// newBookingPayerResponse creates a new booking payer response
+func newBookingPayerResponse(payer *domain.BookingPayer) bookingPayerResponse {
+	return bookingPayerResponse{
+		ID:          payer.ID,
+		BookingID:   payer.BookingID,
+		CustomerID:  payer.CustomerID,
+		CompanyID:   payer.CompanyID,
+		ChargeTypes: payer.ChargeTypes,
+		CreatedAt:   payer.CreatedAt,
+	}
+}
@@ -0,0 +1,370 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// InvoiceHandler represents the HTTP handler for numbered invoices and their series
+type InvoiceHandler struct {
+	svc port.InvoiceService
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler instance
+func NewInvoiceHandler(svc port.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		svc,
+	}
+}
+
+// issueInvoiceRequest represents the request body for issuing a tax invoice
+type issueInvoiceRequest struct {
+	BookingID uint64 `json:"booking_id" binding:"required,min=1" example:"1"`
+	PayerID   uint64 `json:"payer_id" example:"1"`
+	SeriesID  uint64 `json:"series_id" example:"1"`
+}
+
+// IssueInvoice godoc
+//
+//	@Summary		Issue a tax invoice
+//	@Description	Issue a numbered tax invoice for the folio of a booking payer, or of the guest when no payer is given. The number is taken from the given series, or the default tax invoice series, without gaps.
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			issueInvoiceRequest	body		issueInvoiceRequest	true	"Issue invoice request"
+//	@Success		200					{object}	invoiceResponse		"Invoice issued"
+//	@Failure		400					{object}	errorResponse		"Validation error"
+//	@Failure		404					{object}	errorResponse		"Data not found error"
+//	@Failure		409					{object}	errorResponse		"Data conflict error"
+//	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Router			/invoices [post]
+//	@Security		BearerAuth
+func (ih *InvoiceHandler) IssueInvoice(ctx *gin.Context) {
+	var req issueInvoiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	invoice, err := ih.svc.IssueInvoice(ctx, req.BookingID, req.PayerID, req.SeriesID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newInvoiceResponse(invoice)
+
+	handleSuccess(ctx, rsp)
+}
+
+// getInvoiceRequest represents the request body for getting an invoice
+type getInvoiceRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// issueCreditNoteRequest represents the request body for cancelling an invoice
+type issueCreditNoteRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Issued to the wrong company"`
+}
+
+// IssueCreditNote godoc
+//
+//	@Summary		Cancel an invoice with a credit note
+//	@Description	Issue a credit note that cancels a tax invoice in full and references its number (admin only)
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id						path		uint64					true	"Invoice ID"
+//	@Param			issueCreditNoteRequest	body		issueCreditNoteRequest	true	"Issue credit note request"
+//	@Success		200						{object}	invoiceResponse			"Credit note issued"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		409						{object}	errorResponse			"Data conflict error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/invoices/{id}/credit-note [post]
+//	@Security		BearerAuth
+func (ih *InvoiceHandler) IssueCreditNote(ctx *gin.Context) {
+	var uri getInvoiceRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req issueCreditNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	creditNote, err := ih.svc.IssueCreditNote(ctx, uri.ID, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newInvoiceResponse(creditNote)
+
+	handleSuccess(ctx, rsp)
+}
+
+// GetInvoice godoc
+//
+//	@Summary		Get an invoice
+//	@Description	Get an issued tax invoice or credit note by id
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Invoice ID"
+//	@Success		200	{object}	invoiceResponse	"Invoice displayed"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/invoices/{id} [get]
+//	@Security		BearerAuth
+func (ih *InvoiceHandler) GetInvoice(ctx *gin.Context) {
+	var req getInvoiceRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	invoice, err := ih.svc.GetInvoice(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newInvoiceResponse(invoice)
+
+	handleSuccess(ctx, rsp)
+}
+
+// ListBookingInvoices godoc
+//
+//	@Summary		List a booking's invoices
+//	@Description	List the tax invoices and credit notes issued for a booking in issue order
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64				true	"Booking ID"
+//	@Success		200	{array}		invoiceResponse		"Invoices displayed"
+//	@Failure		400	{object}	errorResponse		"Validation error"
+//	@Failure		404	{object}	errorResponse		"Data not found error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/invoices/booking/{id} [get]
+//	@Security		BearerAuth
+func (ih *InvoiceHandler) ListBookingInvoices(ctx *gin.Context) {
+	var req getInvoiceRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	invoices, err := ih.svc.ListBookingInvoices(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []invoiceResponse{}
+	for _, invoice := range invoices {
+		rsp = append(rsp, newInvoiceResponse(&invoice))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// createInvoiceSeriesRequest represents the request body for creating an invoice number series
+type createInvoiceSeriesRequest struct {
+	Code        string             `json:"code" binding:"required" example:"INV-OUTLET"`
+	InvoiceType domain.InvoiceType `json:"invoice_type" binding:"required,min=1,max=2" example:"1"`
+	Prefix      string             `json:"prefix" binding:"required" example:"OUT"`
+	YearlyReset bool               `json:"yearly_reset" example:"true"`
+	Padding     int                `json:"padding" binding:"required,min=1,max=12" example:"6"`
+}
+
+// CreateInvoiceSeries godoc
+//
+//	@Summary		Create an invoice series
+//	@Description	Create a new invoice number series (admin only)
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			createInvoiceSeriesRequest	body		createInvoiceSeriesRequest	true	"Create invoice series request"
+//	@Success		200							{object}	invoiceSeriesResponse		"Invoice series created"
+//	@Failure		400							{object}	errorResponse				"Validation error"
+//	@Failure		403							{object}	errorResponse				"Forbidden error"
+//	@Failure		409							{object}	errorResponse				"Data conflict error"
+//	@Failure		500							{object}	errorResponse				"Internal server error"
+//	@Router			/invoice-series [post]
+//	@Security		BearerAuth
+func (ih *InvoiceHandler) CreateInvoiceSeries(ctx *gin.Context) {
+	var req createInvoiceSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	series := domain.InvoiceSeries{
+		Code:        req.Code,
+		InvoiceType: req.InvoiceType,
+		Prefix:      req.Prefix,
+		YearlyReset: req.YearlyReset,
+		Padding:     req.Padding,
+	}
+
+	createdSeries, err := ih.svc.CreateInvoiceSeries(ctx, &series)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newInvoiceSeriesResponse(createdSeries)
+
+	handleSuccess(ctx, rsp)
+}
+
+// ListInvoiceSeries godoc
+//
+//	@Summary		List invoice series
+//	@Description	List all invoice number series
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		invoiceSeriesResponse	"Invoice series displayed"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/invoice-series [get]
+//	@Security		BearerAuth
+func (ih *InvoiceHandler) ListInvoiceSeries(ctx *gin.Context) {
+	seriesList, err := ih.svc.ListInvoiceSeries(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []invoiceSeriesResponse{}
+	for _, series := range seriesList {
+		rsp = append(rsp, newInvoiceSeriesResponse(&series))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// updateInvoiceSeriesRequest represents the request body for updating an invoice number series
+type updateInvoiceSeriesRequest struct {
+	ID        uint64 `json:"id" binding:"required" example:"1"`
+	Prefix    string `json:"prefix" binding:"required" example:"INV"`
+	Padding   int    `json:"padding" binding:"required,min=1,max=12" example:"6"`
+	IsDefault bool   `json:"is_default" example:"true"`
+}
+
+// UpdateInvoiceSeries godoc
+//
+//	@Summary		Update an invoice series
+//	@Description	Update the prefix, padding or default flag of an invoice number series (admin only)
+//	@Tags			Invoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			updateInvoiceSeriesRequest	body		updateInvoiceSeriesRequest	true	"Update invoice series request"
+//	@Success		200							{object}	invoiceSeriesResponse		"Invoice series updated"
+//	@Failure		400							{object}	errorResponse				"Validation error"
+//	@Failure		403							{object}	errorResponse				"Forbidden error"
+//	@Failure		404							{object}	errorResponse				"Data not found error"
+//	@Failure		409							{object}	errorResponse				"Data conflict error"
+//	@Failure		500							{object}	errorResponse				"Internal server error"
+//	@Router			/invoice-series [put]
+//	@Security		BearerAuth
+func (ih *InvoiceHandler) UpdateInvoiceSeries(ctx *gin.Context) {
+	var req updateInvoiceSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	series := domain.InvoiceSeries{
+		ID:        req.ID,
+		Prefix:    req.Prefix,
+		Padding:   req.Padding,
+		IsDefault: req.IsDefault,
+	}
+
+	updatedSeries, err := ih.svc.UpdateInvoiceSeries(ctx, &series)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newInvoiceSeriesResponse(updatedSeries)
+
+	handleSuccess(ctx, rsp)
+}
+
+// invoiceResponse represents the response body for an issued invoice or credit note
+type invoiceResponse struct {
+	ID                uint64             `json:"id" example:"1"`
+	SeriesID          uint64             `json:"series_id" example:"1"`
+	Number            string             `json:"number" example:"INV2024-000123"`
+	InvoiceType       domain.InvoiceType `json:"invoice_type" example:"1"`
+	BookingID         uint64             `json:"booking_id" example:"1"`
+	OriginalInvoiceID *uint64            `json:"original_invoice_id" example:"1"`
+	Subtotal          float64            `json:"subtotal" example:"2800.00"`
+	VAT               float64            `json:"vat" example:"196.00"`
+	Total             float64            `json:"total" example:"2996.00"`
+	Reason            string             `json:"reason" example:"Issued to the wrong company"`
+	IssuedBy          *uint64            `json:"issued_by" example:"1"`
+	IssuedAt          *time.Time         `json:"issued_at" example:"2024-08-01T15:04:05Z"`
+	PayerID           *uint64            `json:"payer_id" example:"1"`
+	Cancelled         bool               `json:"cancelled" example:"false"`
+}
+
+// newInvoiceResponse creates a new invoice response
+func newInvoiceResponse(invoice *domain.Invoice) invoiceResponse {
+	return invoiceResponse{
+		ID:                invoice.ID,
+		SeriesID:          invoice.SeriesID,
+		Number:            invoice.Number,
+		InvoiceType:       invoice.InvoiceType,
+		BookingID:         invoice.BookingID,
+		OriginalInvoiceID: invoice.OriginalInvoiceID,
+		Subtotal:          invoice.Subtotal,
+		VAT:               invoice.VAT,
+		Total:             invoice.Total,
+		Reason:            invoice.Reason,
+		IssuedBy:          invoice.IssuedBy,
+		IssuedAt:          invoice.IssuedAt,
+		PayerID:           invoice.PayerID,
+		Cancelled:         invoice.Cancelled,
+	}
+}
+
+// invoiceSeriesResponse represents the response body for an invoice number series
+type invoiceSeriesResponse struct {
+	ID          uint64             `json:"id" example:"1"`
+	Code        string             `json:"code" example:"INV"`
+	InvoiceType domain.InvoiceType `json:"invoice_type" example:"1"`
+	Prefix      string             `json:"prefix" example:"INV"`
+	YearlyReset bool               `json:"yearly_reset" example:"true"`
+	Padding     int                `json:"padding" example:"6"`
+	IsDefault   bool               `json:"is_default" example:"true"`
+	CreatedAt   *time.Time         `json:"created_at" example:"2024-08-01T15:04:05Z"`
+	UpdatedAt   *time.Time         `json:"updated_at" example:"2024-08-01T15:04:05Z"`
+}
+
+// newInvoiceSeriesResponse creates a new invoice series response
+func newInvoiceSeriesResponse(series *domain.InvoiceSeries) invoiceSeriesResponse {
+	return invoiceSeriesResponse{
+		ID:          series.ID,
+		Code:        series.Code,
+		InvoiceType: series.InvoiceType,
+		Prefix:      series.Prefix,
+		YearlyReset: series.YearlyReset,
+		Padding:     series.Padding,
+		IsDefault:   series.IsDefault,
+		CreatedAt:   series.CreatedAt,
+		UpdatedAt:   series.UpdatedAt,
+	}
+}
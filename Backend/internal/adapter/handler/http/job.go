@@ -0,0 +1,95 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler represents the HTTP handler for the run history of scheduled jobs
+type JobHandler struct {
+	svc port.JobService
+}
+
+// NewJobHandler creates a new JobHandler instance
+func NewJobHandler(svc port.JobService) *JobHandler {
+	return &JobHandler{
+		svc,
+	}
+}
+
+// listJobRunsRequest represents the request body for listing job runs
+type listJobRunsRequest struct {
+	Job   string `form:"job" example:"daily_summary"`
+	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=1" example:"10"`
+}
+
+// ListJobRuns godoc
+//
+//	@Summary		List scheduled job runs
+//	@Description	List the attempts of scheduled jobs, latest first. Only admins can list them.
+//	@Tags			Jobs
+//	@Accept			json
+//	@Produce		json
+//	@Param			job		query		string			false	"Job name"
+//	@Param			skip	query		uint64			false	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Job runs displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/jobs/runs [get]
+//	@Security		BearerAuth
+func (jh *JobHandler) ListJobRuns(ctx *gin.Context) {
+	var req listJobRunsRequest
+	var runsList []jobRunResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	runs, totalCount, err := jh.svc.ListJobRuns(ctx, req.Job, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, run := range runs {
+		runsList = append(runsList, newJobRunResponse(&run))
+	}
+
+	meta := newMeta(totalCount, req.Limit, req.Skip)
+	rsp := toMap(meta, runsList, "job_runs")
+
+	handleSuccess(ctx, rsp)
+}
+
+// jobRunResponse represents a job run response body
+type jobRunResponse struct {
+	ID           uint64              `json:"id" example:"1"`
+	JobName      string              `json:"job_name" example:"daily_summary"`
+	TargetDate   string              `json:"target_date" example:"2024-08-01"`
+	Status       domain.JobRunStatus `json:"status" example:"2"`
+	Attempt      int                 `json:"attempt" example:"1"`
+	ErrorMessage string              `json:"error_message" example:""`
+	StartedAt    *time.Time          `json:"started_at" example:"2024-08-02T00:30:00Z"`
+	FinishedAt   *time.Time          `json:"finished_at" example:"2024-08-02T00:30:02Z"`
+}
+
+// newJobRunResponse creates a new job run response
+func newJobRunResponse(run *domain.JobRun) jobRunResponse {
+	return jobRunResponse{
+		ID:           run.ID,
+		JobName:      run.JobName,
+		TargetDate:   run.TargetDate.Format("2006-01-02"),
+		Status:       run.Status,
+		Attempt:      run.Attempt,
+		ErrorMessage: run.ErrorMessage,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+	}
+}
@@ -77,6 +77,7 @@ type logResponse struct {
 	Action    string `json:"action"`
 	UserID    uint64 `json:"user_id"`
 	CreatedAt time.Time `json:"created_at"`
+	Amount    *float64 `json:"amount,omitempty"`
 }
 
 // newLogResponse creates a new log response
@@ -94,5 +95,124 @@ func newLogResponse(log *domain.Log) (logResponse, error) {
 		Action:    log.Action,
 		UserID:    log.UserID,
 		CreatedAt: createdAt,
+		Amount:    log.Amount,
 	}, nil
-}	
\ No newline at end of file
+}	
+
+// getStaffActivityRequest represents the request body for getting staff activity
+type getStaffActivityRequest struct {
+	From   string  `form:"from" binding:"required" example:"2024-08-01"`
+	To     string  `form:"to" binding:"required" example:"2024-08-31"`
+	UserID *uint64 `form:"user_id" binding:"omitempty,min=1" example:"1"`
+}
+
+// GetStaffActivity godoc
+//
+//	@Summary		Get staff activity
+//	@Description	Get the bookings created, check-ins, cancellations, payments taken and deletes, with the amount of the payments deleted, of each user
+//	@Description	per day of a date range. Only admins can get it.
+//	@Tags			Logs
+//	@Accept			json
+//	@Produce		json
+//	@Param			from	query		string					true	"First day (YYYY-MM-DD)"
+//	@Param			to		query		string					true	"Last day (YYYY-MM-DD)"
+//	@Param			user_id	query		uint64					false	"User ID"
+//	@Success		200		{object}	staffActivityResponse	"Staff activity displayed"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		403		{object}	errorResponse			"Forbidden error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/logs/activity [get]
+//	@Security		BearerAuth
+func (lh *LogHandler) GetStaffActivity(ctx *gin.Context) {
+	var req getStaffActivityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	filter := &domain.StaffActivityFilter{
+		From:   from,
+		To:     to,
+		UserID: req.UserID,
+	}
+
+	report, err := lh.svc.GetStaffActivity(ctx, filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newStaffActivityResponse(report)
+
+	handleSuccess(ctx, rsp)
+}
+
+// staffActivityCountsResponse represents the work a user logged
+type staffActivityCountsResponse struct {
+	BookingsCreated int     `json:"bookings_created" example:"12"`
+	CheckIns        int     `json:"check_ins" example:"9"`
+	Cancellations   int     `json:"cancellations" example:"1"`
+	PaymentsTaken   int     `json:"payments_taken" example:"10"`
+	PaymentsAmount  float64 `json:"payments_amount" example:"15000.00"`
+	Deletes         int     `json:"deletes" example:"0"`
+	DeletedAmount   float64 `json:"deleted_amount" example:"0.00"`
+}
+
+// staffActivityDayResponse represents the work a user logged on a day
+type staffActivityDayResponse struct {
+	Date     string `json:"date" example:"2024-08-01"`
+	UserID   uint64 `json:"user_id" example:"1"`
+	UserName string `json:"username" example:"frontdesk"`
+	staffActivityCountsResponse
+}
+
+// staffActivityResponse represents a staff activity response body
+type staffActivityResponse struct {
+	From  string                      `json:"from" example:"2024-08-01"`
+	To    string                      `json:"to" example:"2024-08-31"`
+	Total staffActivityCountsResponse `json:"total"`
+	Days  []staffActivityDayResponse  `json:"days"`
+}
+
+func newStaffActivityCountsResponse(counts domain.StaffActivityCounts) staffActivityCountsResponse {
+	return staffActivityCountsResponse{
+		BookingsCreated: counts.BookingsCreated,
+		CheckIns:        counts.CheckIns,
+		Cancellations:   counts.Cancellations,
+		PaymentsTaken:   counts.PaymentsTaken,
+		PaymentsAmount:  counts.PaymentsAmount,
+		Deletes:         counts.Deletes,
+		DeletedAmount:   counts.DeletedAmount,
+	}
+}
+
+// newStaffActivityResponse creates a new staff activity response
+func newStaffActivityResponse(report *domain.StaffActivityReport) staffActivityResponse {
+	days := make([]staffActivityDayResponse, 0, len(report.Days))
+	for _, day := range report.Days {
+		days = append(days, staffActivityDayResponse{
+			Date:                        day.Date.Format("2006-01-02"),
+			UserID:                      day.UserID,
+			UserName:                    day.UserName,
+			staffActivityCountsResponse: newStaffActivityCountsResponse(day.StaffActivityCounts),
+		})
+	}
+
+	return staffActivityResponse{
+		From:  report.Filter.From.Format("2006-01-02"),
+		To:    report.Filter.To.Format("2006-01-02"),
+		Total: newStaffActivityCountsResponse(report.Total),
+		Days:  days,
+	}
+}
@@ -0,0 +1,252 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// LoyaltyHandler represents the HTTP handler for the loyalty points of customers
+type LoyaltyHandler struct {
+	svc port.LoyaltyService
+}
+
+// NewLoyaltyHandler creates a new LoyaltyHandler instance
+func NewLoyaltyHandler(svc port.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		svc,
+	}
+}
+
+// GetLoyaltyAccount godoc
+//
+//	@Summary		Get a loyalty account
+//	@Description	Get a customer's loyalty points balance, its value, their tier and the points to the next tier
+//	@Tags			Loyalty
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64					true	"Customer ID"
+//	@Success		200	{object}	loyaltyAccountResponse	"Loyalty account displayed"
+//	@Failure		400	{object}	errorResponse			"Validation error"
+//	@Failure		404	{object}	errorResponse			"Data not found error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/customers/{id}/loyalty [get]
+//	@Security		BearerAuth
+func (lh *LoyaltyHandler) GetLoyaltyAccount(ctx *gin.Context) {
+	var req getCustomerRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	account, err := lh.svc.GetAccount(ctx, req.CustomerID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newLoyaltyAccountResponse(account)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listLoyaltyTransactionsRequest represents the request body for listing a customer's loyalty transactions
+type listLoyaltyTransactionsRequest struct {
+	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=1" example:"10"`
+}
+
+// ListLoyaltyTransactions godoc
+//
+//	@Summary		List loyalty transactions
+//	@Description	List the earned, redeemed, expired, reinstated and taken back points of a customer, latest first
+//	@Tags			Loyalty
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		uint64			true	"Customer ID"
+//	@Param			skip	query		uint64			false	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Loyalty transactions displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/customers/{id}/loyalty/transactions [get]
+//	@Security		BearerAuth
+func (lh *LoyaltyHandler) ListLoyaltyTransactions(ctx *gin.Context) {
+	var uri getCustomerRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req listLoyaltyTransactionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	transactions, totalCount, err := lh.svc.ListTransactions(ctx, uri.CustomerID, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	transactionsList := []loyaltyTransactionResponse{}
+	for _, transaction := range transactions {
+		transactionsList = append(transactionsList, newLoyaltyTransactionResponse(&transaction))
+	}
+
+	meta := newMeta(totalCount, req.Limit, req.Skip)
+	rsp := toMap(meta, transactionsList, "transactions")
+
+	handleSuccess(ctx, rsp)
+}
+
+// EarnLoyaltyPoints godoc
+//
+//	@Summary		Earn loyalty points for a booking
+//	@Description	Credit the guest of a completed booking with the points for what they paid. Points are earned
+//	@Description	when a booking is completed; this earns them for a booking that missed them.
+//	@Tags			Loyalty
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64						true	"Booking ID"
+//	@Success		200	{object}	loyaltyTransactionResponse	"Points earned"
+//	@Failure		400	{object}	errorResponse				"Validation error"
+//	@Failure		404	{object}	errorResponse				"Data not found error"
+//	@Failure		409	{object}	errorResponse				"Points already earned"
+//	@Failure		500	{object}	errorResponse				"Internal server error"
+//	@Router			/booking/{id}/loyalty/earn [post]
+//	@Security		BearerAuth
+func (lh *LoyaltyHandler) EarnLoyaltyPoints(ctx *gin.Context) {
+	var uri getBookingRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	transaction, err := lh.svc.EarnPoints(ctx, uri.BookingID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	// Nothing was paid that earns points
+	if transaction == nil {
+		handleError(ctx, domain.ErrInvalidData)
+		return
+	}
+
+	rsp := newLoyaltyTransactionResponse(transaction)
+
+	handleSuccess(ctx, rsp)
+}
+
+// redeemLoyaltyDiscountRequest represents the request body for redeeming loyalty points as a discount
+type redeemLoyaltyDiscountRequest struct {
+	Points int `json:"points" binding:"required,min=1" example:"500"`
+}
+
+// RedeemLoyaltyDiscount godoc
+//
+//	@Summary		Redeem loyalty points as a discount
+//	@Description	Spend the guest's loyalty points on an adjustment worth their value, credited to the booking folio.
+//	@Description	To pay with points instead, create a payment with the loyalty points payment method.
+//	@Tags			Loyalty
+//	@Accept			json
+//	@Produce		json
+//	@Param			id								path		uint64							true	"Booking ID"
+//	@Param			redeemLoyaltyDiscountRequest	body		redeemLoyaltyDiscountRequest	true	"Redeem discount request"
+//	@Success		200								{object}	loyaltyTransactionResponse		"Points redeemed"
+//	@Failure		400								{object}	errorResponse					"Validation error"
+//	@Failure		404								{object}	errorResponse					"Data not found error"
+//	@Failure		409								{object}	errorResponse					"Not enough points"
+//	@Failure		500								{object}	errorResponse					"Internal server error"
+//	@Router			/booking/{id}/loyalty/redeem [post]
+//	@Security		BearerAuth
+func (lh *LoyaltyHandler) RedeemLoyaltyDiscount(ctx *gin.Context) {
+	var uri getBookingRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req redeemLoyaltyDiscountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	transaction, err := lh.svc.RedeemDiscount(ctx, uri.BookingID, req.Points)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newLoyaltyTransactionResponse(transaction)
+
+	handleSuccess(ctx, rsp)
+}
+
+// loyaltyTierResponse represents the response body for a loyalty tier
+type loyaltyTierResponse struct {
+	Name      string `json:"name" example:"Gold"`
+	MinPoints int    `json:"min_points" example:"5000"`
+}
+
+// loyaltyAccountResponse represents the response body for a loyalty account
+type loyaltyAccountResponse struct {
+	CustomerID       uint64               `json:"customer_id" example:"1"`
+	Balance          int                  `json:"balance" example:"1250"`
+	BalanceValue     float64              `json:"balance_value" example:"1250.00"`
+	QualifyingPoints int                  `json:"qualifying_points" example:"3400"`
+	Tier             loyaltyTierResponse  `json:"tier"`
+	NextTier         *loyaltyTierResponse `json:"next_tier"`
+	PointsToNextTier int                  `json:"points_to_next_tier" example:"1600"`
+}
+
+// newLoyaltyAccountResponse creates a new loyalty account response
+func newLoyaltyAccountResponse(account *domain.LoyaltyAccount) loyaltyAccountResponse {
+	rsp := loyaltyAccountResponse{
+		CustomerID:       account.CustomerID,
+		Balance:          account.Balance,
+		BalanceValue:     account.BalanceValue,
+		QualifyingPoints: account.QualifyingPoints,
+		Tier:             loyaltyTierResponse{account.Tier.Name, account.Tier.MinPoints},
+		PointsToNextTier: account.PointsToNextTier,
+	}
+	if account.NextTier != nil {
+		rsp.NextTier = &loyaltyTierResponse{account.NextTier.Name, account.NextTier.MinPoints}
+	}
+	return rsp
+}
+
+// loyaltyTransactionResponse represents the response body for a loyalty transaction
+type loyaltyTransactionResponse struct {
+	ID          uint64                        `json:"id" example:"1"`
+	CustomerID  uint64                        `json:"customer_id" example:"1"`
+	Type        domain.LoyaltyTransactionType `json:"type" example:"1"`
+	Points      int                           `json:"points" example:"224"`
+	BookingID   *uint64                       `json:"booking_id" example:"1"`
+	PaymentID   *uint64                       `json:"payment_id" example:"7"`
+	Description string                        `json:"description" example:"Stay of booking 1, 5600.00 THB"`
+	ExpiresAt   *time.Time                    `json:"expires_at" example:"2026-08-01T15:04:05Z"`
+	CreatedBy   *uint64                       `json:"created_by" example:"1"`
+	CreatedAt   *time.Time                    `json:"created_at" example:"2024-08-01T15:04:05Z"`
+}
+
+// newLoyaltyTransactionResponse creates a new loyalty transaction response
+func newLoyaltyTransactionResponse(transaction *domain.LoyaltyTransaction) loyaltyTransactionResponse {
+	return loyaltyTransactionResponse{
+		ID:          transaction.ID,
+		CustomerID:  transaction.CustomerID,
+		Type:        transaction.Type,
+		Points:      transaction.Points,
+		BookingID:   transaction.BookingID,
+		PaymentID:   transaction.PaymentID,
+		Description: transaction.Description,
+		ExpiresAt:   transaction.ExpiresAt,
+		CreatedBy:   transaction.CreatedBy,
+		CreatedAt:   transaction.CreatedAt,
+	}
+}
@@ -1,6 +1,11 @@
 package http
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
 	"github.com/gin-gonic/gin"
 	"github.com/Coke3a/HotelManagement/internal/adapter/config"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
@@ -12,7 +17,7 @@ func CORSMiddleware(config *config.HTTP) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", config.AllowedOrigins)
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Access-Control-Expose-Headers")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Access-Control-Expose-Headers, Idempotency-Key")
 		c.Header("Access-Control-Allow-Methods", "POST,HEAD,PATCH, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -38,6 +43,7 @@ func ExtractUserID() gin.HandlerFunc {
 			return
 		}
 		c.Set("userID", payload.UserID)
+		c.Set("userRole", payload.Role)
 		c.Next()
 	}
 }
@@ -68,3 +74,82 @@ func TokenAuthMiddleware(tokenService port.TokenService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// idempotencyKeyHeader is the request header clients set to make a create request safe to retry
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// responseRecorder copies what a handler writes so the response can be stored
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware honors the Idempotency-Key header. The successful response of the first request with a key
+// is stored and replayed to retries with the same body; reusing the key for a different request is rejected.
+// A retry while the first request is in progress is rejected, until the first request's lease on the key runs
+// out. Requests without the header are handled as usual.
+func IdempotencyMiddleware(svc port.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyValue := c.GetHeader(idempotencyKeyHeader)
+		if keyValue == "" {
+			c.Next()
+			return
+		}
+		if len(keyValue) > 255 {
+			validationError(c, domain.ErrInvalidData)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			validationError(c, err)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash := sha256.Sum256(body)
+
+		key, err := svc.StartRequest(c, &domain.IdempotencyKey{
+			Key:         keyValue,
+			Method:      c.Request.Method,
+			Path:        c.FullPath(),
+			RequestHash: hex.EncodeToString(hash[:]),
+		})
+		if err != nil {
+			handleAbort(c, err)
+			return
+		}
+		if key.IsCompleted() {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(key.ResponseStatus, "application/json; charset=utf-8", key.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{c.Writer, &bytes.Buffer{}}
+		c.Writer = recorder
+		c.Next()
+
+		// Only a success is kept; after an error the client may fix the cause and retry with the same key
+		status := recorder.Status()
+		if status >= http.StatusOK && status < http.StatusMultipleChoices {
+			err = svc.CompleteRequest(c, key, status, recorder.body.Bytes())
+		} else {
+			err = svc.ReleaseRequest(c, key)
+		}
+		if err != nil {
+			slog.Error("Error saving idempotency key", "key", keyValue, "error", err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// stubIdempotencyService answers StartRequest with key or err and records what the middleware calls next
+type stubIdempotencyService struct {
+	key *domain.IdempotencyKey
+	err error
+
+	started   *domain.IdempotencyKey
+	completed bool
+	released  bool
+	status    int
+	body      string
+}
+
+func (s *stubIdempotencyService) StartRequest(ctx *gin.Context, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	s.started = key
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.key != nil {
+		return s.key, nil
+	}
+	return key, nil
+}
+
+func (s *stubIdempotencyService) CompleteRequest(ctx *gin.Context, key *domain.IdempotencyKey, status int, body []byte) error {
+	s.completed, s.status, s.body = true, status, string(body)
+	return nil
+}
+
+func (s *stubIdempotencyService) ReleaseRequest(ctx *gin.Context, key *domain.IdempotencyKey) error {
+	s.released = true
+	return nil
+}
+
+func (s *stubIdempotencyService) DeleteExpiredKeys(ctx context.Context, asOf time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	completedAt := time.Now()
+
+	tests := []struct {
+		name          string
+		key           string
+		svc           *stubIdempotencyService
+		handlerStatus int
+		wantStatus    int
+		wantBody      string
+		wantHandled   bool
+		wantReplayed  bool
+		wantCompleted bool
+		wantReleased  bool
+	}{
+		{
+			name:          "no key",
+			svc:           &stubIdempotencyService{},
+			handlerStatus: http.StatusCreated,
+			wantStatus:    http.StatusCreated,
+			wantBody:      `{"id":1}`,
+			wantHandled:   true,
+		},
+		{
+			name:          "first request succeeds",
+			key:           "k1",
+			svc:           &stubIdempotencyService{},
+			handlerStatus: http.StatusCreated,
+			wantStatus:    http.StatusCreated,
+			wantBody:      `{"id":1}`,
+			wantHandled:   true,
+			wantCompleted: true,
+		},
+		{
+			name:          "first request fails",
+			key:           "k1",
+			svc:           &stubIdempotencyService{},
+			handlerStatus: http.StatusBadRequest,
+			wantStatus:    http.StatusBadRequest,
+			wantBody:      `{"id":1}`,
+			wantHandled:   true,
+			wantReleased:  true,
+		},
+		{
+			name: "retry replays the stored response",
+			key:  "k1",
+			svc: &stubIdempotencyService{key: &domain.IdempotencyKey{
+				Key:            "k1",
+				ResponseStatus: http.StatusCreated,
+				ResponseBody:   []byte(`{"id":42}`),
+				CompletedAt:    &completedAt,
+			}},
+			wantStatus:   http.StatusCreated,
+			wantBody:     `{"id":42}`,
+			wantReplayed: true,
+		},
+		{
+			name:       "retry while in progress",
+			key:        "k1",
+			svc:        &stubIdempotencyService{err: domain.ErrIdempotencyKeyInProgress},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "key reused for another request",
+			key:        "k1",
+			svc:        &stubIdempotencyService{err: domain.ErrIdempotencyKeyMismatch},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "key too long",
+			key:        strings.Repeat("k", 256),
+			svc:        &stubIdempotencyService{},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled := false
+			router := gin.New()
+			router.POST("/payments", IdempotencyMiddleware(tt.svc), func(c *gin.Context) {
+				handled = true
+				c.Data(tt.handlerStatus, "application/json; charset=utf-8", []byte(`{"id":1}`))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{"amount":100}`))
+			if tt.key != "" {
+				req.Header.Set(idempotencyKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", rec.Body.String(), tt.wantBody)
+			}
+			if handled != tt.wantHandled {
+				t.Errorf("handler ran = %v, want %v", handled, tt.wantHandled)
+			}
+			if replayed := rec.Header().Get("Idempotent-Replayed") == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+			if tt.svc.completed != tt.wantCompleted {
+				t.Errorf("completed = %v, want %v", tt.svc.completed, tt.wantCompleted)
+			}
+			if tt.svc.released != tt.wantReleased {
+				t.Errorf("released = %v, want %v", tt.svc.released, tt.wantReleased)
+			}
+			if tt.wantCompleted && (tt.svc.status != tt.handlerStatus || tt.svc.body != `{"id":1}`) {
+				t.Errorf("stored response = %d %s, want %d %s", tt.svc.status, tt.svc.body, tt.handlerStatus, `{"id":1}`)
+			}
+			if tt.key == "k1" && tt.svc.started != nil {
+				if tt.svc.started.Method != http.MethodPost || tt.svc.started.Path != "/payments" || tt.svc.started.RequestHash == "" {
+					t.Errorf("started key = %+v, want the method, route and body hash of the request", tt.svc.started)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// NightAuditHandler represents the HTTP handler for the night audit and the hotel's business date
+type NightAuditHandler struct {
+	svc port.NightAuditService
+}
+
+// NewNightAuditHandler creates a new NightAuditHandler instance
+func NewNightAuditHandler(svc port.NightAuditService) *NightAuditHandler {
+	return &NightAuditHandler{
+		svc,
+	}
+}
+
+// businessDateResponse represents the business date response body
+type businessDateResponse struct {
+	BusinessDate string `json:"business_date" example:"2024-08-01"`
+}
+
+// GetBusinessDate godoc
+//
+//	@Summary		Get the business date
+//	@Description	Get the hotel's current business date, which only moves forward when the night audit runs
+//	@Tags			Night Audit
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	businessDateResponse	"Business date displayed"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/night-audit/business-date [get]
+//	@Security		BearerAuth
+func (nah *NightAuditHandler) GetBusinessDate(ctx *gin.Context) {
+	businessDate, err := nah.svc.GetBusinessDate(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := businessDateResponse{
+		BusinessDate: businessDate.Format("2006-01-02"),
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// RunNightAudit godoc
+//
+//	@Summary		Run the night audit
+//	@Description	Close the current business date: post room nights to in-house bookings, flag no-shows,
+//	@Description	report unbalanced folios of departures, generate the daily summary, lock the day and
+//	@Description	advance the business date. Only admins can run it.
+//	@Tags			Night Audit
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	nightAuditResponse	"Night audit completed"
+//	@Failure		403	{object}	errorResponse		"Forbidden error"
+//	@Failure		409	{object}	errorResponse		"The business date is already being audited"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/night-audit/run [post]
+//	@Security		BearerAuth
+func (nah *NightAuditHandler) RunNightAudit(ctx *gin.Context) {
+	audit, err := nah.svc.RunNightAudit(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newNightAuditResponse(audit)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listNightAuditsRequest represents the request body for listing night audits
+type listNightAuditsRequest struct {
+	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=1" example:"10"`
+}
+
+// ListNightAudits godoc
+//
+//	@Summary		List night audits
+//	@Description	List night audit runs, latest business date first
+//	@Tags			Night Audit
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		uint64			false	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Night audits displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/night-audit [get]
+//	@Security		BearerAuth
+func (nah *NightAuditHandler) ListNightAudits(ctx *gin.Context) {
+	var req listNightAuditsRequest
+	var auditsList []nightAuditResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	audits, totalCount, err := nah.svc.ListNightAudits(ctx, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, audit := range audits {
+		auditsList = append(auditsList, newNightAuditResponse(&audit))
+	}
+
+	meta := newMeta(totalCount, req.Limit, req.Skip)
+	rsp := toMap(meta, auditsList, "night_audits")
+
+	handleSuccess(ctx, rsp)
+}
+
+// nightAuditResponse represents a night audit response body
+type nightAuditResponse struct {
+	ID                 uint64                  `json:"id" example:"1"`
+	BusinessDate       string                  `json:"business_date" example:"2024-08-01"`
+	Status             domain.NightAuditStatus `json:"status" example:"2"`
+	RoomNightsPosted   int                     `json:"room_nights_posted" example:"42"`
+	RoomRevenue        float64                 `json:"room_revenue" example:"63000.00"`
+	NoShowBookings     []uint64                `json:"no_show_bookings"`
+	UnbalancedBookings []uint64                `json:"unbalanced_bookings"`
+	ErrorMessage       string                  `json:"error_message" example:""`
+	RunBy              *uint64                 `json:"run_by" example:"1"`
+	StartedAt          *time.Time              `json:"started_at" example:"2024-08-01T23:30:00Z"`
+	CompletedAt        *time.Time              `json:"completed_at" example:"2024-08-01T23:31:05Z"`
+}
+
+// newNightAuditResponse creates a new night audit response
+func newNightAuditResponse(audit *domain.NightAudit) nightAuditResponse {
+	return nightAuditResponse{
+		ID:                 audit.ID,
+		BusinessDate:       audit.BusinessDate.Format("2006-01-02"),
+		Status:             audit.Status,
+		RoomNightsPosted:   audit.RoomNightsPosted,
+		RoomRevenue:        audit.RoomRevenue,
+		NoShowBookings:     bookingIDs(audit.NoShowBookings),
+		UnbalancedBookings: bookingIDs(audit.UnbalancedBookings),
+		ErrorMessage:       audit.ErrorMessage,
+		RunBy:              audit.RunBy,
+		StartedAt:          audit.StartedAt,
+		CompletedAt:        audit.CompletedAt,
+	}
+}
+
+// bookingIDs returns ids, or an empty list so the response never holds null
+func bookingIDs(ids []uint64) []uint64 {
+	if ids == nil {
+		return []uint64{}
+	}
+	return ids
+}
@@ -28,20 +28,29 @@ type createPaymentRequest struct {
 	Amount        float64 `json:"amount" binding:"required,gt=0" example:"1000.50"`
 	PaymentMethod domain.PaymentMethod  `json:"payment_method" binding:"required" example:"1"`
 	Status        domain.PaymentStatus  `json:"status" binding:"required" example:"1"`
+	Currency      string  `json:"currency" binding:"omitempty,len=3" example:"USD"`
+	PaymentType   domain.PaymentType  `json:"payment_type" example:"3"`
+	CardToken     string  `json:"card_token" example:"tok_test_visa"`
+	PayerID       *uint64 `json:"payer_id" example:"1"`
 }
 
 // CreatePayment godoc
 //
 //	@Summary		Process a payment
-//	@Description	Create a new payment for a booking
+//	@Description	Create a new payment for a booking. Card payments with a card token are charged through the payment gateway.
 //	@Tags			Payments
 //	@Accept			json
 //	@Produce		json
 //	@Param			createPaymentRequest	body		createPaymentRequest	true	"Create payment request"
+//	@Param			Idempotency-Key			header		string					false	"Key that makes retries replay the first response"
 //	@Success		200					{object}	paymentResponse		"Payment processed"
 //	@Failure		400					{object}	errorResponse		"Validation error"
+//	@Failure		402					{object}	errorResponse		"Card declined"
+//	@Failure		404					{object}	errorResponse		"Payer not found on the booking"
 //	@Failure		409					{object}	errorResponse		"Data conflict error"
+//	@Failure		422					{object}	errorResponse		"Idempotency key used with a different request"
 //	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Failure		502					{object}	errorResponse		"Payment gateway error"
 //	@Router			/payments [post]
 func (ph *PaymentHandler) CreatePayment(ctx *gin.Context) {
 	var req createPaymentRequest
@@ -55,9 +64,12 @@ func (ph *PaymentHandler) CreatePayment(ctx *gin.Context) {
 		Amount:        req.Amount,
 		PaymentMethod: domain.PaymentMethod(req.PaymentMethod),
 		Status:        domain.PaymentStatus(req.Status),
+		Currency:      req.Currency,
+		Type:          req.PaymentType,
+		PayerID:       req.PayerID,
 	}
 
-	createdPayment, err := ph.svc.ProcessPayment(ctx, &payment)
+	createdPayment, err := ph.svc.ProcessPayment(ctx, &payment, req.CardToken)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -173,18 +185,66 @@ func (ph *PaymentHandler) GetPayment(ctx *gin.Context) {
 	handleSuccess(ctx, rsp)
 }
 
+// listBookingPaymentsRequest represents the request body for listing the payments of a booking
+type listBookingPaymentsRequest struct {
+	BookingID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// ListBookingPayments godoc
+//
+//	@Summary		List booking payments
+//	@Description	List the deposit, balance and top-up payments taken for a booking
+//	@Tags			Payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64				true	"Booking ID"
+//	@Success		200	{array}		paymentResponse		"Payments displayed"
+//	@Failure		400	{object}	errorResponse		"Validation error"
+//	@Failure		404	{object}	errorResponse		"Data not found error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/payments/booking/{id} [get]
+//	@Security		BearerAuth
+func (ph *PaymentHandler) ListBookingPayments(ctx *gin.Context) {
+	var req listBookingPaymentsRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payments, err := ph.svc.ListPaymentsByBookingID(ctx, req.BookingID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	paymentsList := []paymentResponse{}
+	for _, payment := range payments {
+		rsp, err := newPaymentResponse(&payment)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		paymentsList = append(paymentsList, rsp)
+	}
+
+	handleSuccess(ctx, paymentsList)
+}
+
 // updatePaymentRequest represents the request body for updating a payment
 type updatePaymentRequest struct {
 	ID            uint64  `json:"id" binding:"required" example:"1"`
 	Amount        float64 `json:"amount" binding:"required" example:"1000.50"`
 	PaymentMethod domain.PaymentMethod  `json:"payment_method" example:"0"`
 	Status        int  `json:"status" binding:"required" example:"0"`
+	Currency      string  `json:"currency" binding:"omitempty,len=3" example:"USD"`
+	PaymentType   domain.PaymentType  `json:"payment_type" example:"2"`
+	CardToken     string  `json:"card_token" example:"tok_test_visa"`
 }
 
 // UpdatePayment godoc
 //
 //	@Summary		Update a payment
-//	@Description	Update a payment's details by id
+//	@Description	Update a payment's details by id. Settling a card payment charges the card token given.
 //	@Tags			Payments
 //	@Accept			json
 //	@Produce		json
@@ -208,9 +268,11 @@ func (ph *PaymentHandler) UpdatePayment(ctx *gin.Context) {
 		Amount:        req.Amount,
 		PaymentMethod: domain.PaymentMethod(req.PaymentMethod),
 		Status:        domain.PaymentStatus(req.Status),
+		Currency:      req.Currency,
+		Type:          req.PaymentType,
 	}
 
-	updatedPayment, err := ph.svc.UpdatePayment(ctx, &payment)
+	updatedPayment, err := ph.svc.UpdatePayment(ctx, &payment, req.CardToken)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -260,6 +322,185 @@ func (ph *PaymentHandler) DeletePayment(ctx *gin.Context) {
 	handleSuccess(ctx, "Payment deleted successfully")
 }
 
+// reversePaymentUri represents the path parameters for refunding or voiding a payment
+type reversePaymentUri struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// refundPaymentRequest represents the request body for refunding a payment
+type refundPaymentRequest struct {
+	Amount     float64               `json:"amount" binding:"required,gt=0" example:"500.00"`
+	ReasonCode domain.ReversalReason `json:"reason_code" binding:"required,min=1" example:"1"`
+	ReasonNote string                `json:"reason_note" example:"Guest left one night early"`
+}
+
+// RefundPayment godoc
+//
+//	@Summary		Refund a payment
+//	@Description	Record a refund against a paid payment as a linked negative payment (admin only)
+//	@Tags			Payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id						path		uint64					true	"Payment ID"
+//	@Param			refundPaymentRequest	body		refundPaymentRequest	true	"Refund payment request"
+//	@Success		200						{object}	paymentResponse			"Payment refunded"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		409						{object}	errorResponse			"Payment already reversed"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/payments/{id}/refund [post]
+//	@Security		BearerAuth
+func (ph *PaymentHandler) RefundPayment(ctx *gin.Context) {
+	var uri reversePaymentUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req refundPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	refund, err := ph.svc.RefundPayment(ctx, uri.ID, req.Amount, req.ReasonCode, req.ReasonNote)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp, err := newPaymentResponse(refund)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// voidPaymentRequest represents the request body for voiding a payment
+type voidPaymentRequest struct {
+	ReasonCode domain.ReversalReason `json:"reason_code" binding:"required,min=1" example:"3"`
+	ReasonNote string                `json:"reason_note" example:"Card charged twice"`
+}
+
+// VoidPayment godoc
+//
+//	@Summary		Void a payment
+//	@Description	Reverse a paid payment in full with a linked negative payment (admin only)
+//	@Tags			Payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		uint64				true	"Payment ID"
+//	@Param			voidPaymentRequest	body		voidPaymentRequest	true	"Void payment request"
+//	@Success		200					{object}	paymentResponse		"Payment voided"
+//	@Failure		400					{object}	errorResponse		"Validation error"
+//	@Failure		403					{object}	errorResponse		"Forbidden error"
+//	@Failure		404					{object}	errorResponse		"Data not found error"
+//	@Failure		409					{object}	errorResponse		"Payment already reversed"
+//	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Router			/payments/{id}/void [post]
+//	@Security		BearerAuth
+func (ph *PaymentHandler) VoidPayment(ctx *gin.Context) {
+	var uri reversePaymentUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req voidPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	void, err := ph.svc.VoidPayment(ctx, uri.ID, req.ReasonCode, req.ReasonNote)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp, err := newPaymentResponse(void)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// CapturePayment godoc
+//
+//	@Summary		Capture a card payment
+//	@Description	Collect the amount held on the card of an unpaid gateway payment and mark it paid
+//	@Tags			Payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Payment ID"
+//	@Success		200	{object}	paymentResponse	"Payment captured"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Failure		502	{object}	errorResponse	"Payment gateway error"
+//	@Router			/payments/{id}/capture [post]
+//	@Security		BearerAuth
+func (ph *PaymentHandler) CapturePayment(ctx *gin.Context) {
+	var uri reversePaymentUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payment, err := ph.svc.CapturePayment(ctx, uri.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp, err := newPaymentResponse(payment)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// gatewaySignatureHeader carries the payment gateway's signature of a webhook body
+const gatewaySignatureHeader = "X-Gateway-Signature"
+
+// HandleGatewayWebhook godoc
+//
+//	@Summary		Receive a payment gateway webhook
+//	@Description	Verify a signed payment gateway notification and update the payment it refers to
+//	@Tags			Payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Gateway-Signature	header		string			true	"Webhook signature"
+//	@Success		200					{object}	response		"Webhook processed"
+//	@Failure		400					{object}	errorResponse	"Validation error"
+//	@Failure		401					{object}	errorResponse	"Invalid signature"
+//	@Failure		404					{object}	errorResponse	"Data not found error"
+//	@Failure		409					{object}	errorResponse	"Payment day locked or closed"
+//	@Failure		500					{object}	errorResponse	"Internal server error"
+//	@Router			/payment-gateway/webhook [post]
+func (ph *PaymentHandler) HandleGatewayWebhook(ctx *gin.Context) {
+	payload, err := ctx.GetRawData()
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err = ph.svc.HandleGatewayWebhook(ctx, payload, ctx.GetHeader(gatewaySignatureHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, "Webhook processed successfully")
+}
+
 // paymentResponse represents the response body for a payment
 type paymentResponse struct {
 	ID            uint64    `json:"id" example:"1"`
@@ -268,6 +509,18 @@ type paymentResponse struct {
 	PaymentMethod domain.PaymentMethod    `json:"payment_method" example:"credit_card"`
 	PaymentDate   time.Time `json:"payment_date" example:"2024-07-01T15:04:05Z"`
 	Status        domain.PaymentStatus    `json:"status" example:"0"`
+	Currency      string    `json:"currency" example:"USD"`
+	ExchangeRate  float64   `json:"exchange_rate" example:"35.25"`
+	BaseAmount    float64   `json:"base_amount" example:"35267.63"`
+	PaymentType   domain.PaymentType `json:"payment_type" example:"1"`
+	OriginalPaymentID *uint64 `json:"original_payment_id" example:"1"`
+	ReasonCode    domain.ReversalReason `json:"reason_code" example:"0"`
+	ReasonNote    string    `json:"reason_note" example:""`
+	Gateway       string    `json:"gateway" example:"fake"`
+	GatewayReference string `json:"gateway_reference" example:"fake_3f1c2a9e-8c1d-4f7a-9a3e-2b6f0d6c1e55"`
+	PayerID       *uint64   `json:"payer_id" example:"1"`
+	TakenBy       *uint64   `json:"taken_by" example:"1"`
+	ShiftID       *uint64   `json:"shift_id" example:"1"`
 }
 
 // newPaymentResponse creates a new payment response
@@ -288,5 +541,93 @@ func newPaymentResponse(payment *domain.Payment) (paymentResponse, error) {
 		PaymentMethod: domain.PaymentMethod(payment.PaymentMethod),
 		PaymentDate:   paymentDate,
 		Status:        payment.Status,
+		Currency:      payment.Currency,
+		ExchangeRate:  payment.ExchangeRate,
+		BaseAmount:    payment.BaseAmount,
+		PaymentType:   payment.Type,
+		OriginalPaymentID: payment.OriginalPaymentID,
+		ReasonCode:    payment.ReasonCode,
+		ReasonNote:    payment.ReasonNote,
+		Gateway:       payment.Gateway,
+		GatewayReference: payment.GatewayReference,
+		PayerID:       payment.PayerID,
+		TakenBy:       payment.TakenBy,
+		ShiftID:       payment.ShiftID,
 	}, nil
 }
+
+// paymentTotalsRequest represents the request body for the payment totals report
+type paymentTotalsRequest struct {
+	From string `form:"from" binding:"required" example:"2024-08-01"`
+	To   string `form:"to" binding:"required" example:"2024-08-31"`
+}
+
+// paymentCurrencyTotalResponse represents the paid totals of a single currency
+type paymentCurrencyTotalResponse struct {
+	Currency   string  `json:"currency" example:"USD"`
+	Count      uint64  `json:"count" example:"12"`
+	Amount     float64 `json:"amount" example:"1500.00"`
+	BaseAmount float64 `json:"base_amount" example:"52875.00"`
+}
+
+// paymentTotalsResponse represents the response body for the payment totals report
+type paymentTotalsResponse struct {
+	BaseCurrency    string                         `json:"base_currency" example:"THB"`
+	TotalBaseAmount float64                        `json:"total_base_amount" example:"152875.00"`
+	Currencies      []paymentCurrencyTotalResponse `json:"currencies"`
+}
+
+// GetPaymentTotals godoc
+//
+//	@Summary		Get payment totals
+//	@Description	Sum paid payments per currency between two dates, aggregated in the base currency
+//	@Tags			Payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			from	query		string					true	"From date (YYYY-MM-DD)"
+//	@Param			to		query		string					true	"To date (YYYY-MM-DD)"
+//	@Success		200		{object}	paymentTotalsResponse	"Payment totals displayed"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/payments/totals [get]
+//	@Security		BearerAuth
+func (ph *PaymentHandler) GetPaymentTotals(ctx *gin.Context) {
+	var req paymentTotalsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	totals, err := ph.svc.GetPaymentTotalsByCurrency(ctx, from, to)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := paymentTotalsResponse{
+		BaseCurrency: domain.BaseCurrency,
+		Currencies:   []paymentCurrencyTotalResponse{},
+	}
+	for _, total := range totals {
+		rsp.TotalBaseAmount += total.BaseAmount
+		rsp.Currencies = append(rsp.Currencies, paymentCurrencyTotalResponse{
+			Currency:   total.Currency,
+			Count:      total.Count,
+			Amount:     total.Amount,
+			BaseAmount: total.BaseAmount,
+		})
+	}
+
+	handleSuccess(ctx, rsp)
+}
@@ -0,0 +1,423 @@
+package http
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler represents the HTTP handler for hotel reports
+type ReportHandler struct {
+	svc port.ReportService
+}
+
+// NewReportHandler creates a new ReportHandler instance
+func NewReportHandler(svc port.ReportService) *ReportHandler {
+	return &ReportHandler{
+		svc,
+	}
+}
+
+// getKPIReportRequest represents the request body for getting a KPI report
+type getKPIReportRequest struct {
+	From     string `form:"from" binding:"required" example:"2024-08-01"`
+	To       string `form:"to" binding:"required" example:"2024-08-31"`
+	Interval string `form:"interval" binding:"omitempty,oneof=day week month" example:"day"`
+}
+
+// parse returns the date range and interval of the request, a day by default
+func (req getKPIReportRequest) parse() (time.Time, time.Time, domain.ReportInterval, error) {
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", err
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", err
+	}
+	interval := domain.ReportInterval(req.Interval)
+	if interval == "" {
+		interval = domain.ReportIntervalDay
+	}
+
+	return from, to, interval, nil
+}
+
+// GetKPIReport godoc
+//
+//	@Summary		Get the occupancy, ADR and RevPAR report
+//	@Description	Get occupancy, average daily rate, revenue per available room, room nights sold and revenue
+//	@Description	for the nights of a date range, per day, week or month and broken down by room type and rate price.
+//	@Description	Only admins can get it.
+//	@Tags			Reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			from		query		string				true	"First night (YYYY-MM-DD)"
+//	@Param			to			query		string				true	"Last night (YYYY-MM-DD)"
+//	@Param			interval	query		string				false	"Period length"	Enums(day, week, month)
+//	@Success		200			{object}	kpiReportResponse	"KPI report displayed"
+//	@Failure		400			{object}	errorResponse		"Validation error"
+//	@Failure		403			{object}	errorResponse		"Forbidden error"
+//	@Failure		500			{object}	errorResponse		"Internal server error"
+//	@Router			/reports/kpi [get]
+//	@Security		BearerAuth
+func (rh *ReportHandler) GetKPIReport(ctx *gin.Context) {
+	var req getKPIReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	from, to, interval, err := req.parse()
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	report, err := rh.svc.GetKPIReport(ctx, from, to, interval)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newKPIReportResponse(report)
+
+	handleSuccess(ctx, rsp)
+}
+
+// kpiMetricsResponse represents the room statistics of a period or breakdown
+type kpiMetricsResponse struct {
+	RoomNightsAvailable int     `json:"room_nights_available" example:"300"`
+	RoomNightsSold      int     `json:"room_nights_sold" example:"240"`
+	RoomRevenue         float64 `json:"room_revenue" example:"360000.00"`
+	Occupancy           float64 `json:"occupancy" example:"80.00"`
+	ADR                 float64 `json:"adr" example:"1500.00"`
+	RevPAR              float64 `json:"revpar" example:"1200.00"`
+}
+
+// kpiPeriodResponse represents a day, week or month of a KPI report
+type kpiPeriodResponse struct {
+	Start             string  `json:"start" example:"2024-08-01"`
+	PaymentsCollected float64 `json:"payments_collected" example:"350000.00"`
+	kpiMetricsResponse
+}
+
+// kpiBreakdownResponse represents a room type or rate price of a KPI report
+type kpiBreakdownResponse struct {
+	ID   uint64 `json:"id" example:"1"`
+	Name string `json:"name" example:"Deluxe"`
+	kpiMetricsResponse
+}
+
+// kpiReportResponse represents a KPI report response body
+type kpiReportResponse struct {
+	From              string                 `json:"from" example:"2024-08-01"`
+	To                string                 `json:"to" example:"2024-08-31"`
+	Interval          domain.ReportInterval  `json:"interval" example:"day"`
+	PaymentsCollected float64                `json:"payments_collected" example:"350000.00"`
+	Total             kpiMetricsResponse     `json:"total"`
+	Periods           []kpiPeriodResponse    `json:"periods"`
+	ByRoomType        []kpiBreakdownResponse `json:"by_room_type"`
+	ByRatePrice       []kpiBreakdownResponse `json:"by_rate_price"`
+}
+
+func newKPIMetricsResponse(metrics domain.KPIMetrics) kpiMetricsResponse {
+	return kpiMetricsResponse{
+		RoomNightsAvailable: metrics.RoomNightsAvailable,
+		RoomNightsSold:      metrics.RoomNightsSold,
+		RoomRevenue:         metrics.RoomRevenue,
+		Occupancy:           metrics.Occupancy,
+		ADR:                 metrics.ADR,
+		RevPAR:              metrics.RevPAR,
+	}
+}
+
+func newKPIBreakdownsResponse(breakdowns []domain.KPIBreakdown) []kpiBreakdownResponse {
+	rsp := make([]kpiBreakdownResponse, 0, len(breakdowns))
+	for _, breakdown := range breakdowns {
+		rsp = append(rsp, kpiBreakdownResponse{
+			ID:                 breakdown.ID,
+			Name:               breakdown.Name,
+			kpiMetricsResponse: newKPIMetricsResponse(breakdown.KPIMetrics),
+		})
+	}
+	return rsp
+}
+
+// newKPIReportResponse creates a new KPI report response
+func newKPIReportResponse(report *domain.KPIReport) kpiReportResponse {
+	periods := make([]kpiPeriodResponse, 0, len(report.Periods))
+	for _, period := range report.Periods {
+		periods = append(periods, kpiPeriodResponse{
+			Start:              period.Start.Format("2006-01-02"),
+			PaymentsCollected:  period.PaymentsCollected,
+			kpiMetricsResponse: newKPIMetricsResponse(period.KPIMetrics),
+		})
+	}
+
+	return kpiReportResponse{
+		From:              report.From.Format("2006-01-02"),
+		To:                report.To.Format("2006-01-02"),
+		Interval:          report.Interval,
+		PaymentsCollected: report.PaymentsCollected,
+		Total:             newKPIMetricsResponse(report.Total),
+		Periods:           periods,
+		ByRoomType:        newKPIBreakdownsResponse(report.ByRoomType),
+		ByRatePrice:       newKPIBreakdownsResponse(report.ByRatePrice),
+	}
+}
+
+// getForecastReportRequest represents the request body for getting a forecast report
+type getForecastReportRequest struct {
+	Days       int `form:"days" binding:"omitempty,min=1,max=365" example:"90"`
+	PickupDays int `form:"pickup_days" binding:"omitempty,min=1,max=365" example:"7"`
+}
+
+// GetForecastReport godoc
+//
+//	@Summary		Get the on-the-books forecast
+//	@Description	Get the rooms booked and available, occupancy and expected room revenue for each of the next nights,
+//	@Description	by room type, with the pickup since the snapshot taken pickup_days days ago. Pickup is left out when
+//	@Description	that snapshot is missing. Only admins can get it.
+//	@Tags			Reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			days		query		int						false	"Nights from today (default 90)"
+//	@Param			pickup_days	query		int						false	"Days back to compare to (default 7)"
+//	@Success		200			{object}	forecastReportResponse	"Forecast displayed"
+//	@Failure		400			{object}	errorResponse			"Validation error"
+//	@Failure		403			{object}	errorResponse			"Forbidden error"
+//	@Failure		500			{object}	errorResponse			"Internal server error"
+//	@Router			/reports/forecast [get]
+//	@Security		BearerAuth
+func (rh *ReportHandler) GetForecastReport(ctx *gin.Context) {
+	var req getForecastReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+	if req.Days == 0 {
+		req.Days = domain.DefaultForecastDays
+	}
+	if req.PickupDays == 0 {
+		req.PickupDays = domain.DefaultPickupDays
+	}
+
+	report, err := rh.svc.GetForecastReport(ctx, req.Days, req.PickupDays)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newForecastReportResponse(report)
+
+	handleSuccess(ctx, rsp)
+}
+
+// forecastMetricsResponse represents the rooms on the books for a night
+type forecastMetricsResponse struct {
+	RoomsAvailable  int      `json:"rooms_available" example:"30"`
+	RoomsBooked     int      `json:"rooms_booked" example:"18"`
+	Occupancy       float64  `json:"occupancy" example:"60.00"`
+	ExpectedRevenue float64  `json:"expected_revenue" example:"27000.00"`
+	PickupRooms     *int     `json:"pickup_rooms" example:"3"`
+	PickupRevenue   *float64 `json:"pickup_revenue" example:"4500.00"`
+}
+
+// forecastRoomTypeResponse represents a room type of a forecast night
+type forecastRoomTypeResponse struct {
+	ID   uint64 `json:"id" example:"1"`
+	Name string `json:"name" example:"Deluxe"`
+	forecastMetricsResponse
+}
+
+// forecastDayResponse represents a night of a forecast
+type forecastDayResponse struct {
+	Date string `json:"date" example:"2024-08-01"`
+	forecastMetricsResponse
+	ByRoomType []forecastRoomTypeResponse `json:"by_room_type"`
+}
+
+// forecastReportResponse represents a forecast report response body
+type forecastReportResponse struct {
+	From         string                  `json:"from" example:"2024-08-01"`
+	To           string                  `json:"to" example:"2024-10-29"`
+	PickupDays   int                     `json:"pickup_days" example:"7"`
+	SnapshotDate string                  `json:"snapshot_date" example:"2024-07-25"`
+	HasSnapshot  bool                    `json:"has_snapshot" example:"true"`
+	Total        forecastMetricsResponse `json:"total"`
+	Days         []forecastDayResponse   `json:"days"`
+}
+
+func newForecastMetricsResponse(metrics domain.ForecastMetrics) forecastMetricsResponse {
+	return forecastMetricsResponse{
+		RoomsAvailable:  metrics.RoomsAvailable,
+		RoomsBooked:     metrics.RoomsBooked,
+		Occupancy:       metrics.Occupancy,
+		ExpectedRevenue: metrics.ExpectedRevenue,
+		PickupRooms:     metrics.PickupRooms,
+		PickupRevenue:   metrics.PickupRevenue,
+	}
+}
+
+// newForecastReportResponse creates a new forecast report response
+func newForecastReportResponse(report *domain.ForecastReport) forecastReportResponse {
+	days := make([]forecastDayResponse, 0, len(report.Days))
+	for _, day := range report.Days {
+		roomTypes := make([]forecastRoomTypeResponse, 0, len(day.ByRoomType))
+		for _, roomType := range day.ByRoomType {
+			roomTypes = append(roomTypes, forecastRoomTypeResponse{
+				ID:                      roomType.ID,
+				Name:                    roomType.Name,
+				forecastMetricsResponse: newForecastMetricsResponse(roomType.ForecastMetrics),
+			})
+		}
+		days = append(days, forecastDayResponse{
+			Date:                    day.Date.Format("2006-01-02"),
+			forecastMetricsResponse: newForecastMetricsResponse(day.ForecastMetrics),
+			ByRoomType:              roomTypes,
+		})
+	}
+
+	return forecastReportResponse{
+		From:         report.From.Format("2006-01-02"),
+		To:           report.To.Format("2006-01-02"),
+		PickupDays:   report.PickupDays,
+		SnapshotDate: report.SnapshotDate.Format("2006-01-02"),
+		HasSnapshot:  report.HasSnapshot,
+		Total:        newForecastMetricsResponse(report.Total),
+		Days:         days,
+	}
+}
+
+// getCancellationReportRequest represents the request body for getting a cancellation report
+type getCancellationReportRequest struct {
+	From           string  `form:"from" binding:"required" example:"2024-08-01"`
+	To             string  `form:"to" binding:"required" example:"2024-08-31"`
+	RoomTypeID     *uint64 `form:"room_type_id" binding:"omitempty,min=1" example:"1"`
+	CustomerTypeID *uint64 `form:"customer_type_id" binding:"omitempty,min=1" example:"1"`
+	RatePriceID    *uint64 `form:"rate_price_id" binding:"omitempty,min=1" example:"1"`
+}
+
+// GetCancellationReport godoc
+//
+//	@Summary		Get the cancellation and no-show report
+//	@Description	Get the cancellation rate, average days between cancellation and check-in, no-show rate and lost
+//	@Description	revenue of the bookings checking in over a date range, from their status history, in total and by
+//	@Description	room type, customer type and rate price. Only admins can get it.
+//	@Tags			Reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			from				query		string						true	"First check-in date (YYYY-MM-DD)"
+//	@Param			to					query		string						true	"Last check-in date (YYYY-MM-DD)"
+//	@Param			room_type_id		query		uint64						false	"Room type ID"
+//	@Param			customer_type_id	query		uint64						false	"Customer type ID"
+//	@Param			rate_price_id		query		uint64						false	"Rate price ID"
+//	@Success		200					{object}	cancellationReportResponse	"Cancellation report displayed"
+//	@Failure		400					{object}	errorResponse				"Validation error"
+//	@Failure		403					{object}	errorResponse				"Forbidden error"
+//	@Failure		500					{object}	errorResponse				"Internal server error"
+//	@Router			/reports/cancellations [get]
+//	@Security		BearerAuth
+func (rh *ReportHandler) GetCancellationReport(ctx *gin.Context) {
+	var req getCancellationReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	filter := &domain.CancellationFilter{
+		From:           from,
+		To:             to,
+		RoomTypeID:     req.RoomTypeID,
+		CustomerTypeID: req.CustomerTypeID,
+		RatePriceID:    req.RatePriceID,
+	}
+
+	report, err := rh.svc.GetCancellationReport(ctx, filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCancellationReportResponse(report)
+
+	handleSuccess(ctx, rsp)
+}
+
+// cancellationMetricsResponse represents the cancellations and no-shows of a group of bookings
+type cancellationMetricsResponse struct {
+	Bookings         int     `json:"bookings" example:"120"`
+	Canceled         int     `json:"canceled" example:"18"`
+	NoShows          int     `json:"no_shows" example:"3"`
+	CancellationRate float64 `json:"cancellation_rate" example:"15.00"`
+	NoShowRate       float64 `json:"no_show_rate" example:"2.94"`
+	AvgLeadDays      float64 `json:"avg_lead_days" example:"6.50"`
+	LostRevenue      float64 `json:"lost_revenue" example:"31500.00"`
+}
+
+// cancellationBreakdownResponse represents a room type, customer type or rate price of a cancellation report
+type cancellationBreakdownResponse struct {
+	ID   uint64 `json:"id" example:"1"`
+	Name string `json:"name" example:"Deluxe"`
+	cancellationMetricsResponse
+}
+
+// cancellationReportResponse represents a cancellation report response body
+type cancellationReportResponse struct {
+	From           string                          `json:"from" example:"2024-08-01"`
+	To             string                          `json:"to" example:"2024-08-31"`
+	Total          cancellationMetricsResponse     `json:"total"`
+	ByRoomType     []cancellationBreakdownResponse `json:"by_room_type"`
+	ByCustomerType []cancellationBreakdownResponse `json:"by_customer_type"`
+	ByRatePrice    []cancellationBreakdownResponse `json:"by_rate_price"`
+}
+
+func newCancellationMetricsResponse(metrics domain.CancellationMetrics) cancellationMetricsResponse {
+	return cancellationMetricsResponse{
+		Bookings:         metrics.Bookings,
+		Canceled:         metrics.Canceled,
+		NoShows:          metrics.NoShows,
+		CancellationRate: metrics.CancellationRate,
+		NoShowRate:       metrics.NoShowRate,
+		AvgLeadDays:      metrics.AvgLeadDays,
+		LostRevenue:      metrics.LostRevenue,
+	}
+}
+
+func newCancellationBreakdownsResponse(breakdowns []domain.CancellationBreakdown) []cancellationBreakdownResponse {
+	rsp := make([]cancellationBreakdownResponse, 0, len(breakdowns))
+	for _, breakdown := range breakdowns {
+		rsp = append(rsp, cancellationBreakdownResponse{
+			ID:                          breakdown.ID,
+			Name:                        breakdown.Name,
+			cancellationMetricsResponse: newCancellationMetricsResponse(breakdown.CancellationMetrics),
+		})
+	}
+	return rsp
+}
+
+// newCancellationReportResponse creates a new cancellation report response
+func newCancellationReportResponse(report *domain.CancellationReport) cancellationReportResponse {
+	return cancellationReportResponse{
+		From:           report.Filter.From.Format("2006-01-02"),
+		To:             report.Filter.To.Format("2006-01-02"),
+		Total:          newCancellationMetricsResponse(report.Total),
+		ByRoomType:     newCancellationBreakdownsResponse(report.ByRoomType),
+		ByCustomerType: newCancellationBreakdownsResponse(report.ByCustomerType),
+		ByRatePrice:    newCancellationBreakdownsResponse(report.ByRatePrice),
+	}
+}
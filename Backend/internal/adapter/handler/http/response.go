@@ -57,6 +57,20 @@ var errorStatusMap = map[error]int{
 	domain.ErrNoUpdatedData:              http.StatusBadRequest,
 	domain.ErrInsufficientStock:          http.StatusBadRequest,
 	domain.ErrInsufficientPayment:        http.StatusBadRequest,
+	domain.ErrUnsupportedCurrency:        http.StatusBadRequest,
+	domain.ErrFolioNotSettled:            http.StatusConflict,
+	domain.ErrPaymentReversed:            http.StatusConflict,
+	domain.ErrCreditLimitExceeded:        http.StatusConflict,
+	domain.ErrInsufficientPoints:         http.StatusConflict,
+	domain.ErrShiftNotOpen:               http.StatusConflict,
+	domain.ErrDayLocked:                  http.StatusConflict,
+	domain.ErrDayClosed:                  http.StatusConflict,
+	domain.ErrSummaryOutdated:            http.StatusConflict,
+	domain.ErrIdempotencyKeyMismatch:     http.StatusUnprocessableEntity,
+	domain.ErrIdempotencyKeyInProgress:   http.StatusConflict,
+	domain.ErrPaymentDeclined:            http.StatusPaymentRequired,
+	domain.ErrPaymentGateway:             http.StatusBadGateway,
+	domain.ErrInvalidWebhookSignature:    http.StatusUnauthorized,
 }
 
 // validationError sends an error response for some specific request validation error
@@ -32,6 +32,18 @@ func NewRouter(
 	customerTypeHandler CustomerTypeHandler,
 	logHandler LogHandler,
 	dailyBookingSummaryHandler DailyBookingSummaryHandler,
+	exchangeRateHandler ExchangeRateHandler,
+	folioHandler FolioHandler,
+	documentHandler DocumentHandler,
+	invoiceHandler InvoiceHandler,
+	companyHandler CompanyHandler,
+	cashierShiftHandler CashierShiftHandler,
+	nightAuditHandler NightAuditHandler,
+	jobHandler JobHandler,
+	reportHandler ReportHandler,
+	exportHandler ExportHandler,
+	loyaltyHandler LoyaltyHandler,
+	idempotencyService port.IdempotencyService,
 	tokenService port.TokenService,
 ) (*Router, error) {
 	router := SetupRouter(config, tokenService)
@@ -43,6 +55,8 @@ func NewRouter(
 		{
 			user.POST("/login", authHandler.Login)    // Login
 		}
+		// Signed by the payment gateway instead of a user token
+		v1.POST("/payment-gateway/webhook", paymentHandler.HandleGatewayWebhook)
 
 		// Protected routes (authentication required)
 		protected := v1.Group("")
@@ -62,30 +76,98 @@ func NewRouter(
 			// Other protected routes
 			booking := protected.Group("/booking")
 			{
-				booking.POST("/", bookingHandler.CreateBookingAndPayment)
+				booking.POST("/", IdempotencyMiddleware(idempotencyService), bookingHandler.CreateBookingAndPayment)
 				booking.GET("/", bookingHandler.ListBookingCustomerPaymentsWithFilter)
+				booking.GET("/export", exportHandler.ExportBookingCustomerPayments)
 				// booking.GET("/", bookingHandler.ListBookingsWithFilter)
 				booking.GET("/:id", bookingHandler.GetBooking)
 				booking.PUT("/", bookingHandler.UpdateBooking)
 				booking.DELETE("/:id", bookingHandler.DeleteBooking)
 				booking.GET("/:id/details", bookingHandler.GetBookingCustomerPayment)
+				booking.GET("/:id/status-events", bookingHandler.ListBookingStatusEvents)
+				booking.PUT("/:id/checkout", bookingHandler.CheckOutBooking)
+				booking.GET("/:id/folio", folioHandler.GetFolio)
+				booking.GET("/:id/folios", folioHandler.ListSplitFolios)
+				booking.GET("/:id/payers", folioHandler.ListPayers)
+				booking.POST("/:id/payers", folioHandler.AddPayer)
+				booking.DELETE("/:id/payers/:payer_id", folioHandler.RemovePayer)
+				booking.POST("/:id/folio/charges", folioHandler.PostCharge)
+				booking.POST("/:id/folio/adjustments", folioHandler.PostAdjustment)
+				booking.POST("/:id/loyalty/earn", loyaltyHandler.EarnLoyaltyPoints)
+				booking.POST("/:id/loyalty/redeem", loyaltyHandler.RedeemLoyaltyDiscount)
+				booking.GET("/:id/invoice.pdf", documentHandler.GetBookingInvoicePDF)
 			}
 			customer := protected.Group("/customers")
 			{
 				customer.POST("/", customerHandler.CreateCustomer)
 				customer.GET("/", customerHandler.ListCustomers)
+				customer.GET("/export", exportHandler.ExportCustomers)
+				customer.GET("/search", customerHandler.SearchCustomers)
+				customer.GET("/duplicates", customerHandler.ListCustomerDuplicates)
+				customer.POST("/merge", customerHandler.MergeCustomers)
 				customer.GET("/:id", customerHandler.GetCustomer)
+				customer.GET("/:id/history", customerHandler.GetCustomerHistory)
+				customer.GET("/:id/loyalty", loyaltyHandler.GetLoyaltyAccount)
+				customer.GET("/:id/loyalty/transactions", loyaltyHandler.ListLoyaltyTransactions)
 				customer.PUT("/", customerHandler.UpdateCustomer)
 				customer.DELETE("/:id", customerHandler.DeleteCustomer)
 			}
 			payment := protected.Group("/payments")
 			{
-				payment.POST("/", paymentHandler.CreatePayment)
+				payment.POST("/", IdempotencyMiddleware(idempotencyService), paymentHandler.CreatePayment)
 				payment.GET("/", paymentHandler.ListPayments)
+				payment.GET("/export", exportHandler.ExportPayments)
+				payment.GET("/totals", paymentHandler.GetPaymentTotals)
+				payment.GET("/booking/:id", paymentHandler.ListBookingPayments)
+				payment.POST("/:id/refund", paymentHandler.RefundPayment)
+				payment.POST("/:id/void", paymentHandler.VoidPayment)
+				payment.POST("/:id/capture", paymentHandler.CapturePayment)
 				payment.GET("/:id", paymentHandler.GetPayment)
 				payment.PUT("/", paymentHandler.UpdatePayment)
 				payment.DELETE("/:id", paymentHandler.DeletePayment)
 			}
+			invoice := protected.Group("/invoices")
+			{
+				invoice.POST("/", invoiceHandler.IssueInvoice)
+				invoice.GET("/booking/:id", invoiceHandler.ListBookingInvoices)
+				invoice.GET("/:id", invoiceHandler.GetInvoice)
+				invoice.GET("/:id/pdf", documentHandler.GetInvoicePDF)
+				invoice.POST("/:id/credit-note", invoiceHandler.IssueCreditNote)
+			}
+			invoiceSeries := protected.Group("/invoice-series")
+			{
+				invoiceSeries.POST("/", invoiceHandler.CreateInvoiceSeries)
+				invoiceSeries.GET("/", invoiceHandler.ListInvoiceSeries)
+				invoiceSeries.PUT("/", invoiceHandler.UpdateInvoiceSeries)
+			}
+			company := protected.Group("/companies")
+			{
+				company.POST("/", companyHandler.CreateCompany)
+				company.GET("/", companyHandler.ListCompanies)
+				company.GET("/aging", companyHandler.GetAgingReport)
+				company.GET("/:id", companyHandler.GetCompany)
+				company.PUT("/", companyHandler.UpdateCompany)
+				company.GET("/:id/ledger", companyHandler.GetLedger)
+				company.GET("/:id/invoices", companyHandler.ListOpenInvoices)
+				company.POST("/:id/payments", companyHandler.RecordPayment)
+			}
+			shift := protected.Group("/shifts")
+			{
+				shift.POST("/open", cashierShiftHandler.OpenShift)
+				shift.GET("/current", cashierShiftHandler.GetCurrentShift)
+				shift.POST("/close", cashierShiftHandler.CloseShift)
+				shift.GET("/", cashierShiftHandler.ListShifts)
+				shift.GET("/:id/report", cashierShiftHandler.GetShiftReport)
+			}
+			exchangeRate := protected.Group("/exchange-rates")
+			{
+				exchangeRate.POST("/", exchangeRateHandler.CreateExchangeRate)
+				exchangeRate.GET("/", exchangeRateHandler.ListExchangeRates)
+				exchangeRate.GET("/effective", exchangeRateHandler.GetEffectiveExchangeRate)
+				exchangeRate.GET("/:id", exchangeRateHandler.GetExchangeRate)
+				exchangeRate.PUT("/", exchangeRateHandler.UpdateExchangeRate)
+				exchangeRate.DELETE("/:id", exchangeRateHandler.DeleteExchangeRate)
+			}
 			rank := protected.Group("/ranks")
 			{
 				rank.POST("/", rankHandler.CreateRank)
@@ -133,12 +215,33 @@ func NewRouter(
 			{
 				dailySummary.POST("/generate", dailyBookingSummaryHandler.GenerateDailySummary)
 				dailySummary.PUT("/status", dailyBookingSummaryHandler.UpdateSummaryStatus)
+				dailySummary.POST("/reopen", dailyBookingSummaryHandler.ReopenSummary)
 				dailySummary.GET("/", dailyBookingSummaryHandler.GetSummaryByDate)
 				dailySummary.GET("/list", dailyBookingSummaryHandler.ListSummaries)
+				dailySummary.GET("/export", exportHandler.ExportDailySummaries)
+			}
+			nightAudit := protected.Group("/night-audit")
+			{
+				nightAudit.GET("/business-date", nightAuditHandler.GetBusinessDate)
+				nightAudit.POST("/run", nightAuditHandler.RunNightAudit)
+				nightAudit.GET("/", nightAuditHandler.ListNightAudits)
+			}
+			report := protected.Group("/reports")
+			{
+				report.GET("/kpi", reportHandler.GetKPIReport)
+				report.GET("/kpi/export", exportHandler.ExportKPIReport)
+				report.GET("/forecast", reportHandler.GetForecastReport)
+				report.GET("/cancellations", reportHandler.GetCancellationReport)
+			}
+			job := protected.Group("/jobs")
+			{
+				job.GET("/runs", jobHandler.ListJobRuns)
 			}
 			log := protected.Group("/logs")
 			{
 				log.GET("/", logHandler.GetLogs)
+				log.GET("/export", exportHandler.ExportLogs)
+				log.GET("/activity", logHandler.GetStaffActivity)
 			}
 		}
 	}
@@ -0,0 +1,186 @@
+package fake
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/config"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/google/uuid"
+)
+
+// DeclinedCardToken is a card token the fake gateway always declines
+const DeclinedCardToken = "tok_declined"
+
+/**
+ * Gateway implements port.PaymentGateway interface
+ * and keeps every transaction in memory, so no card processor is contacted
+ */
+type Gateway struct {
+	mu            sync.Mutex
+	transactions  map[string]*transaction
+	webhookSecret []byte
+}
+
+type transaction struct {
+	currency   string
+	voided     bool
+	authorized float64
+	captured   float64
+	refunded   float64
+}
+
+// webhookPayload is the JSON body of a fake gateway webhook
+type webhookPayload struct {
+	Reference string  `json:"reference"`
+	Status    string  `json:"status"`
+	Amount    float64 `json:"amount"`
+}
+
+var webhookStatuses = map[string]domain.GatewayTransactionStatus{
+	"authorized": domain.GatewayTransactionStatusAuthorized,
+	"captured":   domain.GatewayTransactionStatusCaptured,
+	"refunded":   domain.GatewayTransactionStatusRefunded,
+	"declined":   domain.GatewayTransactionStatusDeclined,
+	"voided":     domain.GatewayTransactionStatusVoided,
+}
+
+// New creates a new fake gateway instance
+func New(config *config.PaymentGateway) (port.PaymentGateway, error) {
+	return &Gateway{
+		transactions:  make(map[string]*transaction),
+		webhookSecret: []byte(config.WebhookSecret),
+	}, nil
+}
+
+// Name returns the provider name of the fake gateway
+func (g *Gateway) Name() string {
+	return "fake"
+}
+
+// Authorize approves every charge except those made with DeclinedCardToken
+func (g *Gateway) Authorize(ctx context.Context, charge *domain.GatewayCharge) (*domain.GatewayTransaction, error) {
+	if charge.CardToken == "" || charge.Amount <= 0 {
+		return nil, domain.ErrInvalidData
+	}
+	if charge.CardToken == DeclinedCardToken {
+		return nil, domain.ErrPaymentDeclined
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	reference := "fake_" + uuid.NewString()
+	g.transactions[reference] = &transaction{
+		currency:   charge.Currency,
+		authorized: charge.Amount,
+	}
+
+	return &domain.GatewayTransaction{
+		Reference: reference,
+		Status:    domain.GatewayTransactionStatusAuthorized,
+		Amount:    charge.Amount,
+		Currency:  charge.Currency,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Capture collects an authorized transaction once, up to the authorized amount
+func (g *Gateway) Capture(ctx context.Context, reference string, amount float64) (*domain.GatewayTransaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx, ok := g.transactions[reference]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	if tx.voided || tx.captured > 0 || amount <= 0 || amount > tx.authorized {
+		return nil, domain.ErrInvalidData
+	}
+	tx.captured = amount
+
+	return &domain.GatewayTransaction{
+		Reference: reference,
+		Status:    domain.GatewayTransactionStatusCaptured,
+		Amount:    amount,
+		Currency:  tx.currency,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Void releases an authorized transaction that has not been captured
+func (g *Gateway) Void(ctx context.Context, reference string) (*domain.GatewayTransaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx, ok := g.transactions[reference]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	if tx.voided || tx.captured > 0 {
+		return nil, domain.ErrInvalidData
+	}
+	tx.voided = true
+
+	return &domain.GatewayTransaction{
+		Reference: reference,
+		Status:    domain.GatewayTransactionStatusVoided,
+		Amount:    tx.authorized,
+		Currency:  tx.currency,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Refund returns up to the captured amount that has not been refunded yet
+func (g *Gateway) Refund(ctx context.Context, reference string, amount float64) (*domain.GatewayTransaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx, ok := g.transactions[reference]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	if amount <= 0 || amount > tx.captured-tx.refunded {
+		return nil, domain.ErrInvalidData
+	}
+	tx.refunded += amount
+
+	return &domain.GatewayTransaction{
+		Reference: "fake_refund_" + uuid.NewString(),
+		Status:    domain.GatewayTransactionStatusRefunded,
+		Amount:    amount,
+		Currency:  tx.currency,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// VerifyWebhook checks the hex encoded HMAC-SHA256 of the payload against the configured webhook secret
+func (g *Gateway) VerifyWebhook(payload []byte, signature string) (*domain.GatewayEvent, error) {
+	mac := hmac.New(sha256.New, g.webhookSecret)
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if len(g.webhookSecret) == 0 || !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, domain.ErrInvalidWebhookSignature
+	}
+
+	var body webhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, domain.ErrInvalidData
+	}
+	status, ok := webhookStatuses[body.Status]
+	if !ok || body.Reference == "" {
+		return nil, domain.ErrInvalidData
+	}
+
+	return &domain.GatewayEvent{
+		Reference: body.Reference,
+		Status:    status,
+		Amount:    body.Amount,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/config"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+)
+
+func TestGatewayAuthorize(t *testing.T) {
+	tests := []struct {
+		name    string
+		charge  domain.GatewayCharge
+		wantErr error
+	}{
+		{"approved", domain.GatewayCharge{CardToken: "tok_visa", Amount: 500, Currency: "THB"}, nil},
+		{"declined card", domain.GatewayCharge{CardToken: DeclinedCardToken, Amount: 500, Currency: "THB"}, domain.ErrPaymentDeclined},
+		{"no card", domain.GatewayCharge{Amount: 500, Currency: "THB"}, domain.ErrInvalidData},
+		{"no amount", domain.GatewayCharge{CardToken: "tok_visa", Currency: "THB"}, domain.ErrInvalidData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, _ := New(&config.PaymentGateway{})
+			tx, err := gateway.Authorize(context.Background(), &tt.charge)
+			if err != tt.wantErr {
+				t.Fatalf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tx.Status != domain.GatewayTransactionStatusAuthorized || tx.Amount != tt.charge.Amount || tx.Reference == "" {
+				t.Errorf("Authorize() = %+v, want an authorized transaction of %v", tx, tt.charge.Amount)
+			}
+		})
+	}
+}
+
+func TestGatewayCapture(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(g *Gateway, reference string) // Runs between authorizing 500 and capturing
+		amount  float64
+		wantErr error
+	}{
+		{"in full", nil, 500, nil},
+		{"in part", nil, 300, nil},
+		{"more than authorized", nil, 600, domain.ErrInvalidData},
+		{"nothing", nil, 0, domain.ErrInvalidData},
+		{"twice", func(g *Gateway, reference string) { g.Capture(context.Background(), reference, 100) }, 100, domain.ErrInvalidData},
+		{"after a void", func(g *Gateway, reference string) { g.Void(context.Background(), reference) }, 500, domain.ErrInvalidData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, reference := authorizedGateway(t, 500)
+			if tt.prepare != nil {
+				tt.prepare(g, reference)
+			}
+			tx, err := g.Capture(context.Background(), reference, tt.amount)
+			if err != tt.wantErr {
+				t.Fatalf("Capture() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (tx.Status != domain.GatewayTransactionStatusCaptured || tx.Amount != tt.amount) {
+				t.Errorf("Capture() = %+v, want %v captured", tx, tt.amount)
+			}
+		})
+	}
+
+	t.Run("unknown reference", func(t *testing.T) {
+		g, _ := authorizedGateway(t, 500)
+		if _, err := g.Capture(context.Background(), "fake_unknown", 100); err != domain.ErrDataNotFound {
+			t.Errorf("Capture() error = %v, want %v", err, domain.ErrDataNotFound)
+		}
+	})
+}
+
+func TestGatewayRefund(t *testing.T) {
+	tests := []struct {
+		name     string
+		captured float64 // Of 500 authorized, zero for none
+		refunds  []float64
+		wantErr  error // Of the last refund
+	}{
+		{"in full", 500, []float64{500}, nil},
+		{"in parts up to the captured amount", 500, []float64{200, 300}, nil},
+		{"more than captured", 300, []float64{400}, domain.ErrInvalidData},
+		{"more than left after a refund", 500, []float64{400, 200}, domain.ErrInvalidData},
+		{"before capture", 0, []float64{100}, domain.ErrInvalidData},
+		{"nothing", 500, []float64{0}, domain.ErrInvalidData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, reference := authorizedGateway(t, 500)
+			if tt.captured > 0 {
+				if _, err := g.Capture(context.Background(), reference, tt.captured); err != nil {
+					t.Fatalf("Capture() error = %v", err)
+				}
+			}
+
+			var tx *domain.GatewayTransaction
+			var err error
+			for i, amount := range tt.refunds {
+				tx, err = g.Refund(context.Background(), reference, amount)
+				if i < len(tt.refunds)-1 && err != nil {
+					t.Fatalf("Refund(%v) error = %v", amount, err)
+				}
+			}
+			if err != tt.wantErr {
+				t.Fatalf("Refund() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (tx.Status != domain.GatewayTransactionStatusRefunded || tx.Reference == reference) {
+				t.Errorf("Refund() = %+v, want a refund transaction of its own", tx)
+			}
+		})
+	}
+}
+
+func authorizedGateway(t *testing.T, amount float64) (*Gateway, string) {
+	t.Helper()
+	gateway, _ := New(&config.PaymentGateway{})
+	g := gateway.(*Gateway)
+	tx, err := g.Authorize(context.Background(), &domain.GatewayCharge{CardToken: "tok_visa", Amount: amount, Currency: "THB"})
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	return g, tx.Reference
+}
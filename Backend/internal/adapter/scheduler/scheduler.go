@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/config"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+)
+
+const (
+	defaultDailySummaryTime       = "00:30"
+	defaultForecastSnapshotTime   = "00:45"
+	defaultLoyaltyExpiryTime      = "01:00"
+	defaultIdempotencyCleanupTime = "01:15"
+	defaultRetryAttempts          = 3
+	defaultRetryInterval          = 5 * time.Minute
+)
+
+/**
+ * Scheduler runs the hotel's recurring jobs inside the server process.
+ * Times are local to time.Local, which the server sets from DB_TIMEZONE.
+ */
+type Scheduler struct {
+	svc                  port.JobService
+	dailySummaryAt       time.Time
+	forecastSnapshotAt   time.Time
+	loyaltyExpiryAt      time.Time
+	idempotencyCleanupAt time.Time
+	retryAttempts        int
+	retryInterval        time.Duration
+}
+
+// New creates a new scheduler instance
+func New(config *config.Scheduler, svc port.JobService) (*Scheduler, error) {
+	dailySummaryAt, err := parseTimeOfDay(config.DailySummaryTime, defaultDailySummaryTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daily summary time %q: %w", config.DailySummaryTime, err)
+	}
+	forecastSnapshotAt, err := parseTimeOfDay(config.ForecastSnapshotTime, defaultForecastSnapshotTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forecast snapshot time %q: %w", config.ForecastSnapshotTime, err)
+	}
+	loyaltyExpiryAt, err := parseTimeOfDay(config.LoyaltyExpiryTime, defaultLoyaltyExpiryTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid loyalty expiry time %q: %w", config.LoyaltyExpiryTime, err)
+	}
+	idempotencyCleanupAt, err := parseTimeOfDay(config.IdempotencyCleanupTime, defaultIdempotencyCleanupTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid idempotency cleanup time %q: %w", config.IdempotencyCleanupTime, err)
+	}
+
+	retryAttempts := defaultRetryAttempts
+	if config.RetryAttempts != "" {
+		retryAttempts, err = strconv.Atoi(config.RetryAttempts)
+		if err != nil || retryAttempts < 1 {
+			return nil, fmt.Errorf("invalid retry attempts %q", config.RetryAttempts)
+		}
+	}
+
+	retryInterval := defaultRetryInterval
+	if config.RetryInterval != "" {
+		retryInterval, err = time.ParseDuration(config.RetryInterval)
+		if err != nil || retryInterval <= 0 {
+			return nil, fmt.Errorf("invalid retry interval %q", config.RetryInterval)
+		}
+	}
+
+	return &Scheduler{
+		svc,
+		dailySummaryAt,
+		forecastSnapshotAt,
+		loyaltyExpiryAt,
+		idempotencyCleanupAt,
+		retryAttempts,
+		retryInterval,
+	}, nil
+}
+
+// parseTimeOfDay parses an HH:MM time, or defaultValue when value is empty
+func parseTimeOfDay(value, defaultValue string) (time.Time, error) {
+	if value == "" {
+		value = defaultValue
+	}
+	return time.Parse("15:04", value)
+}
+
+// Start runs the jobs in the background until ctx is canceled
+func (s *Scheduler) Start(ctx context.Context) {
+	// The summary covers the day that has just ended
+	go s.runDaily(ctx, domain.JobDailySummary, s.dailySummaryAt, func(date time.Time, attempt int) error {
+		_, err := s.svc.GenerateDailySummary(ctx, date.AddDate(0, 0, -1), attempt)
+		return err
+	})
+	// The snapshot is taken of the day that has just started
+	go s.runDaily(ctx, domain.JobForecastSnapshot, s.forecastSnapshotAt, func(date time.Time, attempt int) error {
+		_, err := s.svc.SnapshotForecast(ctx, date, attempt)
+		return err
+	})
+	// Points expiring during the day lapse the following night
+	go s.runDaily(ctx, domain.JobLoyaltyExpiry, s.loyaltyExpiryAt, func(date time.Time, attempt int) error {
+		_, err := s.svc.ExpireLoyaltyPoints(ctx, date, attempt)
+		return err
+	})
+	// Keys claimed over a day ago can no longer be replayed, so they are deleted
+	go s.runDaily(ctx, domain.JobIdempotencyCleanup, s.idempotencyCleanupAt, func(date time.Time, attempt int) error {
+		_, err := s.svc.CleanUpIdempotencyKeys(ctx, date, attempt)
+		return err
+	})
+}
+
+// runDaily runs job at the time of day at every day, with the date it runs on
+func (s *Scheduler) runDaily(ctx context.Context, name string, at time.Time, job func(date time.Time, attempt int) error) {
+	for {
+		next := nextRun(time.Now(), at)
+		slog.Info("Scheduled job", "job", name, "next_run", next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		date := time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, time.Local)
+		s.retry(ctx, name, func(attempt int) error {
+			return job(date, attempt)
+		})
+	}
+}
+
+// nextRun returns the first time of day at after now
+func nextRun(now, at time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, time.Local)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// retry runs job until it succeeds or the attempts run out, waiting the retry interval between attempts
+func (s *Scheduler) retry(ctx context.Context, name string, job func(attempt int) error) {
+	for attempt := 1; attempt <= s.retryAttempts; attempt++ {
+		err := job(attempt)
+		if err == nil {
+			return
+		}
+		slog.Error("Scheduled job failed", "job", name, "attempt", attempt, "error", err)
+
+		if attempt == s.retryAttempts {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.retryInterval):
+		}
+	}
+}
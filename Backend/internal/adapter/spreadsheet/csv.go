@@ -0,0 +1,46 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// csvFlushRows is the number of rows buffered before they are sent on
+const csvFlushRows = 100
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) WriteRow(values ...interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+		// Text starting like a formula is quoted so spreadsheet applications do not evaluate it
+		if !isNumber(value) && record[i] != "" && strings.ContainsRune("=+-@", rune(record[i][0])) {
+			record[i] = "'" + record[i]
+		}
+	}
+
+	if err := cw.w.Write(record); err != nil {
+		return err
+	}
+
+	cw.rows++
+	if cw.rows%csvFlushRows == 0 {
+		cw.w.Flush()
+		return cw.w.Error()
+	}
+	return nil
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
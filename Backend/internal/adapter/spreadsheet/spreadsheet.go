@@ -0,0 +1,72 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+)
+
+/**
+ * Factory implements port.TableWriterFactory interface
+ * and writes CSV and XLSX files with the standard library only
+ */
+type Factory struct{}
+
+// New creates a new spreadsheet writer factory
+func New() *Factory {
+	return &Factory{}
+}
+
+// NewTableWriter creates a writer of the given format. sheetName names the worksheet of an XLSX file.
+func (f *Factory) NewTableWriter(w io.Writer, format domain.ExportFormat, sheetName string) (port.TableWriter, error) {
+	switch format {
+	case domain.ExportFormatCSV:
+		return newCSVWriter(w), nil
+	case domain.ExportFormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	default:
+		return nil, domain.ErrInvalidData
+	}
+}
+
+// formatValue renders a cell value as text, dates in the server's local time
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return formatTime(v)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return formatTime(*v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// isNumber reports whether a cell value is written as a number
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int64, uint64, float64:
+		return true
+	default:
+		return false
+	}
+}
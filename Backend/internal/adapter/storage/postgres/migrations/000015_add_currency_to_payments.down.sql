@@ -0,0 +1,4 @@
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS base_amount,
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS currency;
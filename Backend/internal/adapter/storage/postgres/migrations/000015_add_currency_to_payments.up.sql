@@ -0,0 +1,9 @@
+ALTER TABLE payments
+    ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'THB',
+    ADD COLUMN exchange_rate DECIMAL(12, 6) NOT NULL DEFAULT 1,
+    ADD COLUMN base_amount DECIMAL(10, 2);
+
+-- Existing payments were all taken in THB
+UPDATE payments SET base_amount = amount;
+
+ALTER TABLE payments ALTER COLUMN base_amount SET NOT NULL;
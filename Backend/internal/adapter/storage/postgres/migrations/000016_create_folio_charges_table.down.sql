@@ -0,0 +1 @@
+DROP TABLE IF EXISTS folio_charges;
\ No newline at end of file
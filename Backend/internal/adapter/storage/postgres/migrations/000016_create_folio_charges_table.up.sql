@@ -0,0 +1,29 @@
+CREATE TABLE folio_charges (
+    id SERIAL PRIMARY KEY,
+    booking_id INT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    charge_type INT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    quantity INT NOT NULL DEFAULT 1,
+    unit_price DECIMAL(10, 2) NOT NULL,
+    tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10, 2) NOT NULL,
+    posted_by INT REFERENCES users(id),
+    posted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_folio_charges_booking_id ON folio_charges(booking_id);
+
+-- Post the room charge of existing bookings so their folios start from the booking total
+INSERT INTO folio_charges (booking_id, charge_type, description, quantity, unit_price, amount, posted_at)
+SELECT
+    b.id,
+    1,
+    'Room charge',
+    GREATEST(b.check_out_date - b.check_in_date, 1),
+    ROUND(b.total_amount / GREATEST(b.check_out_date - b.check_in_date, 1), 2),
+    b.total_amount,
+    b.created_at
+FROM bookings b
+WHERE b.total_amount IS NOT NULL;
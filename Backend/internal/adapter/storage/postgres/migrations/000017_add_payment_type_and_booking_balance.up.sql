@@ -0,0 +1,56 @@
+-- Existing payments each covered the whole booking amount
+ALTER TABLE payments
+    ADD COLUMN payment_type INT NOT NULL DEFAULT 2;
+
+DROP VIEW IF EXISTS booking_customer_payment;
+
+CREATE VIEW booking_customer_payment AS
+WITH booking_payments AS (
+    SELECT
+        booking_id,
+        COALESCE(SUM(base_amount) FILTER (WHERE status = 2), 0) AS paid_amount,
+        MAX(updated_at) AS payment_update_date
+    FROM payments
+    GROUP BY booking_id
+),
+booking_charges AS (
+    SELECT
+        booking_id,
+        SUM(amount + tax_amount) AS amount_due
+    FROM folio_charges
+    GROUP BY booking_id
+)
+SELECT
+    b.id AS booking_id,
+    b.customer_id,
+    b.total_amount AS booking_price,
+    b.status AS booking_status,
+    b.check_in_date,
+    b.check_out_date,
+    b.created_at AS booking_created_at,
+    b.updated_at AS booking_updated_at,
+    b.room_id,
+    r.room_number,
+    r.type_id AS room_type_id,
+    rt.name AS room_type_name,
+    r.floor,
+    b.rate_prices_id,
+    c.firstname AS customer_firstname,
+    c.surname AS customer_surname,
+    c.identity_number AS customer_identity_number,
+    c.address AS customer_address,
+    COALESCE(bp.paid_amount, 0) AS paid_amount,
+    COALESCE(bc.amount_due, b.total_amount) - COALESCE(bp.paid_amount, 0) AS balance,
+    CASE
+        WHEN COALESCE(bp.paid_amount, 0) <= 0 THEN 1
+        WHEN COALESCE(bc.amount_due, b.total_amount) - bp.paid_amount < 0.005 THEN 2
+        ELSE 5
+    END AS payment_status,
+    bp.payment_update_date
+FROM
+    bookings b
+    JOIN customers c ON b.customer_id = c.id
+    LEFT JOIN booking_payments bp ON b.id = bp.booking_id
+    LEFT JOIN booking_charges bc ON b.id = bc.booking_id
+    JOIN rooms r ON b.room_id = r.id
+    JOIN room_types rt ON r.type_id = rt.id;
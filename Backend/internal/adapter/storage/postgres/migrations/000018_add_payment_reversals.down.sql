@@ -0,0 +1,9 @@
+ALTER TABLE daily_booking_summary
+    DROP COLUMN IF EXISTS total_refunds;
+
+DROP INDEX IF EXISTS idx_payments_original_payment_id;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS reason_note,
+    DROP COLUMN IF EXISTS reason_code,
+    DROP COLUMN IF EXISTS original_payment_id;
@@ -0,0 +1,9 @@
+ALTER TABLE payments
+    ADD COLUMN original_payment_id INT REFERENCES payments(id),
+    ADD COLUMN reason_code INT NOT NULL DEFAULT 0,
+    ADD COLUMN reason_note TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_payments_original_payment_id ON payments(original_payment_id);
+
+ALTER TABLE daily_booking_summary
+    ADD COLUMN total_refunds DECIMAL(10, 2) NOT NULL DEFAULT 0;
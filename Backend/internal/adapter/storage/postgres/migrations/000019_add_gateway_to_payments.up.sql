@@ -0,0 +1,5 @@
+ALTER TABLE payments
+    ADD COLUMN gateway VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN gateway_reference VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_payments_gateway_reference ON payments(gateway_reference) WHERE gateway_reference <> '';
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS invoices_immutable ON invoices;
+DROP FUNCTION IF EXISTS prevent_invoice_modification();
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_series_counters;
+DROP TABLE IF EXISTS invoice_series;
@@ -0,0 +1,58 @@
+CREATE TABLE invoice_series (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    invoice_type INT NOT NULL, -- 1: Tax invoice, 2: Credit note
+    prefix VARCHAR(20) NOT NULL,
+    yearly_reset BOOLEAN NOT NULL DEFAULT TRUE,
+    padding INT NOT NULL DEFAULT 6,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_invoice_series_default ON invoice_series(invoice_type) WHERE is_default;
+
+-- Last number handed out per series and year (0 when the series never resets)
+CREATE TABLE invoice_series_counters (
+    series_id INT NOT NULL REFERENCES invoice_series(id),
+    year INT NOT NULL,
+    last_number INT NOT NULL,
+    PRIMARY KEY (series_id, year)
+);
+
+CREATE TABLE invoices (
+    id SERIAL PRIMARY KEY,
+    series_id INT NOT NULL REFERENCES invoice_series(id),
+    number VARCHAR(40) NOT NULL UNIQUE,
+    sequence INT NOT NULL,
+    year INT NOT NULL,
+    invoice_type INT NOT NULL,
+    booking_id INT NOT NULL REFERENCES bookings(id),
+    original_invoice_id INT REFERENCES invoices(id),
+    subtotal DECIMAL(10, 2) NOT NULL,
+    vat DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    issued_by INT REFERENCES users(id),
+    issued_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (series_id, year, sequence)
+);
+
+CREATE INDEX idx_invoices_booking_id ON invoices(booking_id);
+-- A tax invoice can only be cancelled once
+CREATE UNIQUE INDEX idx_invoices_original_invoice_id ON invoices(original_invoice_id) WHERE original_invoice_id IS NOT NULL;
+
+CREATE FUNCTION prevent_invoice_modification() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'issued invoices cannot be changed; cancel them with a credit note';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER invoices_immutable
+    BEFORE UPDATE OR DELETE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION prevent_invoice_modification();
+
+INSERT INTO invoice_series (code, invoice_type, prefix, yearly_reset, padding, is_default) VALUES
+    ('INV', 1, 'INV', TRUE, 6, TRUE),
+    ('CN', 2, 'CN', TRUE, 6, TRUE);
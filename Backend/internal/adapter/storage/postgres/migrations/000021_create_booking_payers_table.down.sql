@@ -0,0 +1,7 @@
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS fk_invoices_payer;
+ALTER TABLE invoices DROP COLUMN IF EXISTS payer_id;
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS fk_payments_payer;
+ALTER TABLE payments DROP COLUMN IF EXISTS payer_id;
+
+DROP TABLE IF EXISTS booking_payers;
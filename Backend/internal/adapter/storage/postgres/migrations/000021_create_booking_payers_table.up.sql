@@ -0,0 +1,22 @@
+CREATE TABLE booking_payers (
+    id SERIAL PRIMARY KEY,
+    booking_id INT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    customer_id INT NOT NULL REFERENCES customers(id),
+    charge_types INT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (booking_id, customer_id),
+    -- Lets payments and invoices reference a payer of their own booking only
+    UNIQUE (id, booking_id)
+);
+
+CREATE INDEX idx_booking_payers_booking_id ON booking_payers(booking_id);
+
+-- Payments and invoices without a payer belong to the guest of the booking
+ALTER TABLE payments
+    ADD COLUMN payer_id INT,
+    ADD CONSTRAINT fk_payments_payer FOREIGN KEY (payer_id, booking_id) REFERENCES booking_payers(id, booking_id);
+
+ALTER TABLE invoices
+    ADD COLUMN payer_id INT,
+    ADD CONSTRAINT fk_invoices_payer FOREIGN KEY (payer_id, booking_id) REFERENCES booking_payers(id, booking_id);
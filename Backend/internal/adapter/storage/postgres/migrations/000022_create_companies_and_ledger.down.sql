@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS company_ledger_entries;
+DROP TABLE IF EXISTS companies;
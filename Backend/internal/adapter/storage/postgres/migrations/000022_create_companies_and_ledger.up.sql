@@ -0,0 +1,36 @@
+CREATE TABLE companies (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    tax_id VARCHAR(50) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    contact_name VARCHAR(255) NOT NULL DEFAULT '',
+    credit_limit DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    payment_term_days INT NOT NULL DEFAULT 30,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name)
+);
+
+-- Accounts receivable of each company in THB. Invoices are booking balances transferred at
+-- checkout (positive amounts); payments are negative and settle one invoice each.
+CREATE TABLE company_ledger_entries (
+    id SERIAL PRIMARY KEY,
+    company_id INT NOT NULL REFERENCES companies(id),
+    entry_type INT NOT NULL,
+    booking_id INT REFERENCES bookings(id),
+    payment_id INT REFERENCES payments(id),
+    applied_to_id INT REFERENCES company_ledger_entries(id),
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    amount DECIMAL(12, 2) NOT NULL,
+    payment_method INT NOT NULL DEFAULT 0,
+    entry_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    due_date TIMESTAMP,
+    posted_by INT REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_company_ledger_entries_company_id ON company_ledger_entries(company_id);
+CREATE INDEX idx_company_ledger_entries_applied_to_id ON company_ledger_entries(applied_to_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_payments_shift_id;
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS shift_id,
+    DROP COLUMN IF EXISTS taken_by;
+
+DROP TABLE IF EXISTS cashier_shift_counts;
+DROP TABLE IF EXISTS cashier_shifts;
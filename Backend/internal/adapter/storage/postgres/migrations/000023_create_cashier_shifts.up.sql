@@ -0,0 +1,32 @@
+CREATE TABLE cashier_shifts (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    status INT NOT NULL DEFAULT 1,
+    opening_float DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    opened_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP,
+    closing_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user has at most one open shift
+CREATE UNIQUE INDEX idx_cashier_shifts_open_user ON cashier_shifts(user_id) WHERE status = 1;
+
+-- Counted amounts per payment method recorded when a shift is closed
+CREATE TABLE cashier_shift_counts (
+    id SERIAL PRIMARY KEY,
+    shift_id INT NOT NULL REFERENCES cashier_shifts(id) ON DELETE CASCADE,
+    payment_method INT NOT NULL,
+    expected DECIMAL(12, 2) NOT NULL,
+    counted DECIMAL(12, 2) NOT NULL,
+    variance DECIMAL(12, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (shift_id, payment_method)
+);
+
+ALTER TABLE payments
+    ADD COLUMN taken_by INT REFERENCES users(id),
+    ADD COLUMN shift_id INT REFERENCES cashier_shifts(id);
+
+CREATE INDEX idx_payments_shift_id ON payments(shift_id);
@@ -0,0 +1,80 @@
+-- The hotel's business date. Postings are dated on it until the night audit rolls it forward.
+CREATE TABLE business_date (
+    id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    business_date DATE NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO business_date (business_date) VALUES (CURRENT_DATE);
+
+CREATE TABLE night_audits (
+    id SERIAL PRIMARY KEY,
+    business_date DATE NOT NULL UNIQUE,
+    status INT NOT NULL DEFAULT 1, -- 1: Running, 2: Completed, 3: Failed
+    room_nights_posted INT NOT NULL DEFAULT 0,
+    room_revenue DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    no_show_bookings TEXT NOT NULL DEFAULT '', -- Booking IDs separated by semicolons
+    unbalanced_bookings TEXT NOT NULL DEFAULT '', -- Booking IDs separated by semicolons
+    error_message TEXT NOT NULL DEFAULT '',
+    run_by INT REFERENCES users(id),
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Room nights are now posted night by night, so the balance of a booking
+-- still counts the nights of the stay that are not on the folio yet
+DROP VIEW IF EXISTS booking_customer_payment;
+
+CREATE VIEW booking_customer_payment AS
+WITH booking_payments AS (
+    SELECT
+        booking_id,
+        COALESCE(SUM(base_amount) FILTER (WHERE status = 2), 0) AS paid_amount,
+        MAX(updated_at) AS payment_update_date
+    FROM payments
+    GROUP BY booking_id
+),
+booking_charges AS (
+    SELECT
+        booking_id,
+        COALESCE(SUM(amount + tax_amount) FILTER (WHERE charge_type <> 1), 0) AS other_charges,
+        COALESCE(SUM(amount + tax_amount) FILTER (WHERE charge_type = 1), 0) AS room_charges
+    FROM folio_charges
+    GROUP BY booking_id
+)
+SELECT
+    b.id AS booking_id,
+    b.customer_id,
+    b.total_amount AS booking_price,
+    b.status AS booking_status,
+    b.check_in_date,
+    b.check_out_date,
+    b.created_at AS booking_created_at,
+    b.updated_at AS booking_updated_at,
+    b.room_id,
+    r.room_number,
+    r.type_id AS room_type_id,
+    rt.name AS room_type_name,
+    r.floor,
+    b.rate_prices_id,
+    c.firstname AS customer_firstname,
+    c.surname AS customer_surname,
+    c.identity_number AS customer_identity_number,
+    c.address AS customer_address,
+    COALESCE(bp.paid_amount, 0) AS paid_amount,
+    (COALESCE(bc.other_charges, 0) + GREATEST(COALESCE(bc.room_charges, 0), b.total_amount)) - COALESCE(bp.paid_amount, 0) AS balance,
+    CASE
+        WHEN COALESCE(bp.paid_amount, 0) <= 0 THEN 1
+        WHEN (COALESCE(bc.other_charges, 0) + GREATEST(COALESCE(bc.room_charges, 0), b.total_amount)) - bp.paid_amount < 0.005 THEN 2
+        ELSE 5
+    END AS payment_status,
+    bp.payment_update_date
+FROM
+    bookings b
+    JOIN customers c ON b.customer_id = c.id
+    LEFT JOIN booking_payments bp ON b.id = bp.booking_id
+    LEFT JOIN booking_charges bc ON b.id = bc.booking_id
+    JOIN rooms r ON b.room_id = r.id
+    JOIN room_types rt ON r.type_id = rt.id;
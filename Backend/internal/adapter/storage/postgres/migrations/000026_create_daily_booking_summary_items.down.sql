@@ -0,0 +1,12 @@
+ALTER TABLE daily_booking_summary
+    ADD COLUMN created_bookings TEXT NOT NULL DEFAULT '',
+    ADD COLUMN completed_bookings TEXT NOT NULL DEFAULT '',
+    ADD COLUMN canceled_bookings TEXT NOT NULL DEFAULT '';
+
+UPDATE daily_booking_summary s
+SET
+    created_bookings = COALESCE((SELECT string_agg(i.booking_id::TEXT, ';' ORDER BY i.booking_id) FROM daily_booking_summary_items i WHERE i.summary_date = s.summary_date AND i.category = 1), ''),
+    completed_bookings = COALESCE((SELECT string_agg(i.booking_id::TEXT, ';' ORDER BY i.booking_id) FROM daily_booking_summary_items i WHERE i.summary_date = s.summary_date AND i.category = 2), ''),
+    canceled_bookings = COALESCE((SELECT string_agg(i.booking_id::TEXT, ';' ORDER BY i.booking_id) FROM daily_booking_summary_items i WHERE i.summary_date = s.summary_date AND i.category = 3), '');
+
+DROP TABLE IF EXISTS daily_booking_summary_items;
@@ -0,0 +1,36 @@
+CREATE TABLE daily_booking_summary_items (
+    summary_date DATE NOT NULL REFERENCES daily_booking_summary(summary_date) ON DELETE CASCADE,
+    booking_id INT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    category INT NOT NULL CHECK (category BETWEEN 1 AND 3), -- 1: Created, 2: Completed, 3: Canceled
+    amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    PRIMARY KEY (summary_date, category, booking_id)
+);
+
+CREATE INDEX idx_daily_booking_summary_items_booking_id ON daily_booking_summary_items(booking_id);
+
+-- Move the booking IDs of existing summaries out of the semicolon-separated columns,
+-- skipping IDs of bookings that no longer exist. The CASE only casts tokens that are numbers,
+-- whatever order the planner evaluates the conditions in.
+WITH tokens AS (
+    SELECT s.summary_date, c.category, btrim(token) AS token
+    FROM daily_booking_summary s
+    CROSS JOIN LATERAL (
+        VALUES (1, s.created_bookings), (2, s.completed_bookings), (3, s.canceled_bookings)
+    ) AS c(category, booking_ids)
+    CROSS JOIN LATERAL regexp_split_to_table(c.booking_ids, ';') AS token
+),
+ids AS (
+    SELECT summary_date, category,
+        CASE WHEN token ~ '^[0-9]{1,9}$' THEN token::INT END AS booking_id
+    FROM tokens
+)
+INSERT INTO daily_booking_summary_items (summary_date, booking_id, category, amount)
+SELECT ids.summary_date, b.id, ids.category, COALESCE(b.total_amount, 0)
+FROM ids
+JOIN bookings b ON b.id = ids.booking_id
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE daily_booking_summary
+    DROP COLUMN created_bookings,
+    DROP COLUMN completed_bookings,
+    DROP COLUMN canceled_bookings;
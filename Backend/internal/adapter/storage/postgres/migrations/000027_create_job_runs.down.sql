@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS job_runs;
+
+DELETE FROM logs WHERE user_id IN (SELECT id FROM users WHERE username = 'system');
+DELETE FROM users WHERE username = 'system';
@@ -0,0 +1,19 @@
+-- Account that scheduled jobs act as. Its password is not a bcrypt hash, so it cannot log in.
+INSERT INTO users (username, password, role, rank, status)
+VALUES ('system', '!', 0, 'system', 'inactive')
+ON CONFLICT (username) DO NOTHING;
+
+CREATE TABLE job_runs (
+    id SERIAL PRIMARY KEY,
+    job_name VARCHAR(50) NOT NULL,
+    target_date DATE NOT NULL,
+    status INT NOT NULL DEFAULT 1, -- 1: Running, 2: Succeeded, 3: Failed, 4: Skipped
+    attempt INT NOT NULL DEFAULT 1,
+    error_message TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_job_runs_job_name ON job_runs(job_name, started_at DESC);
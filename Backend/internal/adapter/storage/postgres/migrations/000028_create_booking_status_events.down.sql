@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS bookings_status_events ON bookings;
+DROP TABLE IF EXISTS booking_status_events;
+DROP FUNCTION IF EXISTS record_booking_status_event();
+DROP FUNCTION IF EXISTS prevent_booking_status_event_change();
@@ -0,0 +1,54 @@
+CREATE TABLE booking_status_events (
+    id SERIAL PRIMARY KEY,
+    booking_id INT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    from_status INT, -- NULL when the booking was created
+    to_status INT NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL DEFAULT 0, -- Booking total at the time of the change
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_booking_status_events_changed_at ON booking_status_events(changed_at);
+CREATE INDEX idx_booking_status_events_booking_id ON booking_status_events(booking_id);
+
+-- Every status a booking is created with or moved to is recorded, whichever code path changes it
+CREATE FUNCTION record_booking_status_event() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO booking_status_events (booking_id, from_status, to_status, amount, changed_at)
+        VALUES (NEW.id, NULL, COALESCE(NEW.status, 1), COALESCE(NEW.total_amount, 0), COALESCE(NEW.created_at, CURRENT_TIMESTAMP));
+    ELSIF NEW.status IS NOT NULL AND NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO booking_status_events (booking_id, from_status, to_status, amount, changed_at)
+        VALUES (NEW.id, OLD.status, NEW.status, COALESCE(NEW.total_amount, 0), COALESCE(NEW.updated_at, CURRENT_TIMESTAMP));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bookings_status_events
+AFTER INSERT OR UPDATE OF status ON bookings
+FOR EACH ROW EXECUTE FUNCTION record_booking_status_event();
+
+-- Events are never edited. They are only removed along with their booking.
+CREATE FUNCTION prevent_booking_status_event_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND pg_trigger_depth() > 1 THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'booking status events are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER booking_status_events_immutable
+BEFORE UPDATE OR DELETE ON booking_status_events
+FOR EACH ROW EXECUTE FUNCTION prevent_booking_status_event_change();
+
+-- Existing bookings only carry their current status, so their history is rebuilt from the creation
+-- time and, when the status has moved on since, the last update time
+INSERT INTO booking_status_events (booking_id, from_status, to_status, amount, changed_at)
+SELECT id, NULL, 1, COALESCE(total_amount, 0), COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM bookings;
+
+INSERT INTO booking_status_events (booking_id, from_status, to_status, amount, changed_at)
+SELECT id, 1, status, COALESCE(total_amount, 0), COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+FROM bookings
+WHERE status IS NOT NULL AND status <> 1;
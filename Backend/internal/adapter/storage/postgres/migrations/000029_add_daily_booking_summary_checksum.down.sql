@@ -0,0 +1 @@
+ALTER TABLE daily_booking_summary DROP COLUMN checksum;
@@ -0,0 +1,3 @@
+-- SHA-256 of the booking activity and totals a summary was generated from. Summaries generated before
+-- checksums were recorded keep an empty one.
+ALTER TABLE daily_booking_summary ADD COLUMN checksum VARCHAR(64) NOT NULL DEFAULT '';
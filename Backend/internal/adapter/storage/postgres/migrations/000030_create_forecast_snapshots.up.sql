@@ -0,0 +1,11 @@
+-- Rooms on the books for each future night as they stood on snapshot_date, kept to report pickup
+CREATE TABLE forecast_snapshots (
+    snapshot_date DATE NOT NULL,
+    stay_date DATE NOT NULL,
+    room_type_id INT NOT NULL, -- 0 for bookings without a room type
+    rooms_available INT NOT NULL DEFAULT 0,
+    rooms_booked INT NOT NULL DEFAULT 0,
+    revenue DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_date, stay_date, room_type_id)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_customers_search_identity_number;
+DROP INDEX IF EXISTS idx_customers_search_email;
+DROP INDEX IF EXISTS idx_customers_search_phone;
+DROP INDEX IF EXISTS idx_customers_search_name;
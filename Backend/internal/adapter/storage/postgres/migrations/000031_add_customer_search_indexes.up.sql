@@ -0,0 +1,12 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Customer search compares these normalized forms: names lower-cased with whitespace collapsed, phones
+-- as digits only. Trigram indexes serve both substring matches and word similarity.
+CREATE INDEX idx_customers_search_name ON customers
+    USING GIN (lower(regexp_replace(btrim(firstname || ' ' || surname), '\s+', ' ', 'g')) gin_trgm_ops);
+CREATE INDEX idx_customers_search_phone ON customers
+    USING GIN (regexp_replace(COALESCE(phone, ''), '\D', '', 'g') gin_trgm_ops);
+CREATE INDEX idx_customers_search_email ON customers
+    USING GIN (lower(COALESCE(email, '')) gin_trgm_ops);
+CREATE INDEX idx_customers_search_identity_number ON customers
+    USING GIN (lower(COALESCE(identity_number, '')) gin_trgm_ops);
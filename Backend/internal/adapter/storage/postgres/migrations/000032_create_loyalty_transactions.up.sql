@@ -0,0 +1,19 @@
+-- Loyalty points ledger of each customer. Earned and reinstated points are positive; redeemed and
+-- expired points are negative, so a customer's balance is the sum of their transactions.
+CREATE TABLE loyalty_transactions (
+    id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    transaction_type INT NOT NULL,
+    points INT NOT NULL,
+    booking_id INT REFERENCES bookings(id) ON DELETE SET NULL,
+    payment_id INT REFERENCES payments(id) ON DELETE SET NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP, -- When the points of an earn transaction lapse, NULL when they never do
+    created_by INT REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_loyalty_transactions_customer_id ON loyalty_transactions(customer_id);
+CREATE INDEX idx_loyalty_transactions_payment_id ON loyalty_transactions(payment_id);
+-- A booking earns points once, however often it is completed
+CREATE UNIQUE INDEX idx_loyalty_transactions_booking_earn ON loyalty_transactions(booking_id) WHERE transaction_type = 1;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_invoices_open_payer;
+
+CREATE OR REPLACE FUNCTION prevent_invoice_modification() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'issued invoices cannot be changed; cancel them with a credit note';
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS cancelled;
+
+DROP TABLE IF EXISTS invoice_payments;
+DROP TABLE IF EXISTS invoice_charges;
@@ -0,0 +1,80 @@
+-- Charges and payments as printed on an issued tax invoice, so reprints never depend on the live folio
+CREATE TABLE invoice_charges (
+    id SERIAL PRIMARY KEY,
+    invoice_id INT NOT NULL REFERENCES invoices(id),
+    charge_type INT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    quantity INT NOT NULL,
+    unit_price DECIMAL(10, 2) NOT NULL,
+    tax_rate DECIMAL(5, 2) NOT NULL,
+    tax_amount DECIMAL(10, 2) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    posted_at TIMESTAMP
+);
+
+CREATE INDEX idx_invoice_charges_invoice_id ON invoice_charges(invoice_id);
+
+CREATE TABLE invoice_payments (
+    id SERIAL PRIMARY KEY,
+    invoice_id INT NOT NULL REFERENCES invoices(id),
+    payment_method INT NOT NULL,
+    payment_type INT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    base_amount DECIMAL(10, 2) NOT NULL,
+    payment_date TIMESTAMP
+);
+
+CREATE INDEX idx_invoice_payments_invoice_id ON invoice_payments(invoice_id);
+
+-- Store the lines of invoices issued so far as they have been printed: the payer's share of the
+-- folio, taken from the charges and paid payments created before the invoice was issued
+INSERT INTO invoice_charges (invoice_id, charge_type, description, quantity, unit_price, tax_rate, tax_amount, amount, posted_at)
+SELECT i.id, fc.charge_type, fc.description, fc.quantity, fc.unit_price, fc.tax_rate, fc.tax_amount, fc.amount, fc.posted_at
+FROM invoices i
+JOIN folio_charges fc ON fc.booking_id = i.booking_id AND fc.created_at <= i.issued_at
+WHERE i.invoice_type = 1
+  AND i.payer_id IS NOT DISTINCT FROM (
+      SELECT bp.id FROM booking_payers bp
+      WHERE bp.booking_id = fc.booking_id AND fc.charge_type = ANY(bp.charge_types)
+      ORDER BY bp.id
+      LIMIT 1
+  )
+ORDER BY i.id, fc.posted_at, fc.id;
+
+INSERT INTO invoice_payments (invoice_id, payment_method, payment_type, currency, base_amount, payment_date)
+SELECT i.id, p.payment_method, p.payment_type, p.currency, p.base_amount, p.payment_date
+FROM invoices i
+JOIN payments p ON p.booking_id = i.booking_id AND p.created_at <= i.issued_at
+WHERE i.invoice_type = 1
+  AND p.status = 2
+  AND p.payer_id IS NOT DISTINCT FROM i.payer_id
+ORDER BY i.id, p.payment_date, p.id;
+
+-- Set on a tax invoice when its credit note is issued
+ALTER TABLE invoices ADD COLUMN cancelled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Issued invoices and their lines never change, except that a tax invoice is marked cancelled once
+CREATE OR REPLACE FUNCTION prevent_invoice_modification() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'invoices' AND TG_OP = 'UPDATE' THEN
+        IF NOT OLD.cancelled AND NEW.cancelled AND to_jsonb(NEW) - 'cancelled' = to_jsonb(OLD) - 'cancelled' THEN
+            RETURN NEW;
+        END IF;
+    END IF;
+    RAISE EXCEPTION 'issued invoices cannot be changed; cancel them with a credit note';
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE invoices SET cancelled = TRUE
+WHERE id IN (SELECT original_invoice_id FROM invoices WHERE original_invoice_id IS NOT NULL);
+
+CREATE TRIGGER invoice_charges_immutable
+    BEFORE UPDATE OR DELETE ON invoice_charges
+    FOR EACH ROW EXECUTE FUNCTION prevent_invoice_modification();
+
+CREATE TRIGGER invoice_payments_immutable
+    BEFORE UPDATE OR DELETE ON invoice_payments
+    FOR EACH ROW EXECUTE FUNCTION prevent_invoice_modification();
+
+-- A payer has at most one tax invoice per booking that has not been cancelled
+CREATE UNIQUE INDEX idx_invoices_open_payer ON invoices(booking_id, COALESCE(payer_id, 0)) WHERE invoice_type = 1 AND NOT cancelled;
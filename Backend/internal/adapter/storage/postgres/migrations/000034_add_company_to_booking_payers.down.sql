@@ -0,0 +1,7 @@
+DELETE FROM booking_payers WHERE company_id IS NOT NULL;
+
+ALTER TABLE booking_payers
+    DROP CONSTRAINT IF EXISTS booking_payers_booking_id_company_id_key,
+    DROP CONSTRAINT IF EXISTS booking_payers_one_party,
+    DROP COLUMN IF EXISTS company_id,
+    ALTER COLUMN customer_id SET NOT NULL;
@@ -0,0 +1,6 @@
+-- A booking payer is either a customer or a company, whose share is billed to its ledger at checkout
+ALTER TABLE booking_payers
+    ALTER COLUMN customer_id DROP NOT NULL,
+    ADD COLUMN company_id INT REFERENCES companies(id),
+    ADD CONSTRAINT booking_payers_one_party CHECK (num_nonnulls(customer_id, company_id) = 1),
+    ADD CONSTRAINT booking_payers_booking_id_company_id_key UNIQUE (booking_id, company_id);
@@ -0,0 +1,6 @@
+DELETE FROM cashier_shift_counts WHERE currency <> 'THB';
+
+ALTER TABLE cashier_shift_counts
+    DROP CONSTRAINT cashier_shift_counts_shift_id_payment_method_currency_key,
+    ADD CONSTRAINT cashier_shift_counts_shift_id_payment_method_key UNIQUE (shift_id, payment_method),
+    DROP COLUMN currency;
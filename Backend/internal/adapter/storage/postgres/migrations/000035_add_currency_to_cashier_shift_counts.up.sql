@@ -0,0 +1,5 @@
+-- Shifts are counted per payment method and currency; counts made before were all in THB
+ALTER TABLE cashier_shift_counts
+    ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'THB',
+    DROP CONSTRAINT cashier_shift_counts_shift_id_payment_method_key,
+    ADD CONSTRAINT cashier_shift_counts_shift_id_payment_method_currency_key UNIQUE (shift_id, payment_method, currency);
@@ -0,0 +1,17 @@
+ALTER TABLE night_audits
+    ADD COLUMN no_show_bookings TEXT NOT NULL DEFAULT '',
+    ADD COLUMN unbalanced_bookings TEXT NOT NULL DEFAULT '';
+
+UPDATE night_audits a
+SET no_show_bookings = COALESCE((
+        SELECT string_agg(booking_id::TEXT, ';' ORDER BY booking_id)
+        FROM night_audit_bookings
+        WHERE night_audit_id = a.id AND category = 1
+    ), ''),
+    unbalanced_bookings = COALESCE((
+        SELECT string_agg(booking_id::TEXT, ';' ORDER BY booking_id)
+        FROM night_audit_bookings
+        WHERE night_audit_id = a.id AND category = 2
+    ), '');
+
+DROP TABLE IF EXISTS night_audit_bookings;
@@ -0,0 +1,32 @@
+-- Bookings the night audit flagged, one row each instead of ID lists in text columns
+CREATE TABLE night_audit_bookings (
+    night_audit_id INT NOT NULL REFERENCES night_audits(id),
+    booking_id INT NOT NULL REFERENCES bookings(id),
+    category INT NOT NULL, -- 1: No-show, 2: Unbalanced departure
+    PRIMARY KEY (night_audit_id, category, booking_id)
+);
+
+CREATE INDEX idx_night_audit_bookings_booking_id ON night_audit_bookings(booking_id);
+
+-- The CASE only casts tokens that are numbers, whatever order the planner evaluates the conditions in.
+-- Bookings deleted since the audit ran are dropped.
+WITH tokens AS (
+    SELECT a.id AS night_audit_id, 1 AS category, btrim(token) AS token
+    FROM night_audits a, regexp_split_to_table(a.no_show_bookings, ';') AS token
+    UNION ALL
+    SELECT a.id, 2, btrim(token)
+    FROM night_audits a, regexp_split_to_table(a.unbalanced_bookings, ';') AS token
+),
+ids AS (
+    SELECT night_audit_id, category,
+        CASE WHEN token ~ '^[0-9]{1,9}$' THEN token::INT END AS booking_id
+    FROM tokens
+)
+INSERT INTO night_audit_bookings (night_audit_id, booking_id, category)
+SELECT DISTINCT ids.night_audit_id, ids.booking_id, ids.category
+FROM ids
+JOIN bookings b ON b.id = ids.booking_id;
+
+ALTER TABLE night_audits
+    DROP COLUMN no_show_bookings,
+    DROP COLUMN unbalanced_bookings;
@@ -0,0 +1,2 @@
+-- The base amount of a deleted payment, kept so the delete can still be reviewed once the payment is gone
+ALTER TABLE logs ADD COLUMN amount DECIMAL(12, 2);
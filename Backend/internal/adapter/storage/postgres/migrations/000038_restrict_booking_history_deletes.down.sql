@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS bookings_remove_history ON bookings;
+DROP FUNCTION IF EXISTS remove_booking_history();
+
+ALTER TABLE daily_booking_summary_items
+    DROP CONSTRAINT daily_booking_summary_items_booking_id_fkey,
+    ADD CONSTRAINT daily_booking_summary_items_booking_id_fkey FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE;
+
+ALTER TABLE booking_status_events
+    DROP CONSTRAINT booking_status_events_booking_id_fkey,
+    ADD CONSTRAINT booking_status_events_booking_id_fkey FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE;
@@ -0,0 +1,32 @@
+-- Deleting a booking no longer takes its status history or the items of confirmed summaries with it,
+-- so the revenue of a closed day cannot change afterwards
+ALTER TABLE booking_status_events
+    DROP CONSTRAINT booking_status_events_booking_id_fkey,
+    ADD CONSTRAINT booking_status_events_booking_id_fkey FOREIGN KEY (booking_id) REFERENCES bookings(id);
+
+ALTER TABLE daily_booking_summary_items
+    DROP CONSTRAINT daily_booking_summary_items_booking_id_fkey,
+    ADD CONSTRAINT daily_booking_summary_items_booking_id_fkey FOREIGN KEY (booking_id) REFERENCES bookings(id);
+
+-- A booking that was never completed can still be deleted: its status events and its items in summaries
+-- not confirmed yet go with it. Those of a completed booking or a confirmed summary make the delete fail.
+CREATE FUNCTION remove_booking_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM booking_status_events
+        WHERE booking_id = OLD.id AND to_status = 5 AND from_status IS NOT NULL
+    ) THEN
+        DELETE FROM booking_status_events WHERE booking_id = OLD.id;
+    END IF;
+
+    DELETE FROM daily_booking_summary_items i
+    USING daily_booking_summary s
+    WHERE s.summary_date = i.summary_date AND i.booking_id = OLD.id AND s.status <> 2;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bookings_remove_history
+BEFORE DELETE ON bookings
+FOR EACH ROW EXECUTE FUNCTION remove_booking_history();
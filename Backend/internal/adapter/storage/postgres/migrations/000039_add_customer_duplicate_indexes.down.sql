@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_customers_duplicate_email;
+DROP INDEX IF EXISTS idx_customers_duplicate_phone;
+DROP INDEX IF EXISTS idx_customers_duplicate_identity_number;
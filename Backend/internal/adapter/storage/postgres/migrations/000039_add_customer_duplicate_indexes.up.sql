@@ -0,0 +1,8 @@
+-- Duplicate detection pairs customers sharing these normalized keys: the identity number as letters and
+-- digits only, the phone digits and the trimmed email. Similar names go through idx_customers_search_name.
+CREATE INDEX idx_customers_duplicate_identity_number ON customers
+    ((regexp_replace(lower(COALESCE(identity_number, '')), '[^[:alnum:]]', '', 'g')));
+CREATE INDEX idx_customers_duplicate_phone ON customers
+    ((regexp_replace(COALESCE(phone, ''), '\D', '', 'g')));
+CREATE INDEX idx_customers_duplicate_email ON customers
+    ((lower(btrim(COALESCE(email, '')))));
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/gin-gonic/gin"
 	"fmt"
+	"time"
 )
 
 type BookingRepository struct {
@@ -326,7 +327,8 @@ func (br *BookingRepository) GetBookingCustomerPayment(ctx *gin.Context, id uint
 		&bcp.CustomerSurname,
 		&bcp.CustomerIdentityNumber,
 		&bcp.CustomerAddress,
-		&bcp.PaymentID,
+		&bcp.PaidAmount,
+		&bcp.Balance,
 		&bcp.PaymentStatus,
 		&bcp.PaymentUpdateDate,
 	)
@@ -399,7 +401,8 @@ func (br *BookingRepository) ListBookingCustomerPayments(ctx *gin.Context, skip,
 			&bcp.CustomerSurname,
 			&bcp.CustomerIdentityNumber,
 			&bcp.CustomerAddress,
-			&bcp.PaymentID,
+			&bcp.PaidAmount,
+			&bcp.Balance,
 			&bcp.PaymentStatus,
 			&bcp.PaymentUpdateDate,
 		)
@@ -461,6 +464,122 @@ func (br *BookingRepository) UpdateBooking(ctx *gin.Context, booking *domain.Boo
 	return booking, nil
 }
 
+// CheckOutBooking stores a checkout in one transaction, so a failed step leaves neither a posted room charge
+// nor a transferred balance behind. The company row stays locked while its credit limit is checked, and a
+// booking no longer checked in fails with ErrConflictingData.
+func (br *BookingRepository) CheckOutBooking(ctx *gin.Context, checkOut *domain.BookingCheckOut) (*domain.Booking, error) {
+	tx, err := br.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if charge := checkOut.RoomCharge; charge != nil {
+		chargeQuery := br.db.QueryBuilder.Insert("folio_charges").
+			Columns("booking_id", "charge_type", "description", "quantity", "unit_price", "tax_rate", "tax_amount", "amount", "posted_by", "posted_at").
+			Values(charge.BookingID, charge.ChargeType, charge.Description, charge.Quantity, charge.UnitPrice, charge.TaxRate, charge.TaxAmount, charge.Amount, charge.PostedBy, charge.PostedAt).
+			Suffix("RETURNING id, created_at")
+
+		sql, args, err := chargeQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", chargeQuery)
+
+		if err := tx.QueryRow(ctx, sql, args...).Scan(&charge.ID, &charge.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if payment, entry := checkOut.Payment, checkOut.LedgerEntry; payment != nil && entry != nil {
+		var creditLimit, outstanding float64
+		err := tx.QueryRow(ctx, "SELECT credit_limit FROM companies WHERE id = $1 FOR UPDATE", entry.CompanyID).Scan(&creditLimit)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, domain.ErrDataNotFound
+			}
+			return nil, err
+		}
+		err = tx.QueryRow(ctx, "SELECT COALESCE(SUM(amount), 0) FROM company_ledger_entries WHERE company_id = $1", entry.CompanyID).Scan(&outstanding)
+		if err != nil {
+			return nil, err
+		}
+		if outstanding+entry.Amount > creditLimit+0.005 {
+			return nil, domain.ErrCreditLimitExceeded
+		}
+
+		paymentQuery := br.db.QueryBuilder.Insert("payments").
+			Columns("booking_id", "amount", "payment_method", "payment_date", "status", "currency", "exchange_rate", "base_amount", "payment_type", "payer_id", "taken_by", "shift_id").
+			Values(payment.BookingID, payment.Amount, payment.PaymentMethod, payment.PaymentDate, int(payment.Status), payment.Currency, payment.ExchangeRate, payment.BaseAmount, payment.Type, payment.PayerID, payment.TakenBy, payment.ShiftID).
+			Suffix("RETURNING id, created_at, updated_at")
+
+		sql, args, err := paymentQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", paymentQuery)
+
+		if err := tx.QueryRow(ctx, sql, args...).Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		entry.PaymentID = &payment.ID
+		entryQuery := br.db.QueryBuilder.Insert("company_ledger_entries").
+			Columns("company_id", "entry_type", "booking_id", "payment_id", "reference", "amount", "entry_date", "due_date", "posted_by").
+			Values(entry.CompanyID, entry.EntryType, entry.BookingID, entry.PaymentID, entry.Reference, entry.Amount, entry.EntryDate, entry.DueDate, entry.PostedBy).
+			Suffix("RETURNING id, created_at")
+
+		sql, args, err = entryQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", entryQuery)
+
+		if err := tx.QueryRow(ctx, sql, args...).Scan(&entry.ID, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	booking := checkOut.Booking
+	query := br.db.QueryBuilder.Update("bookings").
+		Set("status", domain.BookingStatusCheckedOut).
+		Set("updated_at", booking.UpdatedAt.Format("2006-01-02 15:04:05")).
+		Where(sq.Eq{"id": booking.ID, "status": domain.BookingStatusCheckedIn}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(
+		&booking.ID,
+		&booking.CustomerID,
+		&booking.RatePriceId,
+		&booking.RoomID,
+		&booking.RoomTypeID,
+		&booking.CheckInDate,
+		&booking.CheckOutDate,
+		&booking.Status,
+		&booking.TotalAmount,
+		&booking.CreatedAt,
+		&booking.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
 func (br *BookingRepository) DeleteBooking(ctx *gin.Context, id uint64) error {
 	query := br.db.QueryBuilder.Delete("bookings").
 		Where(sq.Eq{"id": id})
@@ -473,6 +592,10 @@ func (br *BookingRepository) DeleteBooking(ctx *gin.Context, id uint64) error {
 
 	_, err = br.db.Exec(ctx, sql, args...)
 	if err != nil {
+		// Bookings a night audit or a confirmed summary recorded, and completed bookings, are kept
+		if errCode := br.db.ErrorCode(err); errCode == "23503" {
+			return domain.ErrConflictingData
+		}
 		return err
 	}
 
@@ -480,65 +603,17 @@ func (br *BookingRepository) DeleteBooking(ctx *gin.Context, id uint64) error {
 }
 
 func (br *BookingRepository) ListBookingCustomerPaymentsWithFilter(ctx *gin.Context, bookingCustomerPayment *domain.BookingCustomerPayment, skip, limit uint64) ([]domain.BookingCustomerPayment, uint64, error) {
-	var bookings []domain.BookingCustomerPayment
 	var totalCount uint64
 
 	// Build base query conditions that will be used for both count and select
-	conditions := sq.And{}
-	if bookingCustomerPayment.BookingID != 0 {
-		conditions = append(conditions, sq.Eq{"booking_id": bookingCustomerPayment.BookingID})
-	}
-	if bookingCustomerPayment.CustomerID != 0 {
-		conditions = append(conditions, sq.Eq{"customer_id": bookingCustomerPayment.CustomerID})
-	}
-	if bookingCustomerPayment.BookingPrice != 0 {
-		conditions = append(conditions, sq.Eq{"booking_price": bookingCustomerPayment.BookingPrice})
-	}
-	if bookingCustomerPayment.BookingStatus != 0 {
-		conditions = append(conditions, sq.Eq{"booking_status": bookingCustomerPayment.BookingStatus})
-	}
-	if bookingCustomerPayment.CheckInDate != nil {
-		conditions = append(conditions, sq.Eq{"check_in_date": bookingCustomerPayment.CheckInDate})
-	}
-	if bookingCustomerPayment.CheckOutDate != nil {
-		conditions = append(conditions, sq.Eq{"check_out_date": bookingCustomerPayment.CheckOutDate})
-	}
-	if bookingCustomerPayment.RoomID != 0 {
-		conditions = append(conditions, sq.Eq{"room_id": bookingCustomerPayment.RoomID})
-	}
-	if bookingCustomerPayment.RoomNumber != "" {
-		conditions = append(conditions, sq.Eq{"room_number": bookingCustomerPayment.RoomNumber})
-	}
-	if bookingCustomerPayment.RoomTypeID != 0 {
-		conditions = append(conditions, sq.Eq{"room_type_id": bookingCustomerPayment.RoomTypeID})
-	}
-	if bookingCustomerPayment.RoomTypeName != "" {
-		conditions = append(conditions, sq.Eq{"room_type_name": bookingCustomerPayment.RoomTypeName})
-	}
-	if bookingCustomerPayment.CustomerFirstName != "" {
-		conditions = append(conditions, sq.Eq{"customer_firstname": bookingCustomerPayment.CustomerFirstName})
-	}
-	if bookingCustomerPayment.CustomerSurname != "" {
-		conditions = append(conditions, sq.Eq{"customer_surname": bookingCustomerPayment.CustomerSurname})
-	}
-	if bookingCustomerPayment.PaymentStatus != nil {
-		conditions = append(conditions, sq.Eq{"payment_status": bookingCustomerPayment.PaymentStatus})
-	}
-	if bookingCustomerPayment.BookingCreatedAt != nil {
-		dateStr := bookingCustomerPayment.BookingCreatedAt.Format("2006-01-02")
-		conditions = append(conditions, sq.Expr("booking_created_at::date = ?", dateStr))
-	}
-	if bookingCustomerPayment.BookingUpdatedAt != nil {
-		dateStr := bookingCustomerPayment.BookingUpdatedAt.Format("2006-01-02")
-		conditions = append(conditions, sq.Expr("booking_updated_at::date = ?", dateStr))
-	}
+	conditions := bookingCustomerPaymentConditions(bookingCustomerPayment)
 
 	// Apply conditions to count query
 	countQuery := br.db.QueryBuilder.Select("COUNT(*)").From("booking_customer_payment")
 	if len(conditions) > 0 {
 		countQuery = countQuery.Where(conditions)
 	}
-	
+
 	countSql, countArgs, err := countQuery.ToSql()
 	if err != nil {
 		return nil, 0, err
@@ -553,7 +628,7 @@ func (br *BookingRepository) ListBookingCustomerPaymentsWithFilter(ctx *gin.Cont
 		From("booking_customer_payment").
 		OrderBy("booking_id DESC").
 		Limit(limit)
-	
+
 	if len(conditions) > 0 {
 		query = query.Where(conditions)
 	}
@@ -562,15 +637,101 @@ func (br *BookingRepository) ListBookingCustomerPaymentsWithFilter(ctx *gin.Cont
 		query = query.Offset(skip)
 	}
 
-	sql, args, err := query.ToSql()
+	bookings, err := br.queryBookingCustomerPayments(ctx, query)
 	if err != nil {
 		return nil, 0, err
 	}
+
+	return bookings, totalCount, nil
+}
+
+// ListBookingCustomerPaymentsBefore lists up to limit bookings matching the filter with IDs below beforeID,
+// latest first, or the latest bookings when beforeID is zero
+func (br *BookingRepository) ListBookingCustomerPaymentsBefore(ctx *gin.Context, filter *domain.BookingCustomerPayment, beforeID, limit uint64) ([]domain.BookingCustomerPayment, error) {
+	conditions := bookingCustomerPaymentConditions(filter)
+	if beforeID > 0 {
+		conditions = append(conditions, sq.Lt{"booking_id": beforeID})
+	}
+
+	query := br.db.QueryBuilder.Select("*").
+		From("booking_customer_payment").
+		OrderBy("booking_id DESC").
+		Limit(limit)
+
+	if len(conditions) > 0 {
+		query = query.Where(conditions)
+	}
+
+	return br.queryBookingCustomerPayments(ctx, query)
+}
+
+// bookingCustomerPaymentConditions matches the bookings with the set fields of filter
+func bookingCustomerPaymentConditions(filter *domain.BookingCustomerPayment) sq.And {
+	conditions := sq.And{}
+	if filter.BookingID != 0 {
+		conditions = append(conditions, sq.Eq{"booking_id": filter.BookingID})
+	}
+	if filter.CustomerID != 0 {
+		conditions = append(conditions, sq.Eq{"customer_id": filter.CustomerID})
+	}
+	if filter.BookingPrice != 0 {
+		conditions = append(conditions, sq.Eq{"booking_price": filter.BookingPrice})
+	}
+	if filter.BookingStatus != 0 {
+		conditions = append(conditions, sq.Eq{"booking_status": filter.BookingStatus})
+	}
+	if filter.CheckInDate != nil {
+		conditions = append(conditions, sq.Eq{"check_in_date": filter.CheckInDate})
+	}
+	if filter.CheckOutDate != nil {
+		conditions = append(conditions, sq.Eq{"check_out_date": filter.CheckOutDate})
+	}
+	if filter.RoomID != 0 {
+		conditions = append(conditions, sq.Eq{"room_id": filter.RoomID})
+	}
+	if filter.RoomNumber != "" {
+		conditions = append(conditions, sq.Eq{"room_number": filter.RoomNumber})
+	}
+	if filter.RoomTypeID != 0 {
+		conditions = append(conditions, sq.Eq{"room_type_id": filter.RoomTypeID})
+	}
+	if filter.RoomTypeName != "" {
+		conditions = append(conditions, sq.Eq{"room_type_name": filter.RoomTypeName})
+	}
+	if filter.CustomerFirstName != "" {
+		conditions = append(conditions, sq.Eq{"customer_firstname": filter.CustomerFirstName})
+	}
+	if filter.CustomerSurname != "" {
+		conditions = append(conditions, sq.Eq{"customer_surname": filter.CustomerSurname})
+	}
+	if filter.PaymentStatus != nil {
+		conditions = append(conditions, sq.Eq{"payment_status": filter.PaymentStatus})
+	}
+	if filter.BookingCreatedAt != nil {
+		dateStr := filter.BookingCreatedAt.Format("2006-01-02")
+		conditions = append(conditions, sq.Expr("booking_created_at::date = ?", dateStr))
+	}
+	if filter.BookingUpdatedAt != nil {
+		dateStr := filter.BookingUpdatedAt.Format("2006-01-02")
+		conditions = append(conditions, sq.Expr("booking_updated_at::date = ?", dateStr))
+	}
+
+	return conditions
+}
+
+// queryBookingCustomerPayments runs a query selecting * from booking_customer_payment
+func (br *BookingRepository) queryBookingCustomerPayments(ctx *gin.Context, query sq.SelectBuilder) ([]domain.BookingCustomerPayment, error) {
+	var bookings []domain.BookingCustomerPayment
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
 	slog.Debug("SQL QUERY", "query", query)
 
 	rows, err := br.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -595,20 +756,196 @@ func (br *BookingRepository) ListBookingCustomerPaymentsWithFilter(ctx *gin.Cont
 			&booking.CustomerSurname,
 			&booking.CustomerIdentityNumber,
 			&booking.CustomerAddress,
-			&booking.PaymentID,
+			&booking.PaidAmount,
+			&booking.Balance,
 			&booking.PaymentStatus,
 			&booking.PaymentUpdateDate,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 
 		bookings = append(bookings, booking)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return bookings, totalCount, nil
-}
\ No newline at end of file
+	return bookings, nil
+}
+
+// ListBookingCustomerPaymentsByCustomerID lists every booking of a customer, latest arrival first
+func (br *BookingRepository) ListBookingCustomerPaymentsByCustomerID(ctx *gin.Context, customerID uint64) ([]domain.BookingCustomerPayment, error) {
+	var bookings []domain.BookingCustomerPayment
+
+	query := br.db.QueryBuilder.Select("*").
+		From("booking_customer_payment").
+		Where(sq.Eq{"customer_id": customerID}).
+		OrderBy("check_in_date DESC", "booking_id DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := br.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var booking domain.BookingCustomerPayment
+		err := rows.Scan(
+			&booking.BookingID,
+			&booking.CustomerID,
+			&booking.BookingPrice,
+			&booking.BookingStatus,
+			&booking.CheckInDate,
+			&booking.CheckOutDate,
+			&booking.BookingCreatedAt,
+			&booking.BookingUpdatedAt,
+			&booking.RoomID,
+			&booking.RoomNumber,
+			&booking.RoomTypeID,
+			&booking.RoomTypeName,
+			&booking.Floor,
+			&booking.RatePriceID,
+			&booking.CustomerFirstName,
+			&booking.CustomerSurname,
+			&booking.CustomerIdentityNumber,
+			&booking.CustomerAddress,
+			&booking.PaidAmount,
+			&booking.Balance,
+			&booking.PaymentStatus,
+			&booking.PaymentUpdateDate,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return bookings, nil
+}
+
+// ListInHouseBookings lists the bookings of guests currently checked in
+func (br *BookingRepository) ListInHouseBookings(ctx *gin.Context) ([]domain.Booking, error) {
+	return br.listBookings(ctx, sq.Eq{"status": domain.BookingStatusCheckedIn})
+}
+
+// ListDueArrivals lists the bookings not checked in yet whose arrival date is on or before the given date
+func (br *BookingRepository) ListDueArrivals(ctx *gin.Context, date time.Time) ([]domain.Booking, error) {
+	return br.listBookings(ctx, sq.And{
+		sq.Eq{"status": domain.BookingStatusUncheckIn},
+		sq.LtOrEq{"check_in_date": date.Format("2006-01-02")},
+	})
+}
+
+// ListDepartures lists the checked-out bookings departing on the given date
+func (br *BookingRepository) ListDepartures(ctx *gin.Context, date time.Time) ([]domain.Booking, error) {
+	return br.listBookings(ctx, sq.Eq{
+		"status":         []domain.BookingStatus{domain.BookingStatusCheckedOut, domain.BookingStatusCompleted},
+		"check_out_date": date.Format("2006-01-02"),
+	})
+}
+
+func (br *BookingRepository) listBookings(ctx *gin.Context, where sq.Sqlizer) ([]domain.Booking, error) {
+	var bookings []domain.Booking
+
+	query := br.db.QueryBuilder.Select("*").
+		From("bookings").
+		Where(where).
+		OrderBy("id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := br.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var booking domain.Booking
+		err := rows.Scan(
+			&booking.ID,
+			&booking.CustomerID,
+			&booking.RatePriceId,
+			&booking.RoomID,
+			&booking.RoomTypeID,
+			&booking.CheckInDate,
+			&booking.CheckOutDate,
+			&booking.Status,
+			&booking.TotalAmount,
+			&booking.CreatedAt,
+			&booking.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return bookings, nil
+}
+
+func (br *BookingRepository) ListBookingStatusEvents(ctx *gin.Context, bookingID uint64) ([]domain.BookingStatusEvent, error) {
+	var events []domain.BookingStatusEvent
+
+	query := br.db.QueryBuilder.Select("*").
+		From("booking_status_events").
+		Where(sq.Eq{"booking_id": bookingID}).
+		OrderBy("changed_at", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := br.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.BookingStatusEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.BookingID,
+			&event.FromStatus,
+			&event.ToStatus,
+			&event.Amount,
+			&event.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
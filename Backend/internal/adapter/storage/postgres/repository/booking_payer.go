@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"log/slog"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+)
+
+type BookingPayerRepository struct {
+	db *postgres.DB
+}
+
+func NewBookingPayerRepository(db *postgres.DB) *BookingPayerRepository {
+	return &BookingPayerRepository{
+		db,
+	}
+}
+
+func (bpr *BookingPayerRepository) CreateBookingPayer(ctx *gin.Context, payer *domain.BookingPayer) (*domain.BookingPayer, error) {
+	chargeTypes := make([]int, 0, len(payer.ChargeTypes))
+	for _, chargeType := range payer.ChargeTypes {
+		chargeTypes = append(chargeTypes, int(chargeType))
+	}
+
+	query := bpr.db.QueryBuilder.Insert("booking_payers").
+		Columns("booking_id", "customer_id", "company_id", "charge_types").
+		Values(payer.BookingID, payer.CustomerID, payer.CompanyID, chargeTypes).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = bpr.db.QueryRow(ctx, sql, args...).Scan(
+		&payer.ID,
+		&payer.BookingID,
+		&payer.CustomerID,
+		&chargeTypes,
+		&payer.CreatedAt,
+		&payer.UpdatedAt,
+		&payer.CompanyID,
+	)
+	if err != nil {
+		switch bpr.db.ErrorCode(err) {
+		case "23505":
+			return nil, domain.ErrConflictingData
+		case "23503":
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	payer.ChargeTypes = toChargeTypes(chargeTypes)
+
+	return payer, nil
+}
+
+func (bpr *BookingPayerRepository) ListBookingPayersByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.BookingPayer, error) {
+	var payers []domain.BookingPayer
+
+	query := bpr.db.QueryBuilder.Select("*").
+		From("booking_payers").
+		Where(sq.Eq{"booking_id": bookingID}).
+		OrderBy("id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := bpr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payer domain.BookingPayer
+		var chargeTypes []int
+		err := rows.Scan(
+			&payer.ID,
+			&payer.BookingID,
+			&payer.CustomerID,
+			&chargeTypes,
+			&payer.CreatedAt,
+			&payer.UpdatedAt,
+			&payer.CompanyID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payer.ChargeTypes = toChargeTypes(chargeTypes)
+
+		payers = append(payers, payer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payers, nil
+}
+
+// DeleteBookingPayer removes a payer from a booking. Payers that payments or invoices were recorded against are kept.
+func (bpr *BookingPayerRepository) DeleteBookingPayer(ctx *gin.Context, bookingID, id uint64) error {
+	query := bpr.db.QueryBuilder.Delete("booking_payers").
+		Where(sq.Eq{"id": id, "booking_id": bookingID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	result, err := bpr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		if errCode := bpr.db.ErrorCode(err); errCode == "23503" {
+			return domain.ErrConflictingData
+		}
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func toChargeTypes(values []int) []domain.ChargeType {
+	chargeTypes := make([]domain.ChargeType, 0, len(values))
+	for _, value := range values {
+		chargeTypes = append(chargeTypes, domain.ChargeType(value))
+	}
+	return chargeTypes
+}
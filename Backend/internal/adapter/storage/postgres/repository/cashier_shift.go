@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"log/slog"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type CashierShiftRepository struct {
+	db *postgres.DB
+}
+
+func NewCashierShiftRepository(db *postgres.DB) *CashierShiftRepository {
+	return &CashierShiftRepository{
+		db,
+	}
+}
+
+func (csr *CashierShiftRepository) CreateShift(ctx *gin.Context, shift *domain.CashierShift) (*domain.CashierShift, error) {
+	query := csr.db.QueryBuilder.Insert("cashier_shifts").
+		Columns("user_id", "status", "opening_float", "opened_at").
+		Values(shift.UserID, domain.ShiftStatusOpen, shift.OpeningFloat, shift.OpenedAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = csr.db.QueryRow(ctx, sql, args...).Scan(
+		&shift.ID,
+		&shift.UserID,
+		&shift.Status,
+		&shift.OpeningFloat,
+		&shift.OpenedAt,
+		&shift.ClosedAt,
+		&shift.ClosingNote,
+		&shift.CreatedAt,
+		&shift.UpdatedAt,
+	)
+	if err != nil {
+		if errCode := csr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+func (csr *CashierShiftRepository) GetShiftByID(ctx *gin.Context, id uint64) (*domain.CashierShift, error) {
+	return csr.getShift(ctx, sq.Eq{"id": id})
+}
+
+func (csr *CashierShiftRepository) GetOpenShiftByUserID(ctx *gin.Context, userID uint64) (*domain.CashierShift, error) {
+	return csr.getShift(ctx, sq.Eq{"user_id": userID, "status": domain.ShiftStatusOpen})
+}
+
+func (csr *CashierShiftRepository) getShift(ctx *gin.Context, where sq.Eq) (*domain.CashierShift, error) {
+	var shift domain.CashierShift
+
+	query := csr.db.QueryBuilder.Select("*").
+		From("cashier_shifts").
+		Where(where).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = csr.db.QueryRow(ctx, sql, args...).Scan(
+		&shift.ID,
+		&shift.UserID,
+		&shift.Status,
+		&shift.OpeningFloat,
+		&shift.OpenedAt,
+		&shift.ClosedAt,
+		&shift.ClosingNote,
+		&shift.CreatedAt,
+		&shift.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &shift, nil
+}
+
+func (csr *CashierShiftRepository) ListShifts(ctx *gin.Context, userID, skip, limit uint64) ([]domain.CashierShift, uint64, error) {
+	var shifts []domain.CashierShift
+	var totalCount uint64
+
+	conditions := sq.And{}
+	if userID > 0 {
+		conditions = append(conditions, sq.Eq{"user_id": userID})
+	}
+
+	countQuery := csr.db.QueryBuilder.Select("COUNT(*)").From("cashier_shifts")
+	if len(conditions) > 0 {
+		countQuery = countQuery.Where(conditions)
+	}
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	err = csr.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := csr.db.QueryBuilder.Select("*").
+		From("cashier_shifts").
+		OrderBy("opened_at DESC").
+		Limit(limit)
+
+	if len(conditions) > 0 {
+		query = query.Where(conditions)
+	}
+
+	if skip > 0 {
+		query = query.Offset(skip)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := csr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shift domain.CashierShift
+		err := rows.Scan(
+			&shift.ID,
+			&shift.UserID,
+			&shift.Status,
+			&shift.OpeningFloat,
+			&shift.OpenedAt,
+			&shift.ClosedAt,
+			&shift.ClosingNote,
+			&shift.CreatedAt,
+			&shift.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		shifts = append(shifts, shift)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return shifts, totalCount, nil
+}
+
+func (csr *CashierShiftRepository) CloseShift(ctx *gin.Context, shift *domain.CashierShift, counts []domain.ShiftCount) (*domain.CashierShift, error) {
+	tx, err := csr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Only an open shift is closed, so two concurrent close-outs cannot both succeed
+	query := csr.db.QueryBuilder.Update("cashier_shifts").
+		Set("status", domain.ShiftStatusClosed).
+		Set("closed_at", shift.ClosedAt).
+		Set("closing_note", shift.ClosingNote).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": shift.ID, "status": domain.ShiftStatusOpen}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(
+		&shift.ID,
+		&shift.UserID,
+		&shift.Status,
+		&shift.OpeningFloat,
+		&shift.OpenedAt,
+		&shift.ClosedAt,
+		&shift.ClosingNote,
+		&shift.CreatedAt,
+		&shift.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	for _, count := range counts {
+		countQuery := csr.db.QueryBuilder.Insert("cashier_shift_counts").
+			Columns("shift_id", "payment_method", "expected", "counted", "variance", "currency").
+			Values(shift.ID, count.PaymentMethod, count.Expected, count.Counted, count.Variance, count.Currency)
+
+		sql, args, err := countQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", countQuery)
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+func (csr *CashierShiftRepository) ListShiftCounts(ctx *gin.Context, shiftID uint64) ([]domain.ShiftCount, error) {
+	var counts []domain.ShiftCount
+
+	query := csr.db.QueryBuilder.Select("*").
+		From("cashier_shift_counts").
+		Where(sq.Eq{"shift_id": shiftID}).
+		OrderBy("payment_method", "currency = 'THB' DESC", "currency")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := csr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count domain.ShiftCount
+		err := rows.Scan(
+			&count.ID,
+			&count.ShiftID,
+			&count.PaymentMethod,
+			&count.Expected,
+			&count.Counted,
+			&count.Variance,
+			&count.CreatedAt,
+			&count.Currency,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetShiftPaymentTotals sums the paid payments of a shift per payment method and currency in the currency
+// they were paid in, refunds included
+func (csr *CashierShiftRepository) GetShiftPaymentTotals(ctx *gin.Context, shiftID uint64) ([]domain.ShiftPaymentTotal, error) {
+	var totals []domain.ShiftPaymentTotal
+
+	query := csr.db.QueryBuilder.Select("payment_method", "currency", "COUNT(*)", "COALESCE(SUM(amount), 0)").
+		From("payments").
+		Where(sq.Eq{"shift_id": shiftID, "status": domain.PaymentStatusPaid}).
+		GroupBy("payment_method", "currency").
+		OrderBy("payment_method", "currency")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := csr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total domain.ShiftPaymentTotal
+		err := rows.Scan(
+			&total.PaymentMethod,
+			&total.Currency,
+			&total.Count,
+			&total.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type CompanyRepository struct {
+	db *postgres.DB
+}
+
+func NewCompanyRepository(db *postgres.DB) *CompanyRepository {
+	return &CompanyRepository{
+		db,
+	}
+}
+
+func (cr *CompanyRepository) CreateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error) {
+	query := cr.db.QueryBuilder.Insert("companies").
+		Columns("name", "tax_id", "address", "email", "phone", "contact_name", "credit_limit", "payment_term_days", "is_active").
+		Values(company.Name, company.TaxID, company.Address, company.Email, company.Phone, company.ContactName, company.CreditLimit, company.PaymentTermDays, company.IsActive).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&company.ID,
+		&company.Name,
+		&company.TaxID,
+		&company.Address,
+		&company.Email,
+		&company.Phone,
+		&company.ContactName,
+		&company.CreditLimit,
+		&company.PaymentTermDays,
+		&company.IsActive,
+		&company.CreatedAt,
+		&company.UpdatedAt,
+	)
+	if err != nil {
+		if errCode := cr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return company, nil
+}
+
+func (cr *CompanyRepository) GetCompanyByID(ctx *gin.Context, id uint64) (*domain.Company, error) {
+	var company domain.Company
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("companies").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&company.ID,
+		&company.Name,
+		&company.TaxID,
+		&company.Address,
+		&company.Email,
+		&company.Phone,
+		&company.ContactName,
+		&company.CreditLimit,
+		&company.PaymentTermDays,
+		&company.IsActive,
+		&company.CreatedAt,
+		&company.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+func (cr *CompanyRepository) ListCompanies(ctx *gin.Context, skip, limit uint64) ([]domain.Company, uint64, error) {
+	var companies []domain.Company
+	var totalCount uint64
+
+	countQuery := cr.db.QueryBuilder.Select("COUNT(*)").From("companies")
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	err = cr.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("companies").
+		OrderBy("name").
+		Limit(limit)
+
+	if skip > 0 {
+		query = query.Offset(skip)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := cr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var company domain.Company
+		err := rows.Scan(
+			&company.ID,
+			&company.Name,
+			&company.TaxID,
+			&company.Address,
+			&company.Email,
+			&company.Phone,
+			&company.ContactName,
+			&company.CreditLimit,
+			&company.PaymentTermDays,
+			&company.IsActive,
+			&company.CreatedAt,
+			&company.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		companies = append(companies, company)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return companies, totalCount, nil
+}
+
+func (cr *CompanyRepository) UpdateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error) {
+	query := cr.db.QueryBuilder.Update("companies").
+		Set("name", company.Name).
+		Set("tax_id", company.TaxID).
+		Set("address", company.Address).
+		Set("email", company.Email).
+		Set("phone", company.Phone).
+		Set("contact_name", company.ContactName).
+		Set("credit_limit", company.CreditLimit).
+		Set("payment_term_days", company.PaymentTermDays).
+		Set("is_active", company.IsActive).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": company.ID}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&company.ID,
+		&company.Name,
+		&company.TaxID,
+		&company.Address,
+		&company.Email,
+		&company.Phone,
+		&company.ContactName,
+		&company.CreditLimit,
+		&company.PaymentTermDays,
+		&company.IsActive,
+		&company.CreatedAt,
+		&company.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := cr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return company, nil
+}
+
+func (cr *CompanyRepository) CreateLedgerEntry(ctx *gin.Context, entry *domain.CompanyLedgerEntry) (*domain.CompanyLedgerEntry, error) {
+	query := cr.db.QueryBuilder.Insert("company_ledger_entries").
+		Columns("company_id", "entry_type", "booking_id", "payment_id", "applied_to_id", "reference", "amount", "payment_method", "entry_date", "due_date", "posted_by").
+		Values(entry.CompanyID, entry.EntryType, entry.BookingID, entry.PaymentID, entry.AppliedToID, entry.Reference, entry.Amount, entry.PaymentMethod, entry.EntryDate, entry.DueDate, entry.PostedBy).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&entry.ID,
+		&entry.CompanyID,
+		&entry.EntryType,
+		&entry.BookingID,
+		&entry.PaymentID,
+		&entry.AppliedToID,
+		&entry.Reference,
+		&entry.Amount,
+		&entry.PaymentMethod,
+		&entry.EntryDate,
+		&entry.DueDate,
+		&entry.PostedBy,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (cr *CompanyRepository) ListLedgerEntriesByCompanyID(ctx *gin.Context, companyID uint64) ([]domain.CompanyLedgerEntry, error) {
+	var entries []domain.CompanyLedgerEntry
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("company_ledger_entries").
+		Where(sq.Eq{"company_id": companyID}).
+		OrderBy("entry_date", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := cr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.CompanyLedgerEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.CompanyID,
+			&entry.EntryType,
+			&entry.BookingID,
+			&entry.PaymentID,
+			&entry.AppliedToID,
+			&entry.Reference,
+			&entry.Amount,
+			&entry.PaymentMethod,
+			&entry.EntryDate,
+			&entry.DueDate,
+			&entry.PostedBy,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (cr *CompanyRepository) GetCompanyBalance(ctx *gin.Context, companyID uint64) (float64, error) {
+	var balance float64
+
+	query := cr.db.QueryBuilder.Select("COALESCE(SUM(amount), 0)").
+		From("company_ledger_entries").
+		Where(sq.Eq{"company_id": companyID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// invoiceQuery selects ledger invoices with the total of the payments applied to them up to asOf
+func (cr *CompanyRepository) invoiceQuery(asOf time.Time) sq.SelectBuilder {
+	return cr.db.QueryBuilder.Select("e.*", "COALESCE(-SUM(p.amount), 0)").
+		From("company_ledger_entries e").
+		LeftJoin("company_ledger_entries p ON p.applied_to_id = e.id AND p.entry_date <= ?", asOf).
+		Where(sq.Eq{"e.entry_type": domain.LedgerEntryTypeInvoice}).
+		GroupBy("e.id")
+}
+
+func (cr *CompanyRepository) ListOpenInvoices(ctx *gin.Context, companyID uint64, asOf time.Time) ([]domain.CompanyInvoice, error) {
+	var invoices []domain.CompanyInvoice
+
+	query := cr.invoiceQuery(asOf).
+		Where(sq.LtOrEq{"e.entry_date": asOf}).
+		Having("e.amount + COALESCE(SUM(p.amount), 0) > 0").
+		OrderBy("e.company_id", "e.entry_date", "e.id")
+
+	if companyID > 0 {
+		query = query.Where(sq.Eq{"e.company_id": companyID})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := cr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var invoice domain.CompanyInvoice
+		err := rows.Scan(
+			&invoice.ID,
+			&invoice.CompanyID,
+			&invoice.EntryType,
+			&invoice.BookingID,
+			&invoice.PaymentID,
+			&invoice.AppliedToID,
+			&invoice.Reference,
+			&invoice.Amount,
+			&invoice.PaymentMethod,
+			&invoice.EntryDate,
+			&invoice.DueDate,
+			&invoice.PostedBy,
+			&invoice.CreatedAt,
+			&invoice.Paid,
+		)
+		if err != nil {
+			return nil, err
+		}
+		invoice.Outstanding = invoice.Amount - invoice.Paid
+
+		invoices = append(invoices, invoice)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+func (cr *CompanyRepository) GetInvoiceEntryByID(ctx *gin.Context, id uint64) (*domain.CompanyInvoice, error) {
+	var invoice domain.CompanyInvoice
+
+	query := cr.invoiceQuery(time.Now()).
+		Where(sq.Eq{"e.id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&invoice.ID,
+		&invoice.CompanyID,
+		&invoice.EntryType,
+		&invoice.BookingID,
+		&invoice.PaymentID,
+		&invoice.AppliedToID,
+		&invoice.Reference,
+		&invoice.Amount,
+		&invoice.PaymentMethod,
+		&invoice.EntryDate,
+		&invoice.DueDate,
+		&invoice.PostedBy,
+		&invoice.CreatedAt,
+		&invoice.Paid,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	invoice.Outstanding = invoice.Amount - invoice.Paid
+
+	return &invoice, nil
+}
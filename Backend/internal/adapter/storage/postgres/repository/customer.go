@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
@@ -61,7 +62,7 @@ func (cr *CustomerRepository) CreateCustomer(ctx *gin.Context, customer *domain.
 func (cr *CustomerRepository) GetCustomerByID(ctx *gin.Context, id uint64) (*domain.Customer, error) {
 	var customer domain.Customer
 
-	query := cr.db.QueryBuilder.Select("*").
+	query := cr.db.QueryBuilder.Select(customerColumns("customers")).
 		From("customers").
 		Where(sq.Eq{"id": id}).
 		Limit(1)
@@ -163,9 +164,66 @@ func (cr *CustomerRepository) ListCustomers(ctx *gin.Context, skip, limit uint64
 }
 
 func (cr *CustomerRepository) ListCustomersWithFilter(ctx *gin.Context, customer *domain.Customer, skip, limit uint64) ([]domain.Customer, uint64, error) {
-	var customers []domain.Customer
 	var totalCount uint64
 
+	conditions := customerConditions(customer)
+
+	countQuery := cr.db.QueryBuilder.Select("COUNT(*)").From("customers")
+	if len(conditions) > 0 {
+		countQuery = countQuery.Where(conditions)
+	}
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	err = cr.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("customers").
+		OrderBy("id DESC").
+		Limit(limit)
+
+	if len(conditions) > 0 {
+		query = query.Where(conditions)
+	}
+
+	if skip > 0 {
+		query = query.Offset(skip)
+	}
+
+	customers, err := cr.queryCustomers(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return customers, totalCount, nil
+}
+
+// ListCustomersBefore lists up to limit customers matching the filter with IDs below beforeID, latest first,
+// or the latest customers when beforeID is zero
+func (cr *CustomerRepository) ListCustomersBefore(ctx *gin.Context, customer *domain.Customer, beforeID, limit uint64) ([]domain.Customer, error) {
+	conditions := customerConditions(customer)
+	if beforeID > 0 {
+		conditions = append(conditions, sq.Lt{"id": beforeID})
+	}
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("customers").
+		OrderBy("id DESC").
+		Limit(limit)
+
+	if len(conditions) > 0 {
+		query = query.Where(conditions)
+	}
+
+	return cr.queryCustomers(ctx, query)
+}
+
+// customerConditions matches the customers with the set fields of customer
+func customerConditions(customer *domain.Customer) sq.And {
 	conditions := sq.And{}
 	if customer.ID != 0 {
 		conditions = append(conditions, sq.Eq{"id": customer.ID})
@@ -207,41 +265,22 @@ func (cr *CustomerRepository) ListCustomersWithFilter(ctx *gin.Context, customer
 		conditions = append(conditions, sq.Expr("updated_at::date = ?", dateStr))
 	}
 
-	countQuery := cr.db.QueryBuilder.Select("COUNT(*)").From("customers")
-	if len(conditions) > 0 {
-		countQuery = countQuery.Where(conditions)
-	}
-	countSql, countArgs, err := countQuery.ToSql()
-	if err != nil {
-		return nil, 0, err
-	}
-	err = cr.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	query := cr.db.QueryBuilder.Select("*").
-		From("customers").
-		OrderBy("id DESC").
-		Limit(limit)
+	return conditions
+}
 
-	if len(conditions) > 0 {
-		query = query.Where(conditions)
-	}
-	
-	if skip > 0 {
-		query = query.Offset(skip)
-	}
+// queryCustomers runs a query selecting * from customers
+func (cr *CustomerRepository) queryCustomers(ctx *gin.Context, query sq.SelectBuilder) ([]domain.Customer, error) {
+	var customers []domain.Customer
 
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	slog.Debug("SQL QUERY", "query", query)
 
 	rows, err := cr.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -262,16 +301,16 @@ func (cr *CustomerRepository) ListCustomersWithFilter(ctx *gin.Context, customer
 			&customer.UpdatedAt,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		customers = append(customers, customer)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return customers, totalCount, nil
+	return customers, nil
 }
 
 func (cr *CustomerRepository) UpdateCustomer(ctx *gin.Context, customer *domain.Customer) (*domain.Customer, error) {
@@ -336,4 +375,459 @@ func (cr *CustomerRepository) DeleteCustomer(ctx *gin.Context, id uint64) error
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// A customer's normalized name, surname, phone, email and identity number, in the forms the search indexes
+// cover, and the search query as p.q with its digits as p.digits. The LIKE patterns p.pattern and
+// p.digits_pattern hold the escaped query and digits.
+const customerSearchFromSQL = `
+	FROM customers c
+	CROSS JOIN (SELECT $1::text AS q, $2::text AS digits, $3::text AS pattern, $4::text AS digits_pattern) p
+	CROSS JOIN LATERAL (
+		SELECT
+			lower(regexp_replace(btrim(c.firstname || ' ' || c.surname), '\s+', ' ', 'g')) AS name,
+			lower(btrim(c.surname)) AS surname,
+			regexp_replace(COALESCE(c.phone, ''), '\D', '', 'g') AS phone,
+			lower(COALESCE(c.email, '')) AS email,
+			lower(COALESCE(c.identity_number, '')) AS identity_number
+	) n
+	WHERE n.name LIKE p.pattern
+		OR p.q <% n.name
+		OR (p.digits <> '' AND n.phone LIKE p.digits_pattern)
+		OR n.email LIKE p.pattern
+		OR n.identity_number LIKE p.pattern`
+
+// An exact match ranks 1, a prefix above a substring, and a name close in spelling by its word similarity
+const customerRelevanceSQL = `
+	GREATEST(
+		CASE
+			WHEN n.name = p.q THEN 1
+			WHEN left(n.name, length(p.q)) = p.q OR left(n.surname, length(p.q)) = p.q THEN 0.9
+			WHEN strpos(n.name, p.q) > 0 THEN 0.7
+			ELSE 0
+		END,
+		word_similarity(p.q, n.name) * 0.8,
+		CASE
+			WHEN p.digits = '' THEN 0
+			WHEN n.phone = p.digits THEN 1
+			WHEN strpos(n.phone, p.digits) > 0 THEN 0.8
+			ELSE 0
+		END,
+		CASE
+			WHEN n.email = p.q OR n.identity_number = p.q THEN 1
+			WHEN left(n.email, length(p.q)) = p.q OR left(n.identity_number, length(p.q)) = p.q THEN 0.8
+			WHEN strpos(n.email, p.q) > 0 OR strpos(n.identity_number, p.q) > 0 THEN 0.6
+			ELSE 0
+		END
+	)`
+
+// SearchCustomers matches the query against names by prefix, substring and trigram word similarity, and
+// against phone digits, email and identity number, most relevant first
+func (cr *CustomerRepository) SearchCustomers(ctx *gin.Context, search domain.CustomerSearch, skip, limit uint64) ([]domain.CustomerMatch, uint64, error) {
+	var matches []domain.CustomerMatch
+	var totalCount uint64
+
+	args := []interface{}{
+		search.Text,
+		search.Digits,
+		"%" + escapeLike(search.Text) + "%",
+		"%" + escapeLike(search.Digits) + "%",
+	}
+
+	countSql := "SELECT COUNT(*)" + customerSearchFromSQL
+	err := cr.db.QueryRow(ctx, countSql, args...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT
+			c.id,
+			c.firstname,
+			c.surname,
+			COALESCE(c.identity_number, ''),
+			COALESCE(c.email, ''),
+			COALESCE(c.phone, ''),
+			COALESCE(c.address, ''),
+			COALESCE(c.gender, ''),
+			COALESCE(c.customer_type_id, 0),
+			COALESCE(c.preferences, ''),
+			c.created_at,
+			c.updated_at,
+			` + customerRelevanceSQL + ` AS relevance` + customerSearchFromSQL + `
+		ORDER BY relevance DESC, c.id DESC
+		LIMIT $5 OFFSET $6`
+	args = append(args, limit, skip)
+	slog.Debug("SQL QUERY", "query", sql)
+
+	rows, err := cr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var match domain.CustomerMatch
+		err := rows.Scan(
+			&match.ID,
+			&match.FirstName,
+			&match.Surname,
+			&match.IdentityNumber,
+			&match.Email,
+			&match.Phone,
+			&match.Address,
+			&match.Gender,
+			&match.CustomerTypeID,
+			&match.Preferences,
+			&match.CreatedAt,
+			&match.UpdatedAt,
+			&match.Relevance,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		matches = append(matches, match)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return matches, totalCount, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so s only matches itself
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// customerColumns lists the columns of the customer aliased as alias in scan order, blanks for NULLs
+func customerColumns(alias string) string {
+	return strings.ReplaceAll(`
+		$.id, $.firstname, $.surname, COALESCE($.identity_number, ''), COALESCE($.email, ''),
+		COALESCE($.phone, ''), COALESCE($.address, ''), COALESCE($.gender, ''), COALESCE($.customer_type_id, 0),
+		COALESCE($.preferences, ''), $.created_at, $.updated_at`, "$", alias)
+}
+
+// The keys duplicates are compared by, for the customer aliased as $: the name as searched, the identity
+// number as letters and digits only, the phone digits and the trimmed email. Migrations 000031 and 000039
+// index these very expressions, so they must not change without their indexes.
+const (
+	customerNameKeySQL     = `lower(regexp_replace(btrim($.firstname || ' ' || $.surname), '\s+', ' ', 'g'))`
+	customerIdentityKeySQL = `regexp_replace(lower(COALESCE($.identity_number, '')), '[^[:alnum:]]', '', 'g')`
+	customerPhoneKeySQL    = `regexp_replace(COALESCE($.phone, ''), '\D', '', 'g')`
+	customerEmailKeySQL    = `lower(btrim(COALESCE($.email, '')))`
+)
+
+// customerKey returns the key expression for the customer aliased as alias
+func customerKey(key, alias string) string {
+	return strings.ReplaceAll(key, "$", alias)
+}
+
+// customerKeys normalizes the customer aliased as alias into the keys duplicates are compared by, as the
+// lateral keysAlias
+func customerKeys(alias, keysAlias string) string {
+	return `
+		CROSS JOIN LATERAL (
+			SELECT
+				` + customerKey(customerNameKeySQL, alias) + ` AS name,
+				` + customerKey(customerIdentityKeySQL, alias) + ` AS identity_number,
+				` + customerKey(customerPhoneKeySQL, alias) + ` AS phone,
+				` + customerKey(customerEmailKeySQL, alias) + ` AS email
+		) ` + keysAlias
+}
+
+// customerCandidatesSQL pairs each customer with the later customers whose key matches by join, looked up
+// through the index of the key. $a and $b stand for the keys of the two; where, if given, filters the first.
+func customerCandidatesSQL(key, join, where string) string {
+	a, b := customerKey(key, "ca"), customerKey(key, "cb")
+	sql := `
+		SELECT ca.id AS customer_id, cb.id AS duplicate_id
+		FROM customers ca
+		JOIN customers cb ON ` + strings.NewReplacer("$a", a, "$b", b).Replace(join) + ` AND cb.id > ca.id`
+	if where != "" {
+		sql += `
+		WHERE ` + strings.ReplaceAll(where, "$a", a)
+	}
+	return sql
+}
+
+// Pairs sharing an identity number, a phone of at least six digits or an email, or with similar names,
+// scored by what they share. The candidate pairs are found through the indexes on the keys, so only they
+// are normalized and scored rather than every pair of customers. The older customer of a pair comes first.
+var customerDuplicatesSQL = `
+	WITH candidates AS (` +
+	customerCandidatesSQL(customerIdentityKeySQL, "$b = $a", "$a <> ''") + `
+		UNION` +
+	customerCandidatesSQL(customerPhoneKeySQL, "$b = $a", "length($a) >= 6") + `
+		UNION` +
+	customerCandidatesSQL(customerEmailKeySQL, "$b = $a", "$a <> ''") + `
+		UNION` +
+	customerCandidatesSQL(customerNameKeySQL, "$b % $a", "") + `
+	),
+	pairs AS (
+		SELECT
+			c.customer_id,
+			c.duplicate_id,
+			ka.identity_number <> '' AND ka.identity_number = kb.identity_number AS same_identity,
+			length(ka.phone) >= 6 AND ka.phone = kb.phone AS same_phone,
+			ka.email <> '' AND ka.email = kb.email AS same_email,
+			similarity(ka.name, kb.name) AS name_similarity
+		FROM candidates c
+		JOIN customers ca ON ca.id = c.customer_id` + customerKeys("ca", "ka") + `
+		JOIN customers cb ON cb.id = c.duplicate_id` + customerKeys("cb", "kb") + `
+	),
+	scored AS (
+		SELECT
+			*,
+			LEAST(1,
+				CASE WHEN same_identity THEN 0.6 ELSE 0 END
+				+ CASE WHEN same_phone THEN 0.3 ELSE 0 END
+				+ CASE WHEN same_email THEN 0.3 ELSE 0 END
+				+ name_similarity * 0.4
+			) AS score
+		FROM pairs
+	)`
+
+// ListCustomerDuplicates pages the scored pairs, counting them in the same query. Only a page past the end,
+// which has no row to carry the count, runs the query again to count them.
+func (cr *CustomerRepository) ListCustomerDuplicates(ctx *gin.Context, minScore float64, skip, limit uint64) ([]domain.CustomerDuplicate, uint64, error) {
+	var duplicates []domain.CustomerDuplicate
+	var totalCount uint64
+
+	sql := customerDuplicatesSQL + `
+		SELECT` + customerColumns("ca") + `,` + customerColumns("cb") + `,
+			s.score, s.same_identity, s.same_phone, s.same_email, s.name_similarity,
+			COUNT(*) OVER () AS total_count
+		FROM scored s
+		JOIN customers ca ON ca.id = s.customer_id
+		JOIN customers cb ON cb.id = s.duplicate_id
+		WHERE s.score >= $1
+		ORDER BY s.score DESC, s.customer_id, s.duplicate_id
+		LIMIT $2 OFFSET $3`
+	slog.Debug("SQL QUERY", "query", sql)
+
+	rows, err := cr.db.Query(ctx, sql, minScore, limit, skip)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var duplicate domain.CustomerDuplicate
+		a, b := &duplicate.Customer, &duplicate.Duplicate
+		err := rows.Scan(
+			&a.ID, &a.FirstName, &a.Surname, &a.IdentityNumber, &a.Email,
+			&a.Phone, &a.Address, &a.Gender, &a.CustomerTypeID,
+			&a.Preferences, &a.CreatedAt, &a.UpdatedAt,
+			&b.ID, &b.FirstName, &b.Surname, &b.IdentityNumber, &b.Email,
+			&b.Phone, &b.Address, &b.Gender, &b.CustomerTypeID,
+			&b.Preferences, &b.CreatedAt, &b.UpdatedAt,
+			&duplicate.Score,
+			&duplicate.SameIdentity,
+			&duplicate.SamePhone,
+			&duplicate.SameEmail,
+			&duplicate.NameSimilarity,
+			&totalCount,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		duplicates = append(duplicates, duplicate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if len(duplicates) == 0 && skip > 0 {
+		countSql := customerDuplicatesSQL + `
+		SELECT COUNT(*) FROM scored WHERE score >= $1`
+		if err := cr.db.QueryRow(ctx, countSql, minScore).Scan(&totalCount); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return duplicates, totalCount, nil
+}
+
+// MergeCustomers moves the bookings and booking payers of the duplicate to survivor, deletes the duplicate
+// and saves the merged details of survivor, all in one transaction. Where both were payers of a booking,
+// their payer rows become one: the invoiced one is kept, as issued invoices never change payer, and takes
+// on the payments and charges of the other. Where one was the guest of a booking the other paid for, the
+// payer row goes and its payments return to the guest, as a guest never pays for their own booking as a
+// payer. The merge fails with ErrConflictingData when both payer rows have invoices, or when the payer row
+// of a guest has.
+func (cr *CustomerRepository) MergeCustomers(ctx *gin.Context, survivor *domain.Customer, duplicateID uint64) (*domain.Customer, error) {
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Once merged, the guest of these bookings would be one of their payers too, which adding a payer refuses.
+	// Their payments go back to the guest, whose folio holds anything no payer takes.
+	guestPayerQuery := `SELECT p.id, EXISTS (SELECT 1 FROM invoices i WHERE i.payer_id = p.id)
+		FROM booking_payers p
+		JOIN bookings b ON b.id = p.booking_id
+		WHERE p.customer_id IN ($1, $2) AND b.customer_id IN ($1, $2)
+		FOR UPDATE OF p`
+	slog.Debug("SQL QUERY", "query", guestPayerQuery)
+
+	rows, err := tx.Query(ctx, guestPayerQuery, survivor.ID, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	var guestPayerIDs []uint64
+	for rows.Next() {
+		var payerID uint64
+		var invoiced bool
+		if err := rows.Scan(&payerID, &invoiced); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if invoiced {
+			rows.Close()
+			return nil, domain.ErrConflictingData
+		}
+		guestPayerIDs = append(guestPayerIDs, payerID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	guestPayerStatements := []string{
+		`UPDATE payments SET payer_id = NULL WHERE payer_id = $1`,
+		`DELETE FROM booking_payers WHERE id = $1`,
+	}
+	for _, payerID := range guestPayerIDs {
+		for _, sql := range guestPayerStatements {
+			slog.Debug("SQL QUERY", "query", sql)
+			if _, err := tx.Exec(ctx, sql, payerID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Where both customers pay for the same booking their two payer rows become one. Issued invoices cannot
+	// change payer, so the row that has invoices is kept; when both have, the merge is refused.
+	pairQuery := `SELECT s.id, d.id,
+			EXISTS (SELECT 1 FROM invoices i WHERE i.payer_id = s.id),
+			EXISTS (SELECT 1 FROM invoices i WHERE i.payer_id = d.id)
+		FROM booking_payers d
+		JOIN booking_payers s ON s.booking_id = d.booking_id AND s.customer_id = $1
+		WHERE d.customer_id = $2
+		FOR UPDATE OF s, d`
+	slog.Debug("SQL QUERY", "query", pairQuery)
+
+	rows, err = tx.Query(ctx, pairQuery, survivor.ID, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	var pairs [][2]uint64 // Payer row kept and payer row dropped
+	for rows.Next() {
+		var survivorPayerID, duplicatePayerID uint64
+		var survivorInvoiced, duplicateInvoiced bool
+		if err := rows.Scan(&survivorPayerID, &duplicatePayerID, &survivorInvoiced, &duplicateInvoiced); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		switch {
+		case survivorInvoiced && duplicateInvoiced:
+			rows.Close()
+			return nil, domain.ErrConflictingData
+		case duplicateInvoiced:
+			pairs = append(pairs, [2]uint64{duplicatePayerID, survivorPayerID})
+		default:
+			pairs = append(pairs, [2]uint64{survivorPayerID, duplicatePayerID})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pairStatements := []string{
+		`UPDATE payments SET payer_id = $1 WHERE payer_id = $2`,
+		`UPDATE booking_payers k
+		SET charge_types = ARRAY(SELECT DISTINCT unnest(k.charge_types || d.charge_types) ORDER BY 1),
+			updated_at = CURRENT_TIMESTAMP
+		FROM booking_payers d
+		WHERE k.id = $1 AND d.id = $2`,
+		`DELETE FROM booking_payers WHERE id = $2`,
+	}
+	for _, pair := range pairs {
+		for _, sql := range pairStatements {
+			slog.Debug("SQL QUERY", "query", sql)
+			if _, err := tx.Exec(ctx, sql, pair[0], pair[1]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	statements := []string{
+		`UPDATE booking_payers SET customer_id = $1, updated_at = CURRENT_TIMESTAMP WHERE customer_id = $2`,
+		`UPDATE bookings SET customer_id = $1 WHERE customer_id = $2`,
+		`UPDATE loyalty_transactions SET customer_id = $1 WHERE customer_id = $2`,
+		`DELETE FROM customers WHERE id = $2`,
+	}
+	for _, sql := range statements {
+		slog.Debug("SQL QUERY", "query", sql)
+		if _, err := tx.Exec(ctx, sql, survivor.ID, duplicateID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The duplicate is gone by now, so the survivor can take its identity number
+	query := cr.db.QueryBuilder.Update("customers").
+		Set("firstname", survivor.FirstName).
+		Set("surname", survivor.Surname).
+		Set("identity_number", sq.Expr("NULLIF(?, '')", survivor.IdentityNumber)).
+		Set("email", survivor.Email).
+		Set("phone", survivor.Phone).
+		Set("address", survivor.Address).
+		Set("gender", survivor.Gender).
+		Set("customer_type_id", survivor.CustomerTypeID).
+		Set("preferences", survivor.Preferences).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": survivor.ID}).
+		Suffix("RETURNING" + customerColumns("customers"))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(
+		&survivor.ID,
+		&survivor.FirstName,
+		&survivor.Surname,
+		&survivor.IdentityNumber,
+		&survivor.Email,
+		&survivor.Phone,
+		&survivor.Address,
+		&survivor.Gender,
+		&survivor.CustomerTypeID,
+		&survivor.Preferences,
+		&survivor.CreatedAt,
+		&survivor.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := cr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return survivor, nil
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/config"
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// testDB connects to the database named by TEST_DB_NAME with the DB_* settings of the app and migrates it.
+// The test is skipped when TEST_DB_NAME is not set, as it writes rows that issued invoices keep for good.
+func testDB(t *testing.T) *postgres.DB {
+	t.Helper()
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		t.Skip("TEST_DB_NAME is not set")
+	}
+
+	db, err := postgres.Connect(context.Background(), &config.DB{
+		Connection: os.Getenv("DB_CONNECTION"),
+		Host:       os.Getenv("DB_HOST"),
+		Port:       os.Getenv("DB_PORT"),
+		User:       os.Getenv("DB_USER"),
+		Password:   os.Getenv("DB_PASSWORD"),
+		Name:       name,
+		Timezone:   "Asia/Bangkok",
+	})
+	if err != nil {
+		t.Fatalf("connecting to the test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("migrating the test database: %v", err)
+	}
+	return db
+}
+
+func testContext() *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("POST", "/", nil)
+	return ctx
+}
+
+func testBooking(t *testing.T, db *postgres.DB, ctx *gin.Context, customerID uint64) uint64 {
+	t.Helper()
+	var bookingID uint64
+	err := db.QueryRow(ctx, `INSERT INTO bookings (customer_id, check_in_date, check_out_date, status, total_amount, created_at, updated_at)
+		VALUES ($1, CURRENT_DATE, CURRENT_DATE + 1, 1, 1000, NOW(), NOW()) RETURNING id`, customerID).Scan(&bookingID)
+	if err != nil {
+		t.Fatalf("creating booking: %v", err)
+	}
+	return bookingID
+}
+
+func TestMergeCustomersKeepsInvoicedPayer(t *testing.T) {
+	db := testDB(t)
+	ctx := testContext()
+	repo := NewCustomerRepository(db)
+	suffix := time.Now().Format("20060102150405.000000")
+
+	survivor, err := repo.CreateCustomer(ctx, &domain.Customer{FirstName: "Survivor", Surname: suffix})
+	if err != nil {
+		t.Fatalf("creating survivor: %v", err)
+	}
+	duplicate, err := repo.CreateCustomer(ctx, &domain.Customer{FirstName: "Duplicate", Surname: suffix})
+	if err != nil {
+		t.Fatalf("creating duplicate: %v", err)
+	}
+
+	guest, err := repo.CreateCustomer(ctx, &domain.Customer{FirstName: "Guest", Surname: suffix})
+	if err != nil {
+		t.Fatalf("creating guest: %v", err)
+	}
+
+	var survivorPayerID, duplicatePayerID uint64
+	bookingID := testBooking(t, db, ctx, guest.ID)
+	payerQuery := `INSERT INTO booking_payers (booking_id, customer_id, charge_types) VALUES ($1, $2, $3) RETURNING id`
+	if err := db.QueryRow(ctx, payerQuery, bookingID, survivor.ID, []int{1}).Scan(&survivorPayerID); err != nil {
+		t.Fatalf("adding survivor as payer: %v", err)
+	}
+	if err := db.QueryRow(ctx, payerQuery, bookingID, duplicate.ID, []int{2}).Scan(&duplicatePayerID); err != nil {
+		t.Fatalf("adding duplicate as payer: %v", err)
+	}
+
+	var invoiceID uint64
+	err = db.QueryRow(ctx, `INSERT INTO invoices (series_id, number, sequence, year, invoice_type, booking_id, payer_id, subtotal, vat, total, issued_at)
+		SELECT id, 'TEST-' || $1, $2, 9999, invoice_type, $3, $4, 100, 7, 107, NOW()
+		FROM invoice_series WHERE code = 'INV'
+		RETURNING id`, suffix, time.Now().UnixNano()%1e9, bookingID, duplicatePayerID).Scan(&invoiceID)
+	if err != nil {
+		t.Fatalf("issuing invoice to duplicate: %v", err)
+	}
+	var paymentPayerID uint64
+	_, err = db.Exec(ctx, `INSERT INTO payments (booking_id, payer_id, amount, base_amount, payment_method, payment_date, status)
+		VALUES ($1, $2, 50, 50, 1, NOW(), 2)`, bookingID, survivorPayerID)
+	if err != nil {
+		t.Fatalf("recording survivor payment: %v", err)
+	}
+
+	if _, err := repo.MergeCustomers(ctx, survivor, duplicate.ID); err != nil {
+		t.Fatalf("merging customers: %v", err)
+	}
+
+	var invoicePayerID uint64
+	var payerCustomerID uint64
+	var chargeTypes []int
+	err = db.QueryRow(ctx, `SELECT i.payer_id, bp.customer_id, bp.charge_types
+		FROM invoices i JOIN booking_payers bp ON bp.id = i.payer_id
+		WHERE i.id = $1`, invoiceID).Scan(&invoicePayerID, &payerCustomerID, &chargeTypes)
+	if err != nil {
+		t.Fatalf("reading invoice payer: %v", err)
+	}
+	if invoicePayerID != duplicatePayerID {
+		t.Errorf("invoice payer = %d, want the kept payer row %d", invoicePayerID, duplicatePayerID)
+	}
+	if payerCustomerID != survivor.ID {
+		t.Errorf("invoice payer customer = %d, want survivor %d", payerCustomerID, survivor.ID)
+	}
+	if fmt.Sprint(chargeTypes) != "[1 2]" {
+		t.Errorf("payer charge types = %v, want [1 2]", chargeTypes)
+	}
+
+	if err := db.QueryRow(ctx, `SELECT payer_id FROM payments WHERE booking_id = $1`, bookingID).Scan(&paymentPayerID); err != nil {
+		t.Fatalf("reading payment payer: %v", err)
+	}
+	if paymentPayerID != duplicatePayerID {
+		t.Errorf("payment payer = %d, want the kept payer row %d", paymentPayerID, duplicatePayerID)
+	}
+
+	var payers int
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM booking_payers WHERE booking_id = $1`, bookingID).Scan(&payers); err != nil {
+		t.Fatalf("counting payers: %v", err)
+	}
+	if payers != 1 {
+		t.Errorf("booking has %d payers, want 1", payers)
+	}
+}
+
+func TestMergeCustomersFoldsGuestPayerIntoGuest(t *testing.T) {
+	db := testDB(t)
+	ctx := testContext()
+	repo := NewCustomerRepository(db)
+	suffix := time.Now().Format("20060102150405.000000")
+
+	survivor, err := repo.CreateCustomer(ctx, &domain.Customer{FirstName: "Survivor", Surname: suffix})
+	if err != nil {
+		t.Fatalf("creating survivor: %v", err)
+	}
+	duplicate, err := repo.CreateCustomer(ctx, &domain.Customer{FirstName: "Duplicate", Surname: suffix})
+	if err != nil {
+		t.Fatalf("creating duplicate: %v", err)
+	}
+
+	bookingID := testBooking(t, db, ctx, survivor.ID)
+	var duplicatePayerID uint64
+	err = db.QueryRow(ctx, `INSERT INTO booking_payers (booking_id, customer_id, charge_types) VALUES ($1, $2, $3) RETURNING id`,
+		bookingID, duplicate.ID, []int{2}).Scan(&duplicatePayerID)
+	if err != nil {
+		t.Fatalf("adding duplicate as payer: %v", err)
+	}
+	_, err = db.Exec(ctx, `INSERT INTO payments (booking_id, payer_id, amount, base_amount, payment_method, payment_date, status)
+		VALUES ($1, $2, 50, 50, 1, NOW(), 2)`, bookingID, duplicatePayerID)
+	if err != nil {
+		t.Fatalf("recording duplicate payment: %v", err)
+	}
+
+	if _, err := repo.MergeCustomers(ctx, survivor, duplicate.ID); err != nil {
+		t.Fatalf("merging customers: %v", err)
+	}
+
+	var payers int
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM booking_payers WHERE booking_id = $1`, bookingID).Scan(&payers); err != nil {
+		t.Fatalf("counting payers: %v", err)
+	}
+	if payers != 0 {
+		t.Errorf("booking has %d payers, want none as the guest was its only payer", payers)
+	}
+
+	var paymentPayerID *uint64
+	if err := db.QueryRow(ctx, `SELECT payer_id FROM payments WHERE booking_id = $1`, bookingID).Scan(&paymentPayerID); err != nil {
+		t.Fatalf("reading payment payer: %v", err)
+	}
+	if paymentPayerID != nil {
+		t.Errorf("payment payer = %d, want the guest", *paymentPayerID)
+	}
+}
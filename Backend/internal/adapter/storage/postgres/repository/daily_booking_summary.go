@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
@@ -20,32 +21,29 @@ func NewDailyBookingSummaryRepository(db *postgres.DB) *DailyBookingSummaryRepos
 	}
 }
 
-func (dbsr *DailyBookingSummaryRepository) CreateDailyBookingSummary(ctx *gin.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error) {
+func (dbsr *DailyBookingSummaryRepository) CreateDailyBookingSummary(ctx context.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error) {
     query := dbsr.db.QueryBuilder.Insert("daily_booking_summary").
         Columns(
             "summary_date",
-            "created_bookings",
-            "completed_bookings",
-            "canceled_bookings",
             "total_amount",
             "status",
+            "total_refunds",
+            "checksum",
         ).
         Values(
             summary.SummaryDate,
-            summary.CreatedBookings,
-            summary.CompletedBookings,
-            summary.CanceledBookings,
             summary.TotalAmount,
             summary.Status,
+            summary.TotalRefunds,
+            summary.Checksum,
         ).
         Suffix(`
             ON CONFLICT (summary_date) 
             DO UPDATE SET 
-                created_bookings = EXCLUDED.created_bookings,
-                completed_bookings = EXCLUDED.completed_bookings,
-                canceled_bookings = EXCLUDED.canceled_bookings,
                 total_amount = EXCLUDED.total_amount,
+                total_refunds = EXCLUDED.total_refunds,
                 status = EXCLUDED.status,
+                checksum = EXCLUDED.checksum,
                 updated_at = CURRENT_TIMESTAMP
             RETURNING *
         `)
@@ -55,21 +53,65 @@ func (dbsr *DailyBookingSummaryRepository) CreateDailyBookingSummary(ctx *gin.Co
         return nil, err
     }
 
-    err = dbsr.db.QueryRow(ctx, sql, args...).Scan(
+    tx, err := dbsr.db.Begin(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("error starting transaction: %w", err)
+    }
+    defer tx.Rollback(ctx)
+
+    items := summary.Items
+    err = tx.QueryRow(ctx, sql, args...).Scan(
         &summary.SummaryDate,
-        &summary.CreatedBookings,
-        &summary.CompletedBookings,
-        &summary.CanceledBookings,
         &summary.TotalAmount,
         &summary.Status,
         &summary.CreatedAt,
         &summary.UpdatedAt,
+        &summary.TotalRefunds,
+        &summary.Checksum,
     )
 
     if err != nil {
         return nil, fmt.Errorf("error upserting summary: %w", err)
     }
 
+    // Regenerating a summary replaces the bookings listed for the day
+    deleteQuery := dbsr.db.QueryBuilder.Delete("daily_booking_summary_items").
+        Where(sq.Eq{"summary_date": summary.SummaryDate})
+
+    sql, args, err = deleteQuery.ToSql()
+    if err != nil {
+        return nil, fmt.Errorf("error building query: %w", err)
+    }
+
+    if _, err := tx.Exec(ctx, sql, args...); err != nil {
+        return nil, fmt.Errorf("error deleting summary items: %w", err)
+    }
+
+    if len(items) > 0 {
+        itemQuery := dbsr.db.QueryBuilder.Insert("daily_booking_summary_items").
+            Columns("summary_date", "booking_id", "category", "amount")
+        for _, item := range items {
+            itemQuery = itemQuery.Values(summary.SummaryDate, item.BookingID, item.Category, item.Amount)
+        }
+
+        sql, args, err = itemQuery.ToSql()
+        if err != nil {
+            return nil, fmt.Errorf("error building query: %w", err)
+        }
+
+        if _, err := tx.Exec(ctx, sql, args...); err != nil {
+            return nil, fmt.Errorf("error inserting summary items: %w", err)
+        }
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return nil, fmt.Errorf("error committing summary: %w", err)
+    }
+
+    if err := dbsr.loadSummaryItems(ctx, []*domain.DailyBookingSummary{summary}); err != nil {
+        return nil, err
+    }
+
     return summary, nil
 }
 
@@ -78,13 +120,12 @@ func (dbsr *DailyBookingSummaryRepository) GetDailyBookingSummaryByDate(ctx *gin
 
     query := dbsr.db.QueryBuilder.Select(
         "summary_date",
-        "created_bookings",
-        "completed_bookings",
-        "canceled_bookings",
         "total_amount",
         "status",
         "created_at",
         "updated_at",
+        "total_refunds",
+        "checksum",
     ).From("daily_booking_summary").
         Where(sq.Eq{"summary_date": date})
 
@@ -95,13 +136,12 @@ func (dbsr *DailyBookingSummaryRepository) GetDailyBookingSummaryByDate(ctx *gin
 
     err = dbsr.db.QueryRow(ctx, sql, args...).Scan(
         &summary.SummaryDate,
-        &summary.CreatedBookings,
-        &summary.CompletedBookings,
-        &summary.CanceledBookings,
         &summary.TotalAmount,
         &summary.Status,
         &summary.CreatedAt,
         &summary.UpdatedAt,
+        &summary.TotalRefunds,
+        &summary.Checksum,
     )
 
     if err != nil {
@@ -111,11 +151,14 @@ func (dbsr *DailyBookingSummaryRepository) GetDailyBookingSummaryByDate(ctx *gin
         return nil, fmt.Errorf("error querying database: %w", err)
     }
 
+    if err := dbsr.loadSummaryItems(ctx, []*domain.DailyBookingSummary{&summary}); err != nil {
+        return nil, err
+    }
+
     return &summary, nil
 }
 
 func (dbsr *DailyBookingSummaryRepository) ListDailyBookingSummaries(ctx *gin.Context, skip, limit uint64) ([]domain.DailyBookingSummary, uint64, error) {
-    var summaries []domain.DailyBookingSummary
     var totalCount uint64
 
     // Get total count
@@ -131,28 +174,57 @@ func (dbsr *DailyBookingSummaryRepository) ListDailyBookingSummaries(ctx *gin.Co
     }
 
     // Get paginated results
-    query := dbsr.db.QueryBuilder.Select(
+    query := dbsr.summariesQuery().
+        Offset(skip).
+        Limit(limit)
+
+    summaries, err := dbsr.querySummaries(ctx, query)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    return summaries, totalCount, nil
+}
+
+// ListDailyBookingSummariesBefore lists up to limit summaries of dates before before, latest first, or the
+// latest summaries when before is zero
+func (dbsr *DailyBookingSummaryRepository) ListDailyBookingSummariesBefore(ctx *gin.Context, before time.Time, limit uint64) ([]domain.DailyBookingSummary, error) {
+    query := dbsr.summariesQuery().
+        Limit(limit)
+
+    if !before.IsZero() {
+        query = query.Where(sq.Lt{"summary_date": before.Format("2006-01-02")})
+    }
+
+    return dbsr.querySummaries(ctx, query)
+}
+
+// summariesQuery selects the summaries, latest first, in the columns querySummaries scans
+func (dbsr *DailyBookingSummaryRepository) summariesQuery() sq.SelectBuilder {
+    return dbsr.db.QueryBuilder.Select(
         "summary_date",
-        "created_bookings",
-        "completed_bookings",
-        "canceled_bookings",
         "total_amount",
         "status",
         "created_at",
         "updated_at",
+        "total_refunds",
+        "checksum",
     ).From("daily_booking_summary").
-        OrderBy("summary_date DESC").
-        Offset(skip).
-        Limit(limit)
+        OrderBy("summary_date DESC")
+}
+
+// querySummaries runs a summariesQuery and loads the items of the summaries
+func (dbsr *DailyBookingSummaryRepository) querySummaries(ctx *gin.Context, query sq.SelectBuilder) ([]domain.DailyBookingSummary, error) {
+    var summaries []domain.DailyBookingSummary
 
     sql, args, err := query.ToSql()
     if err != nil {
-        return nil, 0, fmt.Errorf("error building query: %w", err)
+        return nil, fmt.Errorf("error building query: %w", err)
     }
 
     rows, err := dbsr.db.Query(ctx, sql, args...)
     if err != nil {
-        return nil, 0, fmt.Errorf("error querying database: %w", err)
+        return nil, fmt.Errorf("error querying database: %w", err)
     }
     defer rows.Close()
 
@@ -160,30 +232,48 @@ func (dbsr *DailyBookingSummaryRepository) ListDailyBookingSummaries(ctx *gin.Co
         var summary domain.DailyBookingSummary
         err := rows.Scan(
             &summary.SummaryDate,
-            &summary.CreatedBookings,
-            &summary.CompletedBookings,
-            &summary.CanceledBookings,
             &summary.TotalAmount,
             &summary.Status,
             &summary.CreatedAt,
             &summary.UpdatedAt,
+            &summary.TotalRefunds,
+            &summary.Checksum,
         )
         if err != nil {
-            return nil, 0, fmt.Errorf("error scanning row: %w", err)
+            return nil, fmt.Errorf("error scanning row: %w", err)
         }
         summaries = append(summaries, summary)
     }
 
-    return summaries, totalCount, nil
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("error reading rows: %w", err)
+    }
+
+    pointers := make([]*domain.DailyBookingSummary, len(summaries))
+    for i := range summaries {
+        pointers[i] = &summaries[i]
+    }
+    if err := dbsr.loadSummaryItems(ctx, pointers); err != nil {
+        return nil, err
+    }
+
+    return summaries, nil
 }
 
+// UpdateDailyBookingSummary saves the totals and status of a summary. Confirming it locks its day again
+// when an admin reopened the day after the night audit.
 func (dbsr *DailyBookingSummaryRepository) UpdateDailyBookingSummary(ctx *gin.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error) {
+    tx, err := dbsr.db.Begin(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("error starting transaction: %w", err)
+    }
+    defer tx.Rollback(ctx)
+
     query := dbsr.db.QueryBuilder.Update("daily_booking_summary").
-        Set("created_bookings", summary.CreatedBookings).
-        Set("completed_bookings", summary.CompletedBookings).
-        Set("canceled_bookings", summary.CanceledBookings).
         Set("total_amount", summary.TotalAmount).
+        Set("total_refunds", summary.TotalRefunds).
         Set("status", summary.Status).
+        Set("checksum", summary.Checksum).
         Set("updated_at", time.Now()).
         Where(sq.Eq{"summary_date": summary.SummaryDate}).
         Suffix("RETURNING *")
@@ -193,24 +283,111 @@ func (dbsr *DailyBookingSummaryRepository) UpdateDailyBookingSummary(ctx *gin.Co
         return nil, fmt.Errorf("error building query: %w", err)
     }
 
-    err = dbsr.db.QueryRow(ctx, sql, args...).Scan(
+    err = tx.QueryRow(ctx, sql, args...).Scan(
         &summary.SummaryDate,
-        &summary.CreatedBookings,
-        &summary.CompletedBookings,
-        &summary.CanceledBookings,
         &summary.TotalAmount,
         &summary.Status,
         &summary.CreatedAt,
         &summary.UpdatedAt,
+        &summary.TotalRefunds,
+        &summary.Checksum,
     )
 
     if err != nil {
+        if err == pgx.ErrNoRows {
+            return nil, domain.ErrDataNotFound
+        }
         return nil, fmt.Errorf("error updating record: %w", err)
     }
 
+    if summary.Status == domain.SummaryStatusConfirmed {
+        if err := dbsr.setNightAuditStatus(ctx, tx, summary.SummaryDate, domain.NightAuditStatusReopened, domain.NightAuditStatusCompleted); err != nil {
+            return nil, err
+        }
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return nil, fmt.Errorf("error committing transaction: %w", err)
+    }
+
+    if err := dbsr.loadSummaryItems(ctx, []*domain.DailyBookingSummary{summary}); err != nil {
+        return nil, err
+    }
+
+    return summary, nil
+}
+
+// ReopenDailyBookingSummary moves a confirmed summary back to checked and lifts the night audit's lock on
+// its day in a single transaction
+func (dbsr *DailyBookingSummaryRepository) ReopenDailyBookingSummary(ctx *gin.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error) {
+    tx, err := dbsr.db.Begin(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("error starting transaction: %w", err)
+    }
+    defer tx.Rollback(ctx)
+
+    query := dbsr.db.QueryBuilder.Update("daily_booking_summary").
+        Set("status", domain.SummaryStatusChecked).
+        Set("updated_at", time.Now()).
+        Where(sq.Eq{"summary_date": summary.SummaryDate, "status": domain.SummaryStatusConfirmed}).
+        Suffix("RETURNING *")
+
+    sql, args, err := query.ToSql()
+    if err != nil {
+        return nil, fmt.Errorf("error building query: %w", err)
+    }
+
+    err = tx.QueryRow(ctx, sql, args...).Scan(
+        &summary.SummaryDate,
+        &summary.TotalAmount,
+        &summary.Status,
+        &summary.CreatedAt,
+        &summary.UpdatedAt,
+        &summary.TotalRefunds,
+        &summary.Checksum,
+    )
+    if err != nil {
+        // Reopened by someone else in the meantime
+        if err == pgx.ErrNoRows {
+            return nil, domain.ErrConflictingData
+        }
+        return nil, fmt.Errorf("error reopening summary: %w", err)
+    }
+
+    if err := dbsr.setNightAuditStatus(ctx, tx, summary.SummaryDate, domain.NightAuditStatusCompleted, domain.NightAuditStatusReopened); err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return nil, fmt.Errorf("error committing transaction: %w", err)
+    }
+
+    if err := dbsr.loadSummaryItems(ctx, []*domain.DailyBookingSummary{summary}); err != nil {
+        return nil, err
+    }
+
     return summary, nil
 }
 
+// setNightAuditStatus moves the night audit of date from one status to another, if it has the first
+func (dbsr *DailyBookingSummaryRepository) setNightAuditStatus(ctx *gin.Context, tx pgx.Tx, date time.Time, from, to domain.NightAuditStatus) error {
+    query := dbsr.db.QueryBuilder.Update("night_audits").
+        Set("status", to).
+        Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+        Where(sq.Eq{"business_date": date.Format("2006-01-02"), "status": from})
+
+    sql, args, err := query.ToSql()
+    if err != nil {
+        return fmt.Errorf("error building query: %w", err)
+    }
+
+    if _, err := tx.Exec(ctx, sql, args...); err != nil {
+        return fmt.Errorf("error updating night audit: %w", err)
+    }
+
+    return nil
+}
+
 func (dbsr *DailyBookingSummaryRepository) DeleteDailyBookingSummary(ctx *gin.Context, date string) error {
     query := dbsr.db.QueryBuilder.Delete("daily_booking_summary").
         Where(sq.Eq{"summary_date": date})
@@ -231,3 +408,245 @@ func (dbsr *DailyBookingSummaryRepository) DeleteDailyBookingSummary(ctx *gin.Co
 
     return nil
 }
+
+// loadSummaryItems fills the items of each summary with the current details of the listed bookings
+func (dbsr *DailyBookingSummaryRepository) loadSummaryItems(ctx context.Context, summaries []*domain.DailyBookingSummary) error {
+    if len(summaries) == 0 {
+        return nil
+    }
+
+    dates := make([]time.Time, 0, len(summaries))
+    byDate := make(map[string]*domain.DailyBookingSummary, len(summaries))
+    for _, summary := range summaries {
+        summary.Items = []domain.DailyBookingSummaryItem{}
+        dates = append(dates, summary.SummaryDate)
+        byDate[summary.SummaryDate.Format("2006-01-02")] = summary
+    }
+
+    query := dbsr.db.QueryBuilder.Select(
+        "i.summary_date",
+        "i.booking_id",
+        "i.category",
+        "i.amount",
+        "v.customer_id",
+        "v.booking_price",
+        "v.booking_status",
+        "v.check_in_date",
+        "v.check_out_date",
+        "v.booking_created_at",
+        "v.booking_updated_at",
+        "v.room_id",
+        "v.room_number",
+        "v.room_type_id",
+        "v.room_type_name",
+        "v.floor",
+        "v.rate_prices_id",
+        "v.customer_firstname",
+        "v.customer_surname",
+        "v.customer_identity_number",
+        "v.customer_address",
+        "v.paid_amount",
+        "v.balance",
+        "v.payment_status",
+        "v.payment_update_date",
+    ).From("daily_booking_summary_items i").
+        Join("booking_customer_payment v ON v.booking_id = i.booking_id").
+        Where(sq.Eq{"i.summary_date": dates}).
+        OrderBy("i.summary_date DESC", "i.category", "i.booking_id")
+
+    sql, args, err := query.ToSql()
+    if err != nil {
+        return fmt.Errorf("error building query: %w", err)
+    }
+
+    rows, err := dbsr.db.Query(ctx, sql, args...)
+    if err != nil {
+        return fmt.Errorf("error querying summary items: %w", err)
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var item domain.DailyBookingSummaryItem
+        var bcp domain.BookingCustomerPayment
+        err := rows.Scan(
+            &item.SummaryDate,
+            &item.BookingID,
+            &item.Category,
+            &item.Amount,
+            &bcp.CustomerID,
+            &bcp.BookingPrice,
+            &bcp.BookingStatus,
+            &bcp.CheckInDate,
+            &bcp.CheckOutDate,
+            &bcp.BookingCreatedAt,
+            &bcp.BookingUpdatedAt,
+            &bcp.RoomID,
+            &bcp.RoomNumber,
+            &bcp.RoomTypeID,
+            &bcp.RoomTypeName,
+            &bcp.Floor,
+            &bcp.RatePriceID,
+            &bcp.CustomerFirstName,
+            &bcp.CustomerSurname,
+            &bcp.CustomerIdentityNumber,
+            &bcp.CustomerAddress,
+            &bcp.PaidAmount,
+            &bcp.Balance,
+            &bcp.PaymentStatus,
+            &bcp.PaymentUpdateDate,
+        )
+        if err != nil {
+            return fmt.Errorf("error scanning summary item: %w", err)
+        }
+        bcp.BookingID = item.BookingID
+        item.Booking = &bcp
+
+        if summary, ok := byDate[item.SummaryDate.Format("2006-01-02")]; ok {
+            summary.Items = append(summary.Items, item)
+        }
+    }
+
+    if err := rows.Err(); err != nil {
+        return fmt.Errorf("error reading summary items: %w", err)
+    }
+
+    return nil
+}
+
+// ListBookingActivity lists the bookings created, completed and canceled on date. Each booking is listed
+// once per category, with its total as of the day's last matching status change. A booking is only listed
+// as completed on the day it was first completed, at its total then.
+func (dbsr *DailyBookingSummaryRepository) ListBookingActivity(ctx context.Context, date time.Time) ([]domain.DailyBookingSummaryItem, error) {
+    var items []domain.DailyBookingSummaryItem
+
+    day := date.Format("2006-01-02")
+    events := dbsr.db.QueryBuilder.Select("e.id", "e.booking_id", "e.amount", "e.changed_at").
+        Column(sq.Expr(
+            "CASE WHEN e.from_status IS NULL THEN ?::int WHEN e.to_status = ? AND NOT ("+earlierCompletion+") THEN ?::int WHEN e.to_status = ? THEN ?::int END AS category",
+            domain.SummaryCategoryCreated,
+            domain.BookingStatusCompleted, domain.SummaryCategoryCompleted,
+            domain.BookingStatusCanceled, domain.SummaryCategoryCanceled,
+        )).
+        From("booking_status_events e").
+        Where("e.changed_at >= ?::date AND e.changed_at < ?::date + 1", day, day)
+
+    query := dbsr.db.QueryBuilder.Select("DISTINCT ON (e.category, e.booking_id) e.category", "e.booking_id", "e.amount").
+        FromSelect(events, "e").
+        Where("e.category IS NOT NULL").
+        OrderBy("e.category", "e.booking_id", "e.changed_at DESC", "e.id DESC")
+
+    sql, args, err := query.ToSql()
+    if err != nil {
+        return nil, fmt.Errorf("error building query: %w", err)
+    }
+
+    rows, err := dbsr.db.Query(ctx, sql, args...)
+    if err != nil {
+        return nil, fmt.Errorf("error querying booking activity: %w", err)
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        item := domain.DailyBookingSummaryItem{SummaryDate: date}
+        if err := rows.Scan(&item.Category, &item.BookingID, &item.Amount); err != nil {
+            return nil, fmt.Errorf("error scanning booking activity: %w", err)
+        }
+        items = append(items, item)
+    }
+
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("error reading booking activity: %w", err)
+    }
+
+    return items, nil
+}
+
+// earlierCompletion is true when the booking of the status event e was completed before e, so that a
+// booking reopened and completed again counts once, on its first completion
+const earlierCompletion = `EXISTS (SELECT 1 FROM booking_status_events p
+        WHERE p.booking_id = e.booking_id AND p.to_status = e.to_status AND p.from_status IS NOT NULL
+        AND (p.changed_at, p.id) < (e.changed_at, e.id))`
+
+// GetCompletedRevenue totals the bookings first completed on date, at their totals then
+func (dbsr *DailyBookingSummaryRepository) GetCompletedRevenue(ctx context.Context, date time.Time) (float64, error) {
+    var total float64
+
+    day := date.Format("2006-01-02")
+    query := dbsr.db.QueryBuilder.Select("COALESCE(SUM(e.amount), 0)").
+        From("booking_status_events e").
+        Where(sq.Eq{"e.to_status": domain.BookingStatusCompleted}).
+        Where(sq.NotEq{"e.from_status": nil}).
+        Where("e.changed_at >= ?::date AND e.changed_at < ?::date + 1", day, day).
+        Where("NOT " + earlierCompletion)
+
+    sql, args, err := query.ToSql()
+    if err != nil {
+        return 0, fmt.Errorf("error building query: %w", err)
+    }
+
+    if err := dbsr.db.QueryRow(ctx, sql, args...).Scan(&total); err != nil {
+        return 0, fmt.Errorf("error summing completed bookings: %w", err)
+    }
+
+    return total, nil
+}
+
+// IsDayClosed reports whether the summary of date has been confirmed
+func (dbsr *DailyBookingSummaryRepository) IsDayClosed(ctx context.Context, date time.Time) (bool, error) {
+    var closed bool
+
+    query := dbsr.db.QueryBuilder.Select("1").
+        From("daily_booking_summary").
+        Where(sq.Eq{"summary_date": date.Format("2006-01-02")}).
+        Where(sq.Eq{"status": domain.SummaryStatusConfirmed}).
+        Prefix("SELECT EXISTS (").
+        Suffix(")")
+
+    sql, args, err := query.ToSql()
+    if err != nil {
+        return false, fmt.Errorf("error building query: %w", err)
+    }
+
+    if err := dbsr.db.QueryRow(ctx, sql, args...).Scan(&closed); err != nil {
+        return false, fmt.Errorf("error checking closed day: %w", err)
+    }
+
+    return closed, nil
+}
+
+// ListClosedBookingItems lists the items of confirmed summaries the booking is listed in, oldest first
+func (dbsr *DailyBookingSummaryRepository) ListClosedBookingItems(ctx *gin.Context, bookingID uint64) ([]domain.DailyBookingSummaryItem, error) {
+    var items []domain.DailyBookingSummaryItem
+
+    query := dbsr.db.QueryBuilder.Select("i.summary_date", "i.booking_id", "i.category", "i.amount").
+        From("daily_booking_summary_items i").
+        Join("daily_booking_summary s ON s.summary_date = i.summary_date").
+        Where(sq.Eq{"i.booking_id": bookingID}).
+        Where(sq.Eq{"s.status": domain.SummaryStatusConfirmed}).
+        OrderBy("i.summary_date", "i.category")
+
+    sql, args, err := query.ToSql()
+    if err != nil {
+        return nil, fmt.Errorf("error building query: %w", err)
+    }
+
+    rows, err := dbsr.db.Query(ctx, sql, args...)
+    if err != nil {
+        return nil, fmt.Errorf("error querying closed booking items: %w", err)
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var item domain.DailyBookingSummaryItem
+        if err := rows.Scan(&item.SummaryDate, &item.BookingID, &item.Category, &item.Amount); err != nil {
+            return nil, fmt.Errorf("error scanning closed booking item: %w", err)
+        }
+        items = append(items, item)
+    }
+
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("error reading closed booking items: %w", err)
+    }
+
+    return items, nil
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type ExchangeRateRepository struct {
+	db *postgres.DB
+}
+
+func NewExchangeRateRepository(db *postgres.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		db,
+	}
+}
+
+func (er *ExchangeRateRepository) CreateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	query := er.db.QueryBuilder.Insert("exchange_rates").
+		Columns("currency", "rate", "effective_date").
+		Values(exchangeRate.Currency, exchangeRate.Rate, exchangeRate.EffectiveDate.Format("2006-01-02")).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = er.db.QueryRow(ctx, sql, args...).Scan(
+		&exchangeRate.ID,
+		&exchangeRate.Currency,
+		&exchangeRate.Rate,
+		&exchangeRate.EffectiveDate,
+		&exchangeRate.CreatedAt,
+		&exchangeRate.UpdatedAt,
+	)
+
+	if err != nil {
+		if errCode := er.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return exchangeRate, nil
+}
+
+func (er *ExchangeRateRepository) GetExchangeRateByID(ctx *gin.Context, id uint64) (*domain.ExchangeRate, error) {
+	var exchangeRate domain.ExchangeRate
+
+	query := er.db.QueryBuilder.Select("*").
+		From("exchange_rates").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = er.db.QueryRow(ctx, sql, args...).Scan(
+		&exchangeRate.ID,
+		&exchangeRate.Currency,
+		&exchangeRate.Rate,
+		&exchangeRate.EffectiveDate,
+		&exchangeRate.CreatedAt,
+		&exchangeRate.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &exchangeRate, nil
+}
+
+// GetEffectiveExchangeRate returns the most recent rate for a currency that is effective on the given date
+func (er *ExchangeRateRepository) GetEffectiveExchangeRate(ctx *gin.Context, currency string, date time.Time) (*domain.ExchangeRate, error) {
+	var exchangeRate domain.ExchangeRate
+
+	query := er.db.QueryBuilder.Select("*").
+		From("exchange_rates").
+		Where(sq.Eq{"currency": currency}).
+		Where(sq.LtOrEq{"effective_date": date.Format("2006-01-02")}).
+		OrderBy("effective_date DESC").
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = er.db.QueryRow(ctx, sql, args...).Scan(
+		&exchangeRate.ID,
+		&exchangeRate.Currency,
+		&exchangeRate.Rate,
+		&exchangeRate.EffectiveDate,
+		&exchangeRate.CreatedAt,
+		&exchangeRate.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &exchangeRate, nil
+}
+
+func (er *ExchangeRateRepository) ListExchangeRates(ctx *gin.Context, currency string, skip, limit uint64) ([]domain.ExchangeRate, uint64, error) {
+	var exchangeRates []domain.ExchangeRate
+	var totalCount uint64
+
+	conditions := sq.And{}
+	if currency != "" {
+		conditions = append(conditions, sq.Eq{"currency": currency})
+	}
+
+	countQuery := er.db.QueryBuilder.Select("COUNT(*)").From("exchange_rates")
+	if len(conditions) > 0 {
+		countQuery = countQuery.Where(conditions)
+	}
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	err = er.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := er.db.QueryBuilder.Select("*").
+		From("exchange_rates").
+		OrderBy("effective_date DESC", "currency").
+		Limit(limit)
+
+	if len(conditions) > 0 {
+		query = query.Where(conditions)
+	}
+
+	if skip > 0 {
+		query = query.Offset(skip)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := er.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exchangeRate domain.ExchangeRate
+		err := rows.Scan(
+			&exchangeRate.ID,
+			&exchangeRate.Currency,
+			&exchangeRate.Rate,
+			&exchangeRate.EffectiveDate,
+			&exchangeRate.CreatedAt,
+			&exchangeRate.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return exchangeRates, totalCount, nil
+}
+
+func (er *ExchangeRateRepository) UpdateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	query := er.db.QueryBuilder.Update("exchange_rates").
+		Set("rate", sq.Expr("COALESCE(?, rate)", exchangeRate.Rate)).
+		Set("effective_date", sq.Expr("COALESCE(?, effective_date)", exchangeRate.EffectiveDate)).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": exchangeRate.ID}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = er.db.QueryRow(ctx, sql, args...).Scan(
+		&exchangeRate.ID,
+		&exchangeRate.Currency,
+		&exchangeRate.Rate,
+		&exchangeRate.EffectiveDate,
+		&exchangeRate.CreatedAt,
+		&exchangeRate.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := er.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return exchangeRate, nil
+}
+
+func (er *ExchangeRateRepository) DeleteExchangeRate(ctx *gin.Context, id uint64) error {
+	query := er.db.QueryBuilder.Delete("exchange_rates").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	_, err = er.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
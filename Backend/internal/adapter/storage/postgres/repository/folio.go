@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"log/slog"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type FolioRepository struct {
+	db *postgres.DB
+}
+
+func NewFolioRepository(db *postgres.DB) *FolioRepository {
+	return &FolioRepository{
+		db,
+	}
+}
+
+func (fr *FolioRepository) CreateFolioCharge(ctx *gin.Context, charge *domain.FolioCharge) (*domain.FolioCharge, error) {
+	query := fr.db.QueryBuilder.Insert("folio_charges").
+		Columns("booking_id", "charge_type", "description", "quantity", "unit_price", "tax_rate", "tax_amount", "amount", "posted_by", "posted_at").
+		Values(charge.BookingID, charge.ChargeType, charge.Description, charge.Quantity, charge.UnitPrice, charge.TaxRate, charge.TaxAmount, charge.Amount, charge.PostedBy, sq.Expr("COALESCE(?::timestamp, CURRENT_TIMESTAMP)", charge.PostedAt)).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = fr.db.QueryRow(ctx, sql, args...).Scan(
+		&charge.ID,
+		&charge.BookingID,
+		&charge.ChargeType,
+		&charge.Description,
+		&charge.Quantity,
+		&charge.UnitPrice,
+		&charge.TaxRate,
+		&charge.TaxAmount,
+		&charge.Amount,
+		&charge.PostedBy,
+		&charge.PostedAt,
+		&charge.CreatedAt,
+	)
+
+	if err != nil {
+		if errCode := fr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return charge, nil
+}
+
+func (fr *FolioRepository) GetFolioChargeByID(ctx *gin.Context, id uint64) (*domain.FolioCharge, error) {
+	var charge domain.FolioCharge
+
+	query := fr.db.QueryBuilder.Select("*").
+		From("folio_charges").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = fr.db.QueryRow(ctx, sql, args...).Scan(
+		&charge.ID,
+		&charge.BookingID,
+		&charge.ChargeType,
+		&charge.Description,
+		&charge.Quantity,
+		&charge.UnitPrice,
+		&charge.TaxRate,
+		&charge.TaxAmount,
+		&charge.Amount,
+		&charge.PostedBy,
+		&charge.PostedAt,
+		&charge.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &charge, nil
+}
+
+func (fr *FolioRepository) ListFolioChargesByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.FolioCharge, error) {
+	var charges []domain.FolioCharge
+
+	query := fr.db.QueryBuilder.Select("*").
+		From("folio_charges").
+		Where(sq.Eq{"booking_id": bookingID}).
+		OrderBy("posted_at", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := fr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var charge domain.FolioCharge
+		err := rows.Scan(
+			&charge.ID,
+			&charge.BookingID,
+			&charge.ChargeType,
+			&charge.Description,
+			&charge.Quantity,
+			&charge.UnitPrice,
+			&charge.TaxRate,
+			&charge.TaxAmount,
+			&charge.Amount,
+			&charge.PostedBy,
+			&charge.PostedAt,
+			&charge.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		charges = append(charges, charge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return charges, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type IdempotencyRepository struct {
+	db *postgres.DB
+}
+
+func NewIdempotencyRepository(db *postgres.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db,
+	}
+}
+
+func (ir *IdempotencyRepository) CreateIdempotencyKey(ctx *gin.Context, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	now := time.Now()
+	expiredBefore := now.Add(-domain.IdempotencyKeyTTL)
+	leaseExpiredBefore := now.Add(-domain.IdempotencyKeyLease)
+
+	query := ir.db.QueryBuilder.Insert("idempotency_keys").
+		Columns("idempotency_key", "user_id", "method", "path", "request_hash").
+		Values(key.Key, key.UserID, key.Method, key.Path, key.RequestHash).
+		Suffix(`
+			ON CONFLICT (user_id, idempotency_key)
+			DO UPDATE SET
+				method = EXCLUDED.method,
+				path = EXCLUDED.path,
+				request_hash = EXCLUDED.request_hash,
+				response_status = 0,
+				response_body = NULL,
+				completed_at = NULL,
+				created_at = CURRENT_TIMESTAMP
+			WHERE idempotency_keys.created_at < ?
+				OR (idempotency_keys.completed_at IS NULL
+					AND idempotency_keys.created_at < ?
+					AND idempotency_keys.method = EXCLUDED.method
+					AND idempotency_keys.path = EXCLUDED.path
+					AND idempotency_keys.request_hash = EXCLUDED.request_hash)
+			RETURNING *
+		`, expiredBefore, leaseExpiredBefore)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&key.ID,
+		&key.Key,
+		&key.UserID,
+		&key.Method,
+		&key.Path,
+		&key.RequestHash,
+		&key.ResponseStatus,
+		&key.ResponseBody,
+		&key.CompletedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (ir *IdempotencyRepository) GetIdempotencyKey(ctx *gin.Context, userID uint64, key string) (*domain.IdempotencyKey, error) {
+	var idempotencyKey domain.IdempotencyKey
+
+	query := ir.db.QueryBuilder.Select("*").
+		From("idempotency_keys").
+		Where(sq.Eq{"user_id": userID, "idempotency_key": key}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&idempotencyKey.ID,
+		&idempotencyKey.Key,
+		&idempotencyKey.UserID,
+		&idempotencyKey.Method,
+		&idempotencyKey.Path,
+		&idempotencyKey.RequestHash,
+		&idempotencyKey.ResponseStatus,
+		&idempotencyKey.ResponseBody,
+		&idempotencyKey.CompletedAt,
+		&idempotencyKey.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &idempotencyKey, nil
+}
+
+func (ir *IdempotencyRepository) CompleteIdempotencyKey(ctx *gin.Context, key *domain.IdempotencyKey) error {
+	query := ir.db.QueryBuilder.Update("idempotency_keys").
+		Set("response_status", key.ResponseStatus).
+		Set("response_body", key.ResponseBody).
+		Set("completed_at", key.CompletedAt).
+		Where(sq.Eq{"id": key.ID, "created_at": key.CreatedAt})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	result, err := ir.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (ir *IdempotencyRepository) DeleteIdempotencyKey(ctx *gin.Context, key *domain.IdempotencyKey) error {
+	// A retry that took the key over claimed it again, so the key is only deleted as claimed
+	query := ir.db.QueryBuilder.Delete("idempotency_keys").
+		Where(sq.Eq{"id": key.ID, "created_at": key.CreatedAt})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	_, err = ir.db.Exec(ctx, sql, args...)
+	return err
+}
+
+func (ir *IdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int, error) {
+	query := ir.db.QueryBuilder.Delete("idempotency_keys").
+		Where(sq.Lt{"created_at": before})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	result, err := ir.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
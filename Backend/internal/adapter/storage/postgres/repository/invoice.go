@@ -0,0 +1,538 @@
+package repository
+
+import (
+	"log/slog"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type InvoiceRepository struct {
+	db *postgres.DB
+}
+
+func NewInvoiceRepository(db *postgres.DB) *InvoiceRepository {
+	return &InvoiceRepository{
+		db,
+	}
+}
+
+func (ir *InvoiceRepository) CreateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error) {
+	query := ir.db.QueryBuilder.Insert("invoice_series").
+		Columns("code", "invoice_type", "prefix", "yearly_reset", "padding", "is_default").
+		Values(series.Code, series.InvoiceType, series.Prefix, series.YearlyReset, series.Padding, false).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&series.ID,
+		&series.Code,
+		&series.InvoiceType,
+		&series.Prefix,
+		&series.YearlyReset,
+		&series.Padding,
+		&series.IsDefault,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		if errCode := ir.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (ir *InvoiceRepository) GetInvoiceSeriesByID(ctx *gin.Context, id uint64) (*domain.InvoiceSeries, error) {
+	return ir.getInvoiceSeries(ctx, sq.Eq{"id": id})
+}
+
+func (ir *InvoiceRepository) GetDefaultInvoiceSeries(ctx *gin.Context, invoiceType domain.InvoiceType) (*domain.InvoiceSeries, error) {
+	return ir.getInvoiceSeries(ctx, sq.Eq{"invoice_type": invoiceType, "is_default": true})
+}
+
+func (ir *InvoiceRepository) getInvoiceSeries(ctx *gin.Context, where sq.Eq) (*domain.InvoiceSeries, error) {
+	var series domain.InvoiceSeries
+
+	query := ir.db.QueryBuilder.Select("*").
+		From("invoice_series").
+		Where(where).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&series.ID,
+		&series.Code,
+		&series.InvoiceType,
+		&series.Prefix,
+		&series.YearlyReset,
+		&series.Padding,
+		&series.IsDefault,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (ir *InvoiceRepository) ListInvoiceSeries(ctx *gin.Context) ([]domain.InvoiceSeries, error) {
+	var seriesList []domain.InvoiceSeries
+
+	query := ir.db.QueryBuilder.Select("*").
+		From("invoice_series").
+		OrderBy("invoice_type", "code")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := ir.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var series domain.InvoiceSeries
+		err := rows.Scan(
+			&series.ID,
+			&series.Code,
+			&series.InvoiceType,
+			&series.Prefix,
+			&series.YearlyReset,
+			&series.Padding,
+			&series.IsDefault,
+			&series.CreatedAt,
+			&series.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		seriesList = append(seriesList, series)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return seriesList, nil
+}
+
+// UpdateInvoiceSeries changes the prefix, padding and default flag of a series. Making a series
+// the default clears the flag on the other series of the same type in the same transaction.
+func (ir *InvoiceRepository) UpdateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error) {
+	tx, err := ir.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if series.IsDefault {
+		clearQuery := ir.db.QueryBuilder.Update("invoice_series").
+			Set("is_default", false).
+			Where(sq.Eq{"invoice_type": series.InvoiceType}).
+			Where(sq.NotEq{"id": series.ID})
+
+		sql, args, err := clearQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", clearQuery)
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	query := ir.db.QueryBuilder.Update("invoice_series").
+		Set("prefix", series.Prefix).
+		Set("padding", series.Padding).
+		Set("is_default", series.IsDefault).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": series.ID}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(
+		&series.ID,
+		&series.Code,
+		&series.InvoiceType,
+		&series.Prefix,
+		&series.YearlyReset,
+		&series.Padding,
+		&series.IsDefault,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := ir.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// IssueInvoice takes the next number of the series and inserts the invoice with its lines in one transaction.
+// The counter row stays locked until commit and a failed insert rolls the counter back, so numbers have no gaps.
+// A second open tax invoice of the same payer fails on the unique index with ErrConflictingData.
+func (ir *InvoiceRepository) IssueInvoice(ctx *gin.Context, invoice *domain.Invoice, series *domain.InvoiceSeries) (*domain.Invoice, error) {
+	tx, err := ir.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	year := series.CounterYear(*invoice.IssuedAt)
+	counterQuery := ir.db.QueryBuilder.Insert("invoice_series_counters").
+		Columns("series_id", "year", "last_number").
+		Values(series.ID, year, 1).
+		Suffix("ON CONFLICT (series_id, year) DO UPDATE SET last_number = invoice_series_counters.last_number + 1 RETURNING last_number")
+
+	sql, args, err := counterQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", counterQuery)
+
+	var sequence int
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&sequence); err != nil {
+		return nil, err
+	}
+
+	invoice.SeriesID = series.ID
+	invoice.Year = year
+	invoice.Sequence = sequence
+	invoice.Number = series.FormatNumber(year, sequence)
+
+	query := ir.db.QueryBuilder.Insert("invoices").
+		Columns("series_id", "number", "sequence", "year", "invoice_type", "booking_id", "original_invoice_id", "subtotal", "vat", "total", "reason", "issued_by", "issued_at", "payer_id").
+		Values(invoice.SeriesID, invoice.Number, invoice.Sequence, invoice.Year, invoice.InvoiceType, invoice.BookingID, invoice.OriginalInvoiceID, invoice.Subtotal, invoice.VAT, invoice.Total, invoice.Reason, invoice.IssuedBy, invoice.IssuedAt, invoice.PayerID).
+		Suffix("RETURNING *")
+
+	sql, args, err = query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(
+		&invoice.ID,
+		&invoice.SeriesID,
+		&invoice.Number,
+		&invoice.Sequence,
+		&invoice.Year,
+		&invoice.InvoiceType,
+		&invoice.BookingID,
+		&invoice.OriginalInvoiceID,
+		&invoice.Subtotal,
+		&invoice.VAT,
+		&invoice.Total,
+		&invoice.Reason,
+		&invoice.IssuedBy,
+		&invoice.IssuedAt,
+		&invoice.CreatedAt,
+		&invoice.PayerID,
+		&invoice.Cancelled,
+	)
+	if err != nil {
+		if errCode := ir.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	if len(invoice.Charges) > 0 {
+		chargesQuery := ir.db.QueryBuilder.Insert("invoice_charges").
+			Columns("invoice_id", "charge_type", "description", "quantity", "unit_price", "tax_rate", "tax_amount", "amount", "posted_at")
+		for _, charge := range invoice.Charges {
+			chargesQuery = chargesQuery.Values(invoice.ID, charge.ChargeType, charge.Description, charge.Quantity, charge.UnitPrice, charge.TaxRate, charge.TaxAmount, charge.Amount, charge.PostedAt)
+		}
+
+		sql, args, err = chargesQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", chargesQuery)
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(invoice.Payments) > 0 {
+		paymentsQuery := ir.db.QueryBuilder.Insert("invoice_payments").
+			Columns("invoice_id", "payment_method", "payment_type", "currency", "base_amount", "payment_date")
+		for _, payment := range invoice.Payments {
+			paymentsQuery = paymentsQuery.Values(invoice.ID, payment.PaymentMethod, payment.Type, payment.Currency, payment.BaseAmount, payment.PaymentDate)
+		}
+
+		sql, args, err = paymentsQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", paymentsQuery)
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	// The credit note and the cancellation of the invoice it reverses commit together, which frees
+	// the payer to be invoiced again
+	if invoice.OriginalInvoiceID != nil {
+		cancelQuery := ir.db.QueryBuilder.Update("invoices").
+			Set("cancelled", true).
+			Where(sq.Eq{"id": *invoice.OriginalInvoiceID, "cancelled": false})
+
+		sql, args, err = cancelQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", cancelQuery)
+
+		tag, err := tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, domain.ErrConflictingData
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// ListInvoiceCharges returns the charges stored with an issued invoice, in the order they were printed
+func (ir *InvoiceRepository) ListInvoiceCharges(ctx *gin.Context, invoiceID uint64) ([]domain.FolioCharge, error) {
+	var charges []domain.FolioCharge
+
+	query := ir.db.QueryBuilder.Select("charge_type", "description", "quantity", "unit_price", "tax_rate", "tax_amount", "amount", "posted_at").
+		From("invoice_charges").
+		Where(sq.Eq{"invoice_id": invoiceID}).
+		OrderBy("id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := ir.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var charge domain.FolioCharge
+		err := rows.Scan(
+			&charge.ChargeType,
+			&charge.Description,
+			&charge.Quantity,
+			&charge.UnitPrice,
+			&charge.TaxRate,
+			&charge.TaxAmount,
+			&charge.Amount,
+			&charge.PostedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		charges = append(charges, charge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return charges, nil
+}
+
+// ListInvoicePayments returns the payments stored with an issued invoice, in the order they were printed
+func (ir *InvoiceRepository) ListInvoicePayments(ctx *gin.Context, invoiceID uint64) ([]domain.Payment, error) {
+	var payments []domain.Payment
+
+	query := ir.db.QueryBuilder.Select("payment_method", "payment_type", "currency", "base_amount", "payment_date").
+		From("invoice_payments").
+		Where(sq.Eq{"invoice_id": invoiceID}).
+		OrderBy("id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := ir.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		payment := domain.Payment{Status: domain.PaymentStatusPaid}
+		err := rows.Scan(
+			&payment.PaymentMethod,
+			&payment.Type,
+			&payment.Currency,
+			&payment.BaseAmount,
+			&payment.PaymentDate,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (ir *InvoiceRepository) GetInvoiceByID(ctx *gin.Context, id uint64) (*domain.Invoice, error) {
+	return ir.getInvoice(ctx, sq.Eq{"id": id})
+}
+
+func (ir *InvoiceRepository) getInvoice(ctx *gin.Context, where sq.Eq) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+
+	query := ir.db.QueryBuilder.Select("*").
+		From("invoices").
+		Where(where).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&invoice.ID,
+		&invoice.SeriesID,
+		&invoice.Number,
+		&invoice.Sequence,
+		&invoice.Year,
+		&invoice.InvoiceType,
+		&invoice.BookingID,
+		&invoice.OriginalInvoiceID,
+		&invoice.Subtotal,
+		&invoice.VAT,
+		&invoice.Total,
+		&invoice.Reason,
+		&invoice.IssuedBy,
+		&invoice.IssuedAt,
+		&invoice.CreatedAt,
+		&invoice.PayerID,
+		&invoice.Cancelled,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+func (ir *InvoiceRepository) ListInvoicesByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+
+	query := ir.db.QueryBuilder.Select("*").
+		From("invoices").
+		Where(sq.Eq{"booking_id": bookingID}).
+		OrderBy("issued_at", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := ir.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var invoice domain.Invoice
+		err := rows.Scan(
+			&invoice.ID,
+			&invoice.SeriesID,
+			&invoice.Number,
+			&invoice.Sequence,
+			&invoice.Year,
+			&invoice.InvoiceType,
+			&invoice.BookingID,
+			&invoice.OriginalInvoiceID,
+			&invoice.Subtotal,
+			&invoice.VAT,
+			&invoice.Total,
+			&invoice.Reason,
+			&invoice.IssuedBy,
+			&invoice.IssuedAt,
+			&invoice.CreatedAt,
+			&invoice.PayerID,
+			&invoice.Cancelled,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		invoices = append(invoices, invoice)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
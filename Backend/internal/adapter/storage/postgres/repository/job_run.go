@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type JobRunRepository struct {
+	db *postgres.DB
+}
+
+func NewJobRunRepository(db *postgres.DB) *JobRunRepository {
+	return &JobRunRepository{
+		db,
+	}
+}
+
+func (jrr *JobRunRepository) CreateJobRun(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error) {
+	query := jrr.db.QueryBuilder.Insert("job_runs").
+		Columns("job_name", "target_date", "status", "attempt", "started_at").
+		Values(run.JobName, run.TargetDate.Format("2006-01-02"), domain.JobRunStatusRunning, run.Attempt, run.StartedAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = jrr.db.QueryRow(ctx, sql, args...).Scan(
+		&run.ID,
+		&run.JobName,
+		&run.TargetDate,
+		&run.Status,
+		&run.Attempt,
+		&run.ErrorMessage,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (jrr *JobRunRepository) FinishJobRun(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error) {
+	query := jrr.db.QueryBuilder.Update("job_runs").
+		Set("status", run.Status).
+		Set("error_message", run.ErrorMessage).
+		Set("finished_at", run.FinishedAt).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": run.ID, "status": domain.JobRunStatusRunning}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = jrr.db.QueryRow(ctx, sql, args...).Scan(
+		&run.ID,
+		&run.JobName,
+		&run.TargetDate,
+		&run.Status,
+		&run.Attempt,
+		&run.ErrorMessage,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (jrr *JobRunRepository) ListJobRuns(ctx *gin.Context, jobName string, skip, limit uint64) ([]domain.JobRun, uint64, error) {
+	var runs []domain.JobRun
+	var totalCount uint64
+
+	countQuery := jrr.db.QueryBuilder.Select("COUNT(*)").From("job_runs")
+	if jobName != "" {
+		countQuery = countQuery.Where(sq.Eq{"job_name": jobName})
+	}
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	err = jrr.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := jrr.db.QueryBuilder.Select("*").
+		From("job_runs").
+		OrderBy("started_at DESC", "id DESC").
+		Limit(limit).
+		Offset(skip)
+	if jobName != "" {
+		query = query.Where(sq.Eq{"job_name": jobName})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := jrr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var run domain.JobRun
+		err := rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.TargetDate,
+			&run.Status,
+			&run.Attempt,
+			&run.ErrorMessage,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.CreatedAt,
+			&run.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return runs, totalCount, nil
+}
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"log/slog"
 	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,10 +19,10 @@ func NewLogRepository(db *postgres.DB) *LogRepository {
 	}
 }
 
-func (lr *LogRepository) CreateLog(ctx *gin.Context, log *domain.Log) (*domain.Log, error) {
+func (lr *LogRepository) CreateLog(ctx context.Context, log *domain.Log) (*domain.Log, error) {
 	query := lr.db.QueryBuilder.Insert("logs").
-		Columns("record_id", "action", "user_id", "table_name").
-		Values(log.RecordID, log.Action, log.UserID, log.TableName).
+		Columns("record_id", "action", "user_id", "table_name", "amount").
+		Values(log.RecordID, log.Action, log.UserID, log.TableName, log.Amount).
 		Suffix("RETURNING *")
 
 	sql, args, err := query.ToSql()
@@ -36,6 +38,7 @@ func (lr *LogRepository) CreateLog(ctx *gin.Context, log *domain.Log) (*domain.L
 		&log.UserID,
 		&log.TableName,
 		&log.CreatedAt,
+		&log.Amount,
 	)
 
 	if err != nil {
@@ -46,7 +49,6 @@ func (lr *LogRepository) CreateLog(ctx *gin.Context, log *domain.Log) (*domain.L
 }
 
 func (lr *LogRepository) GetLogs(ctx *gin.Context, skip, limit uint64) ([]domain.Log, uint64, error) {
-	var logs []domain.Log
 	var totalCount uint64
 
 	countQuery := lr.db.QueryBuilder.Select("COUNT(*)").From("logs")
@@ -68,15 +70,42 @@ func (lr *LogRepository) GetLogs(ctx *gin.Context, skip, limit uint64) ([]domain
 		query = query.Offset(skip)
 	}
 
-	sql, args, err := query.ToSql()
+	logs, err := lr.queryLogs(ctx, query)
 	if err != nil {
 		return nil, 0, err
 	}
+
+	return logs, totalCount, nil
+}
+
+// GetLogsBefore returns up to limit logs with IDs below beforeID, latest first, or the latest logs when
+// beforeID is zero
+func (lr *LogRepository) GetLogsBefore(ctx *gin.Context, beforeID, limit uint64) ([]domain.Log, error) {
+	query := lr.db.QueryBuilder.Select("*").
+		From("logs").
+		OrderBy("id DESC").
+		Limit(limit)
+
+	if beforeID > 0 {
+		query = query.Where(sq.Lt{"id": beforeID})
+	}
+
+	return lr.queryLogs(ctx, query)
+}
+
+// queryLogs runs a query selecting * from logs
+func (lr *LogRepository) queryLogs(ctx *gin.Context, query sq.SelectBuilder) ([]domain.Log, error) {
+	var logs []domain.Log
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
 	slog.Debug("SQL QUERY", "query", query)
 
 	rows, err := lr.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -89,17 +118,115 @@ func (lr *LogRepository) GetLogs(ctx *gin.Context, skip, limit uint64) ([]domain
 			&log.Action,
 			&log.UserID,
 			&log.CreatedAt,
+			&log.Amount,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 
 		logs = append(logs, log)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return logs, totalCount, nil
+	return logs, nil
+}
+
+// ListStaffActivity counts the logs of each user per day. Payments are summed from the payments each user
+// took per payment date, so payments taken without a log and payments changed since are counted as they stand.
+func (lr *LogRepository) ListStaffActivity(ctx *gin.Context, filter *domain.StaffActivityFilter) ([]domain.StaffActivity, error) {
+	var days []domain.StaffActivity
+
+	args := []interface{}{
+		filter.From.Format("2006-01-02"),
+		filter.To.Format("2006-01-02"),
+		domain.LogActionCreate,
+		domain.LogActionCheckIn,
+		domain.LogActionCancel,
+		domain.LogActionDelete,
+		domain.PaymentStatusPaid,
+		domain.PaymentTypeRefund,
+		domain.PaymentTypeVoid,
+	}
+	logUser, paymentUser := "", ""
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		logUser = "AND user_id = $10"
+		paymentUser = "AND taken_by = $10"
+	}
+
+	sql := `
+		WITH logged AS (
+			SELECT
+				created_at::date AS day,
+				user_id,
+				COUNT(*) FILTER (WHERE table_name = 'bookings' AND action = $3) AS bookings_created,
+				COUNT(*) FILTER (WHERE table_name = 'bookings' AND action = $4) AS check_ins,
+				COUNT(*) FILTER (WHERE table_name = 'bookings' AND action = $5) AS cancellations,
+				COUNT(*) FILTER (WHERE action = $6) AS deletes,
+				COALESCE(SUM(amount) FILTER (WHERE table_name = 'payments' AND action = $6), 0) AS deleted_amount
+			FROM logs
+			WHERE created_at >= $1::date AND created_at < $2::date + 1 ` + logUser + `
+			GROUP BY 1, 2
+		),
+		taken AS (
+			SELECT
+				payment_date::date AS day,
+				taken_by AS user_id,
+				COUNT(*) FILTER (WHERE payment_type NOT IN ($8, $9)) AS payments_taken,
+				SUM(base_amount) AS payments_amount
+			FROM payments
+			WHERE status = $7 AND taken_by IS NOT NULL
+				AND payment_date >= $1::date AND payment_date < $2::date + 1 ` + paymentUser + `
+			GROUP BY 1, 2
+		)
+		SELECT
+			COALESCE(l.day, t.day),
+			COALESCE(l.user_id, t.user_id),
+			COALESCE(u.username, ''),
+			COALESCE(l.bookings_created, 0),
+			COALESCE(l.check_ins, 0),
+			COALESCE(l.cancellations, 0),
+			COALESCE(t.payments_taken, 0),
+			COALESCE(t.payments_amount, 0),
+			COALESCE(l.deletes, 0),
+			COALESCE(l.deleted_amount, 0)
+		FROM logged l
+		FULL JOIN taken t ON t.day = l.day AND t.user_id = l.user_id
+		LEFT JOIN users u ON u.id = COALESCE(l.user_id, t.user_id)
+		ORDER BY 1, 2`
+	slog.Debug("SQL QUERY", "query", sql)
+
+	rows, err := lr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day domain.StaffActivity
+		err := rows.Scan(
+			&day.Date,
+			&day.UserID,
+			&day.UserName,
+			&day.BookingsCreated,
+			&day.CheckIns,
+			&day.Cancellations,
+			&day.PaymentsTaken,
+			&day.PaymentsAmount,
+			&day.Deletes,
+			&day.DeletedAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return days, nil
 }
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type LoyaltyRepository struct {
+	db *postgres.DB
+}
+
+func NewLoyaltyRepository(db *postgres.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{
+		db,
+	}
+}
+
+// loyaltyExpirySQL adds an expiry for each customer whose expired earnings exceed what they have spent.
+// Points are spent oldest first, so whatever was spent came out of the earnings that expired before the rest.
+const loyaltyExpirySQL = `INSERT INTO loyalty_transactions (customer_id, transaction_type, points, description)
+SELECT customer_id, $1, spent - expired, 'Points expired'
+FROM (
+	SELECT customer_id,
+		COALESCE(SUM(points) FILTER (WHERE transaction_type = $2 AND expires_at <= $3), 0) AS expired,
+		COALESCE(-SUM(points) FILTER (WHERE transaction_type <> $2), 0) AS spent
+	FROM loyalty_transactions
+	WHERE $4 = 0 OR customer_id = $4
+	GROUP BY customer_id
+) t
+WHERE expired > spent`
+
+func (lr *LoyaltyRepository) CreateTransaction(ctx *gin.Context, transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error) {
+	query := lr.db.QueryBuilder.Insert("loyalty_transactions").
+		Columns("customer_id", "transaction_type", "points", "booking_id", "payment_id", "description", "expires_at", "created_by").
+		Values(transaction.CustomerID, transaction.Type, transaction.Points, transaction.BookingID, transaction.PaymentID, transaction.Description, transaction.ExpiresAt, transaction.CreatedBy).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = scanLoyaltyTransaction(lr.db.QueryRow(ctx, sql, args...), transaction)
+	if err != nil {
+		switch lr.db.ErrorCode(err) {
+		case "23505":
+			return nil, domain.ErrConflictingData
+		case "23503":
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (lr *LoyaltyRepository) RedeemPoints(ctx *gin.Context, transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error) {
+	tx, err := lr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the customer makes concurrent redemptions wait, so they cannot spend the same points
+	var customerID uint64
+	err = tx.QueryRow(ctx, "SELECT id FROM customers WHERE id = $1 FOR UPDATE", transaction.CustomerID).Scan(&customerID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	var balance int
+	err = tx.QueryRow(ctx, "SELECT COALESCE(SUM(points), 0) FROM loyalty_transactions WHERE customer_id = $1", customerID).Scan(&balance)
+	if err != nil {
+		return nil, err
+	}
+	if balance+transaction.Points < 0 {
+		return nil, domain.ErrInsufficientPoints
+	}
+
+	query := lr.db.QueryBuilder.Insert("loyalty_transactions").
+		Columns("customer_id", "transaction_type", "points", "booking_id", "payment_id", "description", "created_by").
+		Values(customerID, domain.LoyaltyTransactionTypeRedeem, transaction.Points, transaction.BookingID, transaction.PaymentID, transaction.Description, transaction.CreatedBy).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	if err := scanLoyaltyTransaction(tx.QueryRow(ctx, sql, args...), transaction); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (lr *LoyaltyRepository) GetBalance(ctx *gin.Context, customerID uint64, qualifyingFrom time.Time) (int, int, error) {
+	var balance, qualifying int
+
+	query := lr.db.QueryBuilder.Select("COALESCE(SUM(points), 0)").
+		Column(sq.Expr("COALESCE(SUM(points) FILTER (WHERE transaction_type IN (?, ?) AND created_at >= ?), 0)",
+			domain.LoyaltyTransactionTypeEarn, domain.LoyaltyTransactionTypeEarnAdjustment, qualifyingFrom)).
+		From("loyalty_transactions").
+		Where(sq.Eq{"customer_id": customerID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = lr.db.QueryRow(ctx, sql, args...).Scan(&balance, &qualifying)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return balance, qualifying, nil
+}
+
+func (lr *LoyaltyRepository) ListTransactionsByCustomerID(ctx *gin.Context, customerID, skip, limit uint64) ([]domain.LoyaltyTransaction, uint64, error) {
+	var transactions []domain.LoyaltyTransaction
+	var totalCount uint64
+
+	countQuery := lr.db.QueryBuilder.Select("COUNT(*)").
+		From("loyalty_transactions").
+		Where(sq.Eq{"customer_id": customerID})
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	err = lr.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := lr.db.QueryBuilder.Select("*").
+		From("loyalty_transactions").
+		Where(sq.Eq{"customer_id": customerID}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(limit)
+
+	if skip > 0 {
+		query = query.Offset(skip)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := lr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction domain.LoyaltyTransaction
+		if err := scanLoyaltyTransaction(rows, &transaction); err != nil {
+			return nil, 0, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, totalCount, nil
+}
+
+func (lr *LoyaltyRepository) GetRedemptionByPaymentID(ctx *gin.Context, paymentID uint64) (*domain.LoyaltyTransaction, error) {
+	var transaction domain.LoyaltyTransaction
+
+	query := lr.db.QueryBuilder.Select("*").
+		From("loyalty_transactions").
+		Where(sq.Eq{"payment_id": paymentID, "transaction_type": domain.LoyaltyTransactionTypeRedeem}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = scanLoyaltyTransaction(lr.db.QueryRow(ctx, sql, args...), &transaction)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+func (lr *LoyaltyRepository) GetReinstatedPoints(ctx *gin.Context, paymentID uint64) (int, error) {
+	var points int
+
+	query := lr.db.QueryBuilder.Select("COALESCE(SUM(l.points), 0)").
+		From("loyalty_transactions l").
+		Join("payments p ON p.id = l.payment_id").
+		Where(sq.Eq{"l.transaction_type": domain.LoyaltyTransactionTypeReinstate, "p.original_payment_id": paymentID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = lr.db.QueryRow(ctx, sql, args...).Scan(&points)
+	if err != nil {
+		return 0, err
+	}
+
+	return points, nil
+}
+
+func (lr *LoyaltyRepository) ExpirePoints(ctx context.Context, asOf time.Time, customerID uint64) (int, error) {
+	slog.Debug("SQL QUERY", "query", loyaltyExpirySQL)
+
+	tag, err := lr.db.Exec(ctx, loyaltyExpirySQL, domain.LoyaltyTransactionTypeExpire, domain.LoyaltyTransactionTypeEarn, asOf, customerID)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (lr *LoyaltyRepository) GetEarnByBookingID(ctx *gin.Context, bookingID uint64) (*domain.LoyaltyTransaction, error) {
+	var transaction domain.LoyaltyTransaction
+
+	query := lr.db.QueryBuilder.Select("*").
+		From("loyalty_transactions").
+		Where(sq.Eq{"booking_id": bookingID, "transaction_type": domain.LoyaltyTransactionTypeEarn})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = scanLoyaltyTransaction(lr.db.QueryRow(ctx, sql, args...), &transaction)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+func (lr *LoyaltyRepository) GetEarnAdjustedPoints(ctx *gin.Context, bookingID uint64) (int, error) {
+	var points int
+
+	query := lr.db.QueryBuilder.Select("COALESCE(SUM(points), 0)").
+		From("loyalty_transactions").
+		Where(sq.Eq{"booking_id": bookingID, "transaction_type": domain.LoyaltyTransactionTypeEarnAdjustment})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = lr.db.QueryRow(ctx, sql, args...).Scan(&points)
+	if err != nil {
+		return 0, err
+	}
+
+	return points, nil
+}
+
+// scanLoyaltyTransaction scans a loyalty_transactions row selected with *
+func scanLoyaltyTransaction(row pgx.Row, transaction *domain.LoyaltyTransaction) error {
+	return row.Scan(
+		&transaction.ID,
+		&transaction.CustomerID,
+		&transaction.Type,
+		&transaction.Points,
+		&transaction.BookingID,
+		&transaction.PaymentID,
+		&transaction.Description,
+		&transaction.ExpiresAt,
+		&transaction.CreatedBy,
+		&transaction.CreatedAt,
+	)
+}
@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type NightAuditRepository struct {
+	db *postgres.DB
+}
+
+func NewNightAuditRepository(db *postgres.DB) *NightAuditRepository {
+	return &NightAuditRepository{
+		db,
+	}
+}
+
+func (nar *NightAuditRepository) GetBusinessDate(ctx *gin.Context) (time.Time, error) {
+	var businessDate time.Time
+
+	query := nar.db.QueryBuilder.Select("business_date").
+		From("business_date").
+		Where(sq.Eq{"id": 1})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return time.Time{}, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = nar.db.QueryRow(ctx, sql, args...).Scan(&businessDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return businessDate, nil
+}
+
+// IsDayLocked reports whether a completed night audit has locked date
+func (nar *NightAuditRepository) IsDayLocked(ctx context.Context, date time.Time) (bool, error) {
+	var locked bool
+
+	query := nar.db.QueryBuilder.Select("1").
+		From("night_audits").
+		Where(sq.Eq{"business_date": date.Format("2006-01-02"), "status": domain.NightAuditStatusCompleted}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return false, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = nar.db.QueryRow(ctx, sql, args...).Scan(&locked)
+	if err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
+
+func (nar *NightAuditRepository) StartNightAudit(ctx *gin.Context, audit *domain.NightAudit) (*domain.NightAudit, error) {
+	// A failed run, or one left running for over an hour by a crashed server, is restarted in place
+	query := nar.db.QueryBuilder.Insert("night_audits").
+		Columns("business_date", "status", "run_by", "started_at").
+		Values(audit.BusinessDate.Format("2006-01-02"), domain.NightAuditStatusRunning, audit.RunBy, audit.StartedAt).
+		Suffix(`
+			ON CONFLICT (business_date)
+			DO UPDATE SET
+				status = EXCLUDED.status,
+				run_by = EXCLUDED.run_by,
+				started_at = EXCLUDED.started_at,
+				error_message = '',
+				updated_at = CURRENT_TIMESTAMP
+			WHERE night_audits.status = ?
+				OR (night_audits.status = ? AND night_audits.started_at < CURRENT_TIMESTAMP - INTERVAL '1 hour')
+			RETURNING *
+		`, domain.NightAuditStatusFailed, domain.NightAuditStatusRunning)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = nar.db.QueryRow(ctx, sql, args...).Scan(
+		&audit.ID,
+		&audit.BusinessDate,
+		&audit.Status,
+		&audit.RoomNightsPosted,
+		&audit.RoomRevenue,
+		&audit.ErrorMessage,
+		&audit.RunBy,
+		&audit.StartedAt,
+		&audit.CompletedAt,
+		&audit.CreatedAt,
+		&audit.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+func (nar *NightAuditRepository) CompleteNightAudit(ctx *gin.Context, audit *domain.NightAudit) (*domain.NightAudit, error) {
+	tx, err := nar.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := nar.db.QueryBuilder.Update("night_audits").
+		Set("status", domain.NightAuditStatusCompleted).
+		Set("room_nights_posted", audit.RoomNightsPosted).
+		Set("room_revenue", audit.RoomRevenue).
+		Set("completed_at", audit.CompletedAt).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": audit.ID, "status": domain.NightAuditStatusRunning}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(
+		&audit.ID,
+		&audit.BusinessDate,
+		&audit.Status,
+		&audit.RoomNightsPosted,
+		&audit.RoomRevenue,
+		&audit.ErrorMessage,
+		&audit.RunBy,
+		&audit.StartedAt,
+		&audit.CompletedAt,
+		&audit.CreatedAt,
+		&audit.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	// The bookings of an earlier run of the date, if it was reopened, are replaced
+	deleteQuery := nar.db.QueryBuilder.Delete("night_audit_bookings").
+		Where(sq.Eq{"night_audit_id": audit.ID})
+
+	sql, args, err = deleteQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", deleteQuery)
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return nil, err
+	}
+
+	bookings := map[domain.NightAuditBookingCategory][]uint64{
+		domain.NightAuditBookingNoShow:     audit.NoShowBookings,
+		domain.NightAuditBookingUnbalanced: audit.UnbalancedBookings,
+	}
+	for category, bookingIDs := range bookings {
+		if len(bookingIDs) == 0 {
+			continue
+		}
+		bookingQuery := nar.db.QueryBuilder.Insert("night_audit_bookings").
+			Columns("night_audit_id", "booking_id", "category")
+		for _, bookingID := range bookingIDs {
+			bookingQuery = bookingQuery.Values(audit.ID, bookingID, category)
+		}
+		bookingQuery = bookingQuery.Suffix("ON CONFLICT DO NOTHING")
+
+		sql, args, err := bookingQuery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("SQL QUERY", "query", bookingQuery)
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the audited date is rolled forward, so a date is never skipped
+	dateQuery := nar.db.QueryBuilder.Update("business_date").
+		Set("business_date", audit.BusinessDate.AddDate(0, 0, 1).Format("2006-01-02")).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": 1, "business_date": audit.BusinessDate.Format("2006-01-02")})
+
+	sql, args, err = dateQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", dateQuery)
+
+	result, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, domain.ErrConflictingData
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+func (nar *NightAuditRepository) FailNightAudit(ctx *gin.Context, audit *domain.NightAudit) error {
+	query := nar.db.QueryBuilder.Update("night_audits").
+		Set("status", domain.NightAuditStatusFailed).
+		Set("error_message", audit.ErrorMessage).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": audit.ID, "status": domain.NightAuditStatusRunning})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	_, err = nar.db.Exec(ctx, sql, args...)
+	return err
+}
+
+func (nar *NightAuditRepository) GetNightAuditByDate(ctx *gin.Context, businessDate time.Time) (*domain.NightAudit, error) {
+	var audit domain.NightAudit
+
+	query := nar.db.QueryBuilder.Select("*").
+		From("night_audits").
+		Where(sq.Eq{"business_date": businessDate.Format("2006-01-02")}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = nar.db.QueryRow(ctx, sql, args...).Scan(
+		&audit.ID,
+		&audit.BusinessDate,
+		&audit.Status,
+		&audit.RoomNightsPosted,
+		&audit.RoomRevenue,
+		&audit.ErrorMessage,
+		&audit.RunBy,
+		&audit.StartedAt,
+		&audit.CompletedAt,
+		&audit.CreatedAt,
+		&audit.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	audits := []domain.NightAudit{audit}
+	if err := nar.loadAuditBookings(ctx, audits); err != nil {
+		return nil, err
+	}
+
+	return &audits[0], nil
+}
+
+func (nar *NightAuditRepository) ListNightAudits(ctx *gin.Context, skip, limit uint64) ([]domain.NightAudit, uint64, error) {
+	var audits []domain.NightAudit
+	var totalCount uint64
+
+	countQuery := nar.db.QueryBuilder.Select("COUNT(*)").From("night_audits")
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	err = nar.db.QueryRow(ctx, countSql, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := nar.db.QueryBuilder.Select("*").
+		From("night_audits").
+		OrderBy("business_date DESC").
+		Limit(limit).
+		Offset(skip)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := nar.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var audit domain.NightAudit
+		err := rows.Scan(
+			&audit.ID,
+			&audit.BusinessDate,
+			&audit.Status,
+			&audit.RoomNightsPosted,
+			&audit.RoomRevenue,
+			&audit.ErrorMessage,
+			&audit.RunBy,
+			&audit.StartedAt,
+			&audit.CompletedAt,
+			&audit.CreatedAt,
+			&audit.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		audits = append(audits, audit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := nar.loadAuditBookings(ctx, audits); err != nil {
+		return nil, 0, err
+	}
+
+	return audits, totalCount, nil
+}
+
+// loadAuditBookings fills in the bookings each of audits flagged, in booking order
+func (nar *NightAuditRepository) loadAuditBookings(ctx *gin.Context, audits []domain.NightAudit) error {
+	if len(audits) == 0 {
+		return nil
+	}
+	index := make(map[uint64]*domain.NightAudit, len(audits))
+	auditIDs := make([]uint64, 0, len(audits))
+	for i := range audits {
+		index[audits[i].ID] = &audits[i]
+		auditIDs = append(auditIDs, audits[i].ID)
+	}
+
+	query := nar.db.QueryBuilder.Select("night_audit_id", "booking_id", "category").
+		From("night_audit_bookings").
+		Where(sq.Eq{"night_audit_id": auditIDs}).
+		OrderBy("night_audit_id", "booking_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := nar.db.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var auditID, bookingID uint64
+		var category domain.NightAuditBookingCategory
+		if err := rows.Scan(&auditID, &bookingID, &category); err != nil {
+			return err
+		}
+		audit := index[auditID]
+		switch category {
+		case domain.NightAuditBookingNoShow:
+			audit.NoShowBookings = append(audit.NoShowBookings, bookingID)
+		case domain.NightAuditBookingUnbalanced:
+			audit.UnbalancedBookings = append(audit.UnbalancedBookings, bookingID)
+		}
+	}
+
+	return rows.Err()
+}
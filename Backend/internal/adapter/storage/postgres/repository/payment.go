@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"log/slog"
+	"time"
 
 	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
@@ -22,8 +24,8 @@ func NewPaymentRepository(db *postgres.DB) *PaymentRepository {
 
 func (pr *PaymentRepository) CreatePayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error) {
 	query := pr.db.QueryBuilder.Insert("payments").
-		Columns("booking_id", "amount", "payment_method", "payment_date", "status").
-		Values(payment.BookingID, payment.Amount, payment.PaymentMethod, payment.PaymentDate, int(payment.Status)).
+		Columns("booking_id", "amount", "payment_method", "payment_date", "status", "currency", "exchange_rate", "base_amount", "payment_type", "original_payment_id", "reason_code", "reason_note", "gateway", "gateway_reference", "payer_id", "taken_by", "shift_id").
+		Values(payment.BookingID, payment.Amount, payment.PaymentMethod, payment.PaymentDate, int(payment.Status), payment.Currency, payment.ExchangeRate, payment.BaseAmount, payment.Type, payment.OriginalPaymentID, payment.ReasonCode, payment.ReasonNote, payment.Gateway, payment.GatewayReference, payment.PayerID, payment.TakenBy, payment.ShiftID).
 		Suffix("RETURNING *")
 
 	sql, args, err := query.ToSql()
@@ -42,9 +44,25 @@ func (pr *PaymentRepository) CreatePayment(ctx *gin.Context, payment *domain.Pay
 		&status,
 		&payment.CreatedAt,
 		&payment.UpdatedAt,
+		&payment.Currency,
+		&payment.ExchangeRate,
+		&payment.BaseAmount,
+		&payment.Type,
+		&payment.OriginalPaymentID,
+		&payment.ReasonCode,
+		&payment.ReasonNote,
+		&payment.Gateway,
+		&payment.GatewayReference,
+		&payment.PayerID,
+		&payment.TakenBy,
+		&payment.ShiftID,
 	)
 
 	if err != nil {
+		// The payer does not belong to the booking
+		if errCode := pr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
 		return nil, err
 	}
 
@@ -75,6 +93,66 @@ func (pr *PaymentRepository) GetPaymentByID(ctx *gin.Context, id uint64) (*domai
 		&payment.Status,
 		&payment.CreatedAt,
 		&payment.UpdatedAt,
+		&payment.Currency,
+		&payment.ExchangeRate,
+		&payment.BaseAmount,
+		&payment.Type,
+		&payment.OriginalPaymentID,
+		&payment.ReasonCode,
+		&payment.ReasonNote,
+		&payment.Gateway,
+		&payment.GatewayReference,
+		&payment.PayerID,
+		&payment.TakenBy,
+		&payment.ShiftID,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// GetPaymentByGatewayReference retrieves the payment created for a payment gateway transaction
+func (pr *PaymentRepository) GetPaymentByGatewayReference(ctx *gin.Context, reference string) (*domain.Payment, error) {
+	var payment domain.Payment
+
+	query := pr.db.QueryBuilder.Select("*").
+		From("payments").
+		Where(sq.Eq{"gateway_reference": reference}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = pr.db.QueryRow(ctx, sql, args...).Scan(
+		&payment.ID,
+		&payment.BookingID,
+		&payment.Amount,
+		&payment.PaymentMethod,
+		&payment.PaymentDate,
+		&payment.Status,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+		&payment.Currency,
+		&payment.ExchangeRate,
+		&payment.BaseAmount,
+		&payment.Type,
+		&payment.OriginalPaymentID,
+		&payment.ReasonCode,
+		&payment.ReasonNote,
+		&payment.Gateway,
+		&payment.GatewayReference,
+		&payment.PayerID,
+		&payment.TakenBy,
+		&payment.ShiftID,
 	)
 
 	if err != nil {
@@ -88,7 +166,6 @@ func (pr *PaymentRepository) GetPaymentByID(ctx *gin.Context, id uint64) (*domai
 }
 
 func (pr *PaymentRepository) ListPayments(ctx *gin.Context, skip, limit uint64) ([]domain.Payment, uint64, error) {
-	var payments []domain.Payment
 	var totalCount uint64
 
 	countQuery := pr.db.QueryBuilder.Select("COUNT(*)").From("payments")
@@ -110,15 +187,42 @@ func (pr *PaymentRepository) ListPayments(ctx *gin.Context, skip, limit uint64)
 		query = query.Offset(skip)
 	}
 
-	sql, args, err := query.ToSql()
+	payments, err := pr.queryPayments(ctx, query)
 	if err != nil {
 		return nil, 0, err
 	}
+
+	return payments, totalCount, nil
+}
+
+// ListPaymentsBefore lists up to limit payments with IDs below beforeID, latest first, or the latest payments
+// when beforeID is zero. Exports page through every payment with it, as it needs neither a count nor an offset.
+func (pr *PaymentRepository) ListPaymentsBefore(ctx *gin.Context, beforeID, limit uint64) ([]domain.Payment, error) {
+	query := pr.db.QueryBuilder.Select("*").
+		From("payments").
+		OrderBy("id DESC").
+		Limit(limit)
+
+	if beforeID > 0 {
+		query = query.Where(sq.Lt{"id": beforeID})
+	}
+
+	return pr.queryPayments(ctx, query)
+}
+
+// queryPayments runs a query selecting * from payments
+func (pr *PaymentRepository) queryPayments(ctx *gin.Context, query sq.SelectBuilder) ([]domain.Payment, error) {
+	var payments []domain.Payment
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
 	slog.Debug("SQL QUERY", "query", query)
 
 	rows, err := pr.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -133,19 +237,89 @@ func (pr *PaymentRepository) ListPayments(ctx *gin.Context, skip, limit uint64)
 			&payment.Status,
 			&payment.CreatedAt,
 			&payment.UpdatedAt,
+			&payment.Currency,
+			&payment.ExchangeRate,
+			&payment.BaseAmount,
+			&payment.Type,
+			&payment.OriginalPaymentID,
+			&payment.ReasonCode,
+			&payment.ReasonNote,
+			&payment.Gateway,
+			&payment.GatewayReference,
+			&payment.PayerID,
+			&payment.TakenBy,
+			&payment.ShiftID,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 
 		payments = append(payments, payment)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return payments, totalCount, nil
+	return payments, nil
+}
+
+func (pr *PaymentRepository) ListPaymentsByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.Payment, error) {
+	var payments []domain.Payment
+
+	query := pr.db.QueryBuilder.Select("*").
+		From("payments").
+		Where(sq.Eq{"booking_id": bookingID}).
+		OrderBy("payment_date", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := pr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payment domain.Payment
+		err := rows.Scan(
+			&payment.ID,
+			&payment.BookingID,
+			&payment.Amount,
+			&payment.PaymentMethod,
+			&payment.PaymentDate,
+			&payment.Status,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+			&payment.Currency,
+			&payment.ExchangeRate,
+			&payment.BaseAmount,
+			&payment.Type,
+			&payment.OriginalPaymentID,
+			&payment.ReasonCode,
+			&payment.ReasonNote,
+			&payment.Gateway,
+			&payment.GatewayReference,
+		&payment.PayerID,
+		&payment.TakenBy,
+		&payment.ShiftID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
 }
 
 func (pr *PaymentRepository) UpdatePayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error) {
@@ -154,6 +328,14 @@ func (pr *PaymentRepository) UpdatePayment(ctx *gin.Context, payment *domain.Pay
 		Set("payment_method", sq.Expr("COALESCE(?, payment_method)", payment.PaymentMethod)).
 		Set("payment_date", sq.Expr("COALESCE(?, payment_date)", payment.PaymentDate)).
 		Set("status", sq.Expr("COALESCE(?, status)", payment.Status)).
+		Set("currency", sq.Expr("COALESCE(?, currency)", payment.Currency)).
+		Set("exchange_rate", sq.Expr("COALESCE(?, exchange_rate)", payment.ExchangeRate)).
+		Set("base_amount", sq.Expr("COALESCE(?, base_amount)", payment.BaseAmount)).
+		Set("payment_type", sq.Expr("COALESCE(NULLIF(?, 0), payment_type)", payment.Type)).
+		Set("gateway", sq.Expr("COALESCE(NULLIF(?, ''), gateway)", payment.Gateway)).
+		Set("gateway_reference", sq.Expr("COALESCE(NULLIF(?, ''), gateway_reference)", payment.GatewayReference)).
+		Set("taken_by", sq.Expr("COALESCE(?, taken_by)", payment.TakenBy)).
+		Set("shift_id", sq.Expr("COALESCE(?, shift_id)", payment.ShiftID)).
 		Where(sq.Eq{"id": payment.ID}).
 		Suffix("RETURNING *")
 
@@ -172,6 +354,18 @@ func (pr *PaymentRepository) UpdatePayment(ctx *gin.Context, payment *domain.Pay
 		&payment.Status,
 		&payment.CreatedAt,
 		&payment.UpdatedAt,
+		&payment.Currency,
+		&payment.ExchangeRate,
+		&payment.BaseAmount,
+		&payment.Type,
+		&payment.OriginalPaymentID,
+		&payment.ReasonCode,
+		&payment.ReasonNote,
+		&payment.Gateway,
+		&payment.GatewayReference,
+		&payment.PayerID,
+		&payment.TakenBy,
+		&payment.ShiftID,
 	)
 
 	if err != nil {
@@ -201,3 +395,175 @@ func (pr *PaymentRepository) DeletePayment(ctx *gin.Context, id uint64) error {
 
 	return nil
 }
+
+// GetPaymentTotalsByCurrency sums paid payments per currency, including their base currency equivalent, between two dates
+func (pr *PaymentRepository) GetPaymentTotalsByCurrency(ctx *gin.Context, from, to time.Time) ([]domain.PaymentCurrencyTotal, error) {
+	var totals []domain.PaymentCurrencyTotal
+
+	query := pr.db.QueryBuilder.Select("currency", "COUNT(*)", "COALESCE(SUM(amount), 0)", "COALESCE(SUM(base_amount), 0)").
+		From("payments").
+		Where(sq.Eq{"status": domain.PaymentStatusPaid}).
+		Where(sq.Expr("payment_date::date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02"))).
+		GroupBy("currency").
+		OrderBy("currency")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	rows, err := pr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total domain.PaymentCurrencyTotal
+		err := rows.Scan(
+			&total.Currency,
+			&total.Count,
+			&total.Amount,
+			&total.BaseAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetReversedAmount returns how much of a payment has already been refunded or voided, in the payment's currency
+func (pr *PaymentRepository) GetReversedAmount(ctx *gin.Context, originalPaymentID uint64) (float64, error) {
+	var reversedAmount float64
+
+	query := pr.db.QueryBuilder.Select("COALESCE(-SUM(amount), 0)").
+		From("payments").
+		Where(sq.Eq{"original_payment_id": originalPaymentID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = pr.db.QueryRow(ctx, sql, args...).Scan(&reversedAmount)
+	if err != nil {
+		return 0, err
+	}
+
+	return reversedAmount, nil
+}
+
+// CreateReversal adds a refund or void of the original payment in one transaction. The original payment is
+// locked first, so concurrent reversals wait and cannot together reverse more than was paid. It returns
+// ErrPaymentReversed when nothing is left to reverse, or when a void follows a refund, and ErrInvalidData
+// when the reversal exceeds what is left.
+func (pr *PaymentRepository) CreateReversal(ctx *gin.Context, reversal *domain.Payment) (*domain.Payment, error) {
+	if reversal.OriginalPaymentID == nil {
+		return nil, domain.ErrInvalidData
+	}
+
+	tx, err := pr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var originalAmount float64
+	err = tx.QueryRow(ctx, "SELECT amount FROM payments WHERE id = $1 FOR UPDATE", *reversal.OriginalPaymentID).Scan(&originalAmount)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	var reversedAmount float64
+	err = tx.QueryRow(ctx, "SELECT COALESCE(-SUM(amount), 0) FROM payments WHERE original_payment_id = $1", *reversal.OriginalPaymentID).Scan(&reversedAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	refundable := originalAmount - reversedAmount
+	if refundable < 0.005 || (reversal.Type == domain.PaymentTypeVoid && reversedAmount > 0) {
+		return nil, domain.ErrPaymentReversed
+	}
+	if -reversal.Amount-refundable > 0.005 {
+		return nil, domain.ErrInvalidData
+	}
+
+	query := pr.db.QueryBuilder.Insert("payments").
+		Columns("booking_id", "amount", "payment_method", "payment_date", "status", "currency", "exchange_rate", "base_amount", "payment_type", "original_payment_id", "reason_code", "reason_note", "gateway", "gateway_reference", "payer_id", "taken_by", "shift_id").
+		Values(reversal.BookingID, reversal.Amount, reversal.PaymentMethod, reversal.PaymentDate, int(reversal.Status), reversal.Currency, reversal.ExchangeRate, reversal.BaseAmount, reversal.Type, reversal.OriginalPaymentID, reversal.ReasonCode, reversal.ReasonNote, reversal.Gateway, reversal.GatewayReference, reversal.PayerID, reversal.TakenBy, reversal.ShiftID).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", sql)
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(
+		&reversal.ID,
+		&reversal.BookingID,
+		&reversal.Amount,
+		&reversal.PaymentMethod,
+		&reversal.PaymentDate,
+		&reversal.Status,
+		&reversal.CreatedAt,
+		&reversal.UpdatedAt,
+		&reversal.Currency,
+		&reversal.ExchangeRate,
+		&reversal.BaseAmount,
+		&reversal.Type,
+		&reversal.OriginalPaymentID,
+		&reversal.ReasonCode,
+		&reversal.ReasonNote,
+		&reversal.Gateway,
+		&reversal.GatewayReference,
+		&reversal.PayerID,
+		&reversal.TakenBy,
+		&reversal.ShiftID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// GetReversalTotal returns the base currency total of refunds and voids recorded between two dates
+func (pr *PaymentRepository) GetReversalTotal(ctx context.Context, from, to time.Time) (float64, error) {
+	var total float64
+
+	query := pr.db.QueryBuilder.Select("COALESCE(-SUM(base_amount), 0)").
+		From("payments").
+		Where(sq.Eq{"payment_type": []domain.PaymentType{domain.PaymentTypeRefund, domain.PaymentTypeVoid}}).
+		Where(sq.Expr("payment_date::date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	err = pr.db.QueryRow(ctx, sql, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportRepository struct {
+	db *postgres.DB
+}
+
+func NewReportRepository(db *postgres.DB) *ReportRepository {
+	return &ReportRepository{
+		db,
+	}
+}
+
+// A booking sells one room night for every night from check-in up to the night before check-out, and its
+// total is spread evenly over those nights. Canceled bookings and no-shows sell nothing.
+const soldNightsSQL = `
+	nights AS (
+		SELECT d::date AS night
+		FROM generate_series($1::date, $2::date, INTERVAL '1 day') AS d
+	),
+	sold AS (
+		SELECT
+			n.night,
+			b.room_type_id,
+			b.rate_prices_id,
+			b.total_amount / GREATEST(b.check_out_date - b.check_in_date, 1) AS nightly_amount
+		FROM bookings b
+		JOIN nights n ON n.night >= b.check_in_date AND n.night < GREATEST(b.check_out_date, b.check_in_date + 1)
+		WHERE b.status NOT IN ($3, $4)
+	)`
+
+// The room nights available and sold per room type and period of the nights $1..$2. $5 is the date_trunc
+// unit of the periods.
+const roomTypeNightsSQL = `
+	WITH ` + soldNightsSQL + `,
+	available AS (
+		SELECT date_trunc($5, n.night)::date AS period, r.type_id AS room_type_id, COUNT(*) AS room_nights
+		FROM nights n
+		JOIN rooms r ON r.created_at::date <= n.night
+		GROUP BY 1, 2
+	),
+	sold_by_type AS (
+		SELECT date_trunc($5, night)::date AS period, room_type_id, COUNT(*) AS room_nights, SUM(nightly_amount) AS revenue
+		FROM sold
+		GROUP BY 1, 2
+	)
+	SELECT
+		COALESCE(a.period, s.period) AS period,
+		COALESCE(a.room_type_id, s.room_type_id, 0) AS room_type_id,
+		COALESCE(rt.name, '') AS room_type_name,
+		COALESCE(a.room_nights, 0) AS available,
+		COALESCE(s.room_nights, 0) AS sold,
+		COALESCE(s.revenue, 0) AS revenue
+	FROM available a
+	FULL JOIN sold_by_type s ON s.period = a.period AND s.room_type_id IS NOT DISTINCT FROM a.room_type_id
+	LEFT JOIN room_types rt ON rt.id = COALESCE(a.room_type_id, s.room_type_id)
+	ORDER BY 1, 2`
+
+// ListRoomTypeNights counts a room as available on every night from the day it was added
+func (rr *ReportRepository) ListRoomTypeNights(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval) ([]domain.RoomTypeNights, error) {
+	var rows []domain.RoomTypeNights
+
+	sql := roomTypeNightsSQL
+	args := []interface{}{
+		from.Format("2006-01-02"),
+		to.Format("2006-01-02"),
+		domain.BookingStatusCanceled,
+		domain.BookingStatusNoShow,
+		string(interval),
+	}
+	slog.Debug("SQL QUERY", "query", sql)
+
+	result, err := rr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var row domain.RoomTypeNights
+		err := result.Scan(
+			&row.Period,
+			&row.RoomTypeID,
+			&row.RoomTypeName,
+			&row.Available,
+			&row.Sold,
+			&row.Revenue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (rr *ReportRepository) ListRatePriceNights(ctx *gin.Context, from, to time.Time) ([]domain.RatePriceNights, error) {
+	var rows []domain.RatePriceNights
+
+	sql := `
+		WITH ` + soldNightsSQL + `
+		SELECT
+			rp.id,
+			rp.name,
+			COALESCE(rp.room_type_id, 0),
+			COUNT(*),
+			SUM(s.nightly_amount)
+		FROM sold s
+		JOIN rate_prices rp ON rp.id = s.rate_prices_id
+		GROUP BY rp.id, rp.name, rp.room_type_id
+		ORDER BY rp.id`
+	args := []interface{}{
+		from.Format("2006-01-02"),
+		to.Format("2006-01-02"),
+		domain.BookingStatusCanceled,
+		domain.BookingStatusNoShow,
+	}
+	slog.Debug("SQL QUERY", "query", sql)
+
+	result, err := rr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var row domain.RatePriceNights
+		err := result.Scan(
+			&row.RatePriceID,
+			&row.RatePriceName,
+			&row.RoomTypeID,
+			&row.Sold,
+			&row.Revenue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// ListPaymentsCollected leaves out city ledger transfers and loyalty points redemptions, which settle a
+// balance rather than take money
+func (rr *ReportRepository) ListPaymentsCollected(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval) ([]domain.PeriodAmount, error) {
+	var rows []domain.PeriodAmount
+
+	query := rr.db.QueryBuilder.Select().
+		Column(sq.Expr("date_trunc(?, payment_date)::date AS period", string(interval))).
+		Column("SUM(base_amount)").
+		From("payments").
+		Where("status = ?", domain.PaymentStatusPaid).
+		Where(sq.NotEq{"payment_method": []domain.PaymentMethod{domain.PaymentMethodCityLedger, domain.PaymentMethodLoyaltyPoints}}).
+		Where("payment_date >= ?::date AND payment_date < ?::date + 1", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		GroupBy("period").
+		OrderBy("period")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", query)
+
+	result, err := rr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var row domain.PeriodAmount
+		if err := result.Scan(&row.Period, &row.Amount); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// SaveForecastSnapshot copies the room nights of every night from..to into the snapshot in one statement,
+// so the snapshot is never read into memory
+func (rr *ReportRepository) SaveForecastSnapshot(ctx context.Context, snapshotDate, from, to time.Time) error {
+	tx, err := rr.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	deleteQuery := rr.db.QueryBuilder.Delete("forecast_snapshots").
+		Where(sq.Eq{"snapshot_date": snapshotDate.Format("2006-01-02")})
+
+	sql, args, err := deleteQuery.ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	sql = `
+		INSERT INTO forecast_snapshots (snapshot_date, stay_date, room_type_id, rooms_available, rooms_booked, revenue)
+		SELECT $6::date, t.period, t.room_type_id, t.available, t.sold, t.revenue
+		FROM (` + roomTypeNightsSQL + `) AS t`
+	args = []interface{}{
+		from.Format("2006-01-02"),
+		to.Format("2006-01-02"),
+		domain.BookingStatusCanceled,
+		domain.BookingStatusNoShow,
+		string(domain.ReportIntervalDay),
+		snapshotDate.Format("2006-01-02"),
+	}
+	slog.Debug("SQL QUERY", "query", sql)
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (rr *ReportRepository) ListForecastSnapshot(ctx *gin.Context, snapshotDate, from, to time.Time) ([]domain.ForecastSnapshot, error) {
+	var snapshots []domain.ForecastSnapshot
+
+	query := rr.db.QueryBuilder.Select("snapshot_date", "stay_date", "room_type_id", "rooms_available", "rooms_booked", "revenue").
+		From("forecast_snapshots").
+		Where(sq.Eq{"snapshot_date": snapshotDate.Format("2006-01-02")}).
+		Where("stay_date BETWEEN ?::date AND ?::date", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		OrderBy("stay_date", "room_type_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", sql)
+
+	result, err := rr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var snapshot domain.ForecastSnapshot
+		err := result.Scan(
+			&snapshot.SnapshotDate,
+			&snapshot.StayDate,
+			&snapshot.RoomTypeID,
+			&snapshot.RoomsAvailable,
+			&snapshot.RoomsBooked,
+			&snapshot.Revenue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// ListCancellationGroups takes the last status of each booking from its status history, so a booking canceled
+// and later reinstated counts as kept. Lead days run from the day of the cancellation to check-in, and lost
+// revenue is the booking total recorded with its last status.
+func (rr *ReportRepository) ListCancellationGroups(ctx *gin.Context, filter *domain.CancellationFilter) ([]domain.CancellationGroup, error) {
+	var groups []domain.CancellationGroup
+
+	canceled := sq.Expr("l.to_status = ?", domain.BookingStatusCanceled)
+	ended := sq.Expr("l.to_status IN (?, ?)", domain.BookingStatusCanceled, domain.BookingStatusNoShow)
+
+	query := rr.db.QueryBuilder.Select(
+		"COALESCE(b.room_type_id, 0)",
+		"COALESCE(rt.name, '')",
+		"COALESCE(c.customer_type_id, 0)",
+		"COALESCE(ct.name, '')",
+		"COALESCE(b.rate_prices_id, 0)",
+		"COALESCE(rp.name, '')",
+		"COUNT(*)",
+	).
+		Prefix(`WITH latest AS (
+			SELECT DISTINCT ON (booking_id) booking_id, to_status, amount, changed_at
+			FROM booking_status_events
+			ORDER BY booking_id, changed_at DESC, id DESC
+		)`).
+		Column(sq.Expr("COUNT(*) FILTER (WHERE ?)", canceled)).
+		Column(sq.Expr("COUNT(*) FILTER (WHERE l.to_status = ?)", domain.BookingStatusNoShow)).
+		Column(sq.Expr("COALESCE(SUM(GREATEST(b.check_in_date - l.changed_at::date, 0)) FILTER (WHERE ?), 0)", canceled)).
+		Column(sq.Expr("COALESCE(SUM(l.amount) FILTER (WHERE ?), 0)", ended)).
+		From("bookings b").
+		Join("latest l ON l.booking_id = b.id").
+		LeftJoin("customers c ON c.id = b.customer_id").
+		LeftJoin("customer_types ct ON ct.id = c.customer_type_id").
+		LeftJoin("room_types rt ON rt.id = b.room_type_id").
+		LeftJoin("rate_prices rp ON rp.id = b.rate_prices_id").
+		Where("b.check_in_date BETWEEN ?::date AND ?::date", filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02")).
+		GroupBy("1", "2", "3", "4", "5", "6").
+		OrderBy("1", "3", "5")
+
+	if filter.RoomTypeID != nil {
+		query = query.Where(sq.Eq{"b.room_type_id": *filter.RoomTypeID})
+	}
+	if filter.CustomerTypeID != nil {
+		query = query.Where(sq.Eq{"c.customer_type_id": *filter.CustomerTypeID})
+	}
+	if filter.RatePriceID != nil {
+		query = query.Where(sq.Eq{"b.rate_prices_id": *filter.RatePriceID})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SQL QUERY", "query", sql)
+
+	result, err := rr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var group domain.CancellationGroup
+		err := result.Scan(
+			&group.RoomTypeID,
+			&group.RoomTypeName,
+			&group.CustomerTypeID,
+			&group.CustomerTypeName,
+			&group.RatePriceID,
+			&group.RatePriceName,
+			&group.Bookings,
+			&group.Canceled,
+			&group.NoShows,
+			&group.LeadDays,
+			&group.LostRevenue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
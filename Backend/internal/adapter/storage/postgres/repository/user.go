@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/Coke3a/HotelManagement/internal/adapter/storage/postgres"
@@ -92,7 +93,7 @@ func (ur *UserRepository) GetUserByID(ctx *gin.Context, id uint64) (*domain.User
 	return &user, nil
 }
 
-func (ur *UserRepository) GetUserByUserName(ctx *gin.Context, userName string) (*domain.User, error) {
+func (ur *UserRepository) GetUserByUserName(ctx context.Context, userName string) (*domain.User, error) {
 	var user domain.User
 
 	query := ur.db.QueryBuilder.Select("*").
@@ -10,8 +10,14 @@ const (
     BookingStatusCheckedOut
     BookingStatusCanceled
     BookingStatusCompleted
+    BookingStatusNoShow
 )
 
+// IsStay reports whether the guest of a booking with this status checked in
+func (s BookingStatus) IsStay() bool {
+    return s == BookingStatusCheckedIn || s == BookingStatusCheckedOut || s == BookingStatusCompleted
+}
+
 type Booking struct {
     ID           uint64
     CustomerID   uint64
@@ -25,3 +31,28 @@ type Booking struct {
     RoomID       uint64
     RoomTypeID   uint64
 }
+
+
+// Nights returns the number of nights between check-in and check-out, at least one
+func (b *Booking) Nights() int {
+    return nightsBetween(b.CheckInDate, b.CheckOutDate)
+}
+
+func nightsBetween(checkIn, checkOut *time.Time) int {
+    if checkIn == nil || checkOut == nil {
+        return 1
+    }
+    nights := int(checkOut.Sub(*checkIn).Hours() / 24)
+    if nights < 1 {
+        return 1
+    }
+    return nights
+}
+
+// BookingCheckOut is everything checking a booking out records, stored together or not at all
+type BookingCheckOut struct {
+	Booking     *Booking
+	RoomCharge  *FolioCharge        // The nights of the stay not posted yet, nil when all are
+	Payment     *Payment            // City ledger payment settling a company payer's share, nil without a transfer
+	LedgerEntry *CompanyLedgerEntry // Invoice of the same amount on the company's ledger
+}
@@ -21,7 +21,13 @@ type BookingCustomerPayment struct {
 	CustomerSurname   string
 	CustomerIdentityNumber string
 	CustomerAddress string
-	PaymentID         *uint64
+	PaidAmount        float64 // Sum of paid payments in BaseCurrency
+	Balance           float64 // Amount still owed on the booking's folio
 	PaymentStatus     *uint64
 	PaymentUpdateDate *time.Time
 }
+
+// Nights returns the number of nights between check-in and check-out, at least one
+func (b *BookingCustomerPayment) Nights() int {
+	return nightsBetween(b.CheckInDate, b.CheckOutDate)
+}
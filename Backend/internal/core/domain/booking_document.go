@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+type DocumentType int
+
+const (
+	DocumentTypeTaxInvoice DocumentType = iota + 1
+	DocumentTypeReceipt
+	DocumentTypeCreditNote
+)
+
+// Title returns the heading printed on the document
+func (t DocumentType) Title() string {
+	switch t {
+	case DocumentTypeReceipt:
+		return "RECEIPT"
+	case DocumentTypeCreditNote:
+		return "CREDIT NOTE"
+	}
+	return "TAX INVOICE"
+}
+
+// IsTaxDocument reports whether the document must carry the issuer's and buyer's tax details
+func (t DocumentType) IsTaxDocument() bool {
+	return t == DocumentTypeTaxInvoice || t == DocumentTypeCreditNote
+}
+
+// Hotel is the issuer printed in the header of invoices and receipts
+type Hotel struct {
+	Name              string
+	Address           string
+	Phone             string
+	TaxID             string
+	ServiceChargeRate float64 // Percentage already included in room and outlet prices
+}
+
+// BillTo is the customer or company a document is addressed to instead of the guest
+type BillTo struct {
+	Name    string
+	TaxID   string
+	Address string
+}
+
+// BookingDocument is everything printed on a booking's invoice or receipt
+type BookingDocument struct {
+	Type     DocumentType
+	Hotel    Hotel
+	Booking  BookingCustomerPayment
+	BillTo   *BillTo // The booking payer billed instead of the guest
+	Nights   int
+	Charges  []FolioCharge // Posted charges, adjustments last
+	Payments []Payment     // Paid payments, including refunds and voids
+	IssuedAt time.Time
+
+	Number         string // Issued invoice or credit note number, empty for a pro forma invoice
+	OriginalNumber string // Number of the tax invoice a credit note cancels
+	Reason         string
+
+	Subtotal      float64 // Charges before VAT
+	ServiceCharge float64 // Service charge included in Subtotal
+	VAT           float64
+	Total         float64
+	Adjustments   float64
+	Paid          float64
+	Balance       float64
+}
+
+// Title returns the heading printed on the document. A tax invoice
+// printed before it has been issued carries no number and is only pro forma.
+func (d *BookingDocument) Title() string {
+	if d.Type == DocumentTypeTaxInvoice && d.Number == "" {
+		return "PRO FORMA INVOICE"
+	}
+	return d.Type.Title()
+}
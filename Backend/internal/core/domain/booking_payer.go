@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// BookingPayer is a customer or company billed for part of a booking, e.g. a company paying for the room.
+// Charges of the types a payer covers are routed to that payer; everything else stays with the guest.
+// Exactly one of CustomerID and CompanyID is set; a company's share is billed to its ledger at checkout.
+type BookingPayer struct {
+	ID          uint64
+	BookingID   uint64
+	CustomerID  *uint64
+	CompanyID   *uint64
+	ChargeTypes []ChargeType
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
+
+// Covers reports whether charges of the given type are billed to the payer
+func (p *BookingPayer) Covers(chargeType ChargeType) bool {
+	for _, t := range p.ChargeTypes {
+		if t == chargeType {
+			return true
+		}
+	}
+	return false
+}
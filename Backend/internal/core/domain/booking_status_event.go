@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// BookingStatusEvent records a booking being created with, or moved to, a status. Events are never
+// edited, so reports built on them do not shift when the booking is changed later.
+type BookingStatusEvent struct {
+	ID         uint64
+	BookingID  uint64
+	FromStatus *BookingStatus // Nil when the booking was created
+	ToStatus   BookingStatus
+	Amount     float64 // Booking total at the time of the change
+	ChangedAt  time.Time
+}
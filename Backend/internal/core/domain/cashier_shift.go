@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+type ShiftStatus int
+
+const (
+	ShiftStatusOpen ShiftStatus = iota + 1
+	ShiftStatusClosed
+)
+
+// CashierShift is a front-desk user's period of taking payments, starting from an opening cash float
+type CashierShift struct {
+	ID           uint64
+	UserID       uint64
+	Status       ShiftStatus
+	OpeningFloat float64
+	OpenedAt     *time.Time
+	ClosedAt     *time.Time
+	ClosingNote  string
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+}
+
+// ShiftTender is a payment method in one currency, what a shift's takings are expected and counted by
+type ShiftTender struct {
+	PaymentMethod PaymentMethod
+	Currency      string
+}
+
+// ShiftCount compares what a shift should hold for one payment method and currency with what was counted
+// at close. Amounts are in Currency; expected BaseCurrency cash includes the opening float.
+type ShiftCount struct {
+	ID            uint64
+	ShiftID       uint64
+	PaymentMethod PaymentMethod
+	Currency      string
+	Expected      float64
+	Counted       float64
+	Variance      float64 // Counted minus expected; negative when short
+	CreatedAt     *time.Time
+}
+
+// Tender returns the payment method and currency the count is for
+func (c ShiftCount) Tender() ShiftTender {
+	return ShiftTender{c.PaymentMethod, c.Currency}
+}
+
+// ShiftPaymentTotal is the total of the paid payments taken in a shift with one payment method in one currency
+type ShiftPaymentTotal struct {
+	PaymentMethod PaymentMethod
+	Currency      string
+	Count         int
+	Amount        float64 // In Currency
+}
+
+// ShiftCurrencyTotal adds up the counts of a shift in one currency
+type ShiftCurrencyTotal struct {
+	Currency string
+	Expected float64
+	Counted  float64
+	Variance float64
+}
+
+// ShiftReport is the close-out report of a shift. Counted and variance stay zero while the shift is open.
+type ShiftReport struct {
+	Shift  CashierShift
+	Counts []ShiftCount
+	Totals []ShiftCurrencyTotal // One per currency, BaseCurrency first
+}
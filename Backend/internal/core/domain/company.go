@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+type LedgerEntryType int
+
+const (
+	// LedgerEntryTypeInvoice is a booking balance transferred to the company at checkout
+	LedgerEntryTypeInvoice LedgerEntryType = iota + 1
+	// LedgerEntryTypePayment is money received from the company against one of its invoices
+	LedgerEntryTypePayment
+)
+
+// Company is a corporate account that settles its guests' bookings on credit
+type Company struct {
+	ID              uint64
+	Name            string
+	TaxID           string
+	Address         string
+	Email           string
+	Phone           string
+	ContactName     string
+	CreditLimit     float64 // Maximum outstanding balance in THB
+	PaymentTermDays int     // Days until a transferred balance is due
+	IsActive        bool
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+}
+
+// CompanyLedgerEntry is a line of a company's accounts receivable ledger, in THB.
+// Invoices are positive; payments are negative and reference the invoice they settle.
+type CompanyLedgerEntry struct {
+	ID            uint64
+	CompanyID     uint64
+	EntryType     LedgerEntryType
+	BookingID     *uint64
+	PaymentID     *uint64 // The city ledger payment that settled the booking folio
+	AppliedToID   *uint64 // The invoice a payment settles
+	Reference     string
+	Amount        float64
+	PaymentMethod PaymentMethod
+	EntryDate     *time.Time
+	DueDate       *time.Time
+	PostedBy      *uint64
+	CreatedAt     *time.Time
+}
+
+// CompanyInvoice is a company ledger invoice with the payments applied to it so far
+type CompanyInvoice struct {
+	CompanyLedgerEntry
+	Paid        float64
+	Outstanding float64
+}
+
+// CompanyLedger is a company's ledger entries with its outstanding balance
+type CompanyLedger struct {
+	Company Company
+	Entries []CompanyLedgerEntry
+	Balance float64
+}
+
+// AgingRow is one company's outstanding balance split by the age of its invoices
+type AgingRow struct {
+	CompanyID   uint64
+	CompanyName string
+	Days0To30   float64
+	Days31To60  float64
+	Days61To90  float64
+	Over90Days  float64
+	Total       float64
+}
+
+// Add puts an outstanding amount into the bucket for an invoice of the given age in days
+func (r *AgingRow) Add(ageDays int, amount float64) {
+	switch {
+	case ageDays <= 30:
+		r.Days0To30 += amount
+	case ageDays <= 60:
+		r.Days31To60 += amount
+	case ageDays <= 90:
+		r.Days61To90 += amount
+	default:
+		r.Over90Days += amount
+	}
+	r.Total += amount
+}
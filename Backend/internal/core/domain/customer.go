@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
 
 type Customer struct {
 	ID              uint64
@@ -15,4 +19,81 @@ type Customer struct {
 	Preferences     string
 	CreatedAt       *time.Time
 	UpdatedAt       *time.Time
-}
\ No newline at end of file
+}
+
+// CustomerMatch is a customer found by a search, with how closely it matched from 0 to 1
+type CustomerMatch struct {
+	Customer
+	Relevance float64
+}
+
+// CustomerSearch is a search query normalized the way customer records are compared: lower case with
+// whitespace trimmed and collapsed, and its digits alone for matching phone numbers
+type CustomerSearch struct {
+	Text   string
+	Digits string // Empty when the query has fewer than MinPhoneSearchDigits digits
+}
+
+// MinPhoneSearchDigits is the fewest digits a search query needs to be matched against phone numbers, so
+// that a name or address with a digit or two in it does not match every phone containing them
+const MinPhoneSearchDigits = 3
+
+// NewCustomerSearch normalizes query. Letters of any script are kept, so Thai and Latin names match alike.
+func NewCustomerSearch(query string) CustomerSearch {
+	search := CustomerSearch{Text: strings.ToLower(strings.Join(strings.Fields(query), " "))}
+	for _, r := range search.Text {
+		if r >= '0' && r <= '9' {
+			search.Digits += string(r)
+		}
+	}
+	if len(search.Digits) < MinPhoneSearchDigits {
+		search.Digits = ""
+	}
+	return search
+}
+
+// DefaultDuplicateScore is the lowest score a pair of customers is reported as likely duplicates at by default
+const DefaultDuplicateScore = 0.5
+
+// CustomerDuplicate is a pair of customers likely to be the same person, scored from 0 to 1 by what they share
+type CustomerDuplicate struct {
+	Customer       Customer
+	Duplicate      Customer
+	Score          float64
+	SameIdentity   bool
+	SamePhone      bool
+	SameEmail      bool
+	NameSimilarity float64 // Trigram similarity of the full names, from 0 to 1
+}
+
+// MaxPreferencesLength is the most characters a customer's preferences hold
+const MaxPreferencesLength = 255
+
+// MergeFrom fills the blank details of c from duplicate and joins their preferences while they fit, keeping
+// c's own details and customer type where set
+func (c *Customer) MergeFrom(duplicate *Customer) {
+	fill := func(field *string, value string) {
+		if strings.TrimSpace(*field) == "" {
+			*field = value
+		}
+	}
+	fill(&c.FirstName, duplicate.FirstName)
+	fill(&c.Surname, duplicate.Surname)
+	fill(&c.IdentityNumber, duplicate.IdentityNumber)
+	fill(&c.Email, duplicate.Email)
+	fill(&c.Phone, duplicate.Phone)
+	fill(&c.Address, duplicate.Address)
+	fill(&c.Gender, duplicate.Gender)
+	if c.CustomerTypeID == 0 {
+		c.CustomerTypeID = duplicate.CustomerTypeID
+	}
+
+	preferences := strings.TrimSpace(duplicate.Preferences)
+	if preferences != "" && !strings.Contains(strings.ToLower(c.Preferences), strings.ToLower(preferences)) {
+		if strings.TrimSpace(c.Preferences) == "" {
+			c.Preferences = preferences
+		} else if merged := c.Preferences + "; " + preferences; utf8.RuneCountInString(merged) <= MaxPreferencesLength {
+			c.Preferences = merged
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// CustomerHistory is every booking of a customer, latest arrival first, with what they add up to
+type CustomerHistory struct {
+	Customer Customer
+	Bookings []BookingCustomerPayment
+	Stats    CustomerStats
+}
+
+// CustomerStats sums up the bookings of a customer. Only stays, the bookings checked in at some point,
+// count towards the nights, the average rate and the favorite room type.
+type CustomerStats struct {
+	Bookings         int
+	Stays            int
+	Cancellations    int
+	NoShows          int
+	TotalNights      int
+	TotalRevenue     float64 // Paid amounts of all bookings in BaseCurrency, net of refunds
+	AverageRate      float64 // Booking totals of stays per night stayed
+	LastStay         *time.Time
+	FavoriteRoomType *CustomerRoomType
+}
+
+// CustomerRoomType is a room type a customer stayed in and for how many nights
+type CustomerRoomType struct {
+	ID     uint64
+	Name   string
+	Nights int
+}
+
+// NewCustomerStats sums up bookings. The favorite room type is the one stayed in for the most nights,
+// the most recently stayed in of those tied when bookings are latest first.
+func NewCustomerStats(bookings []BookingCustomerPayment) CustomerStats {
+	var stats CustomerStats
+	var stayRevenue float64
+	var roomTypes []*CustomerRoomType
+	roomTypeIndex := map[uint64]int{}
+
+	for i := range bookings {
+		booking := &bookings[i]
+		stats.Bookings++
+		stats.TotalRevenue += booking.PaidAmount
+
+		switch booking.BookingStatus {
+		case BookingStatusCanceled:
+			stats.Cancellations++
+		case BookingStatusNoShow:
+			stats.NoShows++
+		}
+		if !booking.BookingStatus.IsStay() {
+			continue
+		}
+
+		nights := booking.Nights()
+		stats.Stays++
+		stats.TotalNights += nights
+		stayRevenue += booking.BookingPrice
+		if booking.CheckInDate != nil && (stats.LastStay == nil || booking.CheckInDate.After(*stats.LastStay)) {
+			stats.LastStay = booking.CheckInDate
+		}
+
+		index, ok := roomTypeIndex[booking.RoomTypeID]
+		if !ok {
+			index = len(roomTypes)
+			roomTypeIndex[booking.RoomTypeID] = index
+			roomTypes = append(roomTypes, &CustomerRoomType{ID: booking.RoomTypeID, Name: booking.RoomTypeName})
+		}
+		roomTypes[index].Nights += nights
+	}
+
+	for _, roomType := range roomTypes {
+		if stats.FavoriteRoomType == nil || roomType.Nights > stats.FavoriteRoomType.Nights {
+			stats.FavoriteRoomType = roomType
+		}
+	}
+	if stats.TotalNights > 0 {
+		stats.AverageRate = stayRevenue / float64(stats.TotalNights)
+	}
+
+	return stats
+}
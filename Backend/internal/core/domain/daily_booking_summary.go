@@ -1,8 +1,11 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 	"fmt"
+	"sort"
 	"strings"
 	"strconv"
 )
@@ -15,15 +18,69 @@ const (
     SummaryStatusConfirmed
 )
 
+// SummaryCategory is the reason a booking is listed in a daily summary
+type SummaryCategory int
+
+const (
+    SummaryCategoryCreated SummaryCategory = iota + 1
+    SummaryCategoryCompleted
+    SummaryCategoryCanceled
+)
+
 type DailyBookingSummary struct {
-    SummaryDate       time.Time
-    CreatedBookings   string // Store booking IDs as a comma-separated string
-    CompletedBookings string // Store booking IDs as a comma-separated string
-    CanceledBookings  string // Store booking IDs as a comma-separated string
-    TotalAmount       float64
-    Status            SummaryStatus
-    CreatedAt         time.Time
-    UpdatedAt         time.Time
+    SummaryDate  time.Time
+    TotalAmount  float64 // Completed booking revenue net of refunds and voids
+    Status       SummaryStatus
+    CreatedAt    time.Time
+    UpdatedAt    time.Time
+    TotalRefunds float64 // Refunded and voided payments in BaseCurrency
+    Checksum     string  // SourceChecksum of the data the summary was generated from
+    Items        []DailyBookingSummaryItem
+}
+
+// DailyBookingSummaryItem lists one booking under a category of a daily summary
+type DailyBookingSummaryItem struct {
+    SummaryDate time.Time
+    BookingID   uint64
+    Category    SummaryCategory
+    Amount      float64 // Booking total when the summary was generated
+    Booking     *BookingCustomerPayment
+}
+
+// BookingsIn returns the items of the summary listed under category
+func (s *DailyBookingSummary) BookingsIn(category SummaryCategory) []DailyBookingSummaryItem {
+    var items []DailyBookingSummaryItem
+    for _, item := range s.Items {
+        if item.Category == category {
+            items = append(items, item)
+        }
+    }
+    return items
+}
+
+// IsClosed reports whether accounting has confirmed the summary, which closes its day to changes
+func (s *DailyBookingSummary) IsClosed() bool {
+    return s.Status == SummaryStatusConfirmed
+}
+
+// SourceChecksum returns the SHA-256 of the summary's date, totals and items, in hex. Items are
+// hashed in a fixed order, so the same source data always gives the same checksum.
+func (s *DailyBookingSummary) SourceChecksum() string {
+    items := make([]DailyBookingSummaryItem, len(s.Items))
+    copy(items, s.Items)
+    sort.Slice(items, func(i, j int) bool {
+        if items[i].Category != items[j].Category {
+            return items[i].Category < items[j].Category
+        }
+        return items[i].BookingID < items[j].BookingID
+    })
+
+    hash := sha256.New()
+    fmt.Fprintf(hash, "%s|%.2f|%.2f\n", s.SummaryDate.Format("2006-01-02"), s.TotalAmount, s.TotalRefunds)
+    for _, item := range items {
+        fmt.Fprintf(hash, "%d|%d|%.2f\n", item.Category, item.BookingID, item.Amount)
+    }
+    return hex.EncodeToString(hash.Sum(nil))
 }
 
 // Helper functions for booking IDs formatting
@@ -39,4 +39,32 @@ var (
 	ErrForbidden = errors.New("user is forbidden to access the resource")
 	// ErrInvalidData is an error for when provided data is invalid
 	ErrInvalidData = errors.New("invalid data provided")
+	// ErrUnsupportedCurrency is an error for when no exchange rate is available for a payment currency
+	ErrUnsupportedCurrency = errors.New("no exchange rate available for currency")
+	// ErrFolioNotSettled is an error for when a booking is checked out while its folio still has a balance
+	ErrFolioNotSettled = errors.New("folio balance must be settled before check-out")
+	// ErrPaymentReversed is an error for when a payment has already been refunded or voided
+	ErrPaymentReversed = errors.New("payment has already been refunded or voided")
+	// ErrCreditLimitExceeded is an error for when a transfer would take a company over its credit limit
+	ErrCreditLimitExceeded = errors.New("company credit limit exceeded")
+	// ErrInsufficientPoints is an error for when a customer redeems more loyalty points than their balance
+	ErrInsufficientPoints = errors.New("not enough loyalty points")
+	// ErrShiftNotOpen is an error for when a cash payment is taken by a user without an open cashier shift
+	ErrShiftNotOpen = errors.New("an open cashier shift is required to take cash payments")
+	// ErrDayLocked is an error for when a business day closed by the night audit is changed
+	ErrDayLocked = errors.New("business day is locked by the night audit")
+	// ErrDayClosed is an error for when a booking or payment of a day closed by a confirmed daily summary is changed
+	ErrDayClosed = errors.New("day is closed by a confirmed daily summary")
+	// ErrSummaryOutdated is an error for when a daily summary is confirmed after its source data has changed
+	ErrSummaryOutdated = errors.New("daily summary source data has changed since it was generated")
+	// ErrIdempotencyKeyMismatch is an error for when an idempotency key is reused with a different request
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used with a different request")
+	// ErrIdempotencyKeyInProgress is an error for when a retry arrives while the first request is still processed
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrPaymentDeclined is an error for when the payment gateway declines a card
+	ErrPaymentDeclined = errors.New("payment was declined by the card issuer")
+	// ErrPaymentGateway is an error for when the payment gateway cannot process the request
+	ErrPaymentGateway = errors.New("payment gateway is unavailable")
+	// ErrInvalidWebhookSignature is an error for when a payment gateway webhook cannot be verified
+	ErrInvalidWebhookSignature = errors.New("webhook signature is invalid")
 )
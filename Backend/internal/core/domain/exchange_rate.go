@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ExchangeRate is the amount of BaseCurrency paid for one unit of Currency,
+// valid from EffectiveDate until a newer rate for the same currency exists
+type ExchangeRate struct {
+	ID            uint64
+	Currency      string
+	Rate          float64
+	EffectiveDate *time.Time
+	CreatedAt     *time.Time
+	UpdatedAt     *time.Time
+}
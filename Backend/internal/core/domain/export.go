@@ -0,0 +1,22 @@
+package domain
+
+// ExportFormat is the spreadsheet file type a list or report is exported as
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+// ExportBatchSize is the number of rows an export reads from the database at a time
+const ExportBatchSize = 500
+
+// ContentType returns the MIME type of the format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+type ChargeType int
+
+const (
+	ChargeTypeRoom ChargeType = iota + 1
+	ChargeTypeMinibar
+	ChargeTypeLaundry
+	ChargeTypeRestaurant
+	ChargeTypeExtraBed
+	ChargeTypeAdjustment
+	ChargeTypeOther
+)
+
+// FolioCharge is a line item posted to a booking's folio. Adjustments are
+// posted with a negative amount and act as credits against the balance.
+type FolioCharge struct {
+	ID          uint64
+	BookingID   uint64
+	ChargeType  ChargeType
+	Description string
+	Quantity    int
+	UnitPrice   float64
+	TaxRate     float64 // Percentage, e.g. 7 for 7% VAT
+	TaxAmount   float64
+	Amount      float64 // Quantity * UnitPrice, before tax
+	PostedBy    *uint64 // Nil for charges posted by the system
+	PostedAt    *time.Time
+	CreatedAt   *time.Time
+}
+
+// Total returns the line amount including tax
+func (fc *FolioCharge) Total() float64 {
+	return fc.Amount + fc.TaxAmount
+}
+
+// Folio is the running account of a booking: posted charges on one side,
+// payments and adjustments as credits on the other
+type Folio struct {
+	BookingID        uint64
+	PayerID          *uint64 // Set on the share of a split folio billed to a booking payer
+	Charges          []FolioCharge
+	Payments         []Payment
+	TotalCharges     float64
+	TotalAdjustments float64
+	TotalPayments    float64
+	Balance          float64
+}
+
+// IsSettled reports whether the folio balance is zero to the nearest satang
+func (f *Folio) IsSettled() bool {
+	return f.Balance > -0.005 && f.Balance < 0.005
+}
+
+// PaymentStatus derives the booking's payment status from the folio payments and balance
+func (f *Folio) PaymentStatus() PaymentStatus {
+	return DerivePaymentStatus(f.TotalPayments, f.Balance)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// IdempotencyKeyTTL is how long a key and its stored response are kept for retries
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyKeyLease is how long a request holds its key while in progress. A retry of the same request
+// takes over a key held longer, as the request that claimed it died without completing or releasing it.
+const IdempotencyKeyLease = 2 * time.Minute
+
+// IdempotencyKey records a client's Idempotency-Key and the response of the first request sent with it,
+// so that retries of the same request replay that response instead of creating duplicates
+type IdempotencyKey struct {
+	ID             uint64
+	Key            string
+	UserID         uint64
+	Method         string
+	Path           string
+	RequestHash    string // SHA-256 of the request body, hex encoded
+	ResponseStatus int
+	ResponseBody   []byte
+	CompletedAt    *time.Time
+	CreatedAt      *time.Time
+}
+
+// IsCompleted reports whether the first request finished and its response was stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt != nil
+}
+
+// Matches reports whether a retry is the same request as the one the key was first used for
+func (k *IdempotencyKey) Matches(other *IdempotencyKey) bool {
+	return k.Method == other.Method && k.Path == other.Path && k.RequestHash == other.RequestHash
+}
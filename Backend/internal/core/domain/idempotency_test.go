@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKeyMatches(t *testing.T) {
+	first := &IdempotencyKey{Key: "k1", UserID: 1, Method: "POST", Path: "/v1/payments", RequestHash: "abc"}
+
+	tests := []struct {
+		name  string
+		retry *IdempotencyKey
+		want  bool
+	}{
+		{"same request", &IdempotencyKey{Key: "k1", UserID: 1, Method: "POST", Path: "/v1/payments", RequestHash: "abc"}, true},
+		{"same request, other key record", &IdempotencyKey{ID: 9, Key: "k1", Method: "POST", Path: "/v1/payments", RequestHash: "abc"}, true},
+		{"other body", &IdempotencyKey{Key: "k1", Method: "POST", Path: "/v1/payments", RequestHash: "def"}, false},
+		{"other path", &IdempotencyKey{Key: "k1", Method: "POST", Path: "/v1/bookings", RequestHash: "abc"}, false},
+		{"other method", &IdempotencyKey{Key: "k1", Method: "PUT", Path: "/v1/payments", RequestHash: "abc"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := first.Matches(tt.retry); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyIsCompleted(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		key  *IdempotencyKey
+		want bool
+	}{
+		{"in progress", &IdempotencyKey{}, false},
+		{"in progress with a status", &IdempotencyKey{ResponseStatus: 201}, false},
+		{"completed", &IdempotencyKey{ResponseStatus: 201, ResponseBody: []byte("{}"), CompletedAt: &now}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.IsCompleted(); got != tt.want {
+				t.Errorf("IsCompleted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type InvoiceType int
+
+const (
+	InvoiceTypeTaxInvoice InvoiceType = iota + 1
+	InvoiceTypeCreditNote
+)
+
+// InvoiceSeries is a gap-free numbering sequence for one invoice type
+type InvoiceSeries struct {
+	ID          uint64
+	Code        string
+	InvoiceType InvoiceType
+	Prefix      string
+	YearlyReset bool // Restart numbering at 1 every calendar year
+	Padding     int  // Minimum digits of the sequence number
+	IsDefault   bool // Used when an invoice is issued without a series
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
+
+// CounterYear returns the year whose counter numbers an invoice issued at the given time, or 0 when numbering never resets
+func (s *InvoiceSeries) CounterYear(issuedAt time.Time) int {
+	if s.YearlyReset {
+		return issuedAt.Year()
+	}
+	return 0
+}
+
+// FormatNumber returns the printed invoice number, e.g. INV2024-000123 or INV-000123
+func (s *InvoiceSeries) FormatNumber(year, sequence int) string {
+	if year > 0 {
+		return fmt.Sprintf("%s%d-%0*d", s.Prefix, year, s.Padding, sequence)
+	}
+	return fmt.Sprintf("%s-%0*d", s.Prefix, s.Padding, sequence)
+}
+
+// Invoice is an issued tax invoice or credit note. Issued invoices never change;
+// a tax invoice is cancelled by issuing a credit note that references it.
+type Invoice struct {
+	ID                uint64
+	SeriesID          uint64
+	Number            string
+	Sequence          int
+	Year              int
+	InvoiceType       InvoiceType
+	BookingID         uint64
+	OriginalInvoiceID *uint64 // The tax invoice a credit note cancels
+	Subtotal          float64
+	VAT               float64
+	Total             float64
+	Reason            string
+	IssuedBy          *uint64
+	IssuedAt          *time.Time
+	CreatedAt         *time.Time
+	PayerID           *uint64 // The booking payer billed, nil for the guest
+	Cancelled         bool    // Set on a tax invoice once a credit note cancels it
+	// Charges and Payments are the lines of a tax invoice as issued, stored with it
+	Charges  []FolioCharge
+	Payments []Payment
+}
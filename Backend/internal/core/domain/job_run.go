@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// SystemUserName is the user that scheduled jobs act as
+const SystemUserName = "system"
+
+const (
+	// JobDailySummary generates the previous day's booking summary
+	JobDailySummary = "daily_summary"
+	// JobForecastSnapshot stores the rooms on the books for the pickup of later forecasts
+	JobForecastSnapshot = "forecast_snapshot"
+	// JobLoyaltyExpiry lapses the loyalty points that expired unspent
+	JobLoyaltyExpiry = "loyalty_expiry"
+	// JobIdempotencyCleanup deletes the idempotency keys kept past their TTL
+	JobIdempotencyCleanup = "idempotency_cleanup"
+)
+
+type JobRunStatus int
+
+const (
+	JobRunStatusRunning JobRunStatus = iota + 1
+	JobRunStatusSucceeded
+	JobRunStatusFailed
+	JobRunStatusSkipped
+)
+
+// JobRun records one attempt of a scheduled job. Every retry is a run of its own.
+type JobRun struct {
+	ID           uint64
+	JobName      string
+	TargetDate   time.Time
+	Status       JobRunStatus
+	Attempt      int
+	ErrorMessage string
+	StartedAt    *time.Time
+	FinishedAt   *time.Time
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+}
@@ -9,4 +9,58 @@ type Log struct {
 	Action    string
 	UserID    uint64
 	CreatedAt time.Time
-}
\ No newline at end of file
+	Amount    *float64 // Base amount of a deleted payment
+}
+
+// Log actions the staff activity report counts
+const (
+	LogActionCreate   = "CREATE"
+	LogActionDelete   = "DELETE"
+	LogActionCheckIn  = "CHECKIN"
+	LogActionCheckOut = "CHECKOUT"
+	LogActionCancel   = "CANCEL"
+)
+
+// StaffActivityFilter selects the logs of a staff activity report by day, and optionally by user
+type StaffActivityFilter struct {
+	From   time.Time
+	To     time.Time
+	UserID *uint64
+}
+
+// StaffActivityCounts holds the work a user logged
+type StaffActivityCounts struct {
+	BookingsCreated int
+	CheckIns        int
+	Cancellations   int
+	PaymentsTaken   int     // Paid payments taken, refunds and voids left out
+	PaymentsAmount  float64 // Base amount of the paid payments taken, net of the refunds and voids taken
+	Deletes         int     // Records deleted from any table
+	DeletedAmount   float64 // Base amount of the payments deleted
+}
+
+// Add accumulates the counts and amount of other
+func (c *StaffActivityCounts) Add(other StaffActivityCounts) {
+	c.BookingsCreated += other.BookingsCreated
+	c.CheckIns += other.CheckIns
+	c.Cancellations += other.Cancellations
+	c.PaymentsTaken += other.PaymentsTaken
+	c.PaymentsAmount += other.PaymentsAmount
+	c.Deletes += other.Deletes
+	c.DeletedAmount += other.DeletedAmount
+}
+
+// StaffActivity is the work a user logged on a day
+type StaffActivity struct {
+	Date     time.Time
+	UserID   uint64
+	UserName string
+	StaffActivityCounts
+}
+
+// StaffActivityReport is the work each user logged per day over a date range
+type StaffActivityReport struct {
+	Filter StaffActivityFilter
+	Total  StaffActivityCounts
+	Days   []StaffActivity
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type LoyaltyTransactionType int
+
+const (
+	// LoyaltyTransactionTypeEarn is points earned by a completed booking
+	LoyaltyTransactionTypeEarn LoyaltyTransactionType = iota + 1
+	// LoyaltyTransactionTypeRedeem is points spent on a payment or a folio discount
+	LoyaltyTransactionTypeRedeem
+	// LoyaltyTransactionTypeExpire is earned points that lapsed unspent
+	LoyaltyTransactionTypeExpire
+	// LoyaltyTransactionTypeReinstate is redeemed points given back when their payment or discount is undone
+	LoyaltyTransactionTypeReinstate
+	// LoyaltyTransactionTypeEarnAdjustment is earned points taken back when a payment of the booking is refunded or voided
+	LoyaltyTransactionTypeEarnAdjustment
+)
+
+// LoyaltyTierMonths is how many months of earned points qualify a customer for a tier
+const LoyaltyTierMonths = 12
+
+// LoyaltyTransaction is a line of a customer's loyalty points ledger. Earned and reinstated points are
+// positive; redeemed and expired points are negative.
+type LoyaltyTransaction struct {
+	ID          uint64
+	CustomerID  uint64
+	Type        LoyaltyTransactionType
+	Points      int
+	BookingID   *uint64
+	PaymentID   *uint64 // The loyalty points payment a redemption paid, or the reversal that reinstated it
+	Description string
+	ExpiresAt   *time.Time // When earned points lapse, nil when they never do
+	CreatedBy   *uint64    // Nil for points expired by the system
+	CreatedAt   *time.Time
+}
+
+// LoyaltyTier is a level of the program a customer reaches by the points they earn
+type LoyaltyTier struct {
+	Name      string
+	MinPoints int // Points to earn over the last LoyaltyTierMonths months to reach the tier
+}
+
+// LoyaltyProgram is how points are earned, what they are worth and when they lapse
+type LoyaltyProgram struct {
+	EarnRate     float64       // Points per THB spent
+	PointValue   float64       // THB a point is worth when redeemed
+	ExpiryMonths int           // Months earned points last, zero when they never lapse
+	Tiers        []LoyaltyTier // Ordered by MinPoints, the first starting at zero
+}
+
+// PointsEarned returns the whole points earned by spending amount THB
+func (p LoyaltyProgram) PointsEarned(amount float64) int {
+	if amount <= 0 {
+		return 0
+	}
+	return int(math.Floor(amount*p.EarnRate + 1e-9))
+}
+
+// PointsFor returns the points needed to pay amount THB, rounded up to a whole point
+func (p LoyaltyProgram) PointsFor(amount float64) int {
+	return int(math.Ceil(amount/p.PointValue - 1e-9))
+}
+
+// Value returns what points are worth in THB
+func (p LoyaltyProgram) Value(points int) float64 {
+	return float64(points) * p.PointValue
+}
+
+// ExpiresAt returns when points earned at earnedAt lapse, nil when they never do
+func (p LoyaltyProgram) ExpiresAt(earnedAt time.Time) *time.Time {
+	if p.ExpiryMonths <= 0 {
+		return nil
+	}
+	expiresAt := earnedAt.AddDate(0, p.ExpiryMonths, 0)
+	return &expiresAt
+}
+
+// TierFor returns the highest tier reached with the qualifying points and the tier after it, nil at the top
+func (p LoyaltyProgram) TierFor(points int) (LoyaltyTier, *LoyaltyTier) {
+	var tier LoyaltyTier
+	for i, t := range p.Tiers {
+		if points < t.MinPoints {
+			next := p.Tiers[i]
+			return tier, &next
+		}
+		tier = t
+	}
+	return tier, nil
+}
+
+// LoyaltyAccount is a customer's standing in the loyalty program
+type LoyaltyAccount struct {
+	CustomerID       uint64
+	Balance          int
+	BalanceValue     float64 // What the balance is worth in THB
+	QualifyingPoints int     // Points earned over the last LoyaltyTierMonths months
+	Tier             LoyaltyTier
+	NextTier         *LoyaltyTier
+	PointsToNextTier int
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestLoyaltyProgramTierFor(t *testing.T) {
+	program := LoyaltyProgram{
+		Tiers: []LoyaltyTier{
+			{Name: "Member", MinPoints: 0},
+			{Name: "Silver", MinPoints: 1000},
+			{Name: "Gold", MinPoints: 5000},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		points   int
+		wantTier string
+		wantNext string // Empty at the top tier
+	}{
+		{"no points", 0, "Member", "Silver"},
+		{"below silver", 999, "Member", "Silver"},
+		{"at silver", 1000, "Silver", "Gold"},
+		{"below gold", 4999, "Silver", "Gold"},
+		{"at gold", 5000, "Gold", ""},
+		{"above gold", 12000, "Gold", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, next := program.TierFor(tt.points)
+			if tier.Name != tt.wantTier {
+				t.Errorf("tier = %q, want %q", tier.Name, tt.wantTier)
+			}
+			switch {
+			case tt.wantNext == "" && next != nil:
+				t.Errorf("next tier = %q, want none", next.Name)
+			case tt.wantNext != "" && next == nil:
+				t.Errorf("next tier = none, want %q", tt.wantNext)
+			case next != nil && next.Name != tt.wantNext:
+				t.Errorf("next tier = %q, want %q", next.Name, tt.wantNext)
+			}
+		})
+	}
+}
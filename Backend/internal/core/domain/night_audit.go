@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type NightAuditStatus int
+
+const (
+	NightAuditStatusRunning NightAuditStatus = iota + 1
+	NightAuditStatusCompleted
+	NightAuditStatusFailed
+	NightAuditStatusReopened // An admin reopened the audited day; confirming its summary locks it again
+)
+
+// NightAuditBookingCategory is why a night audit listed a booking
+type NightAuditBookingCategory int
+
+const (
+	NightAuditBookingNoShow NightAuditBookingCategory = iota + 1
+	NightAuditBookingUnbalanced
+)
+
+// NightAudit is the end-of-day run that closes a business date. A completed audit locks the
+// date and rolls the hotel's business date forward to the next day.
+type NightAudit struct {
+	ID                 uint64
+	BusinessDate       time.Time
+	Status             NightAuditStatus
+	RoomNightsPosted   int
+	RoomRevenue        float64
+	NoShowBookings     []uint64 // Bookings flagged as no-shows
+	UnbalancedBookings []uint64 // Departed bookings whose folio still has a balance
+	ErrorMessage       string
+	RunBy              *uint64
+	StartedAt          *time.Time
+	CompletedAt        *time.Time
+	CreatedAt          *time.Time
+	UpdatedAt          *time.Time
+}
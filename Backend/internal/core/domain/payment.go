@@ -4,10 +4,16 @@ import "time"
 
 type PaymentStatus int
 type PaymentMethod int
+type PaymentType int
+type ReversalReason int
 
 const (
 	PaymentStatusUnpaid PaymentStatus = iota + 1
 	PaymentStatusPaid
+	PaymentStatusFailed
+	PaymentStatusRefunded
+	// PaymentStatusPartiallyPaid is derived for a booking whose payments cover only part of the amount due
+	PaymentStatusPartiallyPaid
 )
 
 const (
@@ -16,8 +22,34 @@ const (
 	PaymentMethodDebitCard
 	PaymentMethodCash
 	PaymentMethodBankTransfer
+	// PaymentMethodCityLedger settles a folio by transferring its balance to a company account
+	PaymentMethodCityLedger
+	// PaymentMethodLoyaltyPoints pays with the guest's loyalty points at the program's point value
+	PaymentMethodLoyaltyPoints
 )
 
+const (
+	PaymentTypeDeposit PaymentType = iota + 1
+	PaymentTypeBalance
+	PaymentTypeTopUp
+	// PaymentTypeRefund and PaymentTypeVoid are negative records reversing an original payment
+	PaymentTypeRefund
+	PaymentTypeVoid
+)
+
+const (
+	ReversalReasonNone ReversalReason = iota
+	ReversalReasonGuestRequest
+	ReversalReasonOvercharge
+	ReversalReasonDuplicatePayment
+	ReversalReasonBookingCanceled
+	ReversalReasonOperatorError
+	ReversalReasonOther
+)
+
+// BaseCurrency is the currency all reports and balances are aggregated in
+const BaseCurrency = "THB"
+
 type Payment struct {
 	ID            uint64
 	BookingID     uint64
@@ -27,4 +59,56 @@ type Payment struct {
 	Status        PaymentStatus
 	CreatedAt     *time.Time
 	UpdatedAt     *time.Time
+	Currency      string
+	ExchangeRate  float64 // THB per one unit of Currency at the time of payment
+	BaseAmount    float64 // Amount converted to BaseCurrency
+	Type          PaymentType
+	// OriginalPaymentID links a refund or void to the payment it reverses
+	OriginalPaymentID *uint64
+	ReasonCode        ReversalReason
+	ReasonNote        string
+	// Gateway and GatewayReference identify the processor transaction of a card payment
+	Gateway          string
+	GatewayReference string
+	// PayerID is the booking payer the payment was taken from, nil for the guest
+	PayerID *uint64
+	// TakenBy and ShiftID record the user who took the payment and their open cashier shift
+	TakenBy *uint64
+	ShiftID *uint64
+}
+
+// IsNonMonetary reports whether the payment method settles a folio without taking money in: a transfer to a
+// company account or a loyalty points redemption
+func (m PaymentMethod) IsNonMonetary() bool {
+	return m == PaymentMethodCityLedger || m == PaymentMethodLoyaltyPoints
+}
+
+// IsCard reports whether the payment method is processed by a card payment gateway
+func (m PaymentMethod) IsCard() bool {
+	return m == PaymentMethodCreditCard || m == PaymentMethodDebitCard
+}
+
+// IsReversal reports whether the payment refunds or voids another payment
+func (p *Payment) IsReversal() bool {
+	return p.Type == PaymentTypeRefund || p.Type == PaymentTypeVoid
+}
+
+// DerivePaymentStatus returns the payment status of a booking from what has been paid and what is still owed
+func DerivePaymentStatus(paidAmount, balance float64) PaymentStatus {
+	switch {
+	case paidAmount <= 0:
+		return PaymentStatusUnpaid
+	case balance < 0.005:
+		return PaymentStatusPaid
+	default:
+		return PaymentStatusPartiallyPaid
+	}
+}
+
+// PaymentCurrencyTotal is the aggregate of payments taken in a single currency
+type PaymentCurrencyTotal struct {
+	Currency   string
+	Count      uint64
+	Amount     float64
+	BaseAmount float64
 }
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type GatewayTransactionStatus int
+
+const (
+	GatewayTransactionStatusAuthorized GatewayTransactionStatus = iota + 1
+	GatewayTransactionStatusCaptured
+	GatewayTransactionStatusRefunded
+	GatewayTransactionStatusDeclined
+	GatewayTransactionStatusVoided
+)
+
+// GatewayCharge is a card charge sent to a payment gateway
+type GatewayCharge struct {
+	Amount      float64
+	Currency    string
+	CardToken   string // Token issued by the provider's client-side card form, never the card number
+	Description string
+}
+
+// GatewayTransaction is the provider's view of a charge or refund
+type GatewayTransaction struct {
+	Reference string
+	Status    GatewayTransactionStatus
+	Amount    float64
+	Currency  string
+	CreatedAt time.Time
+}
+
+// GatewayEvent is a verified webhook notification about a transaction
+type GatewayEvent struct {
+	Reference string
+	Status    GatewayTransactionStatus
+	Amount    float64
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "testing"
+
+func TestDerivePaymentStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		paidAmount float64
+		balance    float64
+		want       PaymentStatus
+	}{
+		{"nothing paid", 0, 1000, PaymentStatusUnpaid},
+		{"nothing paid, nothing owed", 0, 0, PaymentStatusUnpaid},
+		{"refunded in full", -100, 1000, PaymentStatusUnpaid},
+		{"part paid", 400, 600, PaymentStatusPartiallyPaid},
+		{"paid in full", 1000, 0, PaymentStatusPaid},
+		{"overpaid", 1200, -200, PaymentStatusPaid},
+		{"under half a satang owed", 999.996, 0.004, PaymentStatusPaid},
+		{"half a satang owed", 999.995, 0.005, PaymentStatusPartiallyPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DerivePaymentStatus(tt.paidAmount, tt.balance); got != tt.want {
+				t.Errorf("DerivePaymentStatus(%v, %v) = %v, want %v", tt.paidAmount, tt.balance, got, tt.want)
+			}
+		})
+	}
+}
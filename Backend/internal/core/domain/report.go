@@ -0,0 +1,242 @@
+package domain
+
+import "time"
+
+// ReportInterval is the length of the periods a report is broken into
+type ReportInterval string
+
+const (
+	ReportIntervalDay   ReportInterval = "day"
+	ReportIntervalWeek  ReportInterval = "week"
+	ReportIntervalMonth ReportInterval = "month"
+)
+
+// MaxReportDays caps the date range of a single report
+const MaxReportDays = 731
+
+// KPIMetrics holds the room statistics of a period or a breakdown of a report
+type KPIMetrics struct {
+	RoomNightsAvailable int
+	RoomNightsSold      int
+	RoomRevenue         float64 // Booking totals spread evenly over their nights
+	Occupancy           float64 // Percentage of available room nights sold
+	ADR                 float64 // Average daily rate: room revenue per room night sold
+	RevPAR              float64 // Room revenue per available room night
+}
+
+// Calculate fills the ratios from the room nights and revenue
+func (m *KPIMetrics) Calculate() {
+	m.Occupancy, m.ADR, m.RevPAR = 0, 0, 0
+	if m.RoomNightsAvailable > 0 {
+		m.Occupancy = float64(m.RoomNightsSold) * 100 / float64(m.RoomNightsAvailable)
+		m.RevPAR = m.RoomRevenue / float64(m.RoomNightsAvailable)
+	}
+	if m.RoomNightsSold > 0 {
+		m.ADR = m.RoomRevenue / float64(m.RoomNightsSold)
+	}
+}
+
+// Add accumulates the room nights and revenue of other
+func (m *KPIMetrics) Add(other KPIMetrics) {
+	m.RoomNightsAvailable += other.RoomNightsAvailable
+	m.RoomNightsSold += other.RoomNightsSold
+	m.RoomRevenue += other.RoomRevenue
+}
+
+// KPIPeriod is a day, week or month of a report. Start is the first day of the period, which for
+// the first period may fall before the report range.
+type KPIPeriod struct {
+	Start             time.Time
+	PaymentsCollected float64
+	KPIMetrics
+}
+
+// KPIBreakdown is the share of a room type or a rate price over the whole report range
+type KPIBreakdown struct {
+	ID   uint64
+	Name string
+	KPIMetrics
+}
+
+// KPIReport is the occupancy, ADR and RevPAR of the hotel over a date range
+type KPIReport struct {
+	From              time.Time
+	To                time.Time
+	Interval          ReportInterval
+	PaymentsCollected float64
+	Total             KPIMetrics
+	Periods           []KPIPeriod
+	ByRoomType        []KPIBreakdown
+	ByRatePrice       []KPIBreakdown
+}
+
+// RoomTypeNights is the room nights of a room type in one report period
+type RoomTypeNights struct {
+	Period       time.Time
+	RoomTypeID   uint64
+	RoomTypeName string
+	Available    int
+	Sold         int
+	Revenue      float64
+}
+
+// RatePriceNights is the room nights sold at a rate price over a report range
+type RatePriceNights struct {
+	RatePriceID   uint64
+	RatePriceName string
+	RoomTypeID    uint64
+	Sold          int
+	Revenue       float64
+}
+
+// PeriodAmount is an amount taken in one report period
+type PeriodAmount struct {
+	Period time.Time
+	Amount float64
+}
+
+const (
+	// DefaultForecastDays is the number of nights a forecast covers by default
+	DefaultForecastDays = 90
+	// MaxForecastDays caps a forecast, and is how far ahead a forecast snapshot reaches
+	MaxForecastDays = 365
+	// DefaultPickupDays is how many days back a forecast is compared to by default
+	DefaultPickupDays = 7
+)
+
+// ForecastMetrics holds the rooms on the books for a future night
+type ForecastMetrics struct {
+	RoomsAvailable  int
+	RoomsBooked     int
+	Occupancy       float64  // Percentage of available rooms booked
+	ExpectedRevenue float64  // Booking totals spread evenly over their nights
+	PickupRooms     *int     // Rooms booked since the snapshot, nil without a snapshot
+	PickupRevenue   *float64 // Expected revenue booked since the snapshot, nil without a snapshot
+}
+
+// Calculate fills the occupancy from the rooms available and booked
+func (m *ForecastMetrics) Calculate() {
+	m.Occupancy = 0
+	if m.RoomsAvailable > 0 {
+		m.Occupancy = float64(m.RoomsBooked) * 100 / float64(m.RoomsAvailable)
+	}
+}
+
+// Add accumulates the rooms and revenue of other, and its pickup when both have one
+func (m *ForecastMetrics) Add(other ForecastMetrics) {
+	m.RoomsAvailable += other.RoomsAvailable
+	m.RoomsBooked += other.RoomsBooked
+	m.ExpectedRevenue += other.ExpectedRevenue
+	if m.PickupRooms != nil && other.PickupRooms != nil {
+		*m.PickupRooms += *other.PickupRooms
+		*m.PickupRevenue += *other.PickupRevenue
+	}
+}
+
+// ForecastRoomType is the share of a room type in a forecast night
+type ForecastRoomType struct {
+	ID   uint64
+	Name string
+	ForecastMetrics
+}
+
+// ForecastDay is a future night of a forecast
+type ForecastDay struct {
+	Date time.Time
+	ForecastMetrics
+	ByRoomType []ForecastRoomType
+}
+
+// ForecastReport is the rooms on the books for the nights from..to, with the pickup since the
+// snapshot taken PickupDays days ago
+type ForecastReport struct {
+	From         time.Time
+	To           time.Time
+	PickupDays   int
+	SnapshotDate time.Time
+	HasSnapshot  bool
+	Total        ForecastMetrics
+	Days         []ForecastDay
+}
+
+// ForecastSnapshot is the rooms of a room type on the books for a night as they stood on SnapshotDate
+type ForecastSnapshot struct {
+	SnapshotDate   time.Time
+	StayDate       time.Time
+	RoomTypeID     uint64
+	RoomsAvailable int
+	RoomsBooked    int
+	Revenue        float64
+}
+
+// CancellationFilter selects the bookings of a cancellation report by check-in date, and optionally by room
+// type, customer type and rate price
+type CancellationFilter struct {
+	From           time.Time
+	To             time.Time
+	RoomTypeID     *uint64
+	CustomerTypeID *uint64
+	RatePriceID    *uint64
+}
+
+// CancellationMetrics holds the cancellations and no-shows of a group of bookings
+type CancellationMetrics struct {
+	Bookings         int
+	Canceled         int
+	NoShows          int
+	LeadDays         int     // Sum of the days between each cancellation and its check-in
+	LostRevenue      float64 // Totals of the canceled and no-show bookings
+	CancellationRate float64 // Percentage of bookings canceled
+	NoShowRate       float64 // Percentage of the bookings not canceled that did not show
+	AvgLeadDays      float64 // Average days between cancellation and check-in
+}
+
+// Calculate fills the rates and the average lead time from the counts
+func (m *CancellationMetrics) Calculate() {
+	m.CancellationRate, m.NoShowRate, m.AvgLeadDays = 0, 0, 0
+	if m.Bookings > 0 {
+		m.CancellationRate = float64(m.Canceled) * 100 / float64(m.Bookings)
+	}
+	if kept := m.Bookings - m.Canceled; kept > 0 {
+		m.NoShowRate = float64(m.NoShows) * 100 / float64(kept)
+	}
+	if m.Canceled > 0 {
+		m.AvgLeadDays = float64(m.LeadDays) / float64(m.Canceled)
+	}
+}
+
+// Add accumulates the counts, lead days and lost revenue of other
+func (m *CancellationMetrics) Add(other CancellationMetrics) {
+	m.Bookings += other.Bookings
+	m.Canceled += other.Canceled
+	m.NoShows += other.NoShows
+	m.LostRevenue += other.LostRevenue
+	m.LeadDays += other.LeadDays
+}
+
+// CancellationBreakdown is the share of a room type, customer type or rate price of a cancellation report
+type CancellationBreakdown struct {
+	ID   uint64
+	Name string
+	CancellationMetrics
+}
+
+// CancellationReport is the cancellations and no-shows of the bookings checking in over a date range
+type CancellationReport struct {
+	Filter         CancellationFilter
+	Total          CancellationMetrics
+	ByRoomType     []CancellationBreakdown
+	ByCustomerType []CancellationBreakdown
+	ByRatePrice    []CancellationBreakdown
+}
+
+// CancellationGroup is the bookings of one room type, customer type and rate price, and how they ended
+type CancellationGroup struct {
+	RoomTypeID       uint64
+	RoomTypeName     string
+	CustomerTypeID   uint64
+	CustomerTypeName string
+	RatePriceID      uint64
+	RatePriceName    string
+	CancellationMetrics
+}
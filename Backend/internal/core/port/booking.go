@@ -1,6 +1,8 @@
 package port
 
 import (
+	"time"
+
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/gin-gonic/gin"
 )
@@ -12,9 +14,21 @@ type BookingRepository interface {
 	ListBookingsWithFilter(ctx *gin.Context, booking *domain.Booking, skip, limit uint64) ([]domain.Booking, uint64, error)
 	UpdateBooking(ctx *gin.Context, booking *domain.Booking) (*domain.Booking, error)
 	DeleteBooking(ctx *gin.Context, id uint64) error
+	// CheckOutBooking posts the room charge, transfers a company payer's share to its ledger within the
+	// company's credit limit and checks the booking out in a single transaction
+	CheckOutBooking(ctx *gin.Context, checkOut *domain.BookingCheckOut) (*domain.Booking, error)
 	GetBookingCustomerPayment(ctx *gin.Context, id uint64) (*domain.BookingCustomerPayment, error)
 	ListBookingCustomerPayments(ctx *gin.Context, skip, limit uint64) ([]domain.BookingCustomerPayment, uint64, error)
 	ListBookingCustomerPaymentsWithFilter(ctx *gin.Context, bookingCustomerPayment *domain.BookingCustomerPayment, skip, limit uint64) ([]domain.BookingCustomerPayment, uint64, error)
+	// ListBookingCustomerPaymentsBefore lists the bookings matching filter with IDs below beforeID, latest first, without counting them
+	ListBookingCustomerPaymentsBefore(ctx *gin.Context, filter *domain.BookingCustomerPayment, beforeID, limit uint64) ([]domain.BookingCustomerPayment, error)
+	// ListBookingCustomerPaymentsByCustomerID lists every booking of a customer, latest arrival first
+	ListBookingCustomerPaymentsByCustomerID(ctx *gin.Context, customerID uint64) ([]domain.BookingCustomerPayment, error)
+	ListInHouseBookings(ctx *gin.Context) ([]domain.Booking, error)
+	ListDueArrivals(ctx *gin.Context, date time.Time) ([]domain.Booking, error)
+	ListDepartures(ctx *gin.Context, date time.Time) ([]domain.Booking, error)
+	// ListBookingStatusEvents lists the status history of a booking, oldest first
+	ListBookingStatusEvents(ctx *gin.Context, bookingID uint64) ([]domain.BookingStatusEvent, error)
 }
 
 type BookingService interface {
@@ -22,10 +36,12 @@ type BookingService interface {
 	GetBooking(ctx *gin.Context, id uint64) (*domain.Booking, error)
 	ListBookings(ctx *gin.Context, skip, limit uint64) ([]domain.Booking, uint64, error)
 	ListBookingsWithFilter(ctx *gin.Context, booking *domain.Booking, skip, limit uint64) ([]domain.Booking, uint64,error)
-	CreateBookingAndPayment(ctx *gin.Context, booking *domain.Booking) (*domain.Booking, error)
+	CreateBookingAndPayment(ctx *gin.Context, booking *domain.Booking, deposit *domain.Payment) (*domain.Booking, error)
 	UpdateBooking(ctx *gin.Context, booking *domain.Booking) (*domain.Booking, error)
 	DeleteBooking(ctx *gin.Context, id uint64) error
+	CheckOutBooking(ctx *gin.Context, id, companyID uint64, overrideBalance bool) (*domain.Booking, error)
 	GetBookingCustomerPayment(ctx *gin.Context, id uint64) (*domain.BookingCustomerPayment, error)
 	ListBookingCustomerPayments(ctx *gin.Context, skip, limit uint64) ([]domain.BookingCustomerPayment, uint64, error)
 	ListBookingCustomerPaymentsWithFilter(ctx *gin.Context, bookingCustomerPayment *domain.BookingCustomerPayment, skip, limit uint64) ([]domain.BookingCustomerPayment, uint64, error)
+	ListBookingStatusEvents(ctx *gin.Context, bookingID uint64) ([]domain.BookingStatusEvent, error)
 }
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type BookingPayerRepository interface {
+	CreateBookingPayer(ctx *gin.Context, payer *domain.BookingPayer) (*domain.BookingPayer, error)
+	ListBookingPayersByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.BookingPayer, error)
+	DeleteBookingPayer(ctx *gin.Context, bookingID, id uint64) error
+}
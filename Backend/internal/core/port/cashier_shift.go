@@ -0,0 +1,26 @@
+package port
+
+import (
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type CashierShiftRepository interface {
+	CreateShift(ctx *gin.Context, shift *domain.CashierShift) (*domain.CashierShift, error)
+	GetShiftByID(ctx *gin.Context, id uint64) (*domain.CashierShift, error)
+	GetOpenShiftByUserID(ctx *gin.Context, userID uint64) (*domain.CashierShift, error)
+	ListShifts(ctx *gin.Context, userID, skip, limit uint64) ([]domain.CashierShift, uint64, error)
+	// CloseShift marks the shift closed and stores its counts in a single transaction
+	CloseShift(ctx *gin.Context, shift *domain.CashierShift, counts []domain.ShiftCount) (*domain.CashierShift, error)
+	ListShiftCounts(ctx *gin.Context, shiftID uint64) ([]domain.ShiftCount, error)
+	GetShiftPaymentTotals(ctx *gin.Context, shiftID uint64) ([]domain.ShiftPaymentTotal, error)
+}
+
+type CashierShiftService interface {
+	OpenShift(ctx *gin.Context, openingFloat float64) (*domain.CashierShift, error)
+	GetCurrentShift(ctx *gin.Context) (*domain.CashierShift, error)
+	ListShifts(ctx *gin.Context, userID, skip, limit uint64) ([]domain.CashierShift, uint64, error)
+	// CloseShift closes the user's open shift with the amounts counted per payment method and currency and returns the close-out report
+	CloseShift(ctx *gin.Context, counted map[domain.ShiftTender]float64, note string) (*domain.ShiftReport, error)
+	GetShiftReport(ctx *gin.Context, shiftID uint64) (*domain.ShiftReport, error)
+}
@@ -0,0 +1,34 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type CompanyRepository interface {
+	CreateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error)
+	GetCompanyByID(ctx *gin.Context, id uint64) (*domain.Company, error)
+	ListCompanies(ctx *gin.Context, skip, limit uint64) ([]domain.Company, uint64, error)
+	UpdateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error)
+	CreateLedgerEntry(ctx *gin.Context, entry *domain.CompanyLedgerEntry) (*domain.CompanyLedgerEntry, error)
+	ListLedgerEntriesByCompanyID(ctx *gin.Context, companyID uint64) ([]domain.CompanyLedgerEntry, error)
+	// GetCompanyBalance returns the company's outstanding balance, the sum of its ledger entries
+	GetCompanyBalance(ctx *gin.Context, companyID uint64) (float64, error)
+	// ListOpenInvoices returns the ledger invoices issued up to asOf that are not fully paid, of one company or of all when companyID is zero
+	ListOpenInvoices(ctx *gin.Context, companyID uint64, asOf time.Time) ([]domain.CompanyInvoice, error)
+	GetInvoiceEntryByID(ctx *gin.Context, id uint64) (*domain.CompanyInvoice, error)
+}
+
+type CompanyService interface {
+	CreateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error)
+	GetCompany(ctx *gin.Context, id uint64) (*domain.Company, error)
+	ListCompanies(ctx *gin.Context, skip, limit uint64) ([]domain.Company, uint64, error)
+	UpdateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error)
+	GetLedger(ctx *gin.Context, companyID uint64) (*domain.CompanyLedger, error)
+	ListOpenInvoices(ctx *gin.Context, companyID uint64) ([]domain.CompanyInvoice, error)
+	// RecordPayment records money received from a company against one of its outstanding invoices
+	RecordPayment(ctx *gin.Context, companyID, invoiceEntryID uint64, amount float64, method domain.PaymentMethod, reference string) (*domain.CompanyLedgerEntry, error)
+	GetAgingReport(ctx *gin.Context, asOf time.Time) ([]domain.AgingRow, error)
+}
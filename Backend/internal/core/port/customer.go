@@ -12,6 +12,14 @@ type CustomerRepository interface {
 	UpdateCustomer(ctx *gin.Context, customer *domain.Customer) (*domain.Customer, error)
 	DeleteCustomer(ctx *gin.Context, id uint64) error
 	ListCustomersWithFilter(ctx *gin.Context, customer *domain.Customer, skip, limit uint64) ([]domain.Customer, uint64, error)
+	// ListCustomersBefore lists the customers matching the filter with IDs below beforeID, latest first, without counting them
+	ListCustomersBefore(ctx *gin.Context, customer *domain.Customer, beforeID, limit uint64) ([]domain.Customer, error)
+	// SearchCustomers returns the customers matching search, most relevant first
+	SearchCustomers(ctx *gin.Context, search domain.CustomerSearch, skip, limit uint64) ([]domain.CustomerMatch, uint64, error)
+	// ListCustomerDuplicates returns the pairs of customers scoring at least minScore as duplicates, highest first
+	ListCustomerDuplicates(ctx *gin.Context, minScore float64, skip, limit uint64) ([]domain.CustomerDuplicate, uint64, error)
+	// MergeCustomers moves everything of the duplicate to survivor, deletes it and saves survivor's details
+	MergeCustomers(ctx *gin.Context, survivor *domain.Customer, duplicateID uint64) (*domain.Customer, error)
 }
 
 type CustomerService interface {
@@ -21,4 +29,12 @@ type CustomerService interface {
 	UpdateCustomer(ctx *gin.Context, customer *domain.Customer) (*domain.Customer, error)
 	DeleteCustomer(ctx *gin.Context, id uint64) error
 	ListCustomersWithFilter(ctx *gin.Context, customer *domain.Customer, skip, limit uint64) ([]domain.Customer, uint64, error)
+	// SearchCustomers finds customers by name, phone, email or identity number, tolerating case, spacing and typos
+	SearchCustomers(ctx *gin.Context, query string, skip, limit uint64) ([]domain.CustomerMatch, uint64, error)
+	// ListCustomerDuplicates lists the pairs of customers likely to be the same person
+	ListCustomerDuplicates(ctx *gin.Context, minScore float64, skip, limit uint64) ([]domain.CustomerDuplicate, uint64, error)
+	// MergeCustomers merges the duplicate into survivor, moving its stay history over
+	MergeCustomers(ctx *gin.Context, survivorID, duplicateID uint64) (*domain.Customer, error)
+	// GetCustomerHistory returns every booking of a customer with their stay and spending stats
+	GetCustomerHistory(ctx *gin.Context, id uint64) (*domain.CustomerHistory, error)
 }
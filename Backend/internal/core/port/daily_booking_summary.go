@@ -1,22 +1,44 @@
 package port
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"time"
 )
 
+// ClosedPeriodRepository is an interface for reading the days closed by a confirmed daily summary
+type ClosedPeriodRepository interface {
+	// IsDayClosed reports whether the summary of date has been confirmed
+	IsDayClosed(ctx context.Context, date time.Time) (bool, error)
+	// ListClosedBookingItems lists the items of confirmed summaries the booking is listed in
+	ListClosedBookingItems(ctx *gin.Context, bookingID uint64) ([]domain.DailyBookingSummaryItem, error)
+}
+
 type DailyBookingSummaryRepository interface {
-	CreateDailyBookingSummary(ctx *gin.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error)
+	ClosedPeriodRepository
+	CreateDailyBookingSummary(ctx context.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error)
 	GetDailyBookingSummaryByDate(ctx *gin.Context, date string) (*domain.DailyBookingSummary, error)
 	ListDailyBookingSummaries(ctx *gin.Context, skip, limit uint64) ([]domain.DailyBookingSummary, uint64, error)
+	// ListDailyBookingSummariesBefore lists the summaries of dates before before, latest first, without counting them
+	ListDailyBookingSummariesBefore(ctx *gin.Context, before time.Time, limit uint64) ([]domain.DailyBookingSummary, error)
+	// UpdateDailyBookingSummary saves a summary; confirming it locks again a day reopened after the night audit
 	UpdateDailyBookingSummary(ctx *gin.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error)
+	// ReopenDailyBookingSummary moves a confirmed summary back to checked and lifts the night audit's lock on its day
+	ReopenDailyBookingSummary(ctx *gin.Context, summary *domain.DailyBookingSummary) (*domain.DailyBookingSummary, error)
 	DeleteDailyBookingSummary(ctx *gin.Context, date string) error
+	// ListBookingActivity lists the bookings created, completed and canceled on date from their status history
+	ListBookingActivity(ctx context.Context, date time.Time) ([]domain.DailyBookingSummaryItem, error)
+	// GetCompletedRevenue returns the total of the bookings first completed on date
+	GetCompletedRevenue(ctx context.Context, date time.Time) (float64, error)
 }
 
 type DailyBookingSummaryService interface {
-	GenerateDailySummary(ctx *gin.Context, date time.Time) (*domain.DailyBookingSummary, error)
+	// GenerateDailySummary generates the summary of date on behalf of the user actorID, who the change is logged to
+	GenerateDailySummary(ctx context.Context, actorID uint64, date time.Time) (*domain.DailyBookingSummary, error)
 	UpdateSummaryStatus(ctx *gin.Context, date time.Time, status domain.SummaryStatus) (*domain.DailyBookingSummary, error)
+	// ReopenSummary moves a confirmed summary back to checked, opening its day to changes again
+	ReopenSummary(ctx *gin.Context, date time.Time) (*domain.DailyBookingSummary, error)
 	GetSummaryByDate(ctx *gin.Context, date time.Time) (*domain.DailyBookingSummary, error)
 	ListSummaries(ctx *gin.Context, skip, limit uint64) ([]domain.DailyBookingSummary, uint64, error)
 }
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// DocumentRenderer is an interface for rendering printable booking documents
+type DocumentRenderer interface {
+	// RenderBookingDocument renders an invoice or receipt as a PDF file
+	RenderBookingDocument(document *domain.BookingDocument) ([]byte, error)
+}
+
+type DocumentService interface {
+	GetBookingDocumentPDF(ctx *gin.Context, bookingID, payerID uint64, documentType domain.DocumentType) ([]byte, error)
+	GetInvoicePDF(ctx *gin.Context, invoiceID uint64) ([]byte, error)
+}
@@ -0,0 +1,26 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateRepository interface {
+	CreateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	GetExchangeRateByID(ctx *gin.Context, id uint64) (*domain.ExchangeRate, error)
+	GetEffectiveExchangeRate(ctx *gin.Context, currency string, date time.Time) (*domain.ExchangeRate, error)
+	ListExchangeRates(ctx *gin.Context, currency string, skip, limit uint64) ([]domain.ExchangeRate, uint64, error)
+	UpdateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	DeleteExchangeRate(ctx *gin.Context, id uint64) error
+}
+
+type ExchangeRateService interface {
+	CreateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	GetExchangeRate(ctx *gin.Context, id uint64) (*domain.ExchangeRate, error)
+	GetEffectiveExchangeRate(ctx *gin.Context, currency string, date time.Time) (*domain.ExchangeRate, error)
+	ListExchangeRates(ctx *gin.Context, currency string, skip, limit uint64) ([]domain.ExchangeRate, uint64, error)
+	UpdateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	DeleteExchangeRate(ctx *gin.Context, id uint64) error
+}
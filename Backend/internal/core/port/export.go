@@ -0,0 +1,33 @@
+package port
+
+import (
+	"io"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// TableWriter writes a table to a spreadsheet file one row at a time
+type TableWriter interface {
+	// WriteRow writes a row of strings, numbers, times or nils
+	WriteRow(values ...interface{}) error
+	// Close finishes the file. Nothing written before Close is guaranteed to be flushed.
+	Close() error
+}
+
+// TableWriterFactory is an interface for creating spreadsheet writers
+type TableWriterFactory interface {
+	NewTableWriter(w io.Writer, format domain.ExportFormat, sheetName string) (TableWriter, error)
+}
+
+// ExportService is an interface for streaming lists and reports as spreadsheets. Each export takes the
+// same filters as the matching list endpoint and reads the rows in batches of ExportBatchSize.
+type ExportService interface {
+	ExportBookingCustomerPayments(ctx *gin.Context, filter *domain.BookingCustomerPayment, format domain.ExportFormat, w io.Writer) error
+	ExportPayments(ctx *gin.Context, format domain.ExportFormat, w io.Writer) error
+	ExportCustomers(ctx *gin.Context, filter *domain.Customer, format domain.ExportFormat, w io.Writer) error
+	ExportLogs(ctx *gin.Context, format domain.ExportFormat, w io.Writer) error
+	ExportDailySummaries(ctx *gin.Context, format domain.ExportFormat, w io.Writer) error
+	ExportKPIReport(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval, format domain.ExportFormat, w io.Writer) error
+}
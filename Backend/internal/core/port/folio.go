@@ -0,0 +1,23 @@
+package port
+
+import (
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type FolioRepository interface {
+	CreateFolioCharge(ctx *gin.Context, charge *domain.FolioCharge) (*domain.FolioCharge, error)
+	GetFolioChargeByID(ctx *gin.Context, id uint64) (*domain.FolioCharge, error)
+	ListFolioChargesByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.FolioCharge, error)
+}
+
+type FolioService interface {
+	GetFolio(ctx *gin.Context, bookingID uint64) (*domain.Folio, error)
+	PostCharge(ctx *gin.Context, charge *domain.FolioCharge) (*domain.FolioCharge, error)
+	PostAdjustment(ctx *gin.Context, bookingID uint64, amount float64, description string) (*domain.FolioCharge, error)
+	// ListSplitFolios returns the guest's folio and the folio of each booking payer
+	ListSplitFolios(ctx *gin.Context, bookingID uint64) ([]domain.Folio, error)
+	AddPayer(ctx *gin.Context, payer *domain.BookingPayer) (*domain.BookingPayer, error)
+	ListPayers(ctx *gin.Context, bookingID uint64) ([]domain.BookingPayer, error)
+	RemovePayer(ctx *gin.Context, bookingID, payerID uint64) error
+}
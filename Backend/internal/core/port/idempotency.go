@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type IdempotencyRepository interface {
+	// CreateIdempotencyKey stores a new key, taking over the user's key of the same name once it has expired,
+	// or once its lease ran out for the same request. It returns ErrConflictingData while the key is still in use.
+	CreateIdempotencyKey(ctx *gin.Context, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error)
+	GetIdempotencyKey(ctx *gin.Context, userID uint64, key string) (*domain.IdempotencyKey, error)
+	// CompleteIdempotencyKey stores the response of a claimed key, or returns ErrDataNotFound when a retry took it over
+	CompleteIdempotencyKey(ctx *gin.Context, key *domain.IdempotencyKey) error
+	// DeleteIdempotencyKey deletes a claimed key, unless a retry took it over
+	DeleteIdempotencyKey(ctx *gin.Context, key *domain.IdempotencyKey) error
+	// DeleteExpiredIdempotencyKeys deletes the keys claimed before before and returns how many there were
+	DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int, error)
+}
+
+type IdempotencyService interface {
+	// StartRequest claims an idempotency key for a request. A key already used for the same request is
+	// returned completed, with the response to replay.
+	StartRequest(ctx *gin.Context, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error)
+	// CompleteRequest stores the response of a claimed key
+	CompleteRequest(ctx *gin.Context, key *domain.IdempotencyKey, status int, body []byte) error
+	// ReleaseRequest frees a claimed key so the request can be retried with it
+	ReleaseRequest(ctx *gin.Context, key *domain.IdempotencyKey) error
+	// DeleteExpiredKeys deletes the keys past their TTL as of asOf and returns how many there were
+	DeleteExpiredKeys(ctx context.Context, asOf time.Time) (int, error)
+}
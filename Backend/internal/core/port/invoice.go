@@ -0,0 +1,31 @@
+package port
+
+import (
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceRepository interface {
+	CreateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error)
+	GetInvoiceSeriesByID(ctx *gin.Context, id uint64) (*domain.InvoiceSeries, error)
+	GetDefaultInvoiceSeries(ctx *gin.Context, invoiceType domain.InvoiceType) (*domain.InvoiceSeries, error)
+	ListInvoiceSeries(ctx *gin.Context) ([]domain.InvoiceSeries, error)
+	UpdateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error)
+	// IssueInvoice allocates the next number of the series and stores the invoice and its lines in a single
+	// transaction. Issuing a credit note marks the tax invoice it cancels as cancelled.
+	IssueInvoice(ctx *gin.Context, invoice *domain.Invoice, series *domain.InvoiceSeries) (*domain.Invoice, error)
+	GetInvoiceByID(ctx *gin.Context, id uint64) (*domain.Invoice, error)
+	ListInvoicesByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.Invoice, error)
+	ListInvoiceCharges(ctx *gin.Context, invoiceID uint64) ([]domain.FolioCharge, error)
+	ListInvoicePayments(ctx *gin.Context, invoiceID uint64) ([]domain.Payment, error)
+}
+
+type InvoiceService interface {
+	CreateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error)
+	ListInvoiceSeries(ctx *gin.Context) ([]domain.InvoiceSeries, error)
+	UpdateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error)
+	IssueInvoice(ctx *gin.Context, bookingID, payerID, seriesID uint64) (*domain.Invoice, error)
+	IssueCreditNote(ctx *gin.Context, invoiceID uint64, reason string) (*domain.Invoice, error)
+	GetInvoice(ctx *gin.Context, id uint64) (*domain.Invoice, error)
+	ListBookingInvoices(ctx *gin.Context, bookingID uint64) ([]domain.Invoice, error)
+}
@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type JobRunRepository interface {
+	CreateJobRun(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error)
+	// FinishJobRun saves the outcome of a running job
+	FinishJobRun(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error)
+	// ListJobRuns lists the runs of a job, latest first. An empty jobName lists the runs of every job.
+	ListJobRuns(ctx *gin.Context, jobName string, skip, limit uint64) ([]domain.JobRun, uint64, error)
+}
+
+type JobService interface {
+	// GenerateDailySummary generates the summary of date as the system user, outside of any request
+	GenerateDailySummary(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error)
+	// SnapshotForecast stores the rooms on the books as of date as the system user, outside of any request
+	SnapshotForecast(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error)
+	// ExpireLoyaltyPoints lapses the loyalty points that expired by date as the system user, outside of any request
+	ExpireLoyaltyPoints(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error)
+	// CleanUpIdempotencyKeys deletes the expired idempotency keys as the system user, outside of any request
+	CleanUpIdempotencyKeys(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error)
+	ListJobRuns(ctx *gin.Context, jobName string, skip, limit uint64) ([]domain.JobRun, uint64, error)
+}
@@ -1,15 +1,22 @@
 package port
 
 import (
+	"context"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/gin-gonic/gin"
 )
 
 type LogRepository interface {
-	CreateLog(ctx *gin.Context, log *domain.Log) (*domain.Log, error)
+	CreateLog(ctx context.Context, log *domain.Log) (*domain.Log, error)
 	GetLogs(ctx *gin.Context, skip, limit uint64) ([]domain.Log, uint64, error)
+	// GetLogsBefore returns the logs with IDs below beforeID, latest first, without counting them
+	GetLogsBefore(ctx *gin.Context, beforeID, limit uint64) ([]domain.Log, error)
+	// ListStaffActivity returns the work each user logged per day of filter
+	ListStaffActivity(ctx *gin.Context, filter *domain.StaffActivityFilter) ([]domain.StaffActivity, error)
 }
 
 type LogService interface {
 	GetLogs(ctx *gin.Context, skip, limit uint64) ([]domain.Log, uint64, error)
+	// GetStaffActivity reports the bookings, check-ins, cancellations, payments and deletes each user logged per day
+	GetStaffActivity(ctx *gin.Context, filter *domain.StaffActivityFilter) (*domain.StaffActivityReport, error)
 }
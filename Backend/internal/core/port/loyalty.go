@@ -0,0 +1,47 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type LoyaltyRepository interface {
+	// CreateTransaction adds a transaction to a customer's ledger. A second earn for a booking returns ErrConflictingData.
+	CreateTransaction(ctx *gin.Context, transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error)
+	// RedeemPoints adds a redemption of the transaction's negative points, or returns ErrInsufficientPoints
+	// when the customer's balance does not cover them
+	RedeemPoints(ctx *gin.Context, transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error)
+	// GetBalance returns the customer's points balance and the points they earned since qualifyingFrom
+	GetBalance(ctx *gin.Context, customerID uint64, qualifyingFrom time.Time) (int, int, error)
+	ListTransactionsByCustomerID(ctx *gin.Context, customerID, skip, limit uint64) ([]domain.LoyaltyTransaction, uint64, error)
+	// GetRedemptionByPaymentID returns the redemption that paid a loyalty points payment
+	GetRedemptionByPaymentID(ctx *gin.Context, paymentID uint64) (*domain.LoyaltyTransaction, error)
+	// GetReinstatedPoints returns the points already given back for the reversals of a loyalty points payment
+	GetReinstatedPoints(ctx *gin.Context, paymentID uint64) (int, error)
+	// GetEarnByBookingID returns the points a booking earned, or ErrDataNotFound when it earned none
+	GetEarnByBookingID(ctx *gin.Context, bookingID uint64) (*domain.LoyaltyTransaction, error)
+	// GetEarnAdjustedPoints returns the earned points of a booking already taken back, as a negative number
+	GetEarnAdjustedPoints(ctx *gin.Context, bookingID uint64) (int, error)
+	// ExpirePoints lapses the earned points that expired by asOf and are still unspent, of one customer or of all
+	// when customerID is zero, and returns the number of customers whose points expired
+	ExpirePoints(ctx context.Context, asOf time.Time, customerID uint64) (int, error)
+}
+
+type LoyaltyService interface {
+	GetAccount(ctx *gin.Context, customerID uint64) (*domain.LoyaltyAccount, error)
+	ListTransactions(ctx *gin.Context, customerID, skip, limit uint64) ([]domain.LoyaltyTransaction, uint64, error)
+	// EarnPoints credits the guest of a completed booking with points for what they paid, once per booking
+	EarnPoints(ctx *gin.Context, bookingID uint64) (*domain.LoyaltyTransaction, error)
+	// RedeemForPayment spends the points paying a loyalty points payment from the booking guest's balance
+	RedeemForPayment(ctx *gin.Context, payment *domain.Payment) (*domain.LoyaltyTransaction, error)
+	// ReinstateForReversal gives back the points of the share of a loyalty points payment that a refund or void reverses
+	ReinstateForReversal(ctx *gin.Context, original, reversal *domain.Payment) (*domain.LoyaltyTransaction, error)
+	// ClawBackForReversal takes back the points a completed booking earned on the money a refund or void gave back
+	ClawBackForReversal(ctx *gin.Context, reversal *domain.Payment) (*domain.LoyaltyTransaction, error)
+	// RedeemDiscount spends the guest's points on a discount posted to the booking folio
+	RedeemDiscount(ctx *gin.Context, bookingID uint64, points int) (*domain.LoyaltyTransaction, error)
+	ExpirePoints(ctx context.Context, asOf time.Time) (int, error)
+}
@@ -0,0 +1,36 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// BusinessDateRepository is an interface for reading the hotel's business date
+type BusinessDateRepository interface {
+	// GetBusinessDate returns the business date that is currently open
+	GetBusinessDate(ctx *gin.Context) (time.Time, error)
+	// IsDayLocked reports whether a completed night audit has locked date
+	IsDayLocked(ctx context.Context, date time.Time) (bool, error)
+}
+
+type NightAuditRepository interface {
+	BusinessDateRepository
+	// StartNightAudit records a running audit for its business date. A date can only be audited again
+	// after a failed run or a run that was interrupted.
+	StartNightAudit(ctx *gin.Context, audit *domain.NightAudit) (*domain.NightAudit, error)
+	// CompleteNightAudit saves the audit results and rolls the business date forward to the next day
+	CompleteNightAudit(ctx *gin.Context, audit *domain.NightAudit) (*domain.NightAudit, error)
+	FailNightAudit(ctx *gin.Context, audit *domain.NightAudit) error
+	GetNightAuditByDate(ctx *gin.Context, businessDate time.Time) (*domain.NightAudit, error)
+	ListNightAudits(ctx *gin.Context, skip, limit uint64) ([]domain.NightAudit, uint64, error)
+}
+
+type NightAuditService interface {
+	GetBusinessDate(ctx *gin.Context) (time.Time, error)
+	// RunNightAudit closes the current business date and opens the next one
+	RunNightAudit(ctx *gin.Context) (*domain.NightAudit, error)
+	ListNightAudits(ctx *gin.Context, skip, limit uint64) ([]domain.NightAudit, uint64, error)
+}
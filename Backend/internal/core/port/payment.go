@@ -1,6 +1,9 @@
 package port
 
 import (
+	"context"
+	"time"
+
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/gin-gonic/gin"
 )
@@ -8,15 +11,32 @@ import (
 type PaymentRepository interface {
 	CreatePayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error)
 	GetPaymentByID(ctx *gin.Context, id uint64) (*domain.Payment, error)
+	GetPaymentByGatewayReference(ctx *gin.Context, reference string) (*domain.Payment, error)
 	ListPayments(ctx *gin.Context, skip, limit uint64) ([]domain.Payment, uint64, error)
+	// ListPaymentsBefore lists the payments with IDs below beforeID, latest first, without counting them
+	ListPaymentsBefore(ctx *gin.Context, beforeID, limit uint64) ([]domain.Payment, error)
+	ListPaymentsByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.Payment, error)
 	UpdatePayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error)
 	DeletePayment(ctx *gin.Context, id uint64) error
+	GetPaymentTotalsByCurrency(ctx *gin.Context, from, to time.Time) ([]domain.PaymentCurrencyTotal, error)
+	GetReversedAmount(ctx *gin.Context, originalPaymentID uint64) (float64, error)
+	// CreateReversal adds a refund or void while the original payment is locked, so concurrent reversals
+	// cannot together exceed it
+	CreateReversal(ctx *gin.Context, reversal *domain.Payment) (*domain.Payment, error)
+	GetReversalTotal(ctx context.Context, from, to time.Time) (float64, error)
 }
 
 type PaymentService interface {
-	ProcessPayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error)
+	ProcessPayment(ctx *gin.Context, payment *domain.Payment, cardToken string) (*domain.Payment, error)
+	CapturePayment(ctx *gin.Context, id uint64) (*domain.Payment, error)
 	GetPayment(ctx *gin.Context, id uint64) (*domain.Payment, error)
 	ListPayments(ctx *gin.Context, skip, limit uint64) ([]domain.Payment, uint64, error)
-	UpdatePayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error)
+	ListPaymentsByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.Payment, error)
+	// UpdatePayment edits a payment, charging cardToken when it settles a card payment
+	UpdatePayment(ctx *gin.Context, payment *domain.Payment, cardToken string) (*domain.Payment, error)
 	DeletePayment(ctx *gin.Context, id uint64) error
+	GetPaymentTotalsByCurrency(ctx *gin.Context, from, to time.Time) ([]domain.PaymentCurrencyTotal, error)
+	RefundPayment(ctx *gin.Context, id uint64, amount float64, reason domain.ReversalReason, note string) (*domain.Payment, error)
+	VoidPayment(ctx *gin.Context, id uint64, reason domain.ReversalReason, note string) (*domain.Payment, error)
+	HandleGatewayWebhook(ctx *gin.Context, payload []byte, signature string) error
 }
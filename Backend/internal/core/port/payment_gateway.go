@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+)
+
+// PaymentGateway is an interface for charging cards through an external payment processor.
+// Each provider (the local fake, Omise, Stripe, ...) lives in its own package under adapter/payment.
+type PaymentGateway interface {
+	// Name returns the provider name stored on payments processed by the gateway
+	Name() string
+	// Authorize places a hold on the card for the charge amount
+	Authorize(ctx context.Context, charge *domain.GatewayCharge) (*domain.GatewayTransaction, error)
+	// Capture collects up to the authorized amount of a transaction
+	Capture(ctx context.Context, reference string, amount float64) (*domain.GatewayTransaction, error)
+	// Void releases the hold of an authorized transaction that has not been captured
+	Void(ctx context.Context, reference string) (*domain.GatewayTransaction, error)
+	// Refund returns part or all of a captured amount to the card
+	Refund(ctx context.Context, reference string, amount float64) (*domain.GatewayTransaction, error)
+	// VerifyWebhook checks the signature of a webhook payload and parses the event it carries
+	VerifyWebhook(payload []byte, signature string) (*domain.GatewayEvent, error)
+}
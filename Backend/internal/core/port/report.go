@@ -0,0 +1,36 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportRepository interface {
+	// ListRoomTypeNights returns the room nights available and sold per room type and period for the nights from..to
+	ListRoomTypeNights(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval) ([]domain.RoomTypeNights, error)
+	// ListRatePriceNights returns the room nights sold per rate price for the nights from..to
+	ListRatePriceNights(ctx *gin.Context, from, to time.Time) ([]domain.RatePriceNights, error)
+	// ListPaymentsCollected returns the payments taken per period from..to, net of refunds and voids
+	ListPaymentsCollected(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval) ([]domain.PeriodAmount, error)
+	// SaveForecastSnapshot stores the rooms on the books per room type for the nights from..to as the snapshot of
+	// snapshotDate, replacing an earlier snapshot of that date
+	SaveForecastSnapshot(ctx context.Context, snapshotDate, from, to time.Time) error
+	// ListForecastSnapshot returns the snapshot of snapshotDate for the nights from..to
+	ListForecastSnapshot(ctx *gin.Context, snapshotDate, from, to time.Time) ([]domain.ForecastSnapshot, error)
+	// ListCancellationGroups returns the bookings of filter and how they ended, per room type, customer type and
+	// rate price
+	ListCancellationGroups(ctx *gin.Context, filter *domain.CancellationFilter) ([]domain.CancellationGroup, error)
+}
+
+type ReportService interface {
+	GetKPIReport(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval) (*domain.KPIReport, error)
+	// GetForecastReport reports the rooms on the books for the next days nights, starting today
+	GetForecastReport(ctx *gin.Context, days, pickupDays int) (*domain.ForecastReport, error)
+	// SnapshotForecast stores the rooms on the books as of date, for the pickup of later forecasts
+	SnapshotForecast(ctx context.Context, date time.Time) error
+	// GetCancellationReport reports the cancellations and no-shows of the bookings of filter
+	GetCancellationReport(ctx *gin.Context, filter *domain.CancellationFilter) (*domain.CancellationReport, error)
+}
@@ -1,6 +1,7 @@
 package port
 
 import (
+	"context"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/gin-gonic/gin"
 )
@@ -8,7 +9,7 @@ import (
 type UserRepository interface {
 	CreateUser(ctx  *gin.Context, user *domain.User) (*domain.User, error)
 	GetUserByID(ctx *gin.Context, id uint64) (*domain.User, error)
-	GetUserByUserName(ctx *gin.Context, userName string) (*domain.User, error)
+	GetUserByUserName(ctx context.Context, userName string) (*domain.User, error)
 	ListUsers(ctx *gin.Context, skip, limit uint64) ([]domain.User, uint64, error)
 	UpdateUser(ctx *gin.Context, user *domain.User) (*domain.User, error)
 	DeleteUser(ctx *gin.Context, id uint64) error
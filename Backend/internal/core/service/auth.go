@@ -47,3 +47,14 @@ func (as *AuthService) Login(ctx *gin.Context, userName, password string) (strin
 
 	return accessToken, domain.UserRole(user.Role), nil
 }
+
+
+// isAdmin reports whether the authenticated user of the request has the admin role
+func isAdmin(ctx *gin.Context) bool {
+	role, exists := ctx.Get("userRole")
+	if !exists {
+		return false
+	}
+	userRole, ok := role.(domain.UserRole)
+	return ok && userRole == domain.UserRoleAdmin
+}
@@ -6,19 +6,34 @@ import (
 	"fmt"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
 	"github.com/gin-gonic/gin"
 )
 
 type BookingService struct {
 	repo       port.BookingRepository
 	paymentRepo port.PaymentRepository
+	folioRepo   port.FolioRepository
+	payerRepo   port.BookingPayerRepository
+	companyRepo port.CompanyRepository
+	shiftRepo   port.CashierShiftRepository
+	dateRepo    port.BusinessDateRepository
+	periodRepo  port.ClosedPeriodRepository
+	loyaltyService port.LoyaltyService
 	logRepo     port.LogRepository
 }
 
-func NewBookingService(repo port.BookingRepository, paymentRepo port.PaymentRepository, logRepo port.LogRepository) *BookingService {
+func NewBookingService(repo port.BookingRepository, paymentRepo port.PaymentRepository, folioRepo port.FolioRepository, payerRepo port.BookingPayerRepository, companyRepo port.CompanyRepository, shiftRepo port.CashierShiftRepository, dateRepo port.BusinessDateRepository, periodRepo port.ClosedPeriodRepository, loyaltyService port.LoyaltyService, logRepo port.LogRepository) *BookingService {
 	return &BookingService{
 		repo,
 		paymentRepo,
+		folioRepo,
+		payerRepo,
+		companyRepo,
+		shiftRepo,
+		dateRepo,
+		periodRepo,
+		loyaltyService,
 		logRepo,
 	}
 }
@@ -28,6 +43,17 @@ func (bs *BookingService) CreateBooking(ctx *gin.Context, booking *domain.Bookin
 		return nil, domain.ErrInvalidData
 	}
 
+	// The booking is listed under the summary of the business date
+	now, err := businessNow(ctx, bs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePostingOpen(ctx, bs.dateRepo, bs.periodRepo, now); err != nil {
+		return nil, err
+	}
+	booking.CreatedAt = &now
+	booking.UpdatedAt = &now
+
 	// Set initial status to Pending if not provided
 	if booking.Status == 0 {
 		booking.Status = domain.BookingStatusUncheckIn
@@ -60,12 +86,44 @@ func (bs *BookingService) CreateBooking(ctx *gin.Context, booking *domain.Bookin
 	return createdBooking, nil
 }
 
-func (bs *BookingService) CreateBookingAndPayment(ctx *gin.Context, booking *domain.Booking) (*domain.Booking, error) {
+// CreateBookingAndPayment creates a booking with an unpaid balance payment. When a deposit is
+// given it is recorded as paid and the balance payment only covers the remainder.
+func (bs *BookingService) CreateBookingAndPayment(ctx *gin.Context, booking *domain.Booking, deposit *domain.Payment) (*domain.Booking, error) {
 	if booking.CustomerID == 0 || booking.RatePriceId == 0 || booking.CheckInDate == nil || booking.CheckOutDate == nil || booking.TotalAmount <= 0 {
 		return nil, domain.ErrInvalidData
 	}
+	// Points are only redeemed and cards only charged by PaymentService, so a deposit here is taken in cash or by transfer
+	if deposit != nil && (deposit.Amount <= 0 || deposit.Amount > booking.TotalAmount || deposit.PaymentMethod == domain.PaymentMethodNotSpecified || deposit.PaymentMethod.IsNonMonetary() || deposit.PaymentMethod.IsCard()) {
+		return nil, domain.ErrInvalidData
+	}
+
+	now, err := businessNow(ctx, bs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePostingOpen(ctx, bs.dateRepo, bs.periodRepo, now); err != nil {
+		return nil, err
+	}
+	booking.CreatedAt = &now
+	booking.UpdatedAt = &now
+	var depositPayment *domain.Payment
+	if deposit != nil {
+		depositPayment = &domain.Payment{
+			Amount:        deposit.Amount,
+			PaymentMethod: deposit.PaymentMethod,
+			PaymentDate:   &now,
+			Status:        domain.PaymentStatusPaid,
+			Currency:      domain.BaseCurrency,
+			ExchangeRate:  1,
+			BaseAmount:    deposit.Amount,
+			Type:          domain.PaymentTypeDeposit,
+		}
+		// Check the cashier has an open shift before anything is created
+		if err := tagPayment(ctx, bs.shiftRepo, depositPayment); err != nil {
+			return nil, err
+		}
+	}
 
-	now := time.Now()
 	// Set initial status to Pending if not provided
 	if booking.Status == 0 {
 		booking.Status = domain.BookingStatusUncheckIn
@@ -80,21 +138,40 @@ func (bs *BookingService) CreateBookingAndPayment(ctx *gin.Context, booking *dom
 		return nil, domain.ErrInternal
 	}
 
-	// Create the payment record
-	payment := &domain.Payment{
-		BookingID:     createdBooking.ID,
-		Amount:        booking.TotalAmount,
-		PaymentMethod: domain.PaymentMethodNotSpecified,
-		PaymentDate:   &now,
-		Status:        domain.PaymentStatusUnpaid,
+	// Create the payment records
+	var payments []*domain.Payment
+	remaining := booking.TotalAmount
+	if depositPayment != nil {
+		depositPayment.BookingID = createdBooking.ID
+		payments = append(payments, depositPayment)
+		remaining = util.RoundAmount(remaining - deposit.Amount)
+	}
+	if remaining > 0 {
+		payments = append(payments, &domain.Payment{
+			BookingID:     createdBooking.ID,
+			Amount:        remaining,
+			PaymentMethod: domain.PaymentMethodNotSpecified,
+			PaymentDate:   &now,
+			Status:        domain.PaymentStatusUnpaid,
+			Currency:      domain.BaseCurrency,
+			ExchangeRate:  1,
+			BaseAmount:    remaining,
+			Type:          domain.PaymentTypeBalance,
+		})
 	}
 
-	// Create the payment
-	_, err = bs.paymentRepo.CreatePayment(ctx, payment)
-	if err != nil {
-		// Rollback the booking creation if payment creation fails
-		_ = bs.repo.DeleteBooking(ctx, createdBooking.ID)
-		return nil, domain.ErrInternal
+	var createdPaymentIDs []uint64
+	for _, payment := range payments {
+		createdPayment, err := bs.paymentRepo.CreatePayment(ctx, payment)
+		if err != nil {
+			// Rollback the booking creation if payment creation fails
+			for _, id := range createdPaymentIDs {
+				_ = bs.paymentRepo.DeletePayment(ctx, id)
+			}
+			_ = bs.repo.DeleteBooking(ctx, createdBooking.ID)
+			return nil, domain.ErrInternal
+		}
+		createdPaymentIDs = append(createdPaymentIDs, createdPayment.ID)
 	}
 
 	userID, exists := ctx.Get("userID")
@@ -128,6 +205,20 @@ func (bs *BookingService) GetBooking(ctx *gin.Context, id uint64) (*domain.Booki
 	return booking, nil
 }
 
+// ListBookingStatusEvents lists every status the booking has been created with or moved to
+func (bs *BookingService) ListBookingStatusEvents(ctx *gin.Context, bookingID uint64) ([]domain.BookingStatusEvent, error) {
+	if _, err := bs.GetBooking(ctx, bookingID); err != nil {
+		return nil, err
+	}
+
+	events, err := bs.repo.ListBookingStatusEvents(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return events, nil
+}
+
 func (bs *BookingService) ListBookings(ctx *gin.Context, skip, limit uint64) ([]domain.Booking, uint64, error) {
 	bookings, totalCount, err := bs.repo.ListBookings(ctx, skip, limit)
 	if err != nil {
@@ -167,6 +258,31 @@ func (bs *BookingService) UpdateBooking(ctx *gin.Context, booking *domain.Bookin
 	// 	return nil, domain.ErrNoUpdatedData
 	// }
 
+	if err := ensureBookingChangeable(ctx, bs.dateRepo, bs.periodRepo, booking.ID); err != nil {
+		return nil, err
+	}
+
+	existingBooking, err := bs.repo.GetBookingByID(ctx, booking.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// The status history dates changes by updated_at, so they are stamped on the business date
+	now, err := businessNow(ctx, bs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	booking.UpdatedAt = &now
+
+	// Checking out posts the nights still owed and moves the folio to its payers in one transaction, so it
+	// only goes through CheckOutBooking
+	if booking.Status == domain.BookingStatusCheckedOut && existingBooking.Status != domain.BookingStatusCheckedOut {
+		return nil, domain.ErrInvalidData
+	}
+
 	updatedBooking, err := bs.repo.UpdateBooking(ctx, booking)
 	if err != nil {
 		if err == domain.ErrConflictingData {
@@ -182,7 +298,7 @@ func (bs *BookingService) UpdateBooking(ctx *gin.Context, booking *domain.Bookin
 	// Create a log
 	log := &domain.Log{
 		RecordID:  booking.ID,
-		Action:    "UPDATE",
+		Action:    bookingUpdateAction(existingBooking.Status, booking.Status),
 		UserID:    userID.(uint64),
 		TableName: "bookings",
 	}
@@ -191,9 +307,34 @@ func (bs *BookingService) UpdateBooking(ctx *gin.Context, booking *domain.Bookin
 		slog.Error("Error creating log", "error", err)
 	}
 
+	// A booking earns its loyalty points once, the first time it is completed
+	if booking.Status == domain.BookingStatusCompleted && existingBooking.Status != domain.BookingStatusCompleted {
+		if _, err := bs.loyaltyService.EarnPoints(ctx, booking.ID); err != nil && err != domain.ErrConflictingData {
+			slog.Error("Error earning loyalty points", "booking_id", booking.ID, "error", err)
+		}
+	}
+
 	return updatedBooking, nil
 }
 
+// bookingUpdateAction returns the log action of an update moving a booking from one status to another,
+// naming check-ins and cancellations so staff activity can count them
+func bookingUpdateAction(from, to domain.BookingStatus) string {
+	if to == from {
+		return "UPDATE"
+	}
+	switch to {
+	case domain.BookingStatusCheckedIn:
+		return domain.LogActionCheckIn
+	case domain.BookingStatusCanceled:
+		return domain.LogActionCancel
+	}
+	return "UPDATE"
+}
+
+// DeleteBooking deletes a booking taken by mistake. Payments are never deleted with their booking, so a
+// booking with payments returns ErrConflictingData, as does a completed booking or one a confirmed daily
+// summary or a night audit recorded.
 func (bs *BookingService) DeleteBooking(ctx *gin.Context, id uint64) error {
 	_, err := bs.repo.GetBookingByID(ctx, id)
 	if err != nil {
@@ -203,11 +344,30 @@ func (bs *BookingService) DeleteBooking(ctx *gin.Context, id uint64) error {
 		return domain.ErrInternal
 	}
 
+	if err := ensureBookingOpen(ctx, bs.periodRepo, id); err != nil {
+		return err
+	}
+	payments, err := bs.paymentRepo.ListPaymentsByBookingID(ctx, id)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if len(payments) > 0 {
+		return domain.ErrConflictingData
+	}
+
 	userID, exists := ctx.Get("userID")
 	if !exists {
 		return domain.ErrUnauthorized
 	}
-	// Create a log
+
+	if err := bs.repo.DeleteBooking(ctx, id); err != nil {
+		if err == domain.ErrConflictingData {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	// Create a log once the booking is gone, so refused deletes are not counted as deletes
 	log := &domain.Log{
 		RecordID:  id,
 		Action:    "DELETE",
@@ -219,7 +379,213 @@ func (bs *BookingService) DeleteBooking(ctx *gin.Context, id uint64) error {
 		slog.Error("Error creating log", "error", err)
 	}
 
-	return bs.repo.DeleteBooking(ctx, id)
+	return nil
+}
+
+// CheckOutBooking checks a guest out once the share of the folio of the guest and of every payer is
+// settled. A companyID bills the outstanding balance of that company payer's share to the company's
+// ledger. Admins may override an unsettled balance. Nothing is posted unless the checkout goes through:
+// the nights of the stay not posted yet, the transfer and the new status are stored in one transaction.
+func (bs *BookingService) CheckOutBooking(ctx *gin.Context, id, companyID uint64, overrideBalance bool) (*domain.Booking, error) {
+	if overrideBalance && !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	booking, err := bs.repo.GetBookingByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if booking.Status != domain.BookingStatusCheckedIn {
+		return nil, domain.ErrInvalidData
+	}
+	if err := ensureBookingChangeable(ctx, bs.dateRepo, bs.periodRepo, id); err != nil {
+		return nil, err
+	}
+
+	postedAt, err := businessNow(ctx, bs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	folios, payers, roomCharge, err := bs.checkOutFolios(ctx, booking, postedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	booking.UpdatedAt = &postedAt
+	checkOut := &domain.BookingCheckOut{
+		Booking:    booking,
+		RoomCharge: roomCharge,
+	}
+	if companyID > 0 {
+		if err := bs.transferToCompany(ctx, checkOut, folios, payers, companyID, postedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if !overrideBalance {
+		if err := ensureFoliosSettled(folios); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedBooking, err := bs.repo.CheckOutBooking(ctx, checkOut)
+	if err != nil {
+		if err == domain.ErrConflictingData || err == domain.ErrCreditLimitExceeded || err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  id,
+		Action:    domain.LogActionCheckOut,
+		UserID:    userID.(uint64),
+		TableName: "bookings",
+	}
+	_, err = bs.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	if checkOut.LedgerEntry != nil {
+		log := &domain.Log{
+			RecordID:  checkOut.LedgerEntry.ID,
+			Action:    "CREATE",
+			UserID:    userID.(uint64),
+			TableName: "company_ledger_entries",
+		}
+		_, err = bs.logRepo.CreateLog(ctx, log)
+		if err != nil {
+			slog.Error("Error creating log", "error", err)
+		}
+	}
+
+	return updatedBooking, nil
+}
+
+// ensureBookingChangeable returns ErrDayClosed once a confirmed daily summary lists the booking as completed
+// or canceled, or when the business date is locked or closed and the change could not be listed under it
+func ensureBookingChangeable(ctx *gin.Context, dateRepo port.BusinessDateRepository, periodRepo port.ClosedPeriodRepository, bookingID uint64) error {
+	if err := ensureBookingOpen(ctx, periodRepo, bookingID, domain.SummaryCategoryCompleted, domain.SummaryCategoryCanceled); err != nil {
+		return err
+	}
+	now, err := businessNow(ctx, dateRepo)
+	if err != nil {
+		return err
+	}
+	return ensurePostingOpen(ctx, dateRepo, periodRepo, now)
+}
+
+// checkOutFolios returns the payers of a booking and its folio split between the guest and them as it
+// stands once the nights of the stay not posted yet are, along with the room charge posting those nights
+func (bs *BookingService) checkOutFolios(ctx *gin.Context, booking *domain.Booking, postedAt time.Time) ([]domain.Folio, []domain.BookingPayer, *domain.FolioCharge, error) {
+	folio, err := loadFolio(ctx, bs.folioRepo, bs.paymentRepo, booking.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	charges := folio.Charges
+	roomCharge := roomNightsCharge(ctx, booking, charges, booking.Nights(), postedAt)
+	if roomCharge != nil {
+		charges = append(charges, *roomCharge)
+	}
+
+	payers, err := bs.payerRepo.ListBookingPayersByBookingID(ctx, booking.ID)
+	if err != nil {
+		return nil, nil, nil, domain.ErrInternal
+	}
+
+	return splitFolio(newFolio(booking.ID, nil, charges, folio.Payments), payers), payers, roomCharge, nil
+}
+
+// ensureFoliosSettled returns ErrFolioNotSettled while the share of the folio of the guest or of any payer
+// has an outstanding balance, even when another share is overpaid by as much
+func ensureFoliosSettled(folios []domain.Folio) error {
+	for _, folio := range folios {
+		if !folio.IsSettled() {
+			return domain.ErrFolioNotSettled
+		}
+	}
+	return nil
+}
+
+// transferToCompany adds to a checkout the city ledger payment settling the share of the folio routed to
+// the company payer and the invoice of the same amount on the company's accounts receivable, and settles
+// the share in folios. The company must be a payer of the booking; its credit limit is checked when the
+// checkout is stored.
+func (bs *BookingService) transferToCompany(ctx *gin.Context, checkOut *domain.BookingCheckOut, folios []domain.Folio, payers []domain.BookingPayer, companyID uint64, postedAt time.Time) error {
+	company, err := bs.companyRepo.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+	if !company.IsActive {
+		return domain.ErrInvalidData
+	}
+
+	var folio *domain.Folio
+	for i, payer := range payers {
+		if payer.CompanyID != nil && *payer.CompanyID == companyID {
+			folio = &folios[i+1]
+		}
+	}
+	if folio == nil {
+		return domain.ErrInvalidData
+	}
+	if folio.Balance <= 0 {
+		return nil
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return domain.ErrUnauthorized
+	}
+	postedBy := userID.(uint64)
+	bookingID := folio.BookingID
+
+	payment := &domain.Payment{
+		BookingID:     bookingID,
+		Amount:        folio.Balance,
+		PaymentMethod: domain.PaymentMethodCityLedger,
+		PaymentDate:   &postedAt,
+		Status:        domain.PaymentStatusPaid,
+		Currency:      domain.BaseCurrency,
+		ExchangeRate:  1,
+		BaseAmount:    folio.Balance,
+		Type:          domain.PaymentTypeBalance,
+		PayerID:       folio.PayerID,
+	}
+	if err := tagPayment(ctx, bs.shiftRepo, payment); err != nil {
+		return err
+	}
+
+	dueDate := postedAt.AddDate(0, 0, company.PaymentTermDays)
+	checkOut.Payment = payment
+	checkOut.LedgerEntry = &domain.CompanyLedgerEntry{
+		CompanyID: companyID,
+		EntryType: domain.LedgerEntryTypeInvoice,
+		BookingID: &bookingID,
+		Reference: fmt.Sprintf("Booking #%d", bookingID),
+		Amount:    folio.Balance,
+		EntryDate: &postedAt,
+		DueDate:   &dueDate,
+		PostedBy:  &postedBy,
+	}
+
+	*folio = *newFolio(bookingID, folio.PayerID, folio.Charges, append(folio.Payments, *payment))
+
+	return nil
 }
 
 func (bs *BookingService) GetBookingCustomerPayment(ctx *gin.Context, id uint64) (*domain.BookingCustomerPayment, error) {
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type CashierShiftService struct {
+	repo     port.CashierShiftRepository
+	dateRepo port.BusinessDateRepository
+	logRepo  port.LogRepository
+}
+
+func NewCashierShiftService(repo port.CashierShiftRepository, dateRepo port.BusinessDateRepository, logRepo port.LogRepository) *CashierShiftService {
+	return &CashierShiftService{
+		repo,
+		dateRepo,
+		logRepo,
+	}
+}
+
+// OpenShift starts a shift for the authenticated user with the cash float placed in the drawer
+func (css *CashierShiftService) OpenShift(ctx *gin.Context, openingFloat float64) (*domain.CashierShift, error) {
+	if openingFloat < 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	now, err := businessNow(ctx, css.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	shift := &domain.CashierShift{
+		UserID:       userID.(uint64),
+		OpeningFloat: util.RoundAmount(openingFloat),
+		OpenedAt:     &now,
+	}
+
+	createdShift, err := css.repo.CreateShift(ctx, shift)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  createdShift.ID,
+		Action:    "CREATE",
+		UserID:    createdShift.UserID,
+		TableName: "cashier_shifts",
+	}
+	_, err = css.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdShift, nil
+}
+
+// GetCurrentShift returns the authenticated user's open shift
+func (css *CashierShiftService) GetCurrentShift(ctx *gin.Context) (*domain.CashierShift, error) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	shift, err := css.repo.GetOpenShiftByUserID(ctx, userID.(uint64))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return shift, nil
+}
+
+// ListShifts lists shifts newest first. Admins may list any user's shifts; other users only see their own.
+func (css *CashierShiftService) ListShifts(ctx *gin.Context, userID, skip, limit uint64) ([]domain.CashierShift, uint64, error) {
+	if !isAdmin(ctx) {
+		currentUserID, exists := ctx.Get("userID")
+		if !exists {
+			return nil, 0, domain.ErrUnauthorized
+		}
+		userID = currentUserID.(uint64)
+	}
+
+	shifts, totalCount, err := css.repo.ListShifts(ctx, userID, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return shifts, totalCount, nil
+}
+
+func (css *CashierShiftService) CloseShift(ctx *gin.Context, counted map[domain.ShiftTender]float64, note string) (*domain.ShiftReport, error) {
+	shift, err := css.GetCurrentShift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := css.expectedAmounts(ctx, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	for tender := range counted {
+		if tender.PaymentMethod == domain.PaymentMethodNotSpecified || tender.PaymentMethod.IsNonMonetary() || len(tender.Currency) != 3 {
+			return nil, domain.ErrInvalidData
+		}
+		if _, ok := expected[tender]; !ok {
+			expected[tender] = 0
+		}
+	}
+
+	var counts []domain.ShiftCount
+	for tender, expectedAmount := range expected {
+		countedAmount := util.RoundAmount(counted[tender])
+		counts = append(counts, domain.ShiftCount{
+			ShiftID:       shift.ID,
+			PaymentMethod: tender.PaymentMethod,
+			Currency:      tender.Currency,
+			Expected:      expectedAmount,
+			Counted:       countedAmount,
+			Variance:      util.RoundAmount(countedAmount - expectedAmount),
+		})
+	}
+	sortShiftCounts(counts)
+
+	now, err := businessNow(ctx, css.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	shift.ClosedAt = &now
+	shift.ClosingNote = note
+
+	closedShift, err := css.repo.CloseShift(ctx, shift, counts)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  closedShift.ID,
+		Action:    "CLOSE",
+		UserID:    closedShift.UserID,
+		TableName: "cashier_shifts",
+	}
+	_, err = css.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return newShiftReport(closedShift, counts), nil
+}
+
+// GetShiftReport returns the close-out report of a shift. While the shift is open only the expected amounts are known.
+func (css *CashierShiftService) GetShiftReport(ctx *gin.Context, shiftID uint64) (*domain.ShiftReport, error) {
+	shift, err := css.repo.GetShiftByID(ctx, shiftID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	if shift.UserID != userID.(uint64) && !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+
+	if shift.Status == domain.ShiftStatusClosed {
+		counts, err := css.repo.ListShiftCounts(ctx, shift.ID)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		return newShiftReport(shift, counts), nil
+	}
+
+	expected, err := css.expectedAmounts(ctx, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []domain.ShiftCount
+	for tender, expectedAmount := range expected {
+		counts = append(counts, domain.ShiftCount{
+			ShiftID:       shift.ID,
+			PaymentMethod: tender.PaymentMethod,
+			Currency:      tender.Currency,
+			Expected:      expectedAmount,
+		})
+	}
+	sortShiftCounts(counts)
+
+	return newShiftReport(shift, counts), nil
+}
+
+// expectedAmounts returns what the shift should hold per payment method and currency: the opening float plus
+// BaseCurrency cash taken, and the takings of every other method and currency in the currency they were paid in.
+// City ledger transfers are not collected at the desk and are left out.
+func (css *CashierShiftService) expectedAmounts(ctx *gin.Context, shift *domain.CashierShift) (map[domain.ShiftTender]float64, error) {
+	totals, err := css.repo.GetShiftPaymentTotals(ctx, shift.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	expected := map[domain.ShiftTender]float64{
+		{PaymentMethod: domain.PaymentMethodCash, Currency: domain.BaseCurrency}: shift.OpeningFloat,
+	}
+	for _, total := range totals {
+		if total.PaymentMethod == domain.PaymentMethodNotSpecified || total.PaymentMethod.IsNonMonetary() {
+			continue
+		}
+		tender := domain.ShiftTender{PaymentMethod: total.PaymentMethod, Currency: total.Currency}
+		expected[tender] = util.RoundAmount(expected[tender] + total.Amount)
+	}
+
+	return expected, nil
+}
+
+// sortShiftCounts orders counts by payment method, BaseCurrency first within a method and then by currency
+func sortShiftCounts(counts []domain.ShiftCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].PaymentMethod != counts[j].PaymentMethod {
+			return counts[i].PaymentMethod < counts[j].PaymentMethod
+		}
+		return currencyLess(counts[i].Currency, counts[j].Currency)
+	})
+}
+
+// currencyLess orders BaseCurrency before every other currency and the others alphabetically
+func currencyLess(a, b string) bool {
+	if (a == domain.BaseCurrency) != (b == domain.BaseCurrency) {
+		return a == domain.BaseCurrency
+	}
+	return a < b
+}
+
+// newShiftReport adds the counts up per currency; amounts in different currencies are never added together
+func newShiftReport(shift *domain.CashierShift, counts []domain.ShiftCount) *domain.ShiftReport {
+	report := &domain.ShiftReport{
+		Shift:  *shift,
+		Counts: counts,
+	}
+
+	index := map[string]int{}
+	for _, count := range counts {
+		i, ok := index[count.Currency]
+		if !ok {
+			i = len(report.Totals)
+			index[count.Currency] = i
+			report.Totals = append(report.Totals, domain.ShiftCurrencyTotal{Currency: count.Currency})
+		}
+		total := &report.Totals[i]
+		total.Expected = util.RoundAmount(total.Expected + count.Expected)
+		total.Counted = util.RoundAmount(total.Counted + count.Counted)
+		total.Variance = util.RoundAmount(total.Variance + count.Variance)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool { return currencyLess(report.Totals[i].Currency, report.Totals[j].Currency) })
+
+	return report
+}
+
+// tagPayment records who took a paid payment and in which of their shifts. Cash can only be taken
+// during an open shift; payments without an authenticated user, such as gateway webhooks, are left untagged.
+func tagPayment(ctx *gin.Context, shiftRepo port.CashierShiftRepository, payment *domain.Payment) error {
+	if payment.Status != domain.PaymentStatusPaid {
+		return nil
+	}
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil
+	}
+	takenBy := userID.(uint64)
+	payment.TakenBy = &takenBy
+
+	shift, err := shiftRepo.GetOpenShiftByUserID(ctx, takenBy)
+	if err != nil {
+		if err != domain.ErrDataNotFound {
+			return domain.ErrInternal
+		}
+		if payment.PaymentMethod == domain.PaymentMethodCash {
+			return domain.ErrShiftNotOpen
+		}
+		return nil
+	}
+	payment.ShiftID = &shift.ID
+
+	return nil
+}
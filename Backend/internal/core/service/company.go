@@ -0,0 +1,264 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type CompanyService struct {
+	repo       port.CompanyRepository
+	dateRepo   port.BusinessDateRepository
+	periodRepo port.ClosedPeriodRepository
+	logRepo    port.LogRepository
+}
+
+func NewCompanyService(repo port.CompanyRepository, dateRepo port.BusinessDateRepository, periodRepo port.ClosedPeriodRepository, logRepo port.LogRepository) *CompanyService {
+	return &CompanyService{
+		repo,
+		dateRepo,
+		periodRepo,
+		logRepo,
+	}
+}
+
+func (cs *CompanyService) CreateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error) {
+	if company.CreditLimit < 0 || company.PaymentTermDays < 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	createdCompany, err := cs.repo.CreateCompany(ctx, company)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  createdCompany.ID,
+		Action:    "CREATE",
+		UserID:    userID.(uint64),
+		TableName: "companies",
+	}
+	_, err = cs.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdCompany, nil
+}
+
+func (cs *CompanyService) GetCompany(ctx *gin.Context, id uint64) (*domain.Company, error) {
+	company, err := cs.repo.GetCompanyByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return company, nil
+}
+
+func (cs *CompanyService) ListCompanies(ctx *gin.Context, skip, limit uint64) ([]domain.Company, uint64, error) {
+	companies, totalCount, err := cs.repo.ListCompanies(ctx, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return companies, totalCount, nil
+}
+
+// UpdateCompany changes a company's details. Only admins may change its credit limit.
+func (cs *CompanyService) UpdateCompany(ctx *gin.Context, company *domain.Company) (*domain.Company, error) {
+	if company.CreditLimit < 0 || company.PaymentTermDays < 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	existingCompany, err := cs.repo.GetCompanyByID(ctx, company.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if company.CreditLimit != existingCompany.CreditLimit && !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+
+	updatedCompany, err := cs.repo.UpdateCompany(ctx, company)
+	if err != nil {
+		if err == domain.ErrDataNotFound || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  updatedCompany.ID,
+		Action:    "UPDATE",
+		UserID:    userID.(uint64),
+		TableName: "companies",
+	}
+	_, err = cs.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return updatedCompany, nil
+}
+
+func (cs *CompanyService) GetLedger(ctx *gin.Context, companyID uint64) (*domain.CompanyLedger, error) {
+	company, err := cs.GetCompany(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := cs.repo.ListLedgerEntriesByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	ledger := &domain.CompanyLedger{
+		Company: *company,
+		Entries: entries,
+	}
+	for _, entry := range entries {
+		ledger.Balance += entry.Amount
+	}
+	ledger.Balance = util.RoundAmount(ledger.Balance)
+
+	return ledger, nil
+}
+
+func (cs *CompanyService) ListOpenInvoices(ctx *gin.Context, companyID uint64) ([]domain.CompanyInvoice, error) {
+	_, err := cs.GetCompany(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	asOf, err := businessNow(ctx, cs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := cs.repo.ListOpenInvoices(ctx, companyID, asOf)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return invoices, nil
+}
+
+func (cs *CompanyService) RecordPayment(ctx *gin.Context, companyID, invoiceEntryID uint64, amount float64, method domain.PaymentMethod, reference string) (*domain.CompanyLedgerEntry, error) {
+	if amount <= 0 || method == domain.PaymentMethodNotSpecified || method.IsNonMonetary() {
+		return nil, domain.ErrInvalidData
+	}
+
+	invoice, err := cs.repo.GetInvoiceEntryByID(ctx, invoiceEntryID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if invoice.CompanyID != companyID {
+		return nil, domain.ErrDataNotFound
+	}
+	if util.RoundAmount(amount) > util.RoundAmount(invoice.Outstanding) {
+		return nil, domain.ErrInvalidData
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	postedBy := userID.(uint64)
+	now, err := businessNow(ctx, cs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePostingOpen(ctx, cs.dateRepo, cs.periodRepo, now); err != nil {
+		return nil, err
+	}
+
+	entry := &domain.CompanyLedgerEntry{
+		CompanyID:     companyID,
+		EntryType:     domain.LedgerEntryTypePayment,
+		AppliedToID:   &invoice.ID,
+		Reference:     reference,
+		Amount:        -util.RoundAmount(amount),
+		PaymentMethod: method,
+		EntryDate:     &now,
+		PostedBy:      &postedBy,
+	}
+
+	createdEntry, err := cs.repo.CreateLedgerEntry(ctx, entry)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  createdEntry.ID,
+		Action:    "CREATE",
+		UserID:    postedBy,
+		TableName: "company_ledger_entries",
+	}
+	_, err = cs.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdEntry, nil
+}
+
+// GetAgingReport returns each company's outstanding balance as of the given time, bucketed by
+// invoice age: 0-30, 31-60, 61-90 and over 90 days
+func (cs *CompanyService) GetAgingReport(ctx *gin.Context, asOf time.Time) ([]domain.AgingRow, error) {
+	invoices, err := cs.repo.ListOpenInvoices(ctx, 0, asOf)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	var rows []domain.AgingRow
+	for _, invoice := range invoices {
+		if len(rows) == 0 || rows[len(rows)-1].CompanyID != invoice.CompanyID {
+			company, err := cs.repo.GetCompanyByID(ctx, invoice.CompanyID)
+			if err != nil {
+				return nil, domain.ErrInternal
+			}
+			rows = append(rows, domain.AgingRow{CompanyID: company.ID, CompanyName: company.Name})
+		}
+
+		ageDays := int(asOf.Sub(*invoice.EntryDate).Hours() / 24)
+		rows[len(rows)-1].Add(ageDays, invoice.Outstanding)
+	}
+
+	for i := range rows {
+		rows[i].Days0To30 = util.RoundAmount(rows[i].Days0To30)
+		rows[i].Days31To60 = util.RoundAmount(rows[i].Days31To60)
+		rows[i].Days61To90 = util.RoundAmount(rows[i].Days61To90)
+		rows[i].Over90Days = util.RoundAmount(rows[i].Over90Days)
+		rows[i].Total = util.RoundAmount(rows[i].Total)
+	}
+
+	return rows, nil
+}
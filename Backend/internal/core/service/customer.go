@@ -5,16 +5,19 @@ import (
 	"log/slog"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
 )
 
 type CustomerService struct {
-	repo    port.CustomerRepository
-	logRepo port.LogRepository
+	repo        port.CustomerRepository
+	bookingRepo port.BookingRepository
+	logRepo     port.LogRepository
 }
 
-func NewCustomerService(repo port.CustomerRepository, logRepo port.LogRepository) *CustomerService {
+func NewCustomerService(repo port.CustomerRepository, bookingRepo port.BookingRepository, logRepo port.LogRepository) *CustomerService {
 	return &CustomerService{
 		repo,
+		bookingRepo,
 		logRepo,
 	}
 }
@@ -160,4 +163,121 @@ func (cs *CustomerService) ListCustomersWithFilter(ctx *gin.Context, customer *d
 	}
 
 	return customers, totalCount, nil
-}
\ No newline at end of file
+}
+
+// SearchCustomers normalizes query before matching, so "Somchai " and "somchai" find the same customers
+func (cs *CustomerService) SearchCustomers(ctx *gin.Context, query string, skip, limit uint64) ([]domain.CustomerMatch, uint64, error) {
+	search := domain.NewCustomerSearch(query)
+	if search.Text == "" {
+		return nil, 0, domain.ErrInvalidData
+	}
+
+	matches, totalCount, err := cs.repo.SearchCustomers(ctx, search, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return matches, totalCount, nil
+}
+
+// ListCustomerDuplicates lists the pairs of customers scoring at least minScore as likely the same person.
+// Only admins can list them.
+func (cs *CustomerService) ListCustomerDuplicates(ctx *gin.Context, minScore float64, skip, limit uint64) ([]domain.CustomerDuplicate, uint64, error) {
+	if !isAdmin(ctx) {
+		return nil, 0, domain.ErrForbidden
+	}
+	if minScore <= 0 || minScore > 1 {
+		return nil, 0, domain.ErrInvalidData
+	}
+
+	duplicates, totalCount, err := cs.repo.ListCustomerDuplicates(ctx, minScore, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+	for i := range duplicates {
+		duplicates[i].Score = util.RoundAmount(duplicates[i].Score)
+		duplicates[i].NameSimilarity = util.RoundAmount(duplicates[i].NameSimilarity)
+	}
+
+	return duplicates, totalCount, nil
+}
+
+// MergeCustomers merges the duplicate into survivor: its bookings, with their payments, and its booking payers
+// move to survivor, survivor's blank details are filled from it, and it is deleted. Only admins can merge.
+func (cs *CustomerService) MergeCustomers(ctx *gin.Context, survivorID, duplicateID uint64) (*domain.Customer, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	if survivorID == duplicateID {
+		return nil, domain.ErrInvalidData
+	}
+
+	survivor, err := cs.repo.GetCustomerByID(ctx, survivorID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	duplicate, err := cs.repo.GetCustomerByID(ctx, duplicateID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	survivor.MergeFrom(duplicate)
+
+	mergedCustomer, err := cs.repo.MergeCustomers(ctx, survivor, duplicateID)
+	if err != nil {
+		if err == domain.ErrDataNotFound || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	// Log the merge on both records, so each side's history shows it
+	for _, log := range []*domain.Log{
+		{RecordID: survivorID, Action: "MERGE", UserID: userID.(uint64), TableName: "customers"},
+		{RecordID: duplicateID, Action: "MERGED", UserID: userID.(uint64), TableName: "customers"},
+	} {
+		if _, err := cs.logRepo.CreateLog(ctx, log); err != nil {
+			slog.Error("Error creating log", "error", err)
+		}
+	}
+	slog.Info("Merged customers", "survivor_id", survivorID, "duplicate_id", duplicateID)
+
+	return mergedCustomer, nil
+}
+
+// GetCustomerHistory returns every booking of a customer, cancellations and no-shows included, with the nights
+// they stayed, what they paid and the room type they stayed in most
+func (cs *CustomerService) GetCustomerHistory(ctx *gin.Context, id uint64) (*domain.CustomerHistory, error) {
+	customer, err := cs.repo.GetCustomerByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	bookings, err := cs.bookingRepo.ListBookingCustomerPaymentsByCustomerID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	stats := domain.NewCustomerStats(bookings)
+	stats.TotalRevenue = util.RoundAmount(stats.TotalRevenue)
+	stats.AverageRate = util.RoundAmount(stats.AverageRate)
+
+	return &domain.CustomerHistory{
+		Customer: *customer,
+		Bookings: bookings,
+		Stats:    stats,
+	}, nil
+}
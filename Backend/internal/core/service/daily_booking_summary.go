@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"time"
 	"log/slog"
@@ -12,106 +13,134 @@ import (
 
 type DailyBookingSummaryService struct {
 	summaryRepo port.DailyBookingSummaryRepository
-	bookingRepo port.BookingRepository  
+	paymentRepo port.PaymentRepository
+	auditRepo   port.NightAuditRepository
 	logRepo     port.LogRepository
 }
 
 func NewDailyBookingSummaryService(
 	summaryRepo port.DailyBookingSummaryRepository,
-	bookingRepo port.BookingRepository,
+	paymentRepo port.PaymentRepository,
+	auditRepo port.NightAuditRepository,
 	logRepo port.LogRepository,
 ) *DailyBookingSummaryService {
 	return &DailyBookingSummaryService{
 		summaryRepo,
-		bookingRepo,
+		paymentRepo,
+		auditRepo,
 		logRepo,
 	}
 }
 
-func (dbs *DailyBookingSummaryService) GenerateDailySummary(ctx *gin.Context, date time.Time) (*domain.DailyBookingSummary, error) {
-	// Get all created bookings for the specified date
-	createdBookings, createdCount, err := dbs.bookingRepo.ListBookingsWithFilter(ctx, &domain.Booking{
-		CreatedAt: &date,
-	}, 0, 1000)
+// GenerateDailySummary generates the summary of date on behalf of actorID. It takes a plain context, as
+// the scheduler runs it outside of any request.
+func (dbs *DailyBookingSummaryService) GenerateDailySummary(ctx context.Context, actorID uint64, date time.Time) (*domain.DailyBookingSummary, error) {
+	if err := ensureDayUnlocked(ctx, dbs.auditRepo, date); err != nil {
+		return nil, err
+	}
+	// Regenerating a confirmed summary would replace figures accounting signed off
+	if err := ensurePeriodOpen(ctx, dbs.summaryRepo, date); err != nil {
+		return nil, err
+	}
+
+	summary, err := dbs.buildSummary(ctx, date)
 	if err != nil {
-		return nil, fmt.Errorf("error getting created bookings: %w", err)
+		return nil, err
 	}
+	summary.Status = domain.SummaryStatusUnchecked
 
-	// Get all updated bookings for the specified date
-	updatedBookings, updatedCount, err := dbs.bookingRepo.ListBookingsWithFilter(ctx, &domain.Booking{
-		UpdatedAt: &date,
-	}, 0, 1000)
+	// Create or update the summary
+	createdSummary, err := dbs.summaryRepo.CreateDailyBookingSummary(ctx, summary)
 	if err != nil {
-		return nil, fmt.Errorf("error getting updated bookings: %w", err)
+		return nil, fmt.Errorf("error creating summary: %w", err)
 	}
 
-	slog.Info("Retrieved bookings",
+	// Log the action
+	dateStr := date.Format("20060102") // YYYYMMDD format
+	recordID, err := strconv.ParseUint(dateStr, 10, 64)
+	if err != nil {
+		slog.Error("Error parsing date for log record ID", "error", err)
+		recordID = 0 // Use 0 as fallback
+	}
+
+	log := &domain.Log{
+		Action:    "CREATE",
+		UserID:    actorID,
+		TableName: "daily_booking_summary",
+		RecordID:  recordID,
+	}
+	if _, err := dbs.logRepo.CreateLog(ctx, log); err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdSummary, nil
+}
+
+// buildSummary totals the booking activity and reversals of date and records their checksum
+func (dbs *DailyBookingSummaryService) buildSummary(ctx context.Context, date time.Time) (*domain.DailyBookingSummary, error) {
+	// Bookings are taken from their status history, so later edits do not move them between days
+	items, err := dbs.summaryRepo.ListBookingActivity(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("error getting booking activity: %w", err)
+	}
+
+	totalAmount, err := dbs.summaryRepo.GetCompletedRevenue(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("error getting completed revenue: %w", err)
+	}
+
+	slog.Info("Retrieved booking activity",
 		"date", date.Format("2006-01-02"),
-		"created_count", createdCount,
-		"updated_count", updatedCount)
-
-	// Initialize arrays for different booking types
-	var (
-		createdIDs    []uint64
-		completedIDs  []uint64
-		canceledIDs   []uint64
-		totalAmount   float64
-	)
-
-	// Process created bookings
-	for _, booking := range createdBookings {
-		createdIDs = append(createdIDs, booking.ID)
-	}
-
-	// Process updated bookings
-	for _, booking := range updatedBookings {
-		// Check if the booking was already counted in created bookings
-		if booking.Status == domain.BookingStatusCompleted {
-			totalAmount += booking.TotalAmount
-			completedIDs = append(completedIDs, booking.ID)
-		} else if booking.Status == domain.BookingStatusCanceled {
-			canceledIDs = append(canceledIDs, booking.ID)
-		}
+		"item_count", len(items))
+
+	// Refunds and voids taken on the day reduce the day's revenue
+	totalRefunds, err := dbs.paymentRepo.GetReversalTotal(ctx, date, date)
+	if err != nil {
+		return nil, fmt.Errorf("error getting refund total: %w", err)
 	}
+	totalAmount -= totalRefunds
 
-	// Create summary object
 	summary := &domain.DailyBookingSummary{
-		SummaryDate:       date,
-		CreatedBookings:   domain.FormatBookingIDs(createdIDs),
-		CompletedBookings: domain.FormatBookingIDs(completedIDs),
-		CanceledBookings:  domain.FormatBookingIDs(canceledIDs),
-		TotalAmount:       totalAmount,
-		Status:            domain.SummaryStatusUnchecked,
+		SummaryDate:  date,
+		TotalAmount:  totalAmount,
+		TotalRefunds: totalRefunds,
+		Items:        items,
 	}
+	summary.Checksum = summary.SourceChecksum()
 
-	// Create or update the summary
-	createdSummary, err := dbs.summaryRepo.CreateDailyBookingSummary(ctx, summary)
+	return summary, nil
+}
+
+// ensurePeriodOpen returns ErrDayClosed when a confirmed daily summary has closed the day of date
+func ensurePeriodOpen(ctx context.Context, periodRepo port.ClosedPeriodRepository, date time.Time) error {
+	closed, err := periodRepo.IsDayClosed(ctx, date)
 	if err != nil {
-		return nil, fmt.Errorf("error creating summary: %w", err)
+		return domain.ErrInternal
+	}
+	if closed {
+		return domain.ErrDayClosed
 	}
+	return nil
+}
 
-	// Log the action
-	userID, exists := ctx.Get("userID")
-	if exists {
-		dateStr := date.Format("20060102") // YYYYMMDD format
-		recordID, err := strconv.ParseUint(dateStr, 10, 64)
-		if err != nil {
-			slog.Error("Error parsing date for log record ID", "error", err)
-			recordID = 0 // Use 0 as fallback
-		}
-		
-		log := &domain.Log{
-			Action:    "CREATE",
-			UserID:    userID.(uint64),
-			TableName: "daily_booking_summary",
-			RecordID:  recordID,
+// ensureBookingOpen returns ErrDayClosed when a confirmed daily summary lists the booking under one of
+// categories, or under any category when none are given
+func ensureBookingOpen(ctx *gin.Context, periodRepo port.ClosedPeriodRepository, bookingID uint64, categories ...domain.SummaryCategory) error {
+	items, err := periodRepo.ListClosedBookingItems(ctx, bookingID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	for _, item := range items {
+		if len(categories) == 0 {
+			return domain.ErrDayClosed
 		}
-		if _, err := dbs.logRepo.CreateLog(ctx, log); err != nil {
-			slog.Error("Error creating log", "error", err)
+		for _, category := range categories {
+			if item.Category == category {
+				return domain.ErrDayClosed
+			}
 		}
 	}
-
-	return createdSummary, nil
+	return nil
 }
 
 // Helper function to check if a slice contains a value
@@ -125,10 +154,29 @@ func contains(slice []uint64, value uint64) bool {
 }
 
 func (dbs *DailyBookingSummaryService) UpdateSummaryStatus(ctx *gin.Context, date time.Time, status domain.SummaryStatus) (*domain.DailyBookingSummary, error) {
+	if err := ensureDayUnlocked(ctx, dbs.auditRepo, date); err != nil {
+		return nil, err
+	}
+
 	summary, err := dbs.summaryRepo.GetDailyBookingSummaryByDate(ctx, date.Format("2006-01-02"))
 	if err != nil {
 		return nil, fmt.Errorf("error getting summary: %w", err)
 	}
+	// A confirmed summary only changes by being reopened
+	if summary.IsClosed() {
+		return nil, domain.ErrDayClosed
+	}
+
+	// Confirm only figures that still match their source data
+	if status == domain.SummaryStatusConfirmed {
+		current, err := dbs.buildSummary(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		if current.Checksum != summary.Checksum {
+			return nil, domain.ErrSummaryOutdated
+		}
+	}
 
 	summary.Status = status
 	updatedSummary, err := dbs.summaryRepo.UpdateDailyBookingSummary(ctx, summary)
@@ -153,6 +201,69 @@ func (dbs *DailyBookingSummaryService) UpdateSummaryStatus(ctx *gin.Context, dat
 	return updatedSummary, nil
 }
 
+// ReopenSummary moves a confirmed summary back to checked so the bookings and payments of its day can be
+// changed again. Only admins can reopen a day. A day the night audit locked is unlocked with it until the
+// summary is confirmed again; both reopenings are logged.
+func (dbs *DailyBookingSummaryService) ReopenSummary(ctx *gin.Context, date time.Time) (*domain.DailyBookingSummary, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	summary, err := dbs.summaryRepo.GetDailyBookingSummaryByDate(ctx, date.Format("2006-01-02"))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if !summary.IsClosed() {
+		return nil, domain.ErrInvalidData
+	}
+
+	audit, err := dbs.auditRepo.GetNightAuditByDate(ctx, date)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+	auditLocked := audit != nil && audit.Status == domain.NightAuditStatusCompleted
+
+	reopenedSummary, err := dbs.summaryRepo.ReopenDailyBookingSummary(ctx, summary)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	recordID, _ := strconv.ParseUint(date.Format("20060102"), 10, 64)
+	// Create a log
+	log := &domain.Log{
+		RecordID:  recordID,
+		Action:    "REOPEN",
+		UserID:    userID.(uint64),
+		TableName: "daily_booking_summary",
+	}
+	if _, err := dbs.logRepo.CreateLog(ctx, log); err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+	if auditLocked {
+		log := &domain.Log{
+			RecordID:  audit.ID,
+			Action:    "REOPEN",
+			UserID:    userID.(uint64),
+			TableName: "night_audits",
+		}
+		if _, err := dbs.logRepo.CreateLog(ctx, log); err != nil {
+			slog.Error("Error creating log", "error", err)
+		}
+	}
+
+	return reopenedSummary, nil
+}
+
 func (dbs *DailyBookingSummaryService) GetSummaryByDate(ctx *gin.Context, date time.Time) (*domain.DailyBookingSummary, error) {
 	return dbs.summaryRepo.GetDailyBookingSummaryByDate(ctx, date.Format("2006-01-02"))
 }
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentService struct {
+	bookingRepo  port.BookingRepository
+	folioRepo    port.FolioRepository
+	paymentRepo  port.PaymentRepository
+	invoiceRepo  port.InvoiceRepository
+	payerRepo    port.BookingPayerRepository
+	customerRepo port.CustomerRepository
+	companyRepo  port.CompanyRepository
+	dateRepo     port.BusinessDateRepository
+	renderer     port.DocumentRenderer
+	hotel        domain.Hotel
+}
+
+func NewDocumentService(bookingRepo port.BookingRepository, folioRepo port.FolioRepository, paymentRepo port.PaymentRepository, invoiceRepo port.InvoiceRepository, payerRepo port.BookingPayerRepository, customerRepo port.CustomerRepository, companyRepo port.CompanyRepository, dateRepo port.BusinessDateRepository, renderer port.DocumentRenderer, hotel domain.Hotel) *DocumentService {
+	return &DocumentService{
+		bookingRepo,
+		folioRepo,
+		paymentRepo,
+		invoiceRepo,
+		payerRepo,
+		customerRepo,
+		companyRepo,
+		dateRepo,
+		renderer,
+		hotel,
+	}
+}
+
+// GetBookingDocumentPDF renders the receipt, or the pro forma invoice, of a booking payer's current folio.
+// A payerID of zero renders the guest's share.
+func (ds *DocumentService) GetBookingDocumentPDF(ctx *gin.Context, bookingID, payerID uint64, documentType domain.DocumentType) ([]byte, error) {
+	if documentType != domain.DocumentTypeTaxInvoice && documentType != domain.DocumentTypeReceipt {
+		return nil, domain.ErrInvalidData
+	}
+
+	issuedAt, err := businessNow(ctx, ds.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	document, err := ds.buildBookingDocument(ctx, bookingID, payerID, documentType, issuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return ds.render(document)
+}
+
+// GetInvoicePDF renders an issued tax invoice or credit note from the lines and totals stored when it
+// was issued, so reprints match the original whatever has been posted to the folio since
+func (ds *DocumentService) GetInvoicePDF(ctx *gin.Context, invoiceID uint64) ([]byte, error) {
+	invoice, err := ds.invoiceRepo.GetInvoiceByID(ctx, invoiceID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	documentType := domain.DocumentTypeTaxInvoice
+	if invoice.InvoiceType == domain.InvoiceTypeCreditNote {
+		documentType = domain.DocumentTypeCreditNote
+	}
+
+	var payerID uint64
+	if invoice.PayerID != nil {
+		payerID = *invoice.PayerID
+	}
+
+	document, err := ds.newBookingDocument(ctx, invoice.BookingID, payerID, documentType, *invoice.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	document.Number = invoice.Number
+	document.Reason = invoice.Reason
+	document.Subtotal = invoice.Subtotal
+	document.VAT = invoice.VAT
+	document.Total = invoice.Total
+	if rate := ds.hotel.ServiceChargeRate; rate > 0 {
+		document.ServiceCharge = util.RoundAmount(document.Subtotal * rate / (100 + rate))
+	}
+
+	// A credit note reverses the original invoice in full and carries no lines of its own
+	if invoice.OriginalInvoiceID != nil {
+		original, err := ds.invoiceRepo.GetInvoiceByID(ctx, *invoice.OriginalInvoiceID)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		document.OriginalNumber = original.Number
+		document.ServiceCharge = 0
+		return ds.render(document)
+	}
+
+	// Adjustments are lines of the invoice and already netted off its subtotal
+	document.Charges, err = ds.invoiceRepo.ListInvoiceCharges(ctx, invoice.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	document.Payments, err = ds.invoiceRepo.ListInvoicePayments(ctx, invoice.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	for _, payment := range document.Payments {
+		document.Paid += payment.BaseAmount
+	}
+	document.Paid = util.RoundAmount(document.Paid)
+	document.Balance = util.RoundAmount(document.Total - document.Paid)
+
+	return ds.render(document)
+}
+
+func (ds *DocumentService) render(document *domain.BookingDocument) ([]byte, error) {
+	pdf, err := ds.renderer.RenderBookingDocument(document)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return pdf, nil
+}
+
+// buildBookingDocument fills a document from the charges and payments currently on the payer's folio
+func (ds *DocumentService) buildBookingDocument(ctx *gin.Context, bookingID, payerID uint64, documentType domain.DocumentType, issuedAt time.Time) (*domain.BookingDocument, error) {
+	document, err := ds.newBookingDocument(ctx, bookingID, payerID, documentType, issuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	folio, err := loadPayerFolio(ctx, ds.folioRepo, ds.paymentRepo, ds.payerRepo, bookingID, payerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var adjustments []domain.FolioCharge
+	for _, charge := range folio.Charges {
+		if charge.ChargeType == domain.ChargeTypeAdjustment {
+			adjustments = append(adjustments, charge)
+			document.Adjustments -= charge.Total()
+			continue
+		}
+		document.Charges = append(document.Charges, charge)
+		document.Subtotal += charge.Amount
+		document.VAT += charge.TaxAmount
+		document.Total += charge.Total()
+	}
+	document.Charges = append(document.Charges, adjustments...)
+
+	for _, payment := range folio.Payments {
+		if payment.Status == domain.PaymentStatusPaid {
+			document.Payments = append(document.Payments, payment)
+			document.Paid += payment.BaseAmount
+		}
+	}
+
+	// Prices already include the service charge, so it is only broken out of the subtotal
+	document.Subtotal = util.RoundAmount(document.Subtotal)
+	document.VAT = util.RoundAmount(document.VAT)
+	document.Total = util.RoundAmount(document.Total)
+	document.Adjustments = util.RoundAmount(document.Adjustments)
+	document.Paid = util.RoundAmount(document.Paid)
+	document.Balance = util.RoundAmount(document.Total - document.Adjustments - document.Paid)
+	if rate := ds.hotel.ServiceChargeRate; rate > 0 {
+		document.ServiceCharge = util.RoundAmount(document.Subtotal * rate / (100 + rate))
+	}
+
+	return document, nil
+}
+
+// newBookingDocument fills the hotel, booking and bill-to details of a document for a booking payer
+func (ds *DocumentService) newBookingDocument(ctx *gin.Context, bookingID, payerID uint64, documentType domain.DocumentType, issuedAt time.Time) (*domain.BookingDocument, error) {
+	booking, err := ds.bookingRepo.GetBookingCustomerPayment(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	stay := domain.Booking{CheckInDate: booking.CheckInDate, CheckOutDate: booking.CheckOutDate}
+	document := &domain.BookingDocument{
+		Type:     documentType,
+		Hotel:    ds.hotel,
+		Booking:  *booking,
+		Nights:   stay.Nights(),
+		IssuedAt: issuedAt,
+	}
+
+	if payerID != 0 {
+		payers, err := ds.payerRepo.ListBookingPayersByBookingID(ctx, bookingID)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		for _, payer := range payers {
+			if payer.ID != payerID {
+				continue
+			}
+			document.BillTo, err = ds.billTo(ctx, &payer)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if document.BillTo == nil {
+			return nil, domain.ErrDataNotFound
+		}
+	}
+
+	return document, nil
+}
+
+// billTo returns the name, tax ID and address of the customer or company a booking payer is
+func (ds *DocumentService) billTo(ctx *gin.Context, payer *domain.BookingPayer) (*domain.BillTo, error) {
+	if payer.CompanyID != nil {
+		company, err := ds.companyRepo.GetCompanyByID(ctx, *payer.CompanyID)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		return &domain.BillTo{Name: company.Name, TaxID: company.TaxID, Address: company.Address}, nil
+	}
+
+	customer, err := ds.customerRepo.GetCustomerByID(ctx, *payer.CustomerID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return &domain.BillTo{
+		Name:    customer.FirstName + " " + customer.Surname,
+		TaxID:   customer.IdentityNumber,
+		Address: customer.Address,
+	}, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateService struct {
+	repo    port.ExchangeRateRepository
+	logRepo port.LogRepository
+}
+
+func NewExchangeRateService(repo port.ExchangeRateRepository, logRepo port.LogRepository) *ExchangeRateService {
+	return &ExchangeRateService{
+		repo,
+		logRepo,
+	}
+}
+
+func (es *ExchangeRateService) CreateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	exchangeRate.Currency = strings.ToUpper(exchangeRate.Currency)
+	if len(exchangeRate.Currency) != 3 || exchangeRate.Currency == domain.BaseCurrency || exchangeRate.Rate <= 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	if exchangeRate.EffectiveDate == nil {
+		now := time.Now()
+		exchangeRate.EffectiveDate = &now
+	}
+
+	createdExchangeRate, err := es.repo.CreateExchangeRate(ctx, exchangeRate)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	// Create a log
+	log := &domain.Log{
+		RecordID:  createdExchangeRate.ID,
+		Action:    "CREATE",
+		UserID:    userID.(uint64),
+		TableName: "exchange_rates",
+	}
+	_, err = es.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdExchangeRate, nil
+}
+
+func (es *ExchangeRateService) GetExchangeRate(ctx *gin.Context, id uint64) (*domain.ExchangeRate, error) {
+	exchangeRate, err := es.repo.GetExchangeRateByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return exchangeRate, nil
+}
+
+// GetEffectiveExchangeRate returns the rate used to convert a currency into the base currency on a given date
+func (es *ExchangeRateService) GetEffectiveExchangeRate(ctx *gin.Context, currency string, date time.Time) (*domain.ExchangeRate, error) {
+	return effectiveExchangeRate(ctx, es.repo, currency, date)
+}
+
+// effectiveExchangeRate looks up the rate for a currency on a date, treating the base currency as a fixed rate of 1
+func effectiveExchangeRate(ctx *gin.Context, repo port.ExchangeRateRepository, currency string, date time.Time) (*domain.ExchangeRate, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == domain.BaseCurrency {
+		return &domain.ExchangeRate{
+			Currency:      domain.BaseCurrency,
+			Rate:          1,
+			EffectiveDate: &date,
+		}, nil
+	}
+
+	exchangeRate, err := repo.GetEffectiveExchangeRate(ctx, currency, date)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrUnsupportedCurrency
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return exchangeRate, nil
+}
+
+func (es *ExchangeRateService) ListExchangeRates(ctx *gin.Context, currency string, skip, limit uint64) ([]domain.ExchangeRate, uint64, error) {
+	exchangeRates, totalCount, err := es.repo.ListExchangeRates(ctx, strings.ToUpper(currency), skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return exchangeRates, totalCount, nil
+}
+
+func (es *ExchangeRateService) UpdateExchangeRate(ctx *gin.Context, exchangeRate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	existingExchangeRate, err := es.repo.GetExchangeRateByID(ctx, exchangeRate.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if exchangeRate.Rate <= 0 {
+		return nil, domain.ErrInvalidData
+	}
+	if exchangeRate.EffectiveDate == nil {
+		exchangeRate.EffectiveDate = existingExchangeRate.EffectiveDate
+	}
+
+	// Check if there are changes
+	isSame := existingExchangeRate.Rate == exchangeRate.Rate && existingExchangeRate.EffectiveDate.Equal(*exchangeRate.EffectiveDate)
+	if isSame {
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	updatedExchangeRate, err := es.repo.UpdateExchangeRate(ctx, exchangeRate)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	// Create a log
+	log := &domain.Log{
+		RecordID:  exchangeRate.ID,
+		Action:    "UPDATE",
+		UserID:    userID.(uint64),
+		TableName: "exchange_rates",
+	}
+	_, err = es.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return updatedExchangeRate, nil
+}
+
+func (es *ExchangeRateService) DeleteExchangeRate(ctx *gin.Context, id uint64) error {
+	_, err := es.repo.GetExchangeRateByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return domain.ErrUnauthorized
+	}
+	// Create a log
+	log := &domain.Log{
+		RecordID:  id,
+		Action:    "DELETE",
+		UserID:    userID.(uint64),
+		TableName: "exchange_rates",
+	}
+	_, err = es.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return es.repo.DeleteExchangeRate(ctx, id)
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+type ExportService struct {
+	bookingRepo   port.BookingRepository
+	paymentRepo   port.PaymentRepository
+	customerRepo  port.CustomerRepository
+	logRepo       port.LogRepository
+	summaryRepo   port.DailyBookingSummaryRepository
+	reportService port.ReportService
+	writers       port.TableWriterFactory
+}
+
+func NewExportService(bookingRepo port.BookingRepository, paymentRepo port.PaymentRepository, customerRepo port.CustomerRepository, logRepo port.LogRepository, summaryRepo port.DailyBookingSummaryRepository, reportService port.ReportService, writers port.TableWriterFactory) *ExportService {
+	return &ExportService{
+		bookingRepo,
+		paymentRepo,
+		customerRepo,
+		logRepo,
+		summaryRepo,
+		reportService,
+		writers,
+	}
+}
+
+// exportPage returns the rows of the page after last, the last row written, or of the first page when last
+// is nil. Rows are listed by the key in their first column, so a page reads on from the key of last.
+type exportPage func(last []interface{}) ([][]interface{}, error)
+
+// exportTable writes header and then the rows of every page until a short page. The first page is read
+// before anything is written, so a failing export can still be answered with an error.
+func (es *ExportService) exportTable(w io.Writer, format domain.ExportFormat, sheetName string, header []interface{}, page exportPage) error {
+	rows, err := page(nil)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	writer, err := es.writers.NewTableWriter(w, format, sheetName)
+	if err != nil {
+		if err == domain.ErrInvalidData {
+			return err
+		}
+		return domain.ErrInternal
+	}
+	if err := writer.WriteRow(header...); err != nil {
+		return err
+	}
+
+	for {
+		for _, row := range rows {
+			if err := writer.WriteRow(row...); err != nil {
+				return err
+			}
+		}
+		if len(rows) < domain.ExportBatchSize {
+			break
+		}
+
+		last := rows[len(rows)-1]
+		rows, err = page(last)
+		if err != nil {
+			// The response has started, so the file is cut short rather than answered with an error
+			slog.Error("Error reading export page", "sheet", sheetName, "after", last[0], "error", err)
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// exportLastID returns the ID in the first column of last, or zero for the first page
+func exportLastID(last []interface{}) uint64 {
+	if last == nil {
+		return 0
+	}
+	return last[0].(uint64)
+}
+
+func (es *ExportService) ExportBookingCustomerPayments(ctx *gin.Context, filter *domain.BookingCustomerPayment, format domain.ExportFormat, w io.Writer) error {
+	header := []interface{}{
+		"Booking ID", "Status", "Check-in", "Check-out", "Room", "Room type", "Floor",
+		"Customer ID", "First name", "Surname", "Identity number", "Booking price", "Paid", "Balance",
+		"Payment status", "Created at", "Updated at",
+	}
+
+	return es.exportTable(w, format, "Bookings", header, func(last []interface{}) ([][]interface{}, error) {
+		bookings, err := es.bookingRepo.ListBookingCustomerPaymentsBefore(ctx, filter, exportLastID(last), domain.ExportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := make([][]interface{}, 0, len(bookings))
+		for _, b := range bookings {
+			var paymentStatus interface{}
+			if b.PaymentStatus != nil {
+				paymentStatus = *b.PaymentStatus
+			}
+			rows = append(rows, []interface{}{
+				b.BookingID, int(b.BookingStatus), b.CheckInDate, b.CheckOutDate, b.RoomNumber, b.RoomTypeName, b.Floor,
+				b.CustomerID, b.CustomerFirstName, b.CustomerSurname, b.CustomerIdentityNumber, b.BookingPrice, b.PaidAmount, b.Balance,
+				paymentStatus, b.BookingCreatedAt, b.BookingUpdatedAt,
+			})
+		}
+		return rows, nil
+	})
+}
+
+func (es *ExportService) ExportPayments(ctx *gin.Context, format domain.ExportFormat, w io.Writer) error {
+	header := []interface{}{
+		"Payment ID", "Booking ID", "Type", "Method", "Status", "Amount", "Currency", "Exchange rate",
+		"Base amount", "Payment date", "Original payment ID", "Taken by", "Shift ID", "Created at",
+	}
+
+	return es.exportTable(w, format, "Payments", header, func(last []interface{}) ([][]interface{}, error) {
+		payments, err := es.paymentRepo.ListPaymentsBefore(ctx, exportLastID(last), domain.ExportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := make([][]interface{}, 0, len(payments))
+		for _, p := range payments {
+			rows = append(rows, []interface{}{
+				p.ID, p.BookingID, int(p.Type), int(p.PaymentMethod), int(p.Status), p.Amount, p.Currency, p.ExchangeRate,
+				p.BaseAmount, p.PaymentDate, optionalID(p.OriginalPaymentID), optionalID(p.TakenBy), optionalID(p.ShiftID), p.CreatedAt,
+			})
+		}
+		return rows, nil
+	})
+}
+
+func (es *ExportService) ExportCustomers(ctx *gin.Context, filter *domain.Customer, format domain.ExportFormat, w io.Writer) error {
+	header := []interface{}{
+		"Customer ID", "First name", "Surname", "Identity number", "Email", "Phone", "Address", "Gender",
+		"Customer type ID", "Preferences", "Created at", "Updated at",
+	}
+
+	return es.exportTable(w, format, "Customers", header, func(last []interface{}) ([][]interface{}, error) {
+		customers, err := es.customerRepo.ListCustomersBefore(ctx, filter, exportLastID(last), domain.ExportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := make([][]interface{}, 0, len(customers))
+		for _, c := range customers {
+			rows = append(rows, []interface{}{
+				c.ID, c.FirstName, c.Surname, c.IdentityNumber, c.Email, c.Phone, c.Address, c.Gender,
+				c.CustomerTypeID, c.Preferences, c.CreatedAt, c.UpdatedAt,
+			})
+		}
+		return rows, nil
+	})
+}
+
+func (es *ExportService) ExportLogs(ctx *gin.Context, format domain.ExportFormat, w io.Writer) error {
+	header := []interface{}{"Log ID", "Table", "Record ID", "Action", "User ID", "Created at"}
+
+	return es.exportTable(w, format, "Logs", header, func(last []interface{}) ([][]interface{}, error) {
+		logs, err := es.logRepo.GetLogsBefore(ctx, exportLastID(last), domain.ExportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := make([][]interface{}, 0, len(logs))
+		for _, l := range logs {
+			rows = append(rows, []interface{}{l.ID, l.TableName, l.RecordID, l.Action, l.UserID, l.CreatedAt})
+		}
+		return rows, nil
+	})
+}
+
+func (es *ExportService) ExportDailySummaries(ctx *gin.Context, format domain.ExportFormat, w io.Writer) error {
+	header := []interface{}{
+		"Date", "Status", "Created bookings", "Completed bookings", "Canceled bookings",
+		"Total amount", "Total refunds", "Checksum", "Created at", "Updated at",
+	}
+
+	return es.exportTable(w, format, "Daily summaries", header, func(last []interface{}) ([][]interface{}, error) {
+		var before time.Time
+		if last != nil {
+			before = last[0].(time.Time)
+		}
+		summaries, err := es.summaryRepo.ListDailyBookingSummariesBefore(ctx, before, domain.ExportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := make([][]interface{}, 0, len(summaries))
+		for _, s := range summaries {
+			rows = append(rows, []interface{}{
+				s.SummaryDate, int(s.Status),
+				len(s.BookingsIn(domain.SummaryCategoryCreated)),
+				len(s.BookingsIn(domain.SummaryCategoryCompleted)),
+				len(s.BookingsIn(domain.SummaryCategoryCanceled)),
+				s.TotalAmount, s.TotalRefunds, s.Checksum, s.CreatedAt, s.UpdatedAt,
+			})
+		}
+		return rows, nil
+	})
+}
+
+// ExportKPIReport writes the periods of the report, then its room type and rate price breakdowns, each
+// row tagged with its section
+func (es *ExportService) ExportKPIReport(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval, format domain.ExportFormat, w io.Writer) error {
+	report, err := es.reportService.GetKPIReport(ctx, from, to, interval)
+	if err != nil {
+		return err
+	}
+
+	header := []interface{}{
+		"Section", "Period", "ID", "Name", "Room nights available", "Room nights sold", "Room revenue",
+		"Occupancy %", "ADR", "RevPAR", "Payments collected",
+	}
+	metrics := func(m domain.KPIMetrics) []interface{} {
+		return []interface{}{m.RoomNightsAvailable, m.RoomNightsSold, m.RoomRevenue, m.Occupancy, m.ADR, m.RevPAR}
+	}
+
+	var rows [][]interface{}
+	for _, p := range report.Periods {
+		row := append([]interface{}{"Period", p.Start, nil, nil}, metrics(p.KPIMetrics)...)
+		rows = append(rows, append(row, p.PaymentsCollected))
+	}
+	row := append([]interface{}{"Total", nil, nil, nil}, metrics(report.Total)...)
+	rows = append(rows, append(row, report.PaymentsCollected))
+	for _, b := range report.ByRoomType {
+		rows = append(rows, append([]interface{}{"Room type", nil, b.ID, b.Name}, metrics(b.KPIMetrics)...))
+	}
+	for _, b := range report.ByRatePrice {
+		rows = append(rows, append([]interface{}{"Rate price", nil, b.ID, b.Name}, metrics(b.KPIMetrics)...))
+	}
+
+	// The report is already computed in full, so it is written as a single page
+	return es.exportTable(w, format, "KPI report", header, func(last []interface{}) ([][]interface{}, error) {
+		if last != nil {
+			return nil, nil
+		}
+		return rows, nil
+	})
+}
+
+// optionalID returns the ID, or nil for an empty cell
+func optionalID(id *uint64) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type FolioService struct {
+	repo        port.FolioRepository
+	bookingRepo port.BookingRepository
+	paymentRepo port.PaymentRepository
+	payerRepo   port.BookingPayerRepository
+	dateRepo    port.BusinessDateRepository
+	periodRepo  port.ClosedPeriodRepository
+	logRepo     port.LogRepository
+}
+
+func NewFolioService(repo port.FolioRepository, bookingRepo port.BookingRepository, paymentRepo port.PaymentRepository, payerRepo port.BookingPayerRepository, dateRepo port.BusinessDateRepository, periodRepo port.ClosedPeriodRepository, logRepo port.LogRepository) *FolioService {
+	return &FolioService{
+		repo,
+		bookingRepo,
+		paymentRepo,
+		payerRepo,
+		dateRepo,
+		periodRepo,
+		logRepo,
+	}
+}
+
+func (fs *FolioService) GetFolio(ctx *gin.Context, bookingID uint64) (*domain.Folio, error) {
+	_, err := fs.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return loadFolio(ctx, fs.repo, fs.paymentRepo, bookingID)
+}
+
+// ListSplitFolios returns the guest's folio followed by one folio per booking payer
+func (fs *FolioService) ListSplitFolios(ctx *gin.Context, bookingID uint64) ([]domain.Folio, error) {
+	_, err := fs.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	folio, err := loadFolio(ctx, fs.repo, fs.paymentRepo, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	payers, err := fs.payerRepo.ListBookingPayersByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return splitFolio(folio, payers), nil
+}
+
+// AddPayer bills the charge types of a booking to another customer or to a company, e.g. room charges to the guest's company
+func (fs *FolioService) AddPayer(ctx *gin.Context, payer *domain.BookingPayer) (*domain.BookingPayer, error) {
+	if len(payer.ChargeTypes) == 0 || (payer.CustomerID == nil) == (payer.CompanyID == nil) {
+		return nil, domain.ErrInvalidData
+	}
+	for _, chargeType := range payer.ChargeTypes {
+		if chargeType < domain.ChargeTypeRoom || chargeType > domain.ChargeTypeOther {
+			return nil, domain.ErrInvalidData
+		}
+	}
+
+	booking, err := fs.bookingRepo.GetBookingByID(ctx, payer.BookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if payer.CustomerID != nil && booking.CustomerID == *payer.CustomerID {
+		return nil, domain.ErrInvalidData
+	}
+	if err := ensureBookingChangeable(ctx, fs.dateRepo, fs.periodRepo, booking.ID); err != nil {
+		return nil, err
+	}
+
+	// Each charge type is routed to a single payer
+	payers, err := fs.payerRepo.ListBookingPayersByBookingID(ctx, payer.BookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	for _, existingPayer := range payers {
+		for _, chargeType := range payer.ChargeTypes {
+			if existingPayer.Covers(chargeType) {
+				return nil, domain.ErrConflictingData
+			}
+		}
+	}
+
+	createdPayer, err := fs.payerRepo.CreateBookingPayer(ctx, payer)
+	if err != nil {
+		if err == domain.ErrDataNotFound || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  createdPayer.ID,
+		Action:    "CREATE",
+		UserID:    userID.(uint64),
+		TableName: "booking_payers",
+	}
+	_, err = fs.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdPayer, nil
+}
+
+func (fs *FolioService) ListPayers(ctx *gin.Context, bookingID uint64) ([]domain.BookingPayer, error) {
+	_, err := fs.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	payers, err := fs.payerRepo.ListBookingPayersByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return payers, nil
+}
+
+// RemovePayer routes the payer's charge types back to the guest. A payer that paid or was invoiced cannot be removed.
+func (fs *FolioService) RemovePayer(ctx *gin.Context, bookingID, payerID uint64) error {
+	if err := ensureBookingChangeable(ctx, fs.dateRepo, fs.periodRepo, bookingID); err != nil {
+		return err
+	}
+
+	err := fs.payerRepo.DeleteBookingPayer(ctx, bookingID, payerID)
+	if err != nil {
+		if err == domain.ErrDataNotFound || err == domain.ErrConflictingData {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return domain.ErrUnauthorized
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  payerID,
+		Action:    "DELETE",
+		UserID:    userID.(uint64),
+		TableName: "booking_payers",
+	}
+	_, err = fs.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return nil
+}
+
+func (fs *FolioService) PostCharge(ctx *gin.Context, charge *domain.FolioCharge) (*domain.FolioCharge, error) {
+	if charge.ChargeType < domain.ChargeTypeRoom || charge.ChargeType > domain.ChargeTypeOther || charge.ChargeType == domain.ChargeTypeAdjustment {
+		return nil, domain.ErrInvalidData
+	}
+	if charge.Quantity <= 0 || charge.UnitPrice <= 0 || charge.TaxRate < 0 || charge.TaxRate > 100 {
+		return nil, domain.ErrInvalidData
+	}
+
+	charge.Amount = util.RoundAmount(float64(charge.Quantity) * charge.UnitPrice)
+	charge.TaxAmount = util.RoundAmount(charge.Amount * charge.TaxRate / 100)
+
+	return fs.createCharge(ctx, charge)
+}
+
+// PostAdjustment credits the folio with the given amount, e.g. a goodwill discount or a corrected charge
+func (fs *FolioService) PostAdjustment(ctx *gin.Context, bookingID uint64, amount float64, description string) (*domain.FolioCharge, error) {
+	if amount <= 0 || description == "" {
+		return nil, domain.ErrInvalidData
+	}
+
+	credit := util.RoundAmount(-amount)
+	charge := &domain.FolioCharge{
+		BookingID:   bookingID,
+		ChargeType:  domain.ChargeTypeAdjustment,
+		Description: description,
+		Quantity:    1,
+		UnitPrice:   credit,
+		Amount:      credit,
+	}
+
+	return fs.createCharge(ctx, charge)
+}
+
+func (fs *FolioService) createCharge(ctx *gin.Context, charge *domain.FolioCharge) (*domain.FolioCharge, error) {
+	booking, err := fs.bookingRepo.GetBookingByID(ctx, charge.BookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if booking.Status == domain.BookingStatusCanceled {
+		return nil, domain.ErrInvalidData
+	}
+	if err := ensureBookingChangeable(ctx, fs.dateRepo, fs.periodRepo, booking.ID); err != nil {
+		return nil, err
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	postedBy := userID.(uint64)
+	charge.PostedBy = &postedBy
+
+	postedAt, err := businessNow(ctx, fs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	charge.PostedAt = &postedAt
+
+	createdCharge, err := fs.repo.CreateFolioCharge(ctx, charge)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  createdCharge.ID,
+		Action:    "CREATE",
+		UserID:    postedBy,
+		TableName: "folio_charges",
+	}
+	_, err = fs.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdCharge, nil
+}
+
+// postRoomNights posts the room nights of the stay up to and including the given night that are not on the
+// folio yet, and returns the number of nights and the amount posted
+func postRoomNights(ctx *gin.Context, folioRepo port.FolioRepository, booking *domain.Booking, throughNight int, postedAt time.Time) (int, float64, error) {
+	charges, err := folioRepo.ListFolioChargesByBookingID(ctx, booking.ID)
+	if err != nil {
+		return 0, 0, domain.ErrInternal
+	}
+
+	charge := roomNightsCharge(ctx, booking, charges, throughNight, postedAt)
+	if charge == nil {
+		return 0, 0, nil
+	}
+	if _, err := folioRepo.CreateFolioCharge(ctx, charge); err != nil {
+		return 0, 0, domain.ErrInternal
+	}
+
+	return charge.Quantity, charge.Amount, nil
+}
+
+// roomNightsCharge returns the room charge of the nights of the stay up to and including the given night that
+// are not among the posted charges yet, or nil when there are none. Each night is priced so that the posted
+// nights of a full stay add up to the booking total.
+func roomNightsCharge(ctx *gin.Context, booking *domain.Booking, charges []domain.FolioCharge, throughNight int, postedAt time.Time) *domain.FolioCharge {
+	nights := booking.Nights()
+	if throughNight > nights {
+		throughNight = nights
+	}
+
+	posted := 0
+	for _, charge := range charges {
+		if charge.ChargeType == domain.ChargeTypeRoom {
+			posted += charge.Quantity
+		}
+	}
+	if posted >= throughNight {
+		return nil
+	}
+
+	quantity := throughNight - posted
+	amount := util.RoundAmount(roomAmountThrough(booking, throughNight) - roomAmountThrough(booking, posted))
+	charge := &domain.FolioCharge{
+		BookingID:   booking.ID,
+		ChargeType:  domain.ChargeTypeRoom,
+		Description: "Room charge",
+		Quantity:    quantity,
+		UnitPrice:   util.RoundAmount(amount / float64(quantity)),
+		Amount:      amount,
+		PostedAt:    &postedAt,
+	}
+	if userID, exists := ctx.Get("userID"); exists {
+		postedBy := userID.(uint64)
+		charge.PostedBy = &postedBy
+	}
+
+	return charge
+}
+
+// roomAmountThrough returns the room revenue of the first nights of a booking's stay
+func roomAmountThrough(booking *domain.Booking, nights int) float64 {
+	return util.RoundAmount(booking.TotalAmount * float64(nights) / float64(booking.Nights()))
+}
+
+// loadFolio assembles a booking's folio from its posted charges and payments and computes the balance
+func loadFolio(ctx *gin.Context, folioRepo port.FolioRepository, paymentRepo port.PaymentRepository, bookingID uint64) (*domain.Folio, error) {
+	charges, err := folioRepo.ListFolioChargesByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	payments, err := paymentRepo.ListPaymentsByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return newFolio(bookingID, nil, charges, payments), nil
+}
+
+// loadPayerFolio returns the share of a booking's folio billed to a payer, or to the guest when payerID is zero
+func loadPayerFolio(ctx *gin.Context, folioRepo port.FolioRepository, paymentRepo port.PaymentRepository, payerRepo port.BookingPayerRepository, bookingID, payerID uint64) (*domain.Folio, error) {
+	folio, err := loadFolio(ctx, folioRepo, paymentRepo, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	payers, err := payerRepo.ListBookingPayersByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	for _, payerFolio := range splitFolio(folio, payers) {
+		if (payerID == 0 && payerFolio.PayerID == nil) || (payerFolio.PayerID != nil && *payerFolio.PayerID == payerID) {
+			return &payerFolio, nil
+		}
+	}
+
+	return nil, domain.ErrDataNotFound
+}
+
+// splitFolio routes a booking's folio to its payers. Charges go to the payer covering their type and
+// payments to the payer they were taken from; the rest stays on the guest's folio, which comes first.
+func splitFolio(folio *domain.Folio, payers []domain.BookingPayer) []domain.Folio {
+	var guestCharges []domain.FolioCharge
+	var guestPayments []domain.Payment
+	payerCharges := make(map[uint64][]domain.FolioCharge)
+	payerPayments := make(map[uint64][]domain.Payment)
+
+	for _, charge := range folio.Charges {
+		routed := false
+		for _, payer := range payers {
+			if payer.Covers(charge.ChargeType) {
+				payerCharges[payer.ID] = append(payerCharges[payer.ID], charge)
+				routed = true
+				break
+			}
+		}
+		if !routed {
+			guestCharges = append(guestCharges, charge)
+		}
+	}
+
+	for _, payment := range folio.Payments {
+		if payment.PayerID == nil {
+			guestPayments = append(guestPayments, payment)
+			continue
+		}
+		payerPayments[*payment.PayerID] = append(payerPayments[*payment.PayerID], payment)
+	}
+
+	folios := []domain.Folio{*newFolio(folio.BookingID, nil, guestCharges, guestPayments)}
+	for _, payer := range payers {
+		payerID := payer.ID
+		folios = append(folios, *newFolio(folio.BookingID, &payerID, payerCharges[payer.ID], payerPayments[payer.ID]))
+	}
+
+	return folios
+}
+
+// newFolio computes the totals and balance of a folio from its charges and payments
+func newFolio(bookingID uint64, payerID *uint64, charges []domain.FolioCharge, payments []domain.Payment) *domain.Folio {
+	folio := &domain.Folio{
+		BookingID: bookingID,
+		PayerID:   payerID,
+		Charges:   charges,
+		Payments:  payments,
+	}
+
+	for _, charge := range charges {
+		if charge.ChargeType == domain.ChargeTypeAdjustment {
+			folio.TotalAdjustments -= charge.Total()
+			continue
+		}
+		folio.TotalCharges += charge.Total()
+	}
+
+	for _, payment := range payments {
+		if payment.Status == domain.PaymentStatusPaid {
+			folio.TotalPayments += payment.BaseAmount
+		}
+	}
+
+	folio.TotalCharges = util.RoundAmount(folio.TotalCharges)
+	folio.TotalAdjustments = util.RoundAmount(folio.TotalAdjustments)
+	folio.TotalPayments = util.RoundAmount(folio.TotalPayments)
+	folio.Balance = util.RoundAmount(folio.TotalCharges - folio.TotalAdjustments - folio.TotalPayments)
+
+	return folio
+}
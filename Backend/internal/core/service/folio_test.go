@@ -0,0 +1,126 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+func testBooking(totalAmount float64, nights int) *domain.Booking {
+	checkIn := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, nights)
+	return &domain.Booking{ID: 1, TotalAmount: totalAmount, CheckInDate: &checkIn, CheckOutDate: &checkOut}
+}
+
+func TestRoomAmountThrough(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   float64
+		nights  int
+		through int
+		want    float64
+	}{
+		{"no nights", 1000, 3, 0, 0},
+		{"first of three", 1000, 3, 1, 333.33},
+		{"two of three", 1000, 3, 2, 666.67},
+		{"whole stay", 1000, 3, 3, 1000},
+		{"even split", 1500, 3, 2, 1000},
+		{"one of seven", 999.99, 7, 1, 142.86},
+		{"whole stay of seven", 999.99, 7, 7, 999.99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := roomAmountThrough(testBooking(tt.total, tt.nights), tt.through)
+			if got != tt.want {
+				t.Errorf("roomAmountThrough(%d) = %v, want %v", tt.through, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoomNightsCharge(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Set("userID", uint64(7))
+	postedAt := time.Date(2024, 3, 2, 23, 0, 0, 0, time.UTC)
+	room := func(quantity int, amount float64) domain.FolioCharge {
+		return domain.FolioCharge{ChargeType: domain.ChargeTypeRoom, Quantity: quantity, Amount: amount}
+	}
+
+	tests := []struct {
+		name         string
+		charges      []domain.FolioCharge
+		throughNight int
+		wantQuantity int // Zero when no charge is due
+		wantAmount   float64
+	}{
+		{"first night", nil, 1, 1, 333.33},
+		{"second night", []domain.FolioCharge{room(1, 333.33)}, 2, 1, 333.34},
+		{"last night", []domain.FolioCharge{room(2, 666.67)}, 3, 1, 333.33},
+		{"rest of the stay at check-out", []domain.FolioCharge{room(1, 333.33)}, 3, 2, 666.67},
+		{"past check-out stops at the last night", nil, 5, 3, 1000},
+		{"already posted", []domain.FolioCharge{room(2, 666.67)}, 2, 0, 0},
+		{"other charges do not count", []domain.FolioCharge{{ChargeType: domain.ChargeTypeRoom + 1, Quantity: 2, Amount: 50}}, 1, 1, 333.33},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charge := roomNightsCharge(ctx, testBooking(1000, 3), tt.charges, tt.throughNight, postedAt)
+			if tt.wantQuantity == 0 {
+				if charge != nil {
+					t.Fatalf("charge = %+v, want none", charge)
+				}
+				return
+			}
+			if charge == nil {
+				t.Fatal("charge = nil, want one")
+			}
+			if charge.Quantity != tt.wantQuantity || charge.Amount != tt.wantAmount {
+				t.Errorf("charge = %d nights for %v, want %d nights for %v", charge.Quantity, charge.Amount, tt.wantQuantity, tt.wantAmount)
+			}
+			if charge.PostedBy == nil || *charge.PostedBy != 7 {
+				t.Errorf("charge posted by %v, want user 7", charge.PostedBy)
+			}
+		})
+	}
+}
+
+// Posting a stay night by night, as the night audit does, adds up to the booking total however it rounds
+func TestRoomNightsChargeAddsUpToTotal(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	tests := []struct {
+		total  float64
+		nights int
+	}{
+		{1000, 3},
+		{999.99, 7},
+		{100, 6},
+		{2500.5, 4},
+		{1, 3},
+	}
+
+	for _, tt := range tests {
+		booking := testBooking(tt.total, tt.nights)
+		var charges []domain.FolioCharge
+		for night := 1; night <= tt.nights; night++ {
+			charge := roomNightsCharge(ctx, booking, charges, night, time.Now())
+			if charge == nil || charge.Quantity != 1 {
+				t.Fatalf("%v over %d nights: night %d charge = %+v, want one night", tt.total, tt.nights, night, charge)
+			}
+			charges = append(charges, *charge)
+		}
+
+		sum := 0.0
+		for _, charge := range charges {
+			sum += charge.Amount
+		}
+		if util.RoundAmount(sum) != tt.total {
+			t.Errorf("%v over %d nights: nightly charges add up to %v", tt.total, tt.nights, util.RoundAmount(sum))
+		}
+	}
+}
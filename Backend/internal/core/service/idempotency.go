@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+type IdempotencyService struct {
+	repo port.IdempotencyRepository
+}
+
+func NewIdempotencyService(repo port.IdempotencyRepository) *IdempotencyService {
+	return &IdempotencyService{
+		repo,
+	}
+}
+
+func (is *IdempotencyService) StartRequest(ctx *gin.Context, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	key.UserID = userID.(uint64)
+
+	claimedKey, err := is.repo.CreateIdempotencyKey(ctx, key)
+	if err == nil {
+		return claimedKey, nil
+	}
+	if err != domain.ErrConflictingData {
+		return nil, domain.ErrInternal
+	}
+
+	existingKey, err := is.repo.GetIdempotencyKey(ctx, key.UserID, key.Key)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	if !existingKey.Matches(key) {
+		return nil, domain.ErrIdempotencyKeyMismatch
+	}
+	if !existingKey.IsCompleted() {
+		return nil, domain.ErrIdempotencyKeyInProgress
+	}
+
+	return existingKey, nil
+}
+
+func (is *IdempotencyService) CompleteRequest(ctx *gin.Context, key *domain.IdempotencyKey, status int, body []byte) error {
+	now := time.Now()
+	key.ResponseStatus = status
+	key.ResponseBody = body
+	key.CompletedAt = &now
+
+	if err := is.repo.CompleteIdempotencyKey(ctx, key); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (is *IdempotencyService) ReleaseRequest(ctx *gin.Context, key *domain.IdempotencyKey) error {
+	if err := is.repo.DeleteIdempotencyKey(ctx, key); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (is *IdempotencyService) DeleteExpiredKeys(ctx context.Context, asOf time.Time) (int, error) {
+	deleted, err := is.repo.DeleteExpiredIdempotencyKeys(ctx, asOf.Add(-domain.IdempotencyKeyTTL))
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return deleted, nil
+}
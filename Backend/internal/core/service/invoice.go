@@ -0,0 +1,306 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceService struct {
+	repo        port.InvoiceRepository
+	bookingRepo port.BookingRepository
+	folioRepo   port.FolioRepository
+	paymentRepo port.PaymentRepository
+	payerRepo   port.BookingPayerRepository
+	dateRepo    port.BusinessDateRepository
+	logRepo     port.LogRepository
+}
+
+func NewInvoiceService(repo port.InvoiceRepository, bookingRepo port.BookingRepository, folioRepo port.FolioRepository, paymentRepo port.PaymentRepository, payerRepo port.BookingPayerRepository, dateRepo port.BusinessDateRepository, logRepo port.LogRepository) *InvoiceService {
+	return &InvoiceService{
+		repo,
+		bookingRepo,
+		folioRepo,
+		paymentRepo,
+		payerRepo,
+		dateRepo,
+		logRepo,
+	}
+}
+
+func (is *InvoiceService) CreateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	if err := validateInvoiceSeries(series); err != nil {
+		return nil, err
+	}
+
+	createdSeries, err := is.repo.CreateInvoiceSeries(ctx, series)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	is.createLog(ctx, createdSeries.ID, "CREATE", "invoice_series")
+
+	return createdSeries, nil
+}
+
+func (is *InvoiceService) ListInvoiceSeries(ctx *gin.Context) ([]domain.InvoiceSeries, error) {
+	seriesList, err := is.repo.ListInvoiceSeries(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return seriesList, nil
+}
+
+// UpdateInvoiceSeries changes how future numbers of a series are printed. The code, type and
+// reset rule are fixed once the series exists because they decide which counter a number comes from.
+func (is *InvoiceService) UpdateInvoiceSeries(ctx *gin.Context, series *domain.InvoiceSeries) (*domain.InvoiceSeries, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+
+	existingSeries, err := is.repo.GetInvoiceSeriesByID(ctx, series.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	series.Code = existingSeries.Code
+	series.InvoiceType = existingSeries.InvoiceType
+	series.YearlyReset = existingSeries.YearlyReset
+	if err := validateInvoiceSeries(series); err != nil {
+		return nil, err
+	}
+
+	updatedSeries, err := is.repo.UpdateInvoiceSeries(ctx, series)
+	if err != nil {
+		if err == domain.ErrDataNotFound || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	is.createLog(ctx, updatedSeries.ID, "UPDATE", "invoice_series")
+
+	return updatedSeries, nil
+}
+
+func validateInvoiceSeries(series *domain.InvoiceSeries) error {
+	if series.Code == "" || series.Prefix == "" {
+		return domain.ErrInvalidData
+	}
+	if series.InvoiceType != domain.InvoiceTypeTaxInvoice && series.InvoiceType != domain.InvoiceTypeCreditNote {
+		return domain.ErrInvalidData
+	}
+	if series.Padding < 1 || series.Padding > 12 {
+		return domain.ErrInvalidData
+	}
+	return nil
+}
+
+// IssueInvoice issues a numbered tax invoice for the current folio of a booking payer, or of the
+// guest when payerID is zero, and stores its charges and payments with it. A seriesID of zero uses
+// the default tax invoice series. Each payer has at most one tax invoice per booking that has not
+// been cancelled by a credit note.
+func (is *InvoiceService) IssueInvoice(ctx *gin.Context, bookingID, payerID, seriesID uint64) (*domain.Invoice, error) {
+	booking, err := is.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if booking.Status == domain.BookingStatusCanceled {
+		return nil, domain.ErrInvalidData
+	}
+
+	series, err := is.getSeries(ctx, seriesID, domain.InvoiceTypeTaxInvoice)
+	if err != nil {
+		return nil, err
+	}
+
+	folio, err := loadPayerFolio(ctx, is.folioRepo, is.paymentRepo, is.payerRepo, bookingID, payerID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &domain.Invoice{
+		InvoiceType: domain.InvoiceTypeTaxInvoice,
+		BookingID:   bookingID,
+		PayerID:     folio.PayerID,
+	}
+	// Charges are stored as printed, adjustments last
+	var adjustments []domain.FolioCharge
+	for _, charge := range folio.Charges {
+		invoice.Subtotal += charge.Amount
+		invoice.VAT += charge.TaxAmount
+		if charge.ChargeType == domain.ChargeTypeAdjustment {
+			adjustments = append(adjustments, charge)
+			continue
+		}
+		invoice.Charges = append(invoice.Charges, charge)
+	}
+	invoice.Charges = append(invoice.Charges, adjustments...)
+	for _, payment := range folio.Payments {
+		if payment.Status == domain.PaymentStatusPaid {
+			invoice.Payments = append(invoice.Payments, payment)
+		}
+	}
+	invoice.Subtotal = util.RoundAmount(invoice.Subtotal)
+	invoice.VAT = util.RoundAmount(invoice.VAT)
+	invoice.Total = util.RoundAmount(invoice.Subtotal + invoice.VAT)
+	if invoice.Total <= 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	return is.issue(ctx, invoice, series)
+}
+
+// IssueCreditNote cancels a tax invoice in full with a credit note that references its number
+func (is *InvoiceService) IssueCreditNote(ctx *gin.Context, invoiceID uint64, reason string) (*domain.Invoice, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	if reason == "" {
+		return nil, domain.ErrInvalidData
+	}
+
+	original, err := is.repo.GetInvoiceByID(ctx, invoiceID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if original.InvoiceType != domain.InvoiceTypeTaxInvoice {
+		return nil, domain.ErrInvalidData
+	}
+
+	if original.Cancelled {
+		return nil, domain.ErrConflictingData
+	}
+
+	series, err := is.getSeries(ctx, 0, domain.InvoiceTypeCreditNote)
+	if err != nil {
+		return nil, err
+	}
+
+	creditNote := &domain.Invoice{
+		InvoiceType:       domain.InvoiceTypeCreditNote,
+		BookingID:         original.BookingID,
+		OriginalInvoiceID: &original.ID,
+		Subtotal:          -original.Subtotal,
+		VAT:               -original.VAT,
+		Total:             -original.Total,
+		Reason:            reason,
+		PayerID:           original.PayerID,
+	}
+
+	return is.issue(ctx, creditNote, series)
+}
+
+func (is *InvoiceService) GetInvoice(ctx *gin.Context, id uint64) (*domain.Invoice, error) {
+	invoice, err := is.repo.GetInvoiceByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return invoice, nil
+}
+
+func (is *InvoiceService) ListBookingInvoices(ctx *gin.Context, bookingID uint64) ([]domain.Invoice, error) {
+	_, err := is.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	invoices, err := is.repo.ListInvoicesByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return invoices, nil
+}
+
+// getSeries returns the given series, or the default series of the invoice type when id is zero
+func (is *InvoiceService) getSeries(ctx *gin.Context, id uint64, invoiceType domain.InvoiceType) (*domain.InvoiceSeries, error) {
+	var series *domain.InvoiceSeries
+	var err error
+	if id == 0 {
+		series, err = is.repo.GetDefaultInvoiceSeries(ctx, invoiceType)
+	} else {
+		series, err = is.repo.GetInvoiceSeriesByID(ctx, id)
+	}
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if series.InvoiceType != invoiceType {
+		return nil, domain.ErrInvalidData
+	}
+
+	return series, nil
+}
+
+func (is *InvoiceService) issue(ctx *gin.Context, invoice *domain.Invoice, series *domain.InvoiceSeries) (*domain.Invoice, error) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	issuedBy := userID.(uint64)
+	issuedAt, err := businessNow(ctx, is.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	invoice.IssuedBy = &issuedBy
+	invoice.IssuedAt = &issuedAt
+
+	issuedInvoice, err := is.repo.IssueInvoice(ctx, invoice, series)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	is.createLog(ctx, issuedInvoice.ID, "CREATE", "invoices")
+
+	return issuedInvoice, nil
+}
+
+func (is *InvoiceService) createLog(ctx *gin.Context, recordID uint64, action, tableName string) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return
+	}
+
+	log := &domain.Log{
+		RecordID:  recordID,
+		Action:    action,
+		UserID:    userID.(uint64),
+		TableName: tableName,
+	}
+	_, err := is.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+}
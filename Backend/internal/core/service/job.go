@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+type JobService struct {
+	repo               port.JobRunRepository
+	userRepo           port.UserRepository
+	summaryService     port.DailyBookingSummaryService
+	reportService      port.ReportService
+	loyaltyService     port.LoyaltyService
+	idempotencyService port.IdempotencyService
+	logRepo            port.LogRepository
+}
+
+func NewJobService(repo port.JobRunRepository, userRepo port.UserRepository, summaryService port.DailyBookingSummaryService, reportService port.ReportService, loyaltyService port.LoyaltyService, idempotencyService port.IdempotencyService, logRepo port.LogRepository) *JobService {
+	return &JobService{
+		repo,
+		userRepo,
+		summaryService,
+		reportService,
+		loyaltyService,
+		idempotencyService,
+		logRepo,
+	}
+}
+
+// GenerateDailySummary generates the daily summary of date as the system user and records the attempt.
+// A date already closed by the night audit or a confirmed summary keeps its summary, so the run is skipped.
+func (js *JobService) GenerateDailySummary(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error) {
+	return js.runJob(ctx, domain.JobDailySummary, date, attempt, func(actorID uint64) error {
+		_, err := js.summaryService.GenerateDailySummary(ctx, actorID, date)
+		return err
+	})
+}
+
+// SnapshotForecast stores the rooms on the books as of date and records the attempt
+func (js *JobService) SnapshotForecast(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error) {
+	return js.runJob(ctx, domain.JobForecastSnapshot, date, attempt, func(actorID uint64) error {
+		return js.reportService.SnapshotForecast(ctx, date)
+	})
+}
+
+// ExpireLoyaltyPoints lapses the loyalty points that expired by the start of date and records the attempt
+func (js *JobService) ExpireLoyaltyPoints(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error) {
+	return js.runJob(ctx, domain.JobLoyaltyExpiry, date, attempt, func(actorID uint64) error {
+		expired, err := js.loyaltyService.ExpirePoints(ctx, date)
+		if err != nil {
+			return err
+		}
+		slog.Info("Loyalty points expired", "date", date.Format("2006-01-02"), "customers", expired)
+		return nil
+	})
+}
+
+// CleanUpIdempotencyKeys deletes the idempotency keys past their TTL and records the attempt
+func (js *JobService) CleanUpIdempotencyKeys(ctx context.Context, date time.Time, attempt int) (*domain.JobRun, error) {
+	return js.runJob(ctx, domain.JobIdempotencyCleanup, date, attempt, func(actorID uint64) error {
+		deleted, err := js.idempotencyService.DeleteExpiredKeys(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		slog.Info("Idempotency keys deleted", "date", date.Format("2006-01-02"), "keys", deleted)
+		return nil
+	})
+}
+
+// runJob runs job as the system user and records the attempt as a run of name for date. A job refused
+// because its day is locked or closed is recorded as skipped. The run is recorded even when ctx is
+// canceled part way, so a job stopped by shutdown shows as failed rather than running.
+func (js *JobService) runJob(ctx context.Context, name string, date time.Time, attempt int, job func(actorID uint64) error) (*domain.JobRun, error) {
+	user, err := js.userRepo.GetUserByUserName(ctx, domain.SystemUserName)
+	if err != nil {
+		slog.Error("Error getting system user", "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	now := time.Now()
+	run, err := js.repo.CreateJobRun(ctx, &domain.JobRun{
+		JobName:    name,
+		TargetDate: date,
+		Attempt:    attempt,
+		StartedAt:  &now,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	jobErr := job(user.ID)
+	switch jobErr {
+	case nil:
+		run.Status = domain.JobRunStatusSucceeded
+	case domain.ErrDayLocked, domain.ErrDayClosed:
+		run.Status = domain.JobRunStatusSkipped
+		run.ErrorMessage = jobErr.Error()
+		jobErr = nil
+	default:
+		run.Status = domain.JobRunStatusFailed
+		run.ErrorMessage = jobErr.Error()
+	}
+
+	recordCtx := context.WithoutCancel(ctx)
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run, err = js.repo.FinishJobRun(recordCtx, run)
+	if err != nil {
+		slog.Error("Error recording job run", "job", name, "date", date.Format("2006-01-02"), "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	log := &domain.Log{
+		RecordID:  run.ID,
+		Action:    "RUN",
+		UserID:    user.ID,
+		TableName: "job_runs",
+	}
+	if _, err := js.logRepo.CreateLog(recordCtx, log); err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return run, jobErr
+}
+
+func (js *JobService) ListJobRuns(ctx *gin.Context, jobName string, skip, limit uint64) ([]domain.JobRun, uint64, error) {
+	if !isAdmin(ctx) {
+		return nil, 0, domain.ErrForbidden
+	}
+
+	runs, totalCount, err := js.repo.ListJobRuns(ctx, jobName, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return runs, totalCount, nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
 )
 
 type LogService struct {
@@ -26,3 +27,33 @@ func (ls *LogService) GetLogs(ctx *gin.Context, skip, limit uint64) ([]domain.Lo
 
 	return logs, totalCount, nil
 }
+
+// GetStaffActivity reports the work each user logged per day from..to, so managers can review staff and spot
+// unusual deletes. Only admins can get it.
+func (ls *LogService) GetStaffActivity(ctx *gin.Context, filter *domain.StaffActivityFilter) (*domain.StaffActivityReport, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	if filter.To.Before(filter.From) || daysBetween(filter.From, filter.To) >= domain.MaxReportDays {
+		return nil, domain.ErrInvalidData
+	}
+
+	days, err := ls.repo.ListStaffActivity(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	report := &domain.StaffActivityReport{
+		Filter: *filter,
+		Days:   days,
+	}
+	for i := range report.Days {
+		report.Total.Add(report.Days[i].StaffActivityCounts)
+		report.Days[i].PaymentsAmount = util.RoundAmount(report.Days[i].PaymentsAmount)
+		report.Days[i].DeletedAmount = util.RoundAmount(report.Days[i].DeletedAmount)
+	}
+	report.Total.PaymentsAmount = util.RoundAmount(report.Total.PaymentsAmount)
+	report.Total.DeletedAmount = util.RoundAmount(report.Total.DeletedAmount)
+
+	return report, nil
+}
@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type LoyaltyService struct {
+	repo         port.LoyaltyRepository
+	customerRepo port.CustomerRepository
+	bookingRepo  port.BookingRepository
+	paymentRepo  port.PaymentRepository
+	folioService port.FolioService
+	dateRepo     port.BusinessDateRepository
+	periodRepo   port.ClosedPeriodRepository
+	program      domain.LoyaltyProgram
+	logRepo      port.LogRepository
+}
+
+func NewLoyaltyService(repo port.LoyaltyRepository, customerRepo port.CustomerRepository, bookingRepo port.BookingRepository, paymentRepo port.PaymentRepository, folioService port.FolioService, dateRepo port.BusinessDateRepository, periodRepo port.ClosedPeriodRepository, program domain.LoyaltyProgram, logRepo port.LogRepository) *LoyaltyService {
+	return &LoyaltyService{
+		repo,
+		customerRepo,
+		bookingRepo,
+		paymentRepo,
+		folioService,
+		dateRepo,
+		periodRepo,
+		program,
+		logRepo,
+	}
+}
+
+// GetAccount returns the customer's points balance and tier, after lapsing their expired points
+func (ls *LoyaltyService) GetAccount(ctx *gin.Context, customerID uint64) (*domain.LoyaltyAccount, error) {
+	_, err := ls.customerRepo.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	now, err := businessNow(ctx, ls.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ls.repo.ExpirePoints(ctx, now, customerID); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	balance, qualifying, err := ls.repo.GetBalance(ctx, customerID, now.AddDate(0, -domain.LoyaltyTierMonths, 0))
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	account := &domain.LoyaltyAccount{
+		CustomerID:       customerID,
+		Balance:          balance,
+		BalanceValue:     util.RoundAmount(ls.program.Value(balance)),
+		QualifyingPoints: qualifying,
+	}
+	account.Tier, account.NextTier = ls.program.TierFor(qualifying)
+	if account.NextTier != nil {
+		account.PointsToNextTier = account.NextTier.MinPoints - qualifying
+	}
+
+	return account, nil
+}
+
+func (ls *LoyaltyService) ListTransactions(ctx *gin.Context, customerID, skip, limit uint64) ([]domain.LoyaltyTransaction, uint64, error) {
+	transactions, totalCount, err := ls.repo.ListTransactionsByCustomerID(ctx, customerID, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return transactions, totalCount, nil
+}
+
+// EarnPoints credits the guest of a completed booking with points for the money they paid for it. Payments
+// taken from booking payers and payments made with points earn nothing. A booking that already earned its
+// points returns ErrConflictingData.
+func (ls *LoyaltyService) EarnPoints(ctx *gin.Context, bookingID uint64) (*domain.LoyaltyTransaction, error) {
+	booking, err := ls.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if booking.Status != domain.BookingStatusCompleted {
+		return nil, domain.ErrInvalidData
+	}
+
+	payments, err := ls.paymentRepo.ListPaymentsByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	spent := earningSpend(payments)
+	points := ls.program.PointsEarned(spent)
+	if points <= 0 {
+		return nil, nil
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	createdBy := userID.(uint64)
+
+	now, err := businessNow(ctx, ls.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	transaction := &domain.LoyaltyTransaction{
+		CustomerID:  booking.CustomerID,
+		Type:        domain.LoyaltyTransactionTypeEarn,
+		Points:      points,
+		BookingID:   &bookingID,
+		Description: fmt.Sprintf("Stay of booking %d, %.2f THB", bookingID, spent),
+		ExpiresAt:   ls.program.ExpiresAt(now),
+		CreatedBy:   &createdBy,
+	}
+	createdTransaction, err := ls.repo.CreateTransaction(ctx, transaction)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	ls.createLog(ctx, createdTransaction.ID, "EARN")
+
+	return createdTransaction, nil
+}
+
+// ClawBackForReversal takes back the points a booking earned on the money a refund or void of one of its
+// payments gave back, so the points left are those the booking earns on what it still paid. A booking that
+// has not earned its points yet earns them on the net amount when it does, so nothing is taken back.
+func (ls *LoyaltyService) ClawBackForReversal(ctx *gin.Context, reversal *domain.Payment) (*domain.LoyaltyTransaction, error) {
+	earn, err := ls.repo.GetEarnByBookingID(ctx, reversal.BookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, nil
+		}
+		return nil, domain.ErrInternal
+	}
+	adjusted, err := ls.repo.GetEarnAdjustedPoints(ctx, reversal.BookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	payments, err := ls.paymentRepo.ListPaymentsByBookingID(ctx, reversal.BookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	points := ls.program.PointsEarned(earningSpend(payments)) - (earn.Points + adjusted)
+	if points >= 0 {
+		return nil, nil
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	createdBy := userID.(uint64)
+
+	reversalID := reversal.ID
+	transaction := &domain.LoyaltyTransaction{
+		CustomerID:  earn.CustomerID,
+		Type:        domain.LoyaltyTransactionTypeEarnAdjustment,
+		Points:      points,
+		BookingID:   earn.BookingID,
+		PaymentID:   &reversalID,
+		Description: fmt.Sprintf("Reversal of payment %d", *reversal.OriginalPaymentID),
+		CreatedBy:   &createdBy,
+	}
+	createdTransaction, err := ls.repo.CreateTransaction(ctx, transaction)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	ls.createLog(ctx, createdTransaction.ID, "CLAWBACK")
+
+	return createdTransaction, nil
+}
+
+// earningSpend totals the money paid for a booking that earns points. Payments taken from booking payers and
+// payments made with points earn nothing. Refunds and voids are paid negative payments, so they take back
+// what they reverse.
+func earningSpend(payments []domain.Payment) float64 {
+	spent := 0.0
+	for _, payment := range payments {
+		if payment.Status != domain.PaymentStatusPaid || payment.PayerID != nil || payment.PaymentMethod == domain.PaymentMethodLoyaltyPoints {
+			continue
+		}
+		spent += payment.BaseAmount
+	}
+	return util.RoundAmount(spent)
+}
+
+// RedeemForPayment spends the points that pay a loyalty points payment, rounded up to a whole point, from the
+// balance of the booking guest
+func (ls *LoyaltyService) RedeemForPayment(ctx *gin.Context, payment *domain.Payment) (*domain.LoyaltyTransaction, error) {
+	if payment.PaymentMethod != domain.PaymentMethodLoyaltyPoints || payment.BaseAmount <= 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	booking, err := ls.bookingRepo.GetBookingByID(ctx, payment.BookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	paymentID := payment.ID
+	return ls.redeem(ctx, &domain.LoyaltyTransaction{
+		CustomerID:  booking.CustomerID,
+		Points:      -ls.program.PointsFor(payment.BaseAmount),
+		BookingID:   &booking.ID,
+		PaymentID:   &paymentID,
+		Description: fmt.Sprintf("Payment of %.2f THB", payment.BaseAmount),
+	})
+}
+
+// ReinstateForReversal gives back the points of a loyalty points payment in the share of it reversed so far,
+// so the points of partial refunds add up to those redeemed once the payment is fully reversed
+func (ls *LoyaltyService) ReinstateForReversal(ctx *gin.Context, original, reversal *domain.Payment) (*domain.LoyaltyTransaction, error) {
+	if original.PaymentMethod != domain.PaymentMethodLoyaltyPoints || original.Amount <= 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	redemption, err := ls.repo.GetRedemptionByPaymentID(ctx, original.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	reversedAmount, err := ls.paymentRepo.GetReversedAmount(ctx, original.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	reinstated, err := ls.repo.GetReinstatedPoints(ctx, original.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	points := int(math.Round(float64(-redemption.Points)*reversedAmount/original.Amount)) - reinstated
+	if points <= 0 {
+		return nil, nil
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	createdBy := userID.(uint64)
+
+	reversalID := reversal.ID
+	transaction := &domain.LoyaltyTransaction{
+		CustomerID:  redemption.CustomerID,
+		Type:        domain.LoyaltyTransactionTypeReinstate,
+		Points:      points,
+		BookingID:   redemption.BookingID,
+		PaymentID:   &reversalID,
+		Description: fmt.Sprintf("Reversal of payment %d", original.ID),
+		CreatedBy:   &createdBy,
+	}
+	createdTransaction, err := ls.repo.CreateTransaction(ctx, transaction)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	ls.createLog(ctx, createdTransaction.ID, "REINSTATE")
+
+	return createdTransaction, nil
+}
+
+// RedeemDiscount spends points of the booking guest on a folio adjustment worth their value. The points are
+// given back when the adjustment cannot be posted.
+func (ls *LoyaltyService) RedeemDiscount(ctx *gin.Context, bookingID uint64, points int) (*domain.LoyaltyTransaction, error) {
+	if points <= 0 {
+		return nil, domain.ErrInvalidData
+	}
+
+	booking, err := ls.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if booking.Status == domain.BookingStatusCanceled {
+		return nil, domain.ErrInvalidData
+	}
+	// Checked before the points are spent, as the discount could not be posted
+	if err := ensureBookingChangeable(ctx, ls.dateRepo, ls.periodRepo, bookingID); err != nil {
+		return nil, err
+	}
+
+	amount := util.RoundAmount(ls.program.Value(points))
+	redemption, err := ls.redeem(ctx, &domain.LoyaltyTransaction{
+		CustomerID:  booking.CustomerID,
+		Points:      -points,
+		BookingID:   &bookingID,
+		Description: fmt.Sprintf("Discount of %.2f THB", amount),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = ls.folioService.PostAdjustment(ctx, bookingID, amount, fmt.Sprintf("Loyalty points discount (%d points)", points))
+	if err != nil {
+		// Give the points back rather than keep a redemption without its discount
+		reinstatement := &domain.LoyaltyTransaction{
+			CustomerID:  booking.CustomerID,
+			Type:        domain.LoyaltyTransactionTypeReinstate,
+			Points:      points,
+			BookingID:   &bookingID,
+			Description: "Discount not posted",
+			CreatedBy:   redemption.CreatedBy,
+		}
+		if _, reinstateErr := ls.repo.CreateTransaction(ctx, reinstatement); reinstateErr != nil {
+			slog.Error("Error reinstating loyalty points of unposted discount", "transaction_id", redemption.ID, "error", reinstateErr)
+		}
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// ExpirePoints lapses the unspent points of every customer that expired by asOf and returns the number of
+// customers whose points expired
+func (ls *LoyaltyService) ExpirePoints(ctx context.Context, asOf time.Time) (int, error) {
+	expired, err := ls.repo.ExpirePoints(ctx, asOf, 0)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return expired, nil
+}
+
+// redeem records a redemption as the current user, after lapsing the customer's expired points so they
+// cannot be spent
+func (ls *LoyaltyService) redeem(ctx *gin.Context, transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	createdBy := userID.(uint64)
+	transaction.Type = domain.LoyaltyTransactionTypeRedeem
+	transaction.CreatedBy = &createdBy
+
+	now, err := businessNow(ctx, ls.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ls.repo.ExpirePoints(ctx, now, transaction.CustomerID); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	createdTransaction, err := ls.repo.RedeemPoints(ctx, transaction)
+	if err != nil {
+		if err == domain.ErrInsufficientPoints || err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	ls.createLog(ctx, createdTransaction.ID, "REDEEM")
+
+	return createdTransaction, nil
+}
+
+func (ls *LoyaltyService) createLog(ctx *gin.Context, recordID uint64, action string) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return
+	}
+
+	log := &domain.Log{
+		RecordID:  recordID,
+		Action:    action,
+		UserID:    userID.(uint64),
+		TableName: "loyalty_transactions",
+	}
+	_, err := ls.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type NightAuditService struct {
+	repo           port.NightAuditRepository
+	bookingRepo    port.BookingRepository
+	folioRepo      port.FolioRepository
+	paymentRepo    port.PaymentRepository
+	summaryService port.DailyBookingSummaryService
+	logRepo        port.LogRepository
+}
+
+func NewNightAuditService(repo port.NightAuditRepository, bookingRepo port.BookingRepository, folioRepo port.FolioRepository, paymentRepo port.PaymentRepository, summaryService port.DailyBookingSummaryService, logRepo port.LogRepository) *NightAuditService {
+	return &NightAuditService{
+		repo,
+		bookingRepo,
+		folioRepo,
+		paymentRepo,
+		summaryService,
+		logRepo,
+	}
+}
+
+func (nas *NightAuditService) GetBusinessDate(ctx *gin.Context) (time.Time, error) {
+	businessDate, err := nas.repo.GetBusinessDate(ctx)
+	if err != nil {
+		return time.Time{}, domain.ErrInternal
+	}
+
+	return businessDate, nil
+}
+
+// RunNightAudit closes the current business date: it posts the night's room charges to in-house bookings,
+// flags arrivals that never checked in as no-shows, reports departures whose folio still has a balance,
+// generates and confirms the daily summary, then locks the date and opens the next one
+func (nas *NightAuditService) RunNightAudit(ctx *gin.Context) (*domain.NightAudit, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	runBy := userID.(uint64)
+
+	businessDate, err := nas.GetBusinessDate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	audit, err := nas.repo.StartNightAudit(ctx, &domain.NightAudit{
+		BusinessDate: businessDate,
+		RunBy:        &runBy,
+		StartedAt:    &now,
+	})
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if err := nas.audit(ctx, audit); err != nil {
+		audit.ErrorMessage = err.Error()
+		if failErr := nas.repo.FailNightAudit(ctx, audit); failErr != nil {
+			slog.Error("Error recording failed night audit", "business_date", businessDate, "error", failErr)
+		}
+		if err == domain.ErrDayLocked {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	completedAt := time.Now()
+	audit.CompletedAt = &completedAt
+	completedAudit, err := nas.repo.CompleteNightAudit(ctx, audit)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// Create a log
+	log := &domain.Log{
+		RecordID:  completedAudit.ID,
+		Action:    "AUDIT",
+		UserID:    runBy,
+		TableName: "night_audits",
+	}
+	_, err = nas.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return completedAudit, nil
+}
+
+func (nas *NightAuditService) ListNightAudits(ctx *gin.Context, skip, limit uint64) ([]domain.NightAudit, uint64, error) {
+	audits, totalCount, err := nas.repo.ListNightAudits(ctx, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return audits, totalCount, nil
+}
+
+// audit runs the steps of a night audit. Each step can be repeated, so a failed audit is simply run again.
+func (nas *NightAuditService) audit(ctx *gin.Context, audit *domain.NightAudit) error {
+	businessDate := audit.BusinessDate
+	_, postedAt := businessDay(businessDate)
+
+	// Post tonight's room charge, and any night a missed audit did not post, to in-house bookings
+	inHouse, err := nas.bookingRepo.ListInHouseBookings(ctx)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	for _, booking := range inHouse {
+		throughNight := daysBetween(*booking.CheckInDate, businessDate) + 1
+		if throughNight < 1 {
+			continue
+		}
+		nights, amount, err := postRoomNights(ctx, nas.folioRepo, &booking, throughNight, postedAt)
+		if err != nil {
+			return err
+		}
+		audit.RoomNightsPosted += nights
+		audit.RoomRevenue = util.RoundAmount(audit.RoomRevenue + amount)
+	}
+
+	// Guests due to arrive by today who never checked in are no-shows
+	arrivals, err := nas.bookingRepo.ListDueArrivals(ctx, businessDate)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	for _, booking := range arrivals {
+		booking.Status = domain.BookingStatusNoShow
+		booking.UpdatedAt = &postedAt
+		if _, err := nas.bookingRepo.UpdateBooking(ctx, &booking); err != nil {
+			return domain.ErrInternal
+		}
+		audit.NoShowBookings = append(audit.NoShowBookings, booking.ID)
+	}
+
+	// Guests who left today should have settled folios; checkouts with an overridden balance are reported
+	departures, err := nas.bookingRepo.ListDepartures(ctx, businessDate)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	for _, booking := range departures {
+		folio, err := loadFolio(ctx, nas.folioRepo, nas.paymentRepo, booking.ID)
+		if err != nil {
+			return err
+		}
+		if !folio.IsSettled() {
+			audit.UnbalancedBookings = append(audit.UnbalancedBookings, booking.ID)
+		}
+	}
+
+	// A summary accounting already confirmed is kept as it is
+	_, err = nas.summaryService.GenerateDailySummary(ctx, *audit.RunBy, businessDate)
+	switch err {
+	case nil:
+		if _, err := nas.summaryService.UpdateSummaryStatus(ctx, businessDate, domain.SummaryStatusConfirmed); err != nil {
+			return err
+		}
+	case domain.ErrDayClosed:
+	default:
+		return err
+	}
+
+	return nil
+}
+
+// businessNow returns the current time on the hotel's business date. Past midnight, until the night audit
+// rolls the date forward, postings are stamped at the last second of the business day still open.
+func businessNow(ctx *gin.Context, dateRepo port.BusinessDateRepository) (time.Time, error) {
+	businessDate, err := dateRepo.GetBusinessDate(ctx)
+	if err != nil {
+		return time.Time{}, domain.ErrInternal
+	}
+
+	start, end := businessDay(businessDate)
+	now := time.Now()
+	if now.Before(start) {
+		return start, nil
+	}
+	if now.After(end) {
+		return end, nil
+	}
+	return now, nil
+}
+
+// ensureDayUnlocked returns ErrDayLocked once a completed night audit has locked the day of date
+func ensureDayUnlocked(ctx context.Context, dateRepo port.BusinessDateRepository, date time.Time) error {
+	locked, err := dateRepo.IsDayLocked(ctx, date)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if locked {
+		return domain.ErrDayLocked
+	}
+	return nil
+}
+
+// ensurePostingOpen returns ErrDayLocked when the night audit has locked the day of date, and ErrDayClosed
+// when a confirmed daily summary has closed it
+func ensurePostingOpen(ctx *gin.Context, dateRepo port.BusinessDateRepository, periodRepo port.ClosedPeriodRepository, date time.Time) error {
+	if err := ensureDayUnlocked(ctx, dateRepo, date); err != nil {
+		return err
+	}
+	return ensurePeriodOpen(ctx, periodRepo, date)
+}
+
+// businessDay returns the first and last second of a business date in the hotel's time zone
+func businessDay(businessDate time.Time) (time.Time, time.Time) {
+	year, month, day := businessDate.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	return start, start.AddDate(0, 0, 1).Add(-time.Second)
+}
+
+// daysBetween returns the number of calendar days from one date to another
+func daysBetween(from, to time.Time) int {
+	fromYear, fromMonth, fromDay := from.Date()
+	toYear, toMonth, toDay := to.Date()
+	start := time.Date(fromYear, fromMonth, fromDay, 0, 0, 0, 0, time.UTC)
+	end := time.Date(toYear, toMonth, toDay, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
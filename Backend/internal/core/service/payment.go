@@ -1,50 +1,119 @@
 package service
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"time"
 	"log/slog"
+	"strings"
 	"github.com/Coke3a/HotelManagement/internal/core/domain"
 	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
 )
 
 type PaymentService struct {
-	repo    port.PaymentRepository
-	logRepo port.LogRepository
+	repo             port.PaymentRepository
+	exchangeRateRepo port.ExchangeRateRepository
+	shiftRepo        port.CashierShiftRepository
+	dateRepo         port.BusinessDateRepository
+	periodRepo       port.ClosedPeriodRepository
+	gateway          port.PaymentGateway
+	loyaltyService   port.LoyaltyService
+	userRepo         port.UserRepository
+	logRepo          port.LogRepository
 }
 
-func NewPaymentService(repo port.PaymentRepository, logRepo port.LogRepository) *PaymentService {
+func NewPaymentService(repo port.PaymentRepository, exchangeRateRepo port.ExchangeRateRepository, shiftRepo port.CashierShiftRepository, dateRepo port.BusinessDateRepository, periodRepo port.ClosedPeriodRepository, gateway port.PaymentGateway, loyaltyService port.LoyaltyService, userRepo port.UserRepository, logRepo port.LogRepository) *PaymentService {
 	return &PaymentService{
 		repo,
+		exchangeRateRepo,
+		shiftRepo,
+		dateRepo,
+		periodRepo,
+		gateway,
+		loyaltyService,
+		userRepo,
 		logRepo,
 	}
 }
 
-func (ps *PaymentService) ProcessPayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error) {
+// ProcessPayment records a payment. Card payments require a card token and are charged through the payment
+// gateway: a paid payment is authorized and captured at once, an unpaid one only holds the amount until CapturePayment.
+func (ps *PaymentService) ProcessPayment(ctx *gin.Context, payment *domain.Payment, cardToken string) (*domain.Payment, error) {
 	if payment.Amount <= 0 {
 		return nil, domain.ErrInvalidData
 	}
-	if payment.PaymentMethod == domain.PaymentMethodNotSpecified {
+	// City ledger payments are only created by transferring a balance to a company at checkout
+	if payment.PaymentMethod == domain.PaymentMethodNotSpecified || payment.PaymentMethod == domain.PaymentMethodCityLedger {
 		return nil, domain.ErrInvalidData
 	}
 	if payment.Status == 0 {
 		payment.Status = domain.PaymentStatusUnpaid
 	}
+	if payment.Type == 0 {
+		payment.Type = domain.PaymentTypeBalance
+	}
+	if payment.Type > domain.PaymentTypeTopUp {
+		return nil, domain.ErrInvalidData
+	}
+	// Loyalty points are the guest's own and are spent at once, at their value in THB
+	if payment.PaymentMethod == domain.PaymentMethodLoyaltyPoints {
+		if cardToken != "" || payment.PayerID != nil || (payment.Currency != "" && !strings.EqualFold(payment.Currency, domain.BaseCurrency)) {
+			return nil, domain.ErrInvalidData
+		}
+		payment.Status = domain.PaymentStatusPaid
+	}
 
 	// Set the payment date if it's not already set
 	if payment.PaymentDate == nil {
-		now := time.Now()
+		now, err := businessNow(ctx, ps.dateRepo)
+		if err != nil {
+			return nil, err
+		}
 		payment.PaymentDate = &now
 	}
+	if err := ensurePostingOpen(ctx, ps.dateRepo, ps.periodRepo, *payment.PaymentDate); err != nil {
+		return nil, err
+	}
+
+	if err := ps.convertToBaseCurrency(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	if err := tagPayment(ctx, ps.shiftRepo, payment); err != nil {
+		return nil, err
+	}
+
+	// Card payments are never recorded without going through the gateway
+	if payment.PaymentMethod.IsCard() != (cardToken != "") {
+		return nil, domain.ErrInvalidData
+	}
+	if cardToken != "" {
+		if err := ps.chargeCard(ctx, payment, cardToken); err != nil {
+			return nil, err
+		}
+	}
 
 	createdPayment, err := ps.repo.CreatePayment(ctx, payment)
 	if err != nil {
-		if err == domain.ErrConflictingData {
+		// Give the money back rather than keep a charge without a payment record
+		ps.releaseCharge(ctx, payment)
+		if err == domain.ErrConflictingData || err == domain.ErrDataNotFound {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
 	}
 
+	if createdPayment.PaymentMethod == domain.PaymentMethodLoyaltyPoints {
+		if _, err := ps.loyaltyService.RedeemForPayment(ctx, createdPayment); err != nil {
+			// Remove the payment rather than keep one its points did not pay
+			if deleteErr := ps.repo.DeletePayment(ctx, createdPayment.ID); deleteErr != nil {
+				slog.Error("Error deleting unredeemed loyalty points payment", "payment_id", createdPayment.ID, "error", deleteErr)
+			}
+			return nil, err
+		}
+	}
+
 	userID, exists := ctx.Get("userID")
 	if !exists {
 		return nil, domain.ErrUnauthorized
@@ -64,6 +133,138 @@ func (ps *PaymentService) ProcessPayment(ctx *gin.Context, payment *domain.Payme
 	return createdPayment, nil
 }
 
+// CapturePayment collects the amount held on the card of an unpaid gateway payment and marks it paid
+func (ps *PaymentService) CapturePayment(ctx *gin.Context, id uint64) (*domain.Payment, error) {
+	payment, err := ps.repo.GetPaymentByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	if payment.GatewayReference == "" || payment.Status != domain.PaymentStatusUnpaid || payment.IsReversal() {
+		return nil, domain.ErrInvalidData
+	}
+	if err := ps.ensurePaymentOpen(ctx, payment); err != nil {
+		return nil, err
+	}
+	if ps.gateway == nil {
+		return nil, domain.ErrPaymentGateway
+	}
+
+	_, err = ps.gateway.Capture(ctx, payment.GatewayReference, payment.Amount)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	now, err := businessNow(ctx, ps.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	payment.Status = domain.PaymentStatusPaid
+	payment.PaymentDate = &now
+	if err := tagPayment(ctx, ps.shiftRepo, payment); err != nil {
+		return nil, err
+	}
+
+	updatedPayment, err := ps.repo.UpdatePayment(ctx, payment)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+	// Create a log
+	log := &domain.Log{
+		RecordID:  payment.ID,
+		Action:    "CAPTURE",
+		UserID:    userID.(uint64),
+		TableName: "payments",
+	}
+	_, err = ps.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return updatedPayment, nil
+}
+
+// HandleGatewayWebhook applies a verified gateway notification to the payment it refers to.
+// Captures mark unpaid payments paid and declines mark them failed, as the system user; refunds are recorded
+// when they are requested. Like CapturePayment, it is refused while the payment's day is locked or closed.
+func (ps *PaymentService) HandleGatewayWebhook(ctx *gin.Context, payload []byte, signature string) error {
+	if ps.gateway == nil {
+		return domain.ErrPaymentGateway
+	}
+
+	event, err := ps.gateway.VerifyWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	payment, err := ps.repo.GetPaymentByGatewayReference(ctx, event.Reference)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	var action string
+	switch {
+	case event.Status == domain.GatewayTransactionStatusCaptured && payment.Status == domain.PaymentStatusUnpaid:
+		action = "CAPTURE"
+	case event.Status == domain.GatewayTransactionStatusDeclined && payment.Status == domain.PaymentStatusUnpaid:
+		action = "UPDATE"
+	default:
+		return nil
+	}
+	if err := ps.ensurePaymentOpen(ctx, payment); err != nil {
+		return err
+	}
+
+	if action == "CAPTURE" {
+		now, err := businessNow(ctx, ps.dateRepo)
+		if err != nil {
+			return err
+		}
+		payment.Status = domain.PaymentStatusPaid
+		payment.PaymentDate = &now
+		if err := tagPayment(ctx, ps.shiftRepo, payment); err != nil {
+			return err
+		}
+	} else {
+		payment.Status = domain.PaymentStatusFailed
+	}
+
+	_, err = ps.repo.UpdatePayment(ctx, payment)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	// The gateway is not a user, so the change is logged as the system user
+	user, err := ps.userRepo.GetUserByUserName(ctx, domain.SystemUserName)
+	if err != nil {
+		slog.Error("Error getting system user", "error", err)
+	} else {
+		log := &domain.Log{
+			RecordID:  payment.ID,
+			Action:    action,
+			UserID:    user.ID,
+			TableName: "payments",
+		}
+		_, err = ps.logRepo.CreateLog(ctx, log)
+		if err != nil {
+			slog.Error("Error creating log", "error", err)
+		}
+	}
+
+	slog.Info("Payment updated from gateway webhook", "payment_id", payment.ID, "reference", event.Reference, "status", payment.Status)
+	return nil
+}
+
 func (ps *PaymentService) GetPayment(ctx *gin.Context, id uint64) (*domain.Payment, error) {
 	payment, err := ps.repo.GetPaymentByID(ctx, id)
 	if err != nil {
@@ -85,7 +286,18 @@ func (ps *PaymentService) ListPayments(ctx *gin.Context, skip, limit uint64) ([]
 	return payments, totalCount, nil
 }
 
-func (ps *PaymentService) UpdatePayment(ctx *gin.Context, payment *domain.Payment) (*domain.Payment, error) {
+func (ps *PaymentService) ListPaymentsByBookingID(ctx *gin.Context, bookingID uint64) ([]domain.Payment, error) {
+	payments, err := ps.repo.ListPaymentsByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return payments, nil
+}
+
+// UpdatePayment edits a payment. A card payment is settled by charging cardToken through the gateway; a payment
+// the gateway has charged or holds is refunded, voided or captured instead of edited.
+func (ps *PaymentService) UpdatePayment(ctx *gin.Context, payment *domain.Payment, cardToken string) (*domain.Payment, error) {
 	// existingPayment, err := ps.repo.GetPaymentByID(ctx, payment.ID)
 	// if err != nil {
 	// 	if err == domain.ErrDataNotFound {
@@ -102,12 +314,100 @@ func (ps *PaymentService) UpdatePayment(ctx *gin.Context, payment *domain.Paymen
 	// 	return nil, domain.ErrNoUpdatedData
 	// }
 
-	// Update timestamp
-	now := time.Now()
-	payment.PaymentDate = &now
-	
+	if payment.Type < 0 || payment.Type > domain.PaymentTypeTopUp {
+		return nil, domain.ErrInvalidData
+	}
+
+	existingPayment, err := ps.repo.GetPaymentByID(ctx, payment.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// Refunded, voided and reversal payments are history and can no longer be edited
+	if err := ps.ensureNotReversed(ctx, existingPayment); err != nil {
+		return nil, err
+	}
+	if err := ps.ensurePaymentOpen(ctx, existingPayment); err != nil {
+		return nil, err
+	}
+	// Points are redeemed when the payment is taken, so a loyalty points payment is refunded or voided instead
+	if existingPayment.PaymentMethod == domain.PaymentMethodLoyaltyPoints || payment.PaymentMethod == domain.PaymentMethodLoyaltyPoints {
+		return nil, domain.ErrInvalidData
+	}
+
+	// Fields the update leaves out keep their stored values
+	if payment.Amount <= 0 {
+		payment.Amount = existingPayment.Amount
+	}
+	if payment.PaymentMethod == domain.PaymentMethodNotSpecified {
+		payment.PaymentMethod = existingPayment.PaymentMethod
+	}
+	if payment.Status == 0 {
+		payment.Status = existingPayment.Status
+	}
+	payment.Currency = strings.ToUpper(payment.Currency)
+	if payment.Currency == "" {
+		payment.Currency = existingPayment.Currency
+	}
+	payment.PaymentDate = existingPayment.PaymentDate
+	payment.ExchangeRate = existingPayment.ExchangeRate
+	payment.BaseAmount = existingPayment.BaseAmount
+
+	// A payment settled by editing it is taken today, in the current shift
+	settled := payment.Status == domain.PaymentStatusPaid && existingPayment.Status != domain.PaymentStatusPaid
+
+	if existingPayment.GatewayReference != "" {
+		if payment.Amount != existingPayment.Amount || payment.PaymentMethod != existingPayment.PaymentMethod || payment.Currency != existingPayment.Currency || payment.Status != existingPayment.Status {
+			return nil, domain.ErrInvalidData
+		}
+	}
+	// Only a card payment being settled is charged, and it cannot be settled without the card
+	chargesCard := settled && payment.PaymentMethod.IsCard() && existingPayment.GatewayReference == ""
+	if chargesCard != (cardToken != "") {
+		return nil, domain.ErrInvalidData
+	}
+	if payment.PaymentMethod.IsCard() && payment.Status == domain.PaymentStatusPaid && existingPayment.GatewayReference == "" && !chargesCard {
+		return nil, domain.ErrInvalidData
+	}
+	if settled || payment.PaymentDate == nil {
+		now, err := businessNow(ctx, ps.dateRepo)
+		if err != nil {
+			return nil, err
+		}
+		if err := ensurePostingOpen(ctx, ps.dateRepo, ps.periodRepo, now); err != nil {
+			return nil, err
+		}
+		payment.PaymentDate = &now
+	}
+	if settled {
+		if err := tagPayment(ctx, ps.shiftRepo, payment); err != nil {
+			return nil, err
+		}
+	}
+
+	// The exchange rate of the payment date is looked up again only when the currency or the date changed;
+	// a corrected amount keeps the rate it was taken at
+	switch {
+	case payment.Currency != existingPayment.Currency || settled || payment.ExchangeRate == 0:
+		if err := ps.convertToBaseCurrency(ctx, payment); err != nil {
+			return nil, err
+		}
+	case payment.Amount != existingPayment.Amount:
+		payment.BaseAmount = util.RoundAmount(payment.Amount * payment.ExchangeRate)
+	}
+
+	if chargesCard {
+		if err := ps.chargeCard(ctx, payment, cardToken); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedPayment, err := ps.repo.UpdatePayment(ctx, payment)
 	if err != nil {
+		ps.releaseCharge(ctx, payment)
 		if err == domain.ErrConflictingData {
 			return nil, err
 		}
@@ -134,7 +434,7 @@ func (ps *PaymentService) UpdatePayment(ctx *gin.Context, payment *domain.Paymen
 }
 
 func (ps *PaymentService) DeletePayment(ctx *gin.Context, id uint64) error {
-	_, err := ps.repo.GetPaymentByID(ctx, id)
+	payment, err := ps.repo.GetPaymentByID(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			return err
@@ -142,16 +442,28 @@ func (ps *PaymentService) DeletePayment(ctx *gin.Context, id uint64) error {
 		return domain.ErrInternal
 	}
 
+	if err := ps.ensureNotReversed(ctx, payment); err != nil {
+		return err
+	}
+	if err := ps.ensurePaymentOpen(ctx, payment); err != nil {
+		return err
+	}
+	// Voiding a loyalty points payment gives its points back; deleting it would not
+	if payment.PaymentMethod == domain.PaymentMethodLoyaltyPoints {
+		return domain.ErrInvalidData
+	}
+
 	userID, exists := ctx.Get("userID")
 	if !exists {
 		return domain.ErrUnauthorized
 	}
-	// Create a log
+	// Create a log, keeping the amount so the staff activity report still shows what was deleted
 	log := &domain.Log{
 		RecordID:  id,
 		Action:    "DELETE",
 		UserID:    userID.(uint64),
 		TableName: "payments",
+		Amount:    &payment.BaseAmount,
 	}
 	_, err = ps.logRepo.CreateLog(ctx, log)
 	if err != nil {
@@ -160,3 +472,286 @@ func (ps *PaymentService) DeletePayment(ctx *gin.Context, id uint64) error {
 
 	return ps.repo.DeletePayment(ctx, id)
 }
+
+// GetPaymentTotalsByCurrency returns paid totals per currency with their base currency equivalent
+func (ps *PaymentService) GetPaymentTotalsByCurrency(ctx *gin.Context, from, to time.Time) ([]domain.PaymentCurrencyTotal, error) {
+	if to.Before(from) {
+		return nil, domain.ErrInvalidData
+	}
+
+	totals, err := ps.repo.GetPaymentTotalsByCurrency(ctx, from, to)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return totals, nil
+}
+
+// RefundPayment records a negative refund against a paid payment, up to the amount not yet refunded
+func (ps *PaymentService) RefundPayment(ctx *gin.Context, id uint64, amount float64, reason domain.ReversalReason, note string) (*domain.Payment, error) {
+	original, refundable, err := ps.getReversiblePayment(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	amount = util.RoundAmount(amount)
+	if amount <= 0 || amount > refundable {
+		return nil, domain.ErrInvalidData
+	}
+
+	return ps.reversePayment(ctx, original, domain.PaymentTypeRefund, amount, reason, note)
+}
+
+// VoidPayment reverses a paid payment in full. Payments that were partly refunded can only be refunded further.
+func (ps *PaymentService) VoidPayment(ctx *gin.Context, id uint64, reason domain.ReversalReason, note string) (*domain.Payment, error) {
+	original, refundable, err := ps.getReversiblePayment(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+	if refundable != original.Amount {
+		return nil, domain.ErrPaymentReversed
+	}
+
+	return ps.reversePayment(ctx, original, domain.PaymentTypeVoid, original.Amount, reason, note)
+}
+
+// getReversiblePayment checks that the current user may reverse the payment and returns it with the amount still refundable
+func (ps *PaymentService) getReversiblePayment(ctx *gin.Context, id uint64, reason domain.ReversalReason) (*domain.Payment, float64, error) {
+	if !isAdmin(ctx) {
+		return nil, 0, domain.ErrForbidden
+	}
+	if reason <= domain.ReversalReasonNone || reason > domain.ReversalReasonOther {
+		return nil, 0, domain.ErrInvalidData
+	}
+
+	original, err := ps.repo.GetPaymentByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, 0, err
+		}
+		return nil, 0, domain.ErrInternal
+	}
+	if original.IsReversal() || original.Status != domain.PaymentStatusPaid || original.Amount <= 0 {
+		return nil, 0, domain.ErrInvalidData
+	}
+	// A city ledger transfer is settled on the company account, not refunded
+	if original.PaymentMethod == domain.PaymentMethodCityLedger {
+		return nil, 0, domain.ErrInvalidData
+	}
+
+	reversedAmount, err := ps.repo.GetReversedAmount(ctx, original.ID)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	refundable := util.RoundAmount(original.Amount - reversedAmount)
+	if refundable <= 0 {
+		return nil, 0, domain.ErrPaymentReversed
+	}
+
+	return original, refundable, nil
+}
+
+// reversePayment stores the reversal as a paid negative payment in the original currency and exchange rate
+func (ps *PaymentService) reversePayment(ctx *gin.Context, original *domain.Payment, paymentType domain.PaymentType, amount float64, reason domain.ReversalReason, note string) (*domain.Payment, error) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return nil, domain.ErrUnauthorized
+	}
+
+	baseAmount := util.RoundAmount(-amount * original.ExchangeRate)
+	if amount == original.Amount {
+		baseAmount = -original.BaseAmount
+	}
+
+	// The reversal is taken today, so a payment of a closed day is still refunded in an open one
+	now, err := businessNow(ctx, ps.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePostingOpen(ctx, ps.dateRepo, ps.periodRepo, now); err != nil {
+		return nil, err
+	}
+	originalID := original.ID
+	reversal := &domain.Payment{
+		BookingID:         original.BookingID,
+		Amount:            -amount,
+		PaymentMethod:     original.PaymentMethod,
+		PaymentDate:       &now,
+		Status:            domain.PaymentStatusPaid,
+		Currency:          original.Currency,
+		ExchangeRate:      original.ExchangeRate,
+		BaseAmount:        baseAmount,
+		Type:              paymentType,
+		OriginalPaymentID: &originalID,
+		ReasonCode:        reason,
+		ReasonNote:        note,
+		PayerID:           original.PayerID,
+	}
+
+	// Cash given back comes out of the drawer of the current shift
+	if err := tagPayment(ctx, ps.shiftRepo, reversal); err != nil {
+		return nil, err
+	}
+
+	if original.GatewayReference != "" && ps.gateway == nil {
+		return nil, domain.ErrPaymentGateway
+	}
+
+	// The reversal is recorded before the card is refunded, so a concurrent refund sees it and cannot
+	// take the same amount again
+	createdReversal, err := ps.repo.CreateReversal(ctx, reversal)
+	if err != nil {
+		if err == domain.ErrPaymentReversed || err == domain.ErrInvalidData || err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if original.GatewayReference != "" {
+		refund, err := ps.gateway.Refund(ctx, original.GatewayReference, amount)
+		if err != nil {
+			// Remove the reversal rather than keep one the card was not refunded for
+			if deleteErr := ps.repo.DeletePayment(ctx, createdReversal.ID); deleteErr != nil {
+				slog.Error("Error deleting unrefunded reversal", "payment_id", original.ID, "reversal_id", createdReversal.ID, "error", deleteErr)
+			}
+			return nil, gatewayError(err)
+		}
+		createdReversal.Gateway = ps.gateway.Name()
+		createdReversal.GatewayReference = refund.Reference
+		if _, err := ps.repo.UpdatePayment(ctx, createdReversal); err != nil {
+			slog.Error("Error saving gateway refund reference", "reversal_id", createdReversal.ID, "reference", refund.Reference, "error", err)
+		}
+	}
+
+	if original.PaymentMethod == domain.PaymentMethodLoyaltyPoints {
+		if _, err := ps.loyaltyService.ReinstateForReversal(ctx, original, createdReversal); err != nil {
+			slog.Error("Error reinstating loyalty points", "payment_id", original.ID, "reversal_id", createdReversal.ID, "error", err)
+		}
+	} else if original.PayerID == nil {
+		if _, err := ps.loyaltyService.ClawBackForReversal(ctx, createdReversal); err != nil {
+			slog.Error("Error taking back loyalty points", "payment_id", original.ID, "reversal_id", createdReversal.ID, "error", err)
+		}
+	}
+
+	action := "REFUND"
+	if paymentType == domain.PaymentTypeVoid {
+		action = "VOID"
+	}
+	// Create a log
+	log := &domain.Log{
+		RecordID:  createdReversal.ID,
+		Action:    action,
+		UserID:    userID.(uint64),
+		TableName: "payments",
+	}
+	_, err = ps.logRepo.CreateLog(ctx, log)
+	if err != nil {
+		slog.Error("Error creating log", "error", err)
+	}
+
+	return createdReversal, nil
+}
+
+// ensurePaymentOpen returns ErrDayLocked when the night audit has locked the payment's date, and ErrDayClosed
+// when a confirmed daily summary has closed it
+func (ps *PaymentService) ensurePaymentOpen(ctx *gin.Context, payment *domain.Payment) error {
+	if payment.PaymentDate == nil {
+		return nil
+	}
+	return ensurePostingOpen(ctx, ps.dateRepo, ps.periodRepo, *payment.PaymentDate)
+}
+
+// ensureNotReversed returns ErrPaymentReversed for reversal records and for payments that have been refunded or voided
+func (ps *PaymentService) ensureNotReversed(ctx *gin.Context, payment *domain.Payment) error {
+	if payment.IsReversal() {
+		return domain.ErrPaymentReversed
+	}
+
+	reversedAmount, err := ps.repo.GetReversedAmount(ctx, payment.ID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if reversedAmount > 0 {
+		return domain.ErrPaymentReversed
+	}
+
+	return nil
+}
+
+// chargeCard authorizes the payment amount on the card and, for a paid payment, captures it
+func (ps *PaymentService) chargeCard(ctx *gin.Context, payment *domain.Payment, cardToken string) error {
+	if ps.gateway == nil {
+		return domain.ErrPaymentGateway
+	}
+
+	authorization, err := ps.gateway.Authorize(ctx, &domain.GatewayCharge{
+		Amount:      payment.Amount,
+		Currency:    payment.Currency,
+		CardToken:   cardToken,
+		Description: fmt.Sprintf("Booking %d", payment.BookingID),
+	})
+	if err != nil {
+		return gatewayError(err)
+	}
+	payment.Gateway = ps.gateway.Name()
+	payment.GatewayReference = authorization.Reference
+
+	if payment.Status == domain.PaymentStatusPaid {
+		if _, err := ps.gateway.Capture(ctx, authorization.Reference, payment.Amount); err != nil {
+			// Release the hold rather than leave an authorization no payment records
+			if _, voidErr := ps.gateway.Void(ctx, authorization.Reference); voidErr != nil {
+				slog.Error("Error voiding uncaptured card authorization", "reference", authorization.Reference, "error", voidErr)
+			}
+			return gatewayError(err)
+		}
+	}
+
+	return nil
+}
+
+// releaseCharge gives back what chargeCard took for a payment that could not be saved: a captured charge is
+// refunded and a hold is voided
+func (ps *PaymentService) releaseCharge(ctx *gin.Context, payment *domain.Payment) {
+	if payment.GatewayReference == "" {
+		return
+	}
+
+	var err error
+	if payment.Status == domain.PaymentStatusPaid {
+		_, err = ps.gateway.Refund(ctx, payment.GatewayReference, payment.Amount)
+	} else {
+		_, err = ps.gateway.Void(ctx, payment.GatewayReference)
+	}
+	if err != nil {
+		slog.Error("Error releasing unrecorded card payment", "reference", payment.GatewayReference, "error", err)
+	}
+}
+
+// gatewayError keeps the errors a client can act on and reports any other gateway failure as ErrPaymentGateway
+func gatewayError(err error) error {
+	switch err {
+	case domain.ErrPaymentDeclined, domain.ErrInvalidData:
+		return err
+	default:
+		slog.Error("Payment gateway error", "error", err)
+		return domain.ErrPaymentGateway
+	}
+}
+
+// convertToBaseCurrency stores the exchange rate effective on the payment date and the base currency amount
+func (ps *PaymentService) convertToBaseCurrency(ctx *gin.Context, payment *domain.Payment) error {
+	payment.Currency = strings.ToUpper(payment.Currency)
+	if payment.Currency == "" {
+		payment.Currency = domain.BaseCurrency
+	}
+
+	exchangeRate, err := effectiveExchangeRate(ctx, ps.exchangeRateRepo, payment.Currency, *payment.PaymentDate)
+	if err != nil {
+		return err
+	}
+
+	payment.ExchangeRate = exchangeRate.Rate
+	payment.BaseAmount = util.RoundAmount(payment.Amount * exchangeRate.Rate)
+	return nil
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Coke3a/HotelManagement/internal/core/domain"
+	"github.com/Coke3a/HotelManagement/internal/core/port"
+	"github.com/Coke3a/HotelManagement/internal/core/util"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportService struct {
+	repo     port.ReportRepository
+	dateRepo port.BusinessDateRepository
+}
+
+func NewReportService(repo port.ReportRepository, dateRepo port.BusinessDateRepository) *ReportService {
+	return &ReportService{
+		repo,
+		dateRepo,
+	}
+}
+
+// GetKPIReport computes occupancy, ADR and RevPAR for the nights from..to, per period and broken down by
+// room type and rate price. A rate price is measured against the available room nights of its room type.
+func (rs *ReportService) GetKPIReport(ctx *gin.Context, from, to time.Time, interval domain.ReportInterval) (*domain.KPIReport, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+
+	switch interval {
+	case domain.ReportIntervalDay, domain.ReportIntervalWeek, domain.ReportIntervalMonth:
+	default:
+		return nil, domain.ErrInvalidData
+	}
+	if to.Before(from) || daysBetween(from, to) >= domain.MaxReportDays {
+		return nil, domain.ErrInvalidData
+	}
+
+	roomTypeNights, err := rs.repo.ListRoomTypeNights(ctx, from, to, interval)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	ratePriceNights, err := rs.repo.ListRatePriceNights(ctx, from, to)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	payments, err := rs.repo.ListPaymentsCollected(ctx, from, to, interval)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	report := &domain.KPIReport{
+		From:     from,
+		To:       to,
+		Interval: interval,
+	}
+
+	periods := make(map[time.Time]*domain.KPIPeriod)
+	var periodOrder []time.Time
+	period := func(start time.Time) *domain.KPIPeriod {
+		if p, ok := periods[start]; ok {
+			return p
+		}
+		periods[start] = &domain.KPIPeriod{Start: start}
+		periodOrder = append(periodOrder, start)
+		return periods[start]
+	}
+
+	roomTypes := make(map[uint64]*domain.KPIBreakdown)
+	var roomTypeOrder []uint64
+	for _, row := range roomTypeNights {
+		metrics := domain.KPIMetrics{
+			RoomNightsAvailable: row.Available,
+			RoomNightsSold:      row.Sold,
+			RoomRevenue:         row.Revenue,
+		}
+		period(row.Period).Add(metrics)
+		report.Total.Add(metrics)
+
+		roomType, ok := roomTypes[row.RoomTypeID]
+		if !ok {
+			roomType = &domain.KPIBreakdown{ID: row.RoomTypeID, Name: row.RoomTypeName}
+			roomTypes[row.RoomTypeID] = roomType
+			roomTypeOrder = append(roomTypeOrder, row.RoomTypeID)
+		}
+		roomType.Add(metrics)
+	}
+
+	for _, row := range payments {
+		period(row.Period).PaymentsCollected += row.Amount
+		report.PaymentsCollected += row.Amount
+	}
+
+	for _, start := range periodOrder {
+		p := periods[start]
+		p.PaymentsCollected = util.RoundAmount(p.PaymentsCollected)
+		roundMetrics(&p.KPIMetrics)
+		report.Periods = append(report.Periods, *p)
+	}
+	// Payments may open a period no room night falls in
+	sort.Slice(report.Periods, func(i, j int) bool {
+		return report.Periods[i].Start.Before(report.Periods[j].Start)
+	})
+
+	for _, row := range ratePriceNights {
+		ratePrice := domain.KPIBreakdown{
+			ID:   row.RatePriceID,
+			Name: row.RatePriceName,
+			KPIMetrics: domain.KPIMetrics{
+				RoomNightsSold: row.Sold,
+				RoomRevenue:    row.Revenue,
+			},
+		}
+		if roomType, ok := roomTypes[row.RoomTypeID]; ok {
+			ratePrice.RoomNightsAvailable = roomType.RoomNightsAvailable
+		}
+		roundMetrics(&ratePrice.KPIMetrics)
+		report.ByRatePrice = append(report.ByRatePrice, ratePrice)
+	}
+
+	for _, id := range roomTypeOrder {
+		roomType := roomTypes[id]
+		roundMetrics(&roomType.KPIMetrics)
+		report.ByRoomType = append(report.ByRoomType, *roomType)
+	}
+
+	report.PaymentsCollected = util.RoundAmount(report.PaymentsCollected)
+	roundMetrics(&report.Total)
+
+	return report, nil
+}
+
+// GetForecastReport reports the rooms on the books for each of the next days nights, by room type, with the
+// pickup since the snapshot taken pickupDays days ago. Pickup is left out when that snapshot is missing.
+func (rs *ReportService) GetForecastReport(ctx *gin.Context, days, pickupDays int) (*domain.ForecastReport, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	if days < 1 || days > domain.MaxForecastDays || pickupDays < 1 || pickupDays > domain.MaxForecastDays {
+		return nil, domain.ErrInvalidData
+	}
+
+	now, err := businessNow(ctx, rs.dateRepo)
+	if err != nil {
+		return nil, err
+	}
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, days-1)
+	snapshotDate := from.AddDate(0, 0, -pickupDays)
+
+	nights, err := rs.repo.ListRoomTypeNights(ctx, from, to, domain.ReportIntervalDay)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	snapshots, err := rs.repo.ListForecastSnapshot(ctx, snapshotDate, from, to)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	report := &domain.ForecastReport{
+		From:         from,
+		To:           to,
+		PickupDays:   pickupDays,
+		SnapshotDate: snapshotDate,
+		HasSnapshot:  len(snapshots) > 0,
+	}
+
+	type nightKey struct {
+		date       string
+		roomTypeID uint64
+	}
+	booked := make(map[nightKey]domain.ForecastSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		booked[nightKey{snapshot.StayDate.Format("2006-01-02"), snapshot.RoomTypeID}] = snapshot
+	}
+
+	dayIndex := make(map[string]int, days)
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		dayIndex[date.Format("2006-01-02")] = len(report.Days)
+		report.Days = append(report.Days, domain.ForecastDay{Date: date})
+	}
+	if report.HasSnapshot {
+		report.Total.PickupRooms, report.Total.PickupRevenue = new(int), new(float64)
+		for i := range report.Days {
+			report.Days[i].PickupRooms, report.Days[i].PickupRevenue = new(int), new(float64)
+		}
+	}
+
+	for _, row := range nights {
+		i, ok := dayIndex[row.Period.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		roomType := domain.ForecastRoomType{
+			ID:   row.RoomTypeID,
+			Name: row.RoomTypeName,
+			ForecastMetrics: domain.ForecastMetrics{
+				RoomsAvailable:  row.Available,
+				RoomsBooked:     row.Sold,
+				ExpectedRevenue: row.Revenue,
+			},
+		}
+		if report.HasSnapshot {
+			// A night missing from the snapshot had nothing booked yet
+			snapshot := booked[nightKey{row.Period.Format("2006-01-02"), row.RoomTypeID}]
+			pickupRooms := row.Sold - snapshot.RoomsBooked
+			pickupRevenue := row.Revenue - snapshot.Revenue
+			roomType.PickupRooms, roomType.PickupRevenue = &pickupRooms, &pickupRevenue
+		}
+
+		day := &report.Days[i]
+		day.Add(roomType.ForecastMetrics)
+		report.Total.Add(roomType.ForecastMetrics)
+		roundForecast(&roomType.ForecastMetrics)
+		day.ByRoomType = append(day.ByRoomType, roomType)
+	}
+
+	for i := range report.Days {
+		roundForecast(&report.Days[i].ForecastMetrics)
+	}
+	roundForecast(&report.Total)
+
+	return report, nil
+}
+
+// SnapshotForecast stores the rooms on the books for the nights a forecast can reach from date
+func (rs *ReportService) SnapshotForecast(ctx context.Context, date time.Time) error {
+	to := date.AddDate(0, 0, domain.MaxForecastDays-1)
+	if err := rs.repo.SaveForecastSnapshot(ctx, date, date, to); err != nil {
+		return domain.ErrInternal
+	}
+	return nil
+}
+
+// GetCancellationReport reports the cancellation rate, lead time to cancellation, no-show rate and lost
+// revenue of the bookings checking in over the range of filter, in total and by room type, customer type
+// and rate price
+func (rs *ReportService) GetCancellationReport(ctx *gin.Context, filter *domain.CancellationFilter) (*domain.CancellationReport, error) {
+	if !isAdmin(ctx) {
+		return nil, domain.ErrForbidden
+	}
+	if filter.To.Before(filter.From) || daysBetween(filter.From, filter.To) >= domain.MaxReportDays {
+		return nil, domain.ErrInvalidData
+	}
+
+	groups, err := rs.repo.ListCancellationGroups(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	report := &domain.CancellationReport{Filter: *filter}
+
+	// Breakdowns are listed in the order their IDs are first seen
+	type breakdowns struct {
+		byID  map[uint64]*domain.CancellationBreakdown
+		order []uint64
+	}
+	add := func(b *breakdowns, id uint64, name string, metrics domain.CancellationMetrics) {
+		breakdown, ok := b.byID[id]
+		if !ok {
+			breakdown = &domain.CancellationBreakdown{ID: id, Name: name}
+			b.byID[id] = breakdown
+			b.order = append(b.order, id)
+		}
+		breakdown.Add(metrics)
+	}
+	list := func(b *breakdowns) []domain.CancellationBreakdown {
+		rows := make([]domain.CancellationBreakdown, 0, len(b.order))
+		for _, id := range b.order {
+			breakdown := b.byID[id]
+			roundCancellations(&breakdown.CancellationMetrics)
+			rows = append(rows, *breakdown)
+		}
+		return rows
+	}
+
+	roomTypes := &breakdowns{byID: make(map[uint64]*domain.CancellationBreakdown)}
+	customerTypes := &breakdowns{byID: make(map[uint64]*domain.CancellationBreakdown)}
+	ratePrices := &breakdowns{byID: make(map[uint64]*domain.CancellationBreakdown)}
+	for _, group := range groups {
+		report.Total.Add(group.CancellationMetrics)
+		add(roomTypes, group.RoomTypeID, group.RoomTypeName, group.CancellationMetrics)
+		add(customerTypes, group.CustomerTypeID, group.CustomerTypeName, group.CancellationMetrics)
+		add(ratePrices, group.RatePriceID, group.RatePriceName, group.CancellationMetrics)
+	}
+
+	report.ByRoomType = list(roomTypes)
+	report.ByCustomerType = list(customerTypes)
+	report.ByRatePrice = list(ratePrices)
+	roundCancellations(&report.Total)
+
+	return report, nil
+}
+
+// roundCancellations calculates the rates of metrics and rounds its amounts
+func roundCancellations(metrics *domain.CancellationMetrics) {
+	metrics.Calculate()
+	metrics.CancellationRate = util.RoundAmount(metrics.CancellationRate)
+	metrics.NoShowRate = util.RoundAmount(metrics.NoShowRate)
+	metrics.AvgLeadDays = util.RoundAmount(metrics.AvgLeadDays)
+	metrics.LostRevenue = util.RoundAmount(metrics.LostRevenue)
+}
+
+// roundForecast calculates the occupancy of metrics and rounds its amounts
+func roundForecast(metrics *domain.ForecastMetrics) {
+	metrics.Calculate()
+	metrics.Occupancy = util.RoundAmount(metrics.Occupancy)
+	metrics.ExpectedRevenue = util.RoundAmount(metrics.ExpectedRevenue)
+	if metrics.PickupRevenue != nil {
+		*metrics.PickupRevenue = util.RoundAmount(*metrics.PickupRevenue)
+	}
+}
+
+// roundMetrics calculates the ratios of metrics and rounds its amounts
+func roundMetrics(metrics *domain.KPIMetrics) {
+	metrics.Calculate()
+	metrics.RoomRevenue = util.RoundAmount(metrics.RoomRevenue)
+	metrics.Occupancy = util.RoundAmount(metrics.Occupancy)
+	metrics.ADR = util.RoundAmount(metrics.ADR)
+	metrics.RevPAR = util.RoundAmount(metrics.RevPAR)
+}
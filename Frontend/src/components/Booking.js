@@ -275,6 +275,7 @@ const Booking = () => {
                       <MenuItem value="">All Payments</MenuItem>
                       <MenuItem value={PaymentStatus.UNPAID}>Unpaid</MenuItem>
                       <MenuItem value={PaymentStatus.PAID}>Paid</MenuItem>
+                      <MenuItem value={PaymentStatus.PARTIALLY_PAID}>Partially Paid</MenuItem>
                       <MenuItem value={PaymentStatus.FAILED}>Failed</MenuItem>
                       <MenuItem value={PaymentStatus.REFUNDED}>Refunded</MenuItem>
                     </Select>
@@ -36,7 +36,9 @@ const BookingEdit = () => {
     amount: '',
     payment_method: '',
     status: '',
-    payment_date: null
+    payment_date: null,
+    saved_status: '',
+    card_token: ''
   });
   const [error, setError] = useState('');
 
@@ -287,6 +289,7 @@ const BookingEdit = () => {
             amount: parseFloat(payment.amount),
             payment_method: parseInt(payment.payment_method),
             status: parseInt(payment.status),
+            ...(chargesCard() && { card_token: payment.card_token }),
           }),
         });
 
@@ -357,6 +360,14 @@ const BookingEdit = () => {
            booking.rate_price_id;
   };
 
+  // Settling a card payment charges the card through the payment gateway
+  const chargesCard = () => {
+    const method = parseInt(payment.payment_method);
+    return (method === PaymentMethod.CREDIT_CARD || method === PaymentMethod.DEBIT_CARD) &&
+           parseInt(payment.status) === PaymentStatus.PAID &&
+           parseInt(payment.saved_status) !== PaymentStatus.PAID;
+  };
+
   const fetchPaymentData = async (bookingId) => {
     try {
       const response = await fetch(`http://localhost:8080/v1/payments/${bookingId}`, {
@@ -378,7 +389,9 @@ const BookingEdit = () => {
           amount: data.data.amount,
           payment_method: data.data.payment_method,
           status: data.data.status,
-          payment_date: data.data.payment_date
+          payment_date: data.data.payment_date,
+          saved_status: data.data.status,
+          card_token: ''
         });
       }
     } catch (error) {
@@ -548,9 +561,24 @@ const BookingEdit = () => {
                   <MenuItem value={PaymentMethod.DEBIT_CARD}>Debit Card</MenuItem>
                   <MenuItem value={PaymentMethod.CASH}>Cash</MenuItem>
                   <MenuItem value={PaymentMethod.BANK_TRANSFER}>Bank Transfer</MenuItem>
+                  <MenuItem value={PaymentMethod.LOYALTY_POINTS}>Loyalty Points</MenuItem>
                 </Select>
               </FormControl>
             </Grid>
+            {chargesCard() && (
+              <Grid item xs={12} sm={6}>
+                <TextField
+                  fullWidth
+                  label="Card Token"
+                  name="card_token"
+                  value={payment.card_token}
+                  onChange={(e) => setPayment({ ...payment, card_token: e.target.value })}
+                  margin="normal"
+                  required
+                  className="form-input"
+                />
+              </Grid>
+            )}
             <Grid item xs={12}>
               <Button
                 type="submit"
@@ -184,19 +184,19 @@ const DailyBookingSummary = () => {
               </TableRow>
             ) : (
               summaries.map((summary, index) => (
-                <TableRow key={summary.SummaryDate} className={index % 2 === 0 ? "bg-gray-50" : "bg-white"}>
-                  <TableCell>{dayjs(summary.SummaryDate).format('DD/MM/YYYY')}</TableCell>
-                  <TableCell>{countBookings(summary.CreatedBookings)}</TableCell>
-                  <TableCell>{countBookings(summary.CompletedBookings)}</TableCell>
-                  <TableCell>{countBookings(summary.CanceledBookings)}</TableCell>
-                  <TableCell>{summary.TotalAmount}</TableCell>
-                  <TableCell>{renderStatus(summary.Status)}</TableCell>
-                  <TableCell>{dayjs(summary.CreatedAt).format('DD/MM/YYYY HH:mm:ss')}</TableCell>
+                <TableRow key={summary.summary_date} className={index % 2 === 0 ? "bg-gray-50" : "bg-white"}>
+                  <TableCell>{dayjs(summary.summary_date).format('DD/MM/YYYY')}</TableCell>
+                  <TableCell>{countBookings(summary.created_bookings)}</TableCell>
+                  <TableCell>{countBookings(summary.completed_bookings)}</TableCell>
+                  <TableCell>{countBookings(summary.canceled_bookings)}</TableCell>
+                  <TableCell>{summary.total_amount}</TableCell>
+                  <TableCell>{renderStatus(summary.status)}</TableCell>
+                  <TableCell>{dayjs(summary.created_at).format('DD/MM/YYYY HH:mm:ss')}</TableCell>
                   <TableCell>
                     <Button
                       variant="outlined"
                       size="small"
-                      onClick={() => navigate(`/daily-summary/edit/${dayjs(summary.SummaryDate).format('YYYY-MM-DD')}`)}
+                      onClick={() => navigate(`/daily-summary/edit/${dayjs(summary.summary_date).format('YYYY-MM-DD')}`)}
                       icon={<EditOutlined />}
                     >
                       Detail
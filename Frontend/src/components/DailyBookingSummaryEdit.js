@@ -39,34 +39,6 @@ const DailyBookingSummaryEdit = () => {
   const [statusLoading, setStatusLoading] = useState(false);
   const token = localStorage.getItem('token');
 
-  const fetchBookings = async (filterParams, skip = 0, limit = 100) => {
-    const queryParams = new URLSearchParams({
-      ...filterParams,
-      skip: skip.toString(),
-      limit: limit.toString(),
-    });
-
-    try {
-      const response = await fetch(`http://localhost:8080/v1/booking/?${queryParams.toString()}`, {
-        headers: {
-          'Content-Type': 'application/json',
-          'Authorization': `Bearer ${token}`,
-        },
-      });
-
-      if (response.status === 401) {
-        handleTokenExpiration(new Error("access token has expired"), navigate);
-        return [];
-      }
-
-      const data = await response.json();
-      return Array.isArray(data.data?.booking_customer_payments) ? data.data.booking_customer_payments : [];
-    } catch (error) {
-      console.error('Error fetching bookings:', error);
-      return [];
-    }
-  };
-
   const fetchSummary = async () => {
     setLoading(true);
     try {
@@ -84,19 +56,9 @@ const DailyBookingSummaryEdit = () => {
 
       const result = await response.json();
       setSummary(result);
-
-      // Fetch created bookings
-      const createdBookings = await fetchBookings({ created_at: date }, 0, 100);
-      setCreatedBookings(createdBookings);
-
-      // Fetch completed bookings
-      const completedBookings = await fetchBookings({ updated_at: date, booking_status: BookingStatus.COMPLETED }, 0, 100);
-      setCompletedBookings(completedBookings);
-
-      // Fetch canceled bookings
-      const canceledBookings = await fetchBookings({ updated_at: date, booking_status: BookingStatus.CANCELED }, 0, 100);
-      setCanceledBookings(canceledBookings);
-
+      setCreatedBookings(result.created_bookings || []);
+      setCompletedBookings(result.completed_bookings || []);
+      setCanceledBookings(result.canceled_bookings || []);
     } catch (error) {
       console.error('Error fetching summary:', error);
       message.error('Failed to fetch summary');
@@ -173,14 +135,48 @@ const DailyBookingSummaryEdit = () => {
       }
 
       if (!response.ok) {
-        throw new Error('Failed to update status');
+        const errorData = await response.json();
+        throw new Error(errorData.messages?.[0] || 'Failed to update status');
       }
 
-      setSummary(prev => ({ ...prev, Status: parseInt(newStatus) }));
+      setSummary(prev => ({ ...prev, status: parseInt(newStatus) }));
       message.success('Status updated successfully');
     } catch (error) {
       console.error('Error updating status:', error);
-      message.error('Failed to update status');
+      message.error(error.message);
+    } finally {
+      setStatusLoading(false);
+    }
+  };
+
+  // A confirmed day is closed to changes until an admin reopens it
+  const handleReopen = async () => {
+    setStatusLoading(true);
+    try {
+      const response = await fetch(`http://localhost:8080/v1/daily-summary/reopen?date=${date}`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${token}`,
+        },
+      });
+
+      if (response.status === 401) {
+        handleTokenExpiration(new Error("access token has expired"), navigate);
+        return;
+      }
+
+      if (!response.ok) {
+        const errorData = await response.json();
+        throw new Error(errorData.messages?.[0] || 'Failed to reopen day');
+      }
+
+      const data = await response.json();
+      setSummary(prev => ({ ...prev, status: data.status }));
+      message.success('Day reopened successfully');
+    } catch (error) {
+      console.error('Error reopening day:', error);
+      message.error(error.message);
     } finally {
       setStatusLoading(false);
     }
@@ -295,52 +291,63 @@ const DailyBookingSummaryEdit = () => {
                 <Grid item xs={12} sm={6} md={4}>
                   <Typography variant="subtitle2" color="textSecondary">Created Bookings</Typography>
                   <Typography variant="body1">
-                    {summary.CreatedBookings ? summary.CreatedBookings.split(';').filter(id => id).length : 0}
+                    {createdBookings.length}
                   </Typography>
                 </Grid>
                 <Grid item xs={12} sm={6} md={4}>
                   <Typography variant="subtitle2" color="textSecondary">Completed Bookings</Typography>
                   <Typography variant="body1">
-                    {summary.CompletedBookings ? summary.CompletedBookings.split(';').filter(id => id).length : 0}
+                    {completedBookings.length}
                   </Typography>
                 </Grid>
                 <Grid item xs={12} sm={6} md={4}>
                   <Typography variant="subtitle2" color="textSecondary">Canceled Bookings</Typography>
                   <Typography variant="body1">
-                    {summary.CanceledBookings ? summary.CanceledBookings.split(';').filter(id => id).length : 0}
+                    {canceledBookings.length}
                   </Typography>
                 </Grid>
                 <Grid item xs={12} sm={6} md={4}>
                   <Typography variant="subtitle2" color="textSecondary">Total Amount</Typography>
                   <Typography variant="body1">
-                    {summary.TotalAmount}
+                    {summary.total_amount}
                   </Typography>
                 </Grid>
                 <Grid item xs={12} sm={6} md={4}>
                   <Typography variant="subtitle2" color="textSecondary">Created At</Typography>
                   <Typography variant="body1">
-                    {summary.CreatedAt ? dayjs(summary.CreatedAt).format('YYYY-MM-DD HH:mm:ss') : '-'}
+                    {summary.created_at ? dayjs(summary.created_at).format('YYYY-MM-DD HH:mm:ss') : '-'}
                   </Typography>
                 </Grid>
                 <Grid item xs={12} sm={6} md={4}>
                   <Typography variant="subtitle2" color="textSecondary">Updated At</Typography>
                   <Typography variant="body1">
-                    {summary.UpdatedAt ? dayjs(summary.UpdatedAt).format('YYYY-MM-DD HH:mm:ss') : '-'}
+                    {summary.updated_at ? dayjs(summary.updated_at).format('YYYY-MM-DD HH:mm:ss') : '-'}
                   </Typography>
                 </Grid>
                 <Grid item xs={12} sm={6} md={4}>
                   <Typography variant="subtitle2" color="textSecondary">Status</Typography>
                   <FormControl fullWidth size="small" sx={{ mt: 1 }}>
                     <Select
-                      value={summary.Status}
+                      value={summary.status}
                       onChange={handleStatusChange}
-                      disabled={statusLoading}
+                      disabled={statusLoading || summary.status === 2}
                     >
                       <MenuItem value={0}>Unchecked</MenuItem>
                       <MenuItem value={1}>Checked</MenuItem>
                       <MenuItem value={2}>Confirmed</MenuItem>
                     </Select>
                   </FormControl>
+                  {summary.status === 2 && (
+                    <Button
+                      variant="outlined"
+                      size="small"
+                      sx={{ mt: 1 }}
+                      onClick={handleReopen}
+                      disabled={statusLoading}
+                    >
+                      Reopen Day
+                    </Button>
+                  )}
                 </Grid>
               </Grid>
             </CardContent>
@@ -1,6 +1,8 @@
 import React, { useState, useEffect } from 'react';
 import { TextField, Button, Box, Typography, CircularProgress, Select, MenuItem, FormControl, InputLabel, Grid } from '@mui/material';
 import { useNavigate, useParams } from 'react-router-dom';
+import { handleTokenExpiration } from '../utils/api';
+import GuestHistory from './GuestHistory';
 
 const GuestEdit = () => {
   const token = localStorage.getItem('token');
@@ -323,6 +325,7 @@ const GuestEdit = () => {
           </Grid>
         </form>
       )}
+      <GuestHistory guestId={id} />
     </Box>
   );
 };
@@ -0,0 +1,168 @@
+import React, { useState, useEffect } from 'react';
+import { useNavigate } from 'react-router-dom';
+import { handleTokenExpiration } from '../utils/api';
+import {
+  Box,
+  Button,
+  Chip,
+  CircularProgress,
+  Grid,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  Typography,
+} from '@mui/material';
+import { getBookingStatusMessage, getBookingStatusColor } from '../utils/bookingStatusEnums';
+
+const GuestHistory = ({ guestId }) => {
+  const token = localStorage.getItem('token');
+  const navigate = useNavigate();
+  const [history, setHistory] = useState(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState(null);
+
+  useEffect(() => {
+    const fetchHistory = async () => {
+      setLoading(true);
+      setError(null);
+      try {
+        const response = await fetch(`http://localhost:8080/v1/customers/${guestId}/history`, {
+          headers: {
+            'Content-Type': 'application/json',
+            'Authorization': `Bearer ${token}`,
+          },
+        });
+
+        if (response.status === 401) {
+          handleTokenExpiration(new Error("access token has expired"), navigate);
+          return;
+        }
+
+        if (!response.ok) {
+          throw new Error('Failed to fetch stay history');
+        }
+
+        const data = await response.json();
+        setHistory(data.data);
+      } catch (error) {
+        console.error('Error fetching stay history:', error);
+        setError(error.message);
+      } finally {
+        setLoading(false);
+      }
+    };
+
+    fetchHistory();
+  }, [guestId]);
+
+  const formatDate = (date) => (date ? new Date(date).toLocaleDateString('en-GB') : '-');
+
+  const renderStat = (label, value) => (
+    <Grid item xs={6} sm={4} md={2}>
+      <Typography variant="caption" color="textSecondary">{label}</Typography>
+      <Typography variant="h6">{value}</Typography>
+    </Grid>
+  );
+
+  if (loading) {
+    return (
+      <Box display="flex" justifyContent="center" mt={4}>
+        <CircularProgress size={24} />
+      </Box>
+    );
+  }
+
+  if (error || !history) {
+    return (
+      <Typography color="error" variant="body2" mt={4}>{error}</Typography>
+    );
+  }
+
+  const { stats, bookings } = history;
+
+  return (
+    <Box mt={4}>
+      <Typography variant="h5" gutterBottom>
+        Stay History
+      </Typography>
+      <Grid container spacing={2} mb={2}>
+        {renderStat('Stays', `${stats.stays} / ${stats.bookings}`)}
+        {renderStat('Nights', stats.total_nights)}
+        {renderStat('Total Revenue', stats.total_revenue.toFixed(2))}
+        {renderStat('Average Rate', stats.average_rate.toFixed(2))}
+        {renderStat('Last Stay', formatDate(stats.last_stay))}
+        {renderStat('Favorite Room Type', stats.favorite_room_type ? stats.favorite_room_type.name : '-')}
+        {renderStat('Cancellations', stats.cancellations)}
+        {renderStat('No-shows', stats.no_shows)}
+      </Grid>
+      <TableContainer component={Paper}>
+        <Table size="small" className="compact-table">
+          <TableHead>
+            <TableRow>
+              <TableCell>ID</TableCell>
+              <TableCell>Room</TableCell>
+              <TableCell>Check-in</TableCell>
+              <TableCell>Check-out</TableCell>
+              <TableCell>Nights</TableCell>
+              <TableCell>Amount</TableCell>
+              <TableCell>Paid</TableCell>
+              <TableCell>Status</TableCell>
+              <TableCell>Actions</TableCell>
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            {bookings.length === 0 ? (
+              <TableRow>
+                <TableCell colSpan={9} align="center">
+                  <Typography variant="body2">No bookings found</Typography>
+                </TableCell>
+              </TableRow>
+            ) : (
+              bookings.map((booking, index) => (
+                <TableRow key={booking.booking_id} className={index % 2 === 0 ? "bg-gray-50" : "bg-white"}>
+                  <TableCell>{booking.booking_id}</TableCell>
+                  <TableCell>
+                    {booking.room_number}
+                    {booking.room_type_name && (
+                      <Typography variant="caption" color="textSecondary" style={{ marginLeft: '4px' }}>
+                        {booking.room_type_name}
+                      </Typography>
+                    )}
+                  </TableCell>
+                  <TableCell>{formatDate(booking.check_in_date)}</TableCell>
+                  <TableCell>{formatDate(booking.check_out_date)}</TableCell>
+                  <TableCell>{booking.nights}</TableCell>
+                  <TableCell>{booking.booking_price}</TableCell>
+                  <TableCell>{booking.paid_amount}</TableCell>
+                  <TableCell>
+                    <Chip
+                      label={getBookingStatusMessage(booking.booking_status)}
+                      color={getBookingStatusColor(booking.booking_status).chipColor}
+                      size="small"
+                    />
+                  </TableCell>
+                  <TableCell>
+                    <Button
+                      variant="outlined"
+                      color="primary"
+                      size="small"
+                      onClick={() => navigate(`/booking/edit/${booking.booking_id}`)}
+                    >
+                      Detail
+                    </Button>
+                  </TableCell>
+                </TableRow>
+              ))
+            )}
+          </TableBody>
+        </Table>
+      </TableContainer>
+    </Box>
+  );
+};
+
+export default GuestHistory;
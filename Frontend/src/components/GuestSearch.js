@@ -8,7 +8,10 @@ import {
   Card,
   CardContent,
   Divider,
-  Alert
+  Alert,
+  List,
+  ListItemButton,
+  ListItemText
 } from '@mui/material';
 import SearchIcon from '@mui/icons-material/Search';
 import PersonAddIcon from '@mui/icons-material/PersonAdd';
@@ -16,9 +19,10 @@ import { useNavigate } from 'react-router-dom';
 import { handleTokenExpiration } from '../utils/api';
 
 const GuestSearch = ({ onGuestSelected, currentGuestId, guests, setGuests, onAddNewGuest, initialSelectedGuest }) => {
-  const [identityNumber, setIdentityNumber] = useState('');
+  const [searchText, setSearchText] = useState('');
   const [error, setError] = useState('');
   const [selectedGuest, setSelectedGuest] = useState(initialSelectedGuest || null);
+  const [matches, setMatches] = useState([]);
   const token = localStorage.getItem('token');
   const navigate = useNavigate();
 
@@ -27,22 +31,29 @@ const GuestSearch = ({ onGuestSelected, currentGuestId, guests, setGuests, onAdd
       const currentGuest = guests.find(g => g.id === currentGuestId);
       if (currentGuest) {
         setSelectedGuest(currentGuest);
-        setIdentityNumber(currentGuest.identity_number);
+        setSearchText(currentGuest.identity_number);
       }
     }
   }, [guests, currentGuestId]);
 
+  const handleSelect = (guest) => {
+    setMatches([]);
+    setSelectedGuest(guest);
+    onGuestSelected(guest.id);
+    setError('');
+  };
+
   const handleSearch = async () => {
-    if (!identityNumber.trim()) return;
+    if (!searchText.trim()) return;
 
     try {
       const queryParams = new URLSearchParams({
+        q: searchText,
         skip: '0',
-        limit: '100',
-        identity_number: identityNumber
+        limit: '10'
       });
 
-      const response = await fetch(`http://localhost:8080/v1/customers/?${queryParams.toString()}`, {
+      const response = await fetch(`http://localhost:8080/v1/customers/search?${queryParams.toString()}`, {
         headers: {
           'Authorization': `Bearer ${token}`,
         },
@@ -58,18 +69,24 @@ const GuestSearch = ({ onGuestSelected, currentGuestId, guests, setGuests, onAdd
       }
 
       const data = await response.json();
-      if (data.success && data.data && data.data.customers && data.data.customers.length > 0) {
-        const foundGuest = data.data.customers[0]; // Get the first matching guest
-        setSelectedGuest(foundGuest);
-        onGuestSelected(foundGuest.id);
+      const found = (data.success && data.data && data.data.customers) || [];
+      if (found.length === 1 || (found.length > 1 && found[0].relevance === 1 && found[1].relevance < 1)) {
+        // A single or exact match is selected straight away
+        handleSelect(found[0]);
+      } else if (found.length > 1) {
+        setMatches(found);
+        setSelectedGuest(null);
+        onGuestSelected('');
         setError('');
       } else {
+        setMatches([]);
         setSelectedGuest(null);
         onGuestSelected('');
-        setError('No guest found with this identity number');
+        setError('No guest found');
       }
     } catch (error) {
       console.error('Error searching guests:', error);
+      setMatches([]);
       setSelectedGuest(null);
       onGuestSelected('');
       setError(error.message);
@@ -86,9 +103,9 @@ const GuestSearch = ({ onGuestSelected, currentGuestId, guests, setGuests, onAdd
           <TextField
             fullWidth
             size="small"
-            label="Guest Identity Number"
-            value={identityNumber}
-            onChange={(e) => setIdentityNumber(e.target.value)}
+            label="Guest Name, Phone, Email or Identity Number"
+            value={searchText}
+            onChange={(e) => setSearchText(e.target.value)}
             error={!!error}
             helperText={error}
             onKeyPress={(e) => {
@@ -123,6 +140,21 @@ const GuestSearch = ({ onGuestSelected, currentGuestId, guests, setGuests, onAdd
           </Button>
         </Grid>
         
+        {matches.length > 0 && (
+          <Grid item xs={12}>
+            <List dense sx={{ border: '1px solid #e0e0e0', borderRadius: 1 }}>
+              {matches.map((guest) => (
+                <ListItemButton key={guest.id} onClick={() => handleSelect(guest)}>
+                  <ListItemText
+                    primary={`${guest.firstname} ${guest.surname}`}
+                    secondary={[guest.identity_number, guest.phone, guest.email].filter(Boolean).join(' · ')}
+                  />
+                </ListItemButton>
+              ))}
+            </List>
+          </Grid>
+        )}
+
         {selectedGuest && (
           <Grid item xs={12}>
             <Card variant="outlined" sx={{ mt: 2 }}>
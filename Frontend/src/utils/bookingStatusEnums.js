@@ -3,7 +3,8 @@ export const BookingStatus = {
   CHECKED_IN: 2,
   CHECKED_OUT: 3,
   CANCELED: 4,
-  COMPLETED: 5
+  COMPLETED: 5,
+  NO_SHOW: 6
 };
 
 export const getBookingStatusMessage = (status) => {
@@ -18,6 +19,8 @@ export const getBookingStatusMessage = (status) => {
       return 'Cancelled';
     case BookingStatus.COMPLETED:
       return 'Completed';
+    case BookingStatus.NO_SHOW:
+      return 'No-show';
     default:
       return 'Unknown';
   }
@@ -45,6 +48,10 @@ export const getBookingStatusColor = (status) => {
       return {
         chipColor: 'success'
       };
+    case BookingStatus.NO_SHOW:
+      return {
+        chipColor: 'error'
+      };
     default:
       return {
         chipColor: 'default'
@@ -1,8 +1,5 @@
-export const countBookings = (bookingString) => {
-  if (!bookingString || bookingString === '') {
-    return 0;
-  }
-  return bookingString.split(';').length;
+export const countBookings = (bookings) => {
+  return Array.isArray(bookings) ? bookings.length : 0;
 };
 
 export const getStatusText = (status) => {
@@ -2,7 +2,8 @@ export const PaymentStatus = {
   UNPAID: 1,
   PAID: 2,
   FAILED: 3,
-  REFUNDED: 4
+  REFUNDED: 4,
+  PARTIALLY_PAID: 5
 };
 
 export const getPaymentStatusMessage = (status) => {
@@ -15,6 +16,8 @@ export const getPaymentStatusMessage = (status) => {
       return 'Failed';
     case PaymentStatus.REFUNDED:
       return 'Refunded';
+    case PaymentStatus.PARTIALLY_PAID:
+      return 'Partially Paid';
     default:
       return 'Unknown';
   }
@@ -42,6 +45,11 @@ export const getPaymentStatusColor = (status) => {
         backgroundColor: '#E5D7FD',
         textColor: '#4A1D96'
       };
+    case PaymentStatus.PARTIALLY_PAID:
+      return {
+        backgroundColor: '#E5F0FF',
+        textColor: '#0B3D91'
+      };
     default:
       return {
         backgroundColor: '#F5F5F5',
@@ -55,7 +63,9 @@ export const PaymentMethod = {
   CREDIT_CARD: 1,
   DEBIT_CARD: 2,
   CASH: 3,
-  BANK_TRANSFER: 4
+  BANK_TRANSFER: 4,
+  CITY_LEDGER: 5,
+  LOYALTY_POINTS: 6
 };
 
 export const getPaymentMethodMessage = (method) => {
@@ -70,6 +80,10 @@ export const getPaymentMethodMessage = (method) => {
       return 'Cash';
     case PaymentMethod.BANK_TRANSFER:
       return 'Bank Transfer';
+    case PaymentMethod.CITY_LEDGER:
+      return 'City Ledger';
+    case PaymentMethod.LOYALTY_POINTS:
+      return 'Loyalty Points';
     default:
       return `Unknown (${method})`;
   }